@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/dedupe"
+	"github.com/sstent/go-garminconnect/internal/fit"
+	"github.com/sstent/go-garminconnect/internal/search"
+)
+
+var activitiesCmd = &cobra.Command{
+	Use:   "activities",
+	Short: "Upload activities and search them offline",
+	Long: `Keep a full-text index of activity names, descriptions, locations and types
+in ~/.garmin/activities.db and search it without contacting Garmin Connect.
+"activities index" adds a period of activities; "watch --index" keeps the
+index current as new activities arrive.
+
+"activities upload" uploads FIT files. Uploaded files are recorded in
+~/.garmin/uploads.json, so uploading a file again, e.g. after a timeout,
+prints the activity created the first time instead of a duplicate.
+
+--duplicates also checks each file against the activities already in the
+account: one that started within a minute of it and covers the same distance
+(within 2% or 100 m), e.g. the same ride recorded by a watch and a bike
+computer, is a duplicate. "skip" keeps the existing activity, "keep-longest"
+keeps the longer recording, deleting the existing activity when the file is
+longer, and "prompt" asks for each duplicate.`,
+}
+
+var activitiesUploadCmd = &cobra.Command{
+	Use:   "upload <file.fit>...",
+	Short: "Upload FIT files and print the ID of the activity each one created",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   activitiesUploadHandler,
+}
+
+var activitiesIndexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "Add the activities of a period to the search index",
+	Run:   activitiesIndexHandler,
+}
+
+var activitiesSearchCmd = &cobra.Command{
+	Use:   "search <words>...",
+	Short: "List the indexed activities matching every word, best matches first",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   activitiesSearchHandler,
+}
+
+var (
+	searchLimit int
+	// uploadDuplicates is the policy for uploads duplicating an activity
+	uploadDuplicates string
+)
+
+func init() {
+	activitiesIndexCmd.Flags().StringVar(&exportStart, "start", "", "First day (YYYY-MM-DD) to index (default: 365 days ago)")
+	activitiesIndexCmd.Flags().StringVar(&exportEnd, "end", "", "Last day (YYYY-MM-DD) to index (default: yesterday)")
+	activitiesSearchCmd.Flags().IntVar(&searchLimit, "limit", 20, "Maximum number of activities listed")
+	activitiesUploadCmd.Flags().StringVar(&uploadDuplicates, "duplicates", "", "Check for activities already in the account and resolve duplicates: skip, keep-longest or prompt (default: upload unchecked)")
+	activitiesCmd.AddCommand(activitiesIndexCmd, activitiesSearchCmd, activitiesUploadCmd)
+}
+
+// searchIndexPath is where the activity search index is kept
+func searchIndexPath() string {
+	return filepath.Join(os.Getenv("HOME"), ".garmin", "activities.db")
+}
+
+func activitiesIndexHandler(cmd *cobra.Command, args []string) {
+	apiClient, err := newAPIClient()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	ctx := context.Background()
+	start, end := exportPeriod(ctx, apiClient, 365)
+
+	activities, err := apiClient.GetActivitiesByDate(ctx, start, end)
+	if err != nil {
+		fmt.Printf("Failed to get activities: %v\n", err)
+		os.Exit(1)
+	}
+	index, err := search.Open(searchIndexPath())
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	defer index.Close()
+	if err := index.Add(ctx, activities); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Printf("Indexed %d activities\n", len(activities))
+}
+
+func activitiesUploadHandler(cmd *cobra.Command, args []string) {
+	var uploader *dedupe.Uploader
+	if uploadDuplicates != "" {
+		policy, err := dedupe.ParsePolicy(uploadDuplicates)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		uploader = &dedupe.Uploader{Policy: policy, Prompt: promptDuplicate}
+	}
+	apiClient, err := newAPIClient()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	failed := false
+	for _, path := range args {
+		data, release, err := readFIT(path)
+		if err != nil {
+			fmt.Println(err)
+			failed = true
+			continue
+		}
+		if uploader == nil {
+			var id int64
+			if id, err = apiClient.UploadActivity(context.Background(), data); err == nil {
+				fmt.Printf("%s: activity %d\n", path, id)
+			}
+		} else {
+			uploader.Client = apiClient
+			var result dedupe.Result
+			if result, err = uploader.Upload(context.Background(), data); err == nil {
+				printUpload(path, result)
+			}
+		}
+		release()
+		if err != nil {
+			fmt.Printf("failed to upload %s: %v\n", path, err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// readFIT reads the FIT file at path into a pooled buffer, so uploading many
+// files doesn't allocate one buffer per file, and returns a function returning
+// the buffer to the pool
+func readFIT(path string) ([]byte, func(), error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer f.Close()
+
+	buf := fit.GetBuffer()
+	if _, err := buf.ReadFrom(f); err != nil {
+		fit.PutBuffer(buf)
+		return nil, nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return buf.Bytes(), func() { fit.PutBuffer(buf) }, nil
+}
+
+// printUpload reports the outcome of a checked upload
+func printUpload(path string, result dedupe.Result) {
+	if result.Duplicate == nil {
+		fmt.Printf("%s: activity %d\n", path, result.ActivityID)
+		return
+	}
+	switch result.Resolution {
+	case dedupe.Skip:
+		fmt.Printf("%s: skipped, duplicates activity %d\n", path, result.Duplicate.ActivityID)
+	case dedupe.Replace:
+		fmt.Printf("%s: activity %d, replacing duplicate activity %d\n", path, result.ActivityID, result.Duplicate.ActivityID)
+	default:
+		fmt.Printf("%s: activity %d, duplicating activity %d\n", path, result.ActivityID, result.Duplicate.ActivityID)
+	}
+}
+
+// duplicateAnswers reads the answers of every prompt, so answers piped in
+// for several files aren't lost to a scanner's buffer
+var duplicateAnswers = bufio.NewScanner(os.Stdin)
+
+// promptDuplicate asks on the console what to do with a duplicate upload
+func promptDuplicate(existing, upload api.Activity) (dedupe.Resolution, error) {
+	fmt.Printf("Duplicate of activity %d %q (%s, %.2f km, %s); file: %.2f km, %s\n",
+		existing.ActivityID, existing.Name, existing.StartTime.Format("2006-01-02 15:04"),
+		existing.Distance/1000, time.Duration(existing.Duration)*time.Second,
+		upload.Distance/1000, time.Duration(upload.Duration)*time.Second)
+	scanner := duplicateAnswers
+	for {
+		fmt.Print("[s]kip, [r]eplace the activity or [k]eep both? ")
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return dedupe.Skip, err
+			}
+			return dedupe.Skip, errors.New("no answer to the duplicate prompt")
+		}
+		switch strings.ToLower(strings.TrimSpace(scanner.Text())) {
+		case "s", "skip":
+			return dedupe.Skip, nil
+		case "r", "replace":
+			return dedupe.Replace, nil
+		case "k", "keep", "keep both":
+			return dedupe.KeepBoth, nil
+		}
+	}
+}
+
+func activitiesSearchHandler(cmd *cobra.Command, args []string) {
+	index, err := search.Open(searchIndexPath())
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	defer index.Close()
+
+	results, err := index.Search(context.Background(), strings.Join(args, " "), searchLimit)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if len(results) == 0 {
+		fmt.Println("No matching activities")
+		return
+	}
+	for _, r := range results {
+		line := fmt.Sprintf("%d  %s  %-18s %s", r.ActivityID, r.StartTime.Format("2006-01-02"), r.Type, r.Name)
+		if r.Location != "" {
+			line += " (" + r.Location + ")"
+		}
+		fmt.Println(line)
+	}
+}
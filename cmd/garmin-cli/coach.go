@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/report"
+)
+
+var coachCmd = &cobra.Command{
+	Use:   "coach",
+	Short: "Manage athlete accounts and report on a squad",
+	Long: `Manage the sessions of athletes who consented to share their Garmin data, each
+stored in ~/.garmin/athletes/<name>/session.json, and report on all of them
+at once. Athlete passwords are only used to log in and are never stored.`,
+}
+
+var coachAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Log in an athlete with GARMIN_USERNAME and GARMIN_PASSWORD and save their session",
+	Args:  cobra.ExactArgs(1),
+	Run:   coachAddHandler,
+}
+
+var coachRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Delete an athlete's saved session",
+	Args:  cobra.ExactArgs(1),
+	Run:   coachRemoveHandler,
+}
+
+var coachListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the athletes with a saved session",
+	Run:   coachListHandler,
+}
+
+var coachReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate a weekly report combining every athlete",
+	Run:   coachReportHandler,
+}
+
+var (
+	coachDate        string
+	coachFormat      string
+	coachOutput      string
+	coachConcurrency int
+)
+
+func init() {
+	coachReportCmd.Flags().StringVar(&coachDate, "date", "", "Any date (YYYY-MM-DD) inside the week to report on (default: last week)")
+	coachReportCmd.Flags().StringVar(&coachFormat, "format", "markdown", "Output format: markdown or html")
+	coachReportCmd.Flags().StringVarP(&coachOutput, "output", "o", "", "Write the report to a file instead of stdout")
+	coachReportCmd.Flags().IntVar(&coachConcurrency, "concurrency", 4, "Number of athletes fetched at once")
+	coachCmd.AddCommand(coachAddCmd, coachRemoveCmd, coachListCmd, coachReportCmd)
+}
+
+// athletesDir holds one directory per athlete with their session
+func athletesDir() string {
+	return filepath.Join(os.Getenv("HOME"), ".garmin", "athletes")
+}
+
+func athleteSessionPath(name string) string {
+	return filepath.Join(athletesDir(), name, "session.json")
+}
+
+// athleteNames lists the athletes with a saved session, sorted
+func athleteNames() ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(athletesDir(), "*", "session.json"))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(matches))
+	for i, m := range matches {
+		names[i] = filepath.Base(filepath.Dir(m))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// validAthleteName reports whether name is a single path element, so it can't
+// point outside the athletes directory
+func validAthleteName(name string) bool {
+	return name != "" && name == filepath.Base(name) && name != "." && name != ".."
+}
+
+func coachAddHandler(cmd *cobra.Command, args []string) {
+	name := args[0]
+	if !validAthleteName(name) {
+		fmt.Printf("Invalid athlete name %q\n", name)
+		os.Exit(1)
+	}
+	username, password, err := envCredentials(context.Background())
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if _, err := newAuthenticatorAt(athleteSessionPath(name)).Login(username, password); err != nil {
+		fmt.Printf("Failed to log in %s: %v\n", name, err)
+		os.Exit(1)
+	}
+	fmt.Printf("Saved session for %s\n", name)
+}
+
+func coachRemoveHandler(cmd *cobra.Command, args []string) {
+	name := args[0]
+	if !validAthleteName(name) {
+		fmt.Printf("Invalid athlete name %q\n", name)
+		os.Exit(1)
+	}
+	sessionPath := athleteSessionPath(name)
+	if err := os.Remove(sessionPath); err != nil {
+		fmt.Printf("Failed to remove %s: %v\n", name, err)
+		os.Exit(1)
+	}
+	// Remove the athlete's directory only if nothing else is stored in it
+	os.Remove(filepath.Dir(sessionPath))
+}
+
+func coachListHandler(cmd *cobra.Command, args []string) {
+	names, err := athleteNames()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	for _, name := range names {
+		fmt.Println(name)
+	}
+}
+
+// failedSource reports why an athlete's session could not be loaded in their
+// row of the squad report
+type failedSource struct {
+	err error
+}
+
+func (f failedSource) GetActivitiesByDate(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.Activity, error) {
+	return nil, f.err
+}
+
+func (f failedSource) GetActivityHRZones(ctx context.Context, activityID int64, opts ...api.RequestOption) ([]api.HRZone, error) {
+	return nil, f.err
+}
+
+func (f failedSource) GetSleepData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.SleepData, error) {
+	return nil, f.err
+}
+
+func (f failedSource) GetActivityDetails(ctx context.Context, activityID int64, opts ...api.RequestOption) (*api.ActivityDetail, error) {
+	return nil, f.err
+}
+
+func coachReportHandler(cmd *cobra.Command, args []string) {
+	names, err := athleteNames()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if len(names) == 0 {
+		fmt.Println("No athletes yet, add one with: garmin-cli coach add <name>")
+		os.Exit(1)
+	}
+	opts, err := clientOptions()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	athletes := make([]report.Athlete, len(names))
+	for i, name := range names {
+		path := athleteSessionPath(name)
+		athletes[i] = report.Athlete{Name: name}
+		client, err := api.LoadSession(ctx, newAuthenticatorAt(path), path, opts...)
+		if err != nil {
+			athletes[i].Source = failedSource{err}
+			continue
+		}
+		athletes[i].Source = client
+	}
+
+	day := time.Now().AddDate(0, 0, -7)
+	if coachDate != "" {
+		day, err = time.ParseInLocation("2006-01-02", coachDate, time.Local)
+		if err != nil {
+			fmt.Printf("Invalid date %q: %v\n", coachDate, err)
+			os.Exit(1)
+		}
+	}
+	squad, err := report.BuildSquad(ctx, athletes, day, coachConcurrency)
+	if err != nil {
+		fmt.Printf("Failed to build squad report: %v\n", err)
+		os.Exit(1)
+	}
+
+	out := os.Stdout
+	if coachOutput != "" {
+		f, err := os.Create(coachOutput)
+		if err != nil {
+			fmt.Printf("Failed to create output file: %v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := squad.Render(out, report.Format(coachFormat)); err != nil {
+		fmt.Printf("Failed to render report: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/sstent/go-garminconnect/internal/sheets"
+)
+
+// cliConfig holds the settings of integrations, read from ~/.garmin/config.json
+type cliConfig struct {
+	Sheets *sheets.Config `json:"sheets,omitempty"`
+}
+
+func configPath() string {
+	return filepath.Join(os.Getenv("HOME"), ".garmin", "config.json")
+}
+
+// loadConfig reads the config file; a missing file is an empty configuration
+func loadConfig() (*cliConfig, error) {
+	var config cliConfig
+	data, err := os.ReadFile(configPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return &config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", configPath(), err)
+	}
+	return &config, nil
+}
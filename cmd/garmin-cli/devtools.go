@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/sstent/go-garminconnect/internal/devtools"
+)
+
+var devtoolsCmd = &cobra.Command{
+	Use:   "devtools",
+	Short: "Tools for developing go-garminconnect",
+}
+
+var captureCmd = &cobra.Command{
+	Use:   "capture",
+	Short: "Capture anonymized JSON fixtures from a live account",
+	Run:   captureHandler,
+}
+
+var (
+	captureDir  string
+	captureDate string
+)
+
+func init() {
+	captureCmd.Flags().StringVar(&captureDir, "out", "garmintest/fixtures", "Directory to write fixtures to")
+	captureCmd.Flags().StringVar(&captureDate, "date", "", "Date (YYYY-MM-DD) to capture daily endpoints for (default: yesterday)")
+	devtoolsCmd.AddCommand(captureCmd)
+}
+
+func captureHandler(cmd *cobra.Command, args []string) {
+	apiClient, err := newAPIClient()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	var date time.Time
+	if captureDate != "" {
+		if date, err = time.Parse("2006-01-02", captureDate); err != nil {
+			fmt.Printf("Invalid date %q: %v\n", captureDate, err)
+			os.Exit(1)
+		}
+	} else {
+		today, err := apiClient.Today(context.Background())
+		if err != nil {
+			fmt.Printf("Failed to determine today's date: %v\n", err)
+			os.Exit(1)
+		}
+		date = today.AddDate(0, 0, -1)
+	}
+
+	results, err := devtools.Capture(context.Background(), apiClient, captureDir, date)
+	if err != nil {
+		fmt.Printf("Capture failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, r := range results {
+		if r.Err != nil {
+			fmt.Printf("%-16s FAILED  %v\n", r.Endpoint, r.Err)
+			continue
+		}
+		fmt.Printf("%-16s ok      %s\n", r.Endpoint, r.File)
+	}
+}
@@ -0,0 +1,737 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/applehealth"
+	"github.com/sstent/go-garminconnect/internal/archive"
+	"github.com/sstent/go-garminconnect/internal/csvlog"
+	"github.com/sstent/go-garminconnect/internal/fhir"
+	"github.com/sstent/go-garminconnect/internal/geo"
+	"github.com/sstent/go-garminconnect/internal/gpx"
+	"github.com/sstent/go-garminconnect/internal/heatmap"
+	"github.com/sstent/go-garminconnect/internal/privacy"
+	"github.com/sstent/go-garminconnect/internal/sheets"
+	"github.com/sstent/go-garminconnect/internal/sqlexport"
+	"github.com/sstent/go-garminconnect/internal/trainer"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export Garmin Connect data to other formats",
+}
+
+var exportGPXCmd = &cobra.Command{
+	Use:   "gpx <activity-id>",
+	Short: "Export an activity as GPX",
+	Args:  cobra.ExactArgs(1),
+	Run:   exportGPXHandler,
+}
+
+var exportAppleHealthCmd = &cobra.Command{
+	Use:   "apple-health",
+	Short: "Export steps, resting heart rate, sleep and workouts in Apple Health's export.xml format",
+	Run:   exportAppleHealthHandler,
+}
+
+var exportFHIRCmd = &cobra.Command{
+	Use:   "fhir",
+	Short: "Export resting heart rate, weight, SpO2, blood pressure and sleep as a FHIR R4 Observation bundle",
+	Run:   exportFHIRHandler,
+}
+
+var exportHeatmapCmd = &cobra.Command{
+	Use:   "heatmap",
+	Short: "Export a GeoJSON density grid of the GPS tracks of all activities in a period",
+	Run:   exportHeatmapHandler,
+}
+
+var exportWorkoutCmd = &cobra.Command{
+	Use:   "workout <workout-id>",
+	Short: "Export a structured workout as a Zwift ZWO or MRC/ERG trainer file",
+	Args:  cobra.ExactArgs(1),
+	Run:   exportWorkoutHandler,
+}
+
+var exportCSVCmd = &cobra.Command{
+	Use:   "csv",
+	Short: "Append daily steps, resting heart rate and sleep to one CSV file per metric",
+	Long: `Append one row per day to steps.csv, resting_hr.csv, sleep_minutes.csv and
+sleep_score.csv in --dir. Days already in a file are skipped or corrected, so the
+command can run daily (e.g. from cron) over overlapping periods.`,
+	Run: exportCSVHandler,
+}
+
+var exportSQLiteCmd = &cobra.Command{
+	Use:   "sqlite",
+	Short: "Write activities and daily health data to a SQLite database",
+	Long: `Write the activities, steps, resting heart rate and sleep of a period to
+SQLite. Rows already in the database are updated, so the command can run daily
+over overlapping periods.
+
+With --schema garmindb the tables of the GarminDB project are written instead,
+to garmin.db and garmin_activities.db in --output (default ~/HealthData/DBs,
+GarminDB's own location), so its notebooks and plots work with data synced by
+this client. Times are local, distances in kilometers and speeds in km/h, as
+GarminDB stores them with metric units; columns this client has no data for,
+such as heart rate zones, are left NULL. GarminDB's own bookkeeping tables are
+not written.`,
+	Run: exportSQLiteHandler,
+}
+
+var exportSheetsCmd = &cobra.Command{
+	Use:   "sheets",
+	Short: "Push daily summaries and new activities to a Google Sheet",
+	Long: `Append a row per day (steps, resting heart rate, sleep) to the daily sheet and
+a row per activity to the activities sheet of a Google spreadsheet. Rows already
+in the sheets are skipped. Configure the "sheets" section of ~/.garmin/config.json:
+
+  {"sheets": {"spreadsheetId": "...", "credentialsFile": "/path/to/service-account.json"}}
+
+and share the spreadsheet with the service account's email address.`,
+	Run: exportSheetsHandler,
+}
+
+var exportAccountCmd = &cobra.Command{
+	Use:   "account",
+	Short: "Request a full account data export and download the archive when ready",
+	Long: `Request Garmin's full account data export (GDPR) and poll until the archive
+is ready, then download it. Preparing the archive can take days; pass --request
+with the ID printed by an earlier run to resume waiting on an existing export.`,
+	Run: exportAccountHandler,
+}
+
+var (
+	exportProfile string
+	exportOutput  string
+	exportStart   string
+	exportEnd     string
+	exportRequest string
+	exportPoll    time.Duration
+	exportArchive string
+	exportPatient string
+	exportType    string
+	exportZoom    int
+	exportFormat  string
+	exportFTP     float64
+	// exportAnonymize hides the start and end of tracks and drops activity names
+	exportAnonymize bool
+	exportRadius    float64
+	exportZones     string
+	exportZoneMode  string
+	exportClean     bool
+	exportDir       string
+	exportSchema    string
+	// exportOffline is a native SQLite export read instead of Garmin Connect
+	exportOffline string
+	// exportFallback is a native SQLite export read when Garmin Connect fails
+	exportFallback string
+)
+
+func init() {
+	exportGPXCmd.Flags().StringVar(&exportProfile, "profile", string(gpx.ProfileStandard), "GPX profile: standard or strava (adds heart rate, cadence and temperature)")
+	exportGPXCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write the export to a file instead of stdout")
+	addTrackFlags(exportGPXCmd)
+	exportCmd.AddCommand(exportGPXCmd)
+
+	exportAppleHealthCmd.Flags().StringVar(&exportStart, "start", "", "First day (YYYY-MM-DD) to export (default: 30 days ago)")
+	exportAppleHealthCmd.Flags().StringVar(&exportEnd, "end", "", "Last day (YYYY-MM-DD) to export (default: yesterday)")
+	exportAppleHealthCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write the export to a file instead of stdout")
+	addOfflineFlag(exportAppleHealthCmd)
+	exportCmd.AddCommand(exportAppleHealthCmd)
+
+	exportFHIRCmd.Flags().StringVar(&exportStart, "start", "", "First day (YYYY-MM-DD) to export (default: 30 days ago)")
+	exportFHIRCmd.Flags().StringVar(&exportEnd, "end", "", "Last day (YYYY-MM-DD) to export (default: yesterday)")
+	exportFHIRCmd.Flags().StringVar(&exportPatient, "patient", "", "Subject reference for the observations, e.g. Patient/123")
+	exportFHIRCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write the export to a file instead of stdout")
+	exportCmd.AddCommand(exportFHIRCmd)
+
+	exportHeatmapCmd.Flags().StringVar(&exportStart, "start", "", "First day (YYYY-MM-DD) to export (default: 30 days ago)")
+	exportHeatmapCmd.Flags().StringVar(&exportEnd, "end", "", "Last day (YYYY-MM-DD) to export (default: yesterday)")
+	exportHeatmapCmd.Flags().StringVar(&exportType, "type", "", "Only include activities of this type, e.g. running")
+	exportHeatmapCmd.Flags().IntVar(&exportZoom, "zoom", heatmap.DefaultZoom, "Map zoom level whose tiles set the cell size")
+	exportHeatmapCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write the export to a file instead of stdout")
+	addTrackFlags(exportHeatmapCmd)
+	exportCmd.AddCommand(exportHeatmapCmd)
+
+	exportWorkoutCmd.Flags().StringVar(&exportFormat, "format", "zwo", "Trainer file format: zwo, mrc or erg")
+	exportWorkoutCmd.Flags().Float64Var(&exportFTP, "ftp", 0, "Functional threshold power in watts; required for ERG files and power targets")
+	exportWorkoutCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Write the export to a file instead of stdout")
+	exportCmd.AddCommand(exportWorkoutCmd)
+
+	exportCSVCmd.Flags().StringVar(&exportStart, "start", "", "First day (YYYY-MM-DD) to export (default: 7 days ago)")
+	exportCSVCmd.Flags().StringVar(&exportEnd, "end", "", "Last day (YYYY-MM-DD) to export (default: yesterday)")
+	exportCSVCmd.Flags().StringVar(&exportDir, "dir", ".", "Directory of the CSV files")
+	addOfflineFlag(exportCSVCmd)
+	exportCmd.AddCommand(exportCSVCmd)
+
+	exportSQLiteCmd.Flags().StringVar(&exportStart, "start", "", "First day (YYYY-MM-DD) to export (default: 7 days ago)")
+	exportSQLiteCmd.Flags().StringVar(&exportEnd, "end", "", "Last day (YYYY-MM-DD) to export (default: yesterday)")
+	exportSQLiteCmd.Flags().StringVar(&exportSchema, "schema", string(sqlexport.SchemaNative), "Table layout: native or garmindb")
+	exportSQLiteCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "Database file, or directory for garmindb (default: garmin.sqlite or ~/HealthData/DBs)")
+	exportCmd.AddCommand(exportSQLiteCmd)
+
+	exportSheetsCmd.Flags().StringVar(&exportStart, "start", "", "First day (YYYY-MM-DD) to push (default: 7 days ago)")
+	exportSheetsCmd.Flags().StringVar(&exportEnd, "end", "", "Last day (YYYY-MM-DD) to push (default: yesterday)")
+	addOfflineFlag(exportSheetsCmd)
+	exportCmd.AddCommand(exportSheetsCmd)
+
+	exportAccountCmd.Flags().StringVar(&exportRequest, "request", "", "Resume an existing export request instead of starting a new one")
+	exportAccountCmd.Flags().DurationVar(&exportPoll, "poll", 10*time.Minute, "Interval between status checks")
+	exportAccountCmd.Flags().StringVarP(&exportArchive, "output", "o", "garmin-export.zip", "File to save the archive to")
+	exportCmd.AddCommand(exportAccountCmd)
+}
+
+// addOfflineFlag adds the option of reading a SQLite export instead of Garmin Connect
+func addOfflineFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&exportOffline, "offline", "", "Read this database written by 'export sqlite' instead of Garmin Connect")
+	cmd.Flags().StringVar(&exportFallback, "fallback", "", "Read this database written by 'export sqlite' when Garmin Connect is unreachable, rate limited or failing")
+}
+
+// exportReader returns the source selected by --offline and --fallback, or
+// else the client of the account, and a function releasing it. Data read from
+// a fallback archive is reported on stderr once.
+func exportReader() (archive.Reader, func()) {
+	var warned sync.Once
+	return openSource(exportOffline, exportFallback, func(archivedAt time.Time, err error) {
+		warned.Do(func() {
+			fmt.Fprintf(os.Stderr, "Warning: Garmin Connect failed (%v); using archived data from %s\n", err, archiveAge(archivedAt))
+		})
+	})
+}
+
+// openSource returns the archive at offline, the client of the account
+// falling back to the archive at fallback, or the client alone, and a function
+// releasing it. onFallback is called for every call answered from the
+// fallback archive.
+func openSource(offline, fallback string, onFallback func(archivedAt time.Time, err error)) (archive.Source, func()) {
+	if offline != "" && fallback != "" {
+		fmt.Println("--offline and --fallback are mutually exclusive")
+		os.Exit(1)
+	}
+	if offline != "" {
+		a, err := sqlexport.OpenArchive(offline)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return a, func() { a.Close() }
+	}
+	apiClient, err := newAPIClient()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if fallback == "" {
+		return apiClient, func() {}
+	}
+	a, err := sqlexport.OpenArchive(fallback)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	archivedAt := a.UpdatedAt()
+	return &archive.Fallback{
+		Live:       apiClient,
+		Archive:    a,
+		ArchivedAt: archivedAt,
+		OnFallback: func(err error) { onFallback(archivedAt, err) },
+	}, func() { a.Close() }
+}
+
+// archiveAge describes when an archive was written
+func archiveAge(archivedAt time.Time) string {
+	if archivedAt.IsZero() {
+		return "an unknown time"
+	}
+	return fmt.Sprintf("%s (%s ago)", archivedAt.Format("2006-01-02 15:04"), time.Since(archivedAt).Round(time.Minute))
+}
+
+// addTrackFlags adds the options for cleaning tracks and hiding locations in
+// track exports
+func addTrackFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&exportClean, "clean", false, "Remove GPS spikes and stops from tracks before exporting, reporting what changed on stderr")
+	cmd.Flags().BoolVar(&exportAnonymize, "anonymize", false, "Hide where tracks start and end and leave out activity names, for sharing publicly")
+	cmd.Flags().Float64Var(&exportRadius, "privacy-radius", privacy.DefaultRadius, "Distance in meters hidden around the start and end of tracks with --anonymize")
+	cmd.Flags().StringVar(&exportZones, "privacy-zones", "", "JSON file of privacy zones to hide (default: ~/.garmin/privacy-zones.json when present)")
+	cmd.Flags().StringVar(&exportZoneMode, "privacy-mode", string(privacy.ZoneTrim), "How points in privacy zones are hidden: trim or fuzz")
+}
+
+// trackFilter returns the function cleaning tracks and hiding locations
+// according to the track flags
+func trackFilter() func([]api.TrackPoint) []api.TrackPoint {
+	mode := privacy.ZoneMode(exportZoneMode)
+	if mode != privacy.ZoneTrim && mode != privacy.ZoneFuzz {
+		fmt.Printf("Unknown privacy mode %q\n", exportZoneMode)
+		os.Exit(1)
+	}
+
+	path := exportZones
+	if path == "" {
+		path = filepath.Join(os.Getenv("HOME"), ".garmin", "privacy-zones.json")
+		if _, err := os.Stat(path); err != nil {
+			path = ""
+		}
+	}
+	var zones []privacy.Zone
+	if path != "" {
+		var err error
+		if zones, err = privacy.LoadZones(path); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	}
+
+	return func(points []api.TrackPoint) []api.TrackPoint {
+		if exportClean {
+			result := geo.Clean(points, geo.DefaultCleanOptions)
+			fmt.Fprintf(os.Stderr, "Cleaned track: %d spikes and %d stops removed, %d -> %d points, %.2f -> %.2f km, %s -> %s\n",
+				result.Spikes, result.Pauses, result.Before.Points, result.After.Points,
+				result.Before.Distance/1000, result.After.Distance/1000, result.Before.Duration, result.After.Duration)
+			points = result.Points
+		}
+		if exportAnonymize {
+			points = privacy.TrimEnds(points, exportRadius)
+		}
+		return privacy.ApplyZones(points, zones, mode)
+	}
+}
+
+// exportOutputFile returns the writer selected by --output
+func exportOutputFile() *os.File {
+	if exportOutput == "" {
+		return os.Stdout
+	}
+	f, err := os.Create(exportOutput)
+	if err != nil {
+		fmt.Printf("Failed to create output file: %v\n", err)
+		os.Exit(1)
+	}
+	return f
+}
+
+func exportGPXHandler(cmd *cobra.Command, args []string) {
+	activityID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		fmt.Printf("Invalid activity ID %q\n", args[0])
+		os.Exit(1)
+	}
+	profile := gpx.Profile(exportProfile)
+	if profile != gpx.ProfileStandard && profile != gpx.ProfileStrava {
+		fmt.Printf("Unknown GPX profile %q\n", exportProfile)
+		os.Exit(1)
+	}
+	scrub := trackFilter()
+
+	apiClient, err := newAPIClient()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	detail, err := apiClient.GetActivityDetails(ctx, activityID)
+	if err != nil {
+		fmt.Printf("Failed to get activity: %v\n", err)
+		os.Exit(1)
+	}
+	points, err := apiClient.GetActivityTrack(ctx, activityID)
+	if err != nil {
+		fmt.Printf("Failed to get activity track: %v\n", err)
+		os.Exit(1)
+	}
+
+	track := gpxTrack(detail, scrub(points))
+	if exportAnonymize {
+		track.Name = ""
+	}
+
+	out := exportOutputFile()
+	defer out.Close()
+
+	if err := gpx.Encode(out, track, profile); err != nil {
+		fmt.Printf("Failed to write GPX: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// gpxTrack converts an activity and its track series into a GPX track
+func gpxTrack(detail *api.ActivityDetail, points []api.TrackPoint) gpx.Track {
+	track := gpx.Track{
+		Name:   detail.Name,
+		Type:   detail.Type,
+		Points: make([]gpx.Point, len(points)),
+	}
+	if len(points) > 0 {
+		track.Start = points[0].Timestamp.Time
+	}
+	for i, p := range points {
+		track.Points[i] = gpx.Point{
+			Time:        p.Timestamp.Time,
+			Lat:         api.Value(p.Lat),
+			Lon:         api.Value(p.Lon),
+			HasPosition: p.Lat != nil && p.Lon != nil,
+			Elevation:   p.Elevation,
+			HeartRate:   p.HeartRate,
+			Cadence:     p.Cadence,
+			Temperature: p.Temperature,
+		}
+	}
+	return track
+}
+
+func exportAppleHealthHandler(cmd *cobra.Command, args []string) {
+	source, release := exportReader()
+	defer release()
+
+	ctx := context.Background()
+	start, end := exportPeriod(ctx, source, 30)
+	data := applehealth.Data{Location: start.Location()}
+
+	var err error
+	data.Steps, err = source.GetStepsDataRange(ctx, start, end)
+	warnPartial("steps", err)
+	data.Sleep, err = source.GetSleepDataRange(ctx, start, end)
+	warnPartial("sleep", err)
+	data.Stats, err = api.FetchRange(ctx, start, end, api.DefaultRangeOptions(), func(ctx context.Context, day time.Time) (api.UserStats, error) {
+		stats, err := source.GetUserStats(ctx, day)
+		if err != nil {
+			return api.UserStats{}, err
+		}
+		return *stats, nil
+	})
+	warnPartial("daily stats", err)
+	data.Workouts, err = source.GetActivitiesByDate(ctx, start, end)
+	warnPartial("activities", err)
+
+	out := exportOutputFile()
+	defer out.Close()
+
+	if err := applehealth.Encode(out, data); err != nil {
+		fmt.Printf("Failed to write export: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func exportFHIRHandler(cmd *cobra.Command, args []string) {
+	apiClient, err := newAPIClient()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	start, end := exportPeriod(ctx, apiClient, 30)
+	data := fhir.Data{Patient: exportPatient, Location: start.Location()}
+
+	data.HeartRate, err = api.FetchRange(ctx, start, end, api.DefaultRangeOptions(), func(ctx context.Context, day time.Time) (api.HeartRateData, error) {
+		hr, err := apiClient.GetHeartRateData(ctx, day)
+		if err != nil {
+			return api.HeartRateData{}, err
+		}
+		return *hr, nil
+	})
+	warnPartial("heart rate", err)
+	data.Weight, err = apiClient.GetBodyComposition(ctx, api.BodyCompositionRequest{
+		StartDate: api.NewGarminTime(start),
+		EndDate:   api.NewGarminTime(end.AddDate(0, 0, 1)),
+	})
+	warnPartial("weight", err)
+	data.SpO2, err = apiClient.GetSpO2DataRange(ctx, start, end)
+	warnPartial("SpO2", err)
+	data.BloodPressure, err = apiClient.GetBloodPressure(ctx, start, end)
+	warnPartial("blood pressure", err)
+	data.Sleep, err = apiClient.GetSleepDataRange(ctx, start, end)
+	warnPartial("sleep", err)
+
+	out := exportOutputFile()
+	defer out.Close()
+
+	if err := fhir.Encode(out, data); err != nil {
+		fmt.Printf("Failed to write export: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func exportHeatmapHandler(cmd *cobra.Command, args []string) {
+	scrub := trackFilter()
+	apiClient, err := newAPIClient()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	loc, err := apiClient.Location(ctx)
+	if err != nil {
+		fmt.Printf("Failed to determine time zone: %v\n", err)
+		os.Exit(1)
+	}
+	today := api.NormalizeDate(time.Now(), loc)
+	start := parseExportDate(exportStart, today.AddDate(0, 0, -30), loc)
+	end := parseExportDate(exportEnd, today.AddDate(0, 0, -1), loc)
+
+	activities, err := apiClient.GetActivitiesByDate(ctx, start, end)
+	if err != nil {
+		fmt.Printf("Failed to get activities: %v\n", err)
+		os.Exit(1)
+	}
+
+	grid := heatmap.NewGrid(exportZoom)
+	for _, a := range activities {
+		if exportType != "" && !strings.EqualFold(a.Type, exportType) {
+			continue
+		}
+		// Activities whose track fails to load are left out with a warning
+		track, err := apiClient.GetActivityTrack(ctx, a.ActivityID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping activity %d: %v\n", a.ActivityID, err)
+			continue
+		}
+		grid.Add(scrub(track))
+	}
+
+	out := exportOutputFile()
+	defer out.Close()
+
+	if err := heatmap.Encode(out, grid.Zoom, grid.Cells()); err != nil {
+		fmt.Printf("Failed to write export: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func exportWorkoutHandler(cmd *cobra.Command, args []string) {
+	workoutID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		fmt.Printf("Invalid workout ID %q\n", args[0])
+		os.Exit(1)
+	}
+	encode := map[string]func(io.Writer, api.Workout, float64) error{
+		"zwo": trainer.EncodeZWO,
+		"mrc": trainer.EncodeMRC,
+		"erg": trainer.EncodeERG,
+	}[exportFormat]
+	if encode == nil {
+		fmt.Printf("Unknown workout format %q\n", exportFormat)
+		os.Exit(1)
+	}
+
+	apiClient, err := newAPIClient()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	workout, err := apiClient.GetWorkout(context.Background(), workoutID)
+	if err != nil {
+		fmt.Printf("Failed to get workout: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Convert before creating the output file so a failure leaves no empty file
+	var buf bytes.Buffer
+	if err := encode(&buf, *workout, exportFTP); err != nil {
+		fmt.Printf("Failed to convert workout: %v\n", err)
+		os.Exit(1)
+	}
+
+	out := exportOutputFile()
+	defer out.Close()
+
+	if _, err := buf.WriteTo(out); err != nil {
+		fmt.Printf("Failed to write export: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// parseExportDate parses a YYYY-MM-DD flag in loc, returning def when unset
+func parseExportDate(value string, def time.Time, loc *time.Location) time.Time {
+	if value == "" {
+		return def
+	}
+	day, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		fmt.Printf("Invalid date %q: %v\n", value, err)
+		os.Exit(1)
+	}
+	return day
+}
+
+func exportCSVHandler(cmd *cobra.Command, args []string) {
+	source, release := exportReader()
+	defer release()
+	ctx := context.Background()
+	start, end := exportPeriod(ctx, source, 7)
+
+	result, err := csvlog.Append(exportDir, dailyValues(ctx, source, start, end))
+	if err != nil {
+		fmt.Printf("Failed to write CSV files: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Appended %d rows, updated %d\n", result.Appended, result.Updated)
+}
+
+func exportSQLiteHandler(cmd *cobra.Command, args []string) {
+	schema := sqlexport.Schema(exportSchema)
+	dest := exportOutput
+	switch {
+	case schema != sqlexport.SchemaNative && schema != sqlexport.SchemaGarminDB:
+		fmt.Printf("Unknown schema %q\n", exportSchema)
+		os.Exit(1)
+	case dest != "":
+	case schema == sqlexport.SchemaGarminDB:
+		dest = filepath.Join(os.Getenv("HOME"), "HealthData", "DBs")
+	default:
+		dest = "garmin.sqlite"
+	}
+
+	apiClient, err := newAPIClient()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	ctx := context.Background()
+	start, end := exportPeriod(ctx, apiClient, 7)
+
+	var data sqlexport.Data
+	data.Days, err = csvlog.FetchDays(ctx, apiClient, start, end)
+	if err != nil {
+		if !api.IsPartial(err) {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Warning: some days are missing: %v\n", err)
+	}
+	data.Activities, err = apiClient.GetActivitiesByDate(ctx, start, end)
+	if err != nil {
+		fmt.Printf("Failed to get activities: %v\n", err)
+		os.Exit(1)
+	}
+
+	result, err := sqlexport.Write(ctx, dest, schema, data)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Printf("Wrote %d activities and %d days to %s\n", result.Activities, result.Days, dest)
+}
+
+func exportSheetsHandler(cmd *cobra.Command, args []string) {
+	config, err := loadConfig()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if config.Sheets == nil {
+		fmt.Printf("Add a \"sheets\" section to %s first\n", configPath())
+		os.Exit(1)
+	}
+	exporter, err := sheets.NewExporter(*config.Sheets)
+	if err != nil {
+		fmt.Printf("Invalid sheets configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	source, release := exportReader()
+	defer release()
+	ctx := context.Background()
+	start, end := exportPeriod(ctx, source, 7)
+
+	days, err := exporter.PushDaily(ctx, dailyValues(ctx, source, start, end))
+	if err != nil {
+		fmt.Printf("Failed to push daily summaries: %v\n", err)
+		os.Exit(1)
+	}
+	activities, err := source.GetActivitiesByDate(ctx, start, end)
+	if err != nil {
+		fmt.Printf("Failed to get activities: %v\n", err)
+		os.Exit(1)
+	}
+	added, err := exporter.PushActivities(ctx, activities)
+	if err != nil {
+		fmt.Printf("Failed to push activities: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Added %d days and %d activities\n", days, added)
+}
+
+// exportPeriod returns the days selected by --start and --end in the user's
+// time zone, by default the given number of days up to yesterday. The zone is
+// that of the returned days, start.Location().
+func exportPeriod(ctx context.Context, source archive.Reader, days int) (time.Time, time.Time) {
+	loc, err := source.Location(ctx)
+	if err != nil {
+		fmt.Printf("Failed to determine time zone: %v\n", err)
+		os.Exit(1)
+	}
+	today := api.NormalizeDate(time.Now(), loc)
+	start := parseExportDate(exportStart, today.AddDate(0, 0, -days), loc)
+	end := parseExportDate(exportEnd, today.AddDate(0, 0, -1), loc)
+	return start, end
+}
+
+// dailyValues fetches the daily steps, resting heart rate and sleep metrics
+// from start to end
+func dailyValues(ctx context.Context, source csvlog.Source, start, end time.Time) []csvlog.Value {
+	values, err := csvlog.Fetch(ctx, source, start, end)
+	warnPartial("daily metrics", err)
+	return values
+}
+
+// warnPartial exits on a failed fetch of what, unless only some days failed
+// to load: those are left out with a warning and filled in by a later run
+func warnPartial(what string, err error) {
+	if err == nil {
+		return
+	}
+	if !api.IsPartial(err) {
+		fmt.Printf("Failed to get %s: %v\n", what, err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "Warning: some days of %s are missing: %v\n", what, err)
+}
+
+func exportAccountHandler(cmd *cobra.Command, args []string) {
+	apiClient, err := newAPIClient()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	requestID := exportRequest
+	if requestID == "" {
+		export, err := apiClient.RequestDataExport(ctx)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		requestID = export.RequestID
+		fmt.Printf("Requested data export %s; resume with --request %s\n", requestID, requestID)
+	}
+
+	fmt.Printf("Waiting for export %s (checking every %s)...\n", requestID, exportPoll)
+	export, err := apiClient.WaitForDataExport(ctx, requestID, exportPoll)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	n, err := apiClient.DownloadDataExport(ctx, export.RequestID, exportArchive)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Printf("Saved %d bytes to %s\n", n, exportArchive)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/sstent/go-garminconnect/internal/trainer"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import data from other platforms into Garmin Connect",
+}
+
+var importZWOCmd = &cobra.Command{
+	Use:   "zwo <file.zwo>",
+	Short: "Import a Zwift workout file into the Garmin workout library",
+	Args:  cobra.ExactArgs(1),
+	Run:   importZWOHandler,
+}
+
+var (
+	importFTP  float64
+	importName string
+)
+
+func init() {
+	importZWOCmd.Flags().Float64Var(&importFTP, "ftp", 0, "Functional threshold power in watts used to turn FTP fractions into power targets")
+	importZWOCmd.Flags().StringVar(&importName, "name", "", "Workout name (default: the name in the file)")
+	importZWOCmd.MarkFlagRequired("ftp")
+	importCmd.AddCommand(importZWOCmd)
+}
+
+func importZWOHandler(cmd *cobra.Command, args []string) {
+	f, err := os.Open(args[0])
+	if err != nil {
+		fmt.Printf("Failed to open workout file: %v\n", err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	workout, err := trainer.DecodeZWO(f, importFTP)
+	if err != nil {
+		fmt.Printf("Failed to convert workout: %v\n", err)
+		os.Exit(1)
+	}
+	if importName != "" {
+		workout.Name = importName
+	}
+
+	apiClient, err := newAPIClient()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	created, err := apiClient.CreateWorkout(context.Background(), *workout)
+	if err != nil {
+		fmt.Printf("Failed to create workout: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Created workout %q (ID %d)\n", created.Name, created.ID)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/sstent/go-garminconnect/internal/analysis"
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/intervals"
+)
+
+var intervalsCmd = &cobra.Command{
+	Use:   "intervals",
+	Short: "Forward Garmin data to intervals.icu",
+}
+
+var intervalsSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Upload recent activities and wellness data to intervals.icu",
+	Long: `Upload recent activities (FIT) and wellness data (HRV, sleep, resting heart
+rate, weight) to intervals.icu. Credentials are read from INTERVALS_API_KEY and
+INTERVALS_ATHLETE_ID. With --every the sync repeats until interrupted.
+
+With --records the best efforts of each uploaded activity (fastest 1K, 5K and
+10K, best 20 minute power) are kept in ~/.garmin/records.json and new personal
+records are printed.`,
+	Run: intervalsSyncHandler,
+}
+
+var (
+	intervalsDays    int
+	intervalsEvery   time.Duration
+	intervalsRecords bool
+)
+
+func init() {
+	intervalsSyncCmd.Flags().IntVar(&intervalsDays, "days", 7, "Number of days up to today to sync")
+	intervalsSyncCmd.Flags().DurationVar(&intervalsEvery, "every", 0, "Repeat the sync at this interval (0 syncs once)")
+	intervalsSyncCmd.Flags().BoolVar(&intervalsRecords, "records", false, "Track personal records of uploaded activities")
+	intervalsCmd.AddCommand(intervalsSyncCmd)
+}
+
+func intervalsSyncHandler(cmd *cobra.Command, args []string) {
+	apiKey := os.Getenv("INTERVALS_API_KEY")
+	if apiKey == "" {
+		fmt.Println("INTERVALS_API_KEY must be set")
+		os.Exit(1)
+	}
+
+	apiClient, err := newAPIClient()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	bridge := &intervals.Bridge{
+		Source: apiClient,
+		Target: intervals.NewClient(apiKey, os.Getenv("INTERVALS_ATHLETE_ID")),
+	}
+	if intervalsRecords {
+		book, err := analysis.OpenRecordBook(filepath.Join(os.Getenv("HOME"), ".garmin", "records.json"))
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		bridge.Records = book
+		bridge.OnRecord = printRecord
+	}
+
+	for {
+		if err := intervalsSync(context.Background(), apiClient, bridge); err != nil {
+			fmt.Printf("Sync failed: %v\n", err)
+			if intervalsEvery == 0 {
+				os.Exit(1)
+			}
+		}
+		if intervalsEvery == 0 {
+			return
+		}
+		time.Sleep(intervalsEvery)
+	}
+}
+
+// intervalsSync forwards the configured number of days up to today
+func intervalsSync(ctx context.Context, apiClient *api.Client, bridge *intervals.Bridge) error {
+	today, err := apiClient.Today(ctx)
+	if err != nil {
+		return err
+	}
+	start := today.AddDate(0, 0, 1-intervalsDays)
+
+	result, err := bridge.Sync(ctx, start, today)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s: uploaded %d activities (%d already present), updated %d days of wellness\n",
+		time.Now().Format(time.RFC3339), result.Uploaded, result.Duplicates, result.Days)
+	return nil
+}
+
+// printRecord announces a personal record set during sync
+func printRecord(r analysis.PersonalRecord) {
+	value := func(e analysis.BestEffort) string {
+		if e.Distance > 0 {
+			return e.Time.Round(time.Second).String()
+		}
+		return fmt.Sprintf("%.0f W", e.Watts)
+	}
+	msg := fmt.Sprintf("New personal record: %s %s %s (activity %d)", r.Sport, r.Name, value(r.BestEffort), r.ActivityID)
+	if r.Previous != nil {
+		msg += fmt.Sprintf(", previously %s", value(*r.Previous))
+	}
+	fmt.Println(msg)
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -20,6 +21,20 @@ var rootCmd = &cobra.Command{
 	Short: "CLI for interacting with Garmin Connect API",
 }
 
+// Global flags applied to the API client of every command
+var (
+	// useConnectAPI selects connectapi.garmin.com instead of the legacy proxy
+	useConnectAPI bool
+	timeout       time.Duration
+	retries       int
+	rateLimit     float64
+	// reportDrift logs response fields the models don't know about
+	reportDrift bool
+	// validateMode is what happens to responses breaking their models'
+	// validator tags: off, warn or error
+	validateMode string
+)
+
 var authCmd = &cobra.Command{
 	Use:   "auth",
 	Short: "Authentication commands",
@@ -32,51 +47,14 @@ var loginCmd = &cobra.Command{
 }
 
 func loginHandler(cmd *cobra.Command, args []string) {
-	// Try to load from .env if environment variables not set
-	if os.Getenv("GARMIN_USERNAME") == "" || os.Getenv("GARMIN_PASSWORD") == "" {
-		if err := godotenv.Load(); err != nil {
-			fmt.Println("Failed to load .env file:", err)
-		}
-
-		// Re-check after loading .env
-		if os.Getenv("GARMIN_USERNAME") == "" || os.Getenv("GARMIN_PASSWORD") == "" {
-			fmt.Println("GARMIN_USERNAME and GARMIN_PASSWORD must be set in environment or .env file")
-			os.Exit(1)
-		}
-	}
-
-	// Configure session persistence
-	sessionPath := filepath.Join(os.Getenv("HOME"), ".garmin", "session.json")
-	authClient := garth.NewAuthenticator("https://connect.garmin.com", sessionPath)
-
-	// Implement CLI prompter
-	authClient.MFAPrompter = ConsolePrompter{}
-
-	// Try to load existing session
-	var session *garth.Session
-	var err error
-	if _, err = os.Stat(sessionPath); err == nil {
-		session, err = garth.LoadSession(sessionPath)
-		if err != nil {
-			fmt.Printf("Session loading failed: %v\n", err)
-		}
+	if deferMFA {
+		deferredLogin()
+		return
 	}
 
-	// Perform authentication if no valid session
-	if session == nil {
-		username := os.Getenv("GARMIN_USERNAME")
-		password := os.Getenv("GARMIN_PASSWORD")
-		session, err = authClient.Login(username, password)
-		if err != nil {
-			fmt.Printf("Authentication failed: %v\n", err)
-			os.Exit(1)
-		}
-	}
-
-	// Create API client with session management
-	apiClient, err := api.NewClient(authClient, session, sessionPath)
+	apiClient, err := newAPIClient()
 	if err != nil {
-		fmt.Printf("Failed to create API client: %v\n", err)
+		fmt.Println(err)
 		os.Exit(1)
 	}
 
@@ -86,8 +64,8 @@ func loginHandler(cmd *cobra.Command, args []string) {
 
 	// Get body composition data
 	composition, err := apiClient.GetBodyComposition(context.Background(), api.BodyCompositionRequest{
-		StartDate: api.Time(startDate),
-		EndDate:   api.Time(endDate),
+		StartDate: api.NewGarminTime(startDate),
+		EndDate:   api.NewGarminTime(endDate),
 	})
 	if err != nil {
 		fmt.Printf("Failed to get body composition: %v\n", err)
@@ -99,7 +77,7 @@ func loginHandler(cmd *cobra.Command, args []string) {
 	fmt.Println("Date\t\tBone Mass\tMuscle Mass\tBody Fat\tHydration")
 	for _, entry := range composition {
 		fmt.Printf("%s\t%.1fg\t\t%.1fg\t\t%.1f%%\t\t%.1f%%\n",
-			time.Time(entry.Timestamp).Format("2006-01-02"),
+			entry.Timestamp.Format("2006-01-02"),
 			entry.BoneMass,
 			entry.MuscleMass,
 			entry.BodyFat,
@@ -108,10 +86,178 @@ func loginHandler(cmd *cobra.Command, args []string) {
 	}
 }
 
+// newAPIClient loads the persisted session, logging in with the environment
+// credentials when none exists, and returns a ready API client
+func newAPIClient() (*api.Client, error) {
+	authClient := newAuthenticator()
+	sessionPath := authClient.SessionPath
+
+	opts, err := clientOptions()
+	if err != nil {
+		return nil, err
+	}
+	// Revoked tokens are replaced by logging in again mid-run
+	opts = append(opts, api.WithCredentials(api.CredentialsFunc(envCredentials)))
+	// Retried uploads resolve to the activity the first attempt created
+	uploads, err := api.OpenUploadLog(filepath.Join(os.Getenv("HOME"), ".garmin", "uploads.json"))
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, api.WithUploadLog(uploads))
+
+	// Check a saved session before running anything so an expired login is
+	// caught up front rather than partway through a long job
+	if _, err := os.Stat(sessionPath); err == nil {
+		apiClient, err := api.LoadSession(context.Background(), authClient, sessionPath, opts...)
+		if err == nil {
+			return apiClient, nil
+		}
+		if !errors.Is(err, api.ErrLoginRequired) {
+			return nil, err
+		}
+		fmt.Fprintf(os.Stderr, "Saved session is no longer valid: %v\n", err)
+	}
+
+	// Reuse tokens from the Python garth library when present
+	session := importGarthSession(sessionPath)
+
+	// Perform authentication if no valid session
+	if session == nil {
+		username, password, err := envCredentials(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		session, err = authClient.Login(username, password)
+		if err != nil {
+			return nil, fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+
+	// Create API client with session management
+	apiClient, err := api.NewClient(authClient, session, sessionPath, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API client: %w", err)
+	}
+	return apiClient, nil
+}
+
+// clientOptions returns the client options set by the global flags and
+// ~/.garmin/routes.json, shared by every account the CLI talks to
+func clientOptions() ([]api.ClientOption, error) {
+	opts := []api.ClientOption{
+		api.WithTimeout(timeout),
+		api.WithRetries(retries),
+		api.WithRateLimit(rateLimit),
+	}
+	if useConnectAPI {
+		opts = append(opts, api.WithConnectAPI())
+	}
+	if reportDrift {
+		opts = append(opts, api.WithDriftReporter(api.NewDriftReporter(func(endpoint, field string) {
+			fmt.Fprintf(os.Stderr, "schema drift: %s returned unknown field %q\n", endpoint, field)
+		})))
+	}
+	switch validateMode {
+	case "off":
+	case "warn":
+		opts = append(opts, api.WithValidationReporter(func(err *api.ValidationError) {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}))
+	case "error":
+		opts = append(opts, api.WithResponseValidation())
+	default:
+		return nil, fmt.Errorf("invalid --validate %q, expected off, warn or error", validateMode)
+	}
+	// Service routing overrides let moved Garmin services be followed without a new release
+	routesPath := filepath.Join(os.Getenv("HOME"), ".garmin", "routes.json")
+	if _, err := os.Stat(routesPath); err == nil {
+		routes, err := api.LoadRoutes(routesPath)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, api.WithRoutes(routes))
+	}
+	return opts, nil
+}
+
+// newAuthenticator returns the Garmin authenticator, persisting the session
+// to ~/.garmin/session.json
+func newAuthenticator() *garth.GarthAuthenticator {
+	return newAuthenticatorAt(filepath.Join(os.Getenv("HOME"), ".garmin", "session.json"))
+}
+
+// newAuthenticatorAt returns the Garmin authenticator persisting the session
+// to sessionPath
+func newAuthenticatorAt(sessionPath string) *garth.GarthAuthenticator {
+	authClient := garth.NewAuthenticator("https://connect.garmin.com", sessionPath)
+
+	// Implement CLI prompter, or answer MFA unattended from the authenticator secret
+	authClient.MFAPrompter = ConsolePrompter{}
+	if secret := os.Getenv("GARMIN_TOTP_SECRET"); secret != "" {
+		authClient.MFAPrompter = garth.TOTPPrompter{Secret: secret}
+	}
+	return authClient
+}
+
+// envCredentials reads GARMIN_USERNAME and GARMIN_PASSWORD, loading .env when
+// they are not already set
+func envCredentials(ctx context.Context) (string, string, error) {
+	if os.Getenv("GARMIN_USERNAME") == "" || os.Getenv("GARMIN_PASSWORD") == "" {
+		if err := godotenv.Load(); err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to load .env file:", err)
+		}
+
+		// Re-check after loading .env
+		if os.Getenv("GARMIN_USERNAME") == "" || os.Getenv("GARMIN_PASSWORD") == "" {
+			return "", "", errors.New("GARMIN_USERNAME and GARMIN_PASSWORD must be set in environment or .env file")
+		}
+	}
+	return os.Getenv("GARMIN_USERNAME"), os.Getenv("GARMIN_PASSWORD"), nil
+}
+
+// importGarthSession converts the tokens in $GARTH_HOME (default ~/.garth) and
+// saves them to sessionPath, returning nil when there is nothing to import
+func importGarthSession(sessionPath string) *garth.Session {
+	dir := os.Getenv("GARTH_HOME")
+	if dir == "" {
+		dir = filepath.Join(os.Getenv("HOME"), ".garth")
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return nil
+	}
+
+	session, err := garth.LoadSessionFromGarth(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Garth token import failed: %v\n", err)
+		return nil
+	}
+	if err := session.Save(sessionPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save imported session: %v\n", err)
+	}
+	return session
+}
+
 func main() {
 	// Setup command structure
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 30*time.Second, "Timeout of each API request")
+	rootCmd.PersistentFlags().IntVar(&retries, "retries", 2, "Retries of failed read requests (network errors, 429 and 5xx)")
+	rootCmd.PersistentFlags().Float64Var(&rateLimit, "rate-limit", 0, "Maximum API requests per second (0 for unlimited)")
+	rootCmd.PersistentFlags().BoolVar(&reportDrift, "report-drift", false, "Log response fields unknown to the API models, to spot Garmin schema changes")
+	rootCmd.PersistentFlags().StringVar(&validateMode, "validate", "warn", "Check responses against the models' constraints, e.g. no negative durations: off, warn or error")
+	rootCmd.PersistentFlags().BoolVar(&useConnectAPI, "connectapi", false, "Use connectapi.garmin.com (mobile API) instead of the connect.garmin.com proxy")
 	authCmd.AddCommand(loginCmd)
 	rootCmd.AddCommand(authCmd)
+	rootCmd.AddCommand(reportCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(intervalsCmd)
+	rootCmd.AddCommand(devtoolsCmd)
+	rootCmd.AddCommand(reloadCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(planCmd)
+	rootCmd.AddCommand(coachCmd)
+	rootCmd.AddCommand(watchCmd)
+	rootCmd.AddCommand(activitiesCmd)
 
 	// Execute CLI
 	if err := rootCmd.Execute(); err != nil {
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/sstent/go-garminconnect/internal/auth"
+)
+
+var mfaCmd = &cobra.Command{
+	Use:   "mfa <code>",
+	Short: "Finish a login started with --defer-mfa using the MFA code",
+	Args:  cobra.ExactArgs(1),
+	Run:   mfaHandler,
+}
+
+// deferMFA makes auth login save a pending MFA challenge instead of prompting
+var deferMFA bool
+
+func init() {
+	loginCmd.Flags().BoolVar(&deferMFA, "defer-mfa", false, "Don't prompt for an MFA code; save the challenge for 'auth mfa <code>'")
+	authCmd.AddCommand(mfaCmd)
+}
+
+// mfaStorage keeps a login paused at the MFA step between invocations
+func mfaStorage() *auth.FileMFAStorage {
+	return auth.NewFileMFAStorageAt(filepath.Join(os.Getenv("HOME"), ".garmin", "mfa_state.json"))
+}
+
+// deferredLogin logs in without prompting, leaving an MFA challenge for mfaHandler
+func deferredLogin() {
+	username, password, err := envCredentials(context.Background())
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	_, err = auth.LoginWithMFAStorage(newAuthenticator(), mfaStorage(), username, password)
+	if errors.Is(err, auth.ErrMFAPending) {
+		fmt.Println("MFA code required; finish the login with: garmin-cli auth mfa <code>")
+		return
+	}
+	if err != nil {
+		fmt.Printf("Authentication failed: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("Logged in")
+}
+
+func mfaHandler(cmd *cobra.Command, args []string) {
+	if _, err := auth.ResumeMFALogin(newAuthenticator(), mfaStorage(), args[0]); err != nil {
+		fmt.Printf("Failed to finish login: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("Logged in")
+}
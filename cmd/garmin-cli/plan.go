@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/sstent/go-garminconnect/internal/planner"
+)
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Generate a training plan for a goal race and schedule its workouts",
+	Long: `Generate a periodized running plan (base, build, peak and taper) from today up
+to the goal race, with three workouts a week. The workouts are saved to the
+Garmin workout library and scheduled on the calendar, from where they sync to
+the watch. Use --dry-run to only print the plan.`,
+	Run: planHandler,
+}
+
+var (
+	planRaceDate string
+	planDistance float64
+	planTarget   time.Duration
+	planName     string
+	planDryRun   bool
+)
+
+func init() {
+	planCmd.Flags().StringVar(&planRaceDate, "race-date", "", "Date of the goal race (YYYY-MM-DD)")
+	planCmd.Flags().Float64Var(&planDistance, "distance", 0, "Race distance in kilometers, e.g. 42.195")
+	planCmd.Flags().DurationVar(&planTarget, "target", 0, "Target finish time, e.g. 3h30m")
+	planCmd.Flags().StringVar(&planName, "name", "", "Race name used in workout names (default: Race)")
+	planCmd.Flags().BoolVar(&planDryRun, "dry-run", false, "Print the plan without creating workouts")
+	planCmd.MarkFlagRequired("race-date")
+	planCmd.MarkFlagRequired("distance")
+	planCmd.MarkFlagRequired("target")
+}
+
+func planHandler(cmd *cobra.Command, args []string) {
+	raceDate, err := time.ParseInLocation("2006-01-02", planRaceDate, time.Local)
+	if err != nil {
+		fmt.Printf("Invalid race date %q: %v\n", planRaceDate, err)
+		os.Exit(1)
+	}
+	goal := planner.Goal{Name: planName, Date: raceDate, Distance: planDistance * 1000, TargetTime: planTarget}
+	plan, err := planner.Generate(goal, time.Now())
+	if err != nil {
+		fmt.Printf("Failed to generate plan: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, p := range plan {
+		fmt.Printf("%s  %-5s  %s\n", p.Date.Format("Mon 2006-01-02"), p.Phase, p.Workout.Name)
+	}
+	if planDryRun {
+		return
+	}
+
+	apiClient, err := newAPIClient()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	scheduled, err := planner.Schedule(context.Background(), apiClient, plan)
+	if err != nil {
+		fmt.Printf("Scheduled %d of %d workouts: %v\n", len(scheduled), len(plan), err)
+		os.Exit(1)
+	}
+	fmt.Printf("Scheduled %d workouts\n", len(scheduled))
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+var reloadCmd = &cobra.Command{
+	Use:   "reload",
+	Short: "Ask Garmin to reprocess wellness data for a range of days",
+	Long: `Ask Garmin to reprocess the wellness data uploaded for each day in the range,
+e.g. after the watch re-synced data that was missing or wrong.`,
+	Run: reloadHandler,
+}
+
+var (
+	reloadStart string
+	reloadEnd   string
+)
+
+func init() {
+	reloadCmd.Flags().StringVar(&reloadStart, "start", "", "First day (YYYY-MM-DD) to reload (default: today)")
+	reloadCmd.Flags().StringVar(&reloadEnd, "end", "", "Last day (YYYY-MM-DD) to reload (default: the start day)")
+}
+
+func reloadHandler(cmd *cobra.Command, args []string) {
+	apiClient, err := newAPIClient()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	loc, err := apiClient.Location(ctx)
+	if err != nil {
+		fmt.Printf("Failed to determine time zone: %v\n", err)
+		os.Exit(1)
+	}
+	start := parseExportDate(reloadStart, api.NormalizeDate(time.Now(), loc), loc)
+	end := parseExportDate(reloadEnd, start, loc)
+
+	days, err := apiClient.RequestReloadRange(ctx, start, end)
+	for _, day := range days {
+		fmt.Printf("Requested reload of %s\n", day.Format("2006-01-02"))
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
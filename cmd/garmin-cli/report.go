@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/sstent/go-garminconnect/internal/analysis"
+	"github.com/sstent/go-garminconnect/internal/report"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate training summary reports",
+	Run:   reportHandler,
+}
+
+var (
+	reportWeek    bool
+	reportDate    string
+	reportFormat  string
+	reportOutput  string
+	reportLoad    bool
+	reportDays    int
+	reportAcute   float64
+	reportChronic float64
+)
+
+func init() {
+	reportCmd.Flags().BoolVar(&reportWeek, "week", false, "Generate a weekly report (Monday to Sunday)")
+	reportCmd.Flags().StringVar(&reportDate, "date", "", "Any date (YYYY-MM-DD) inside the week to report on (default: last week)")
+	reportCmd.Flags().StringVar(&reportFormat, "format", "markdown", "Output format: markdown or html")
+	reportCmd.Flags().StringVarP(&reportOutput, "output", "o", "", "Write the report to a file instead of stdout")
+	reportCmd.Flags().BoolVar(&reportLoad, "load", false, "Generate a training load report with the acute-to-chronic workload ratio")
+	reportCmd.Flags().IntVar(&reportDays, "days", 14, "Number of days shown in the training load report, ending on --date (default: yesterday)")
+	reportCmd.Flags().Float64Var(&reportAcute, "acute-days", 7, "Time constant in days of the acute training load")
+	reportCmd.Flags().Float64Var(&reportChronic, "chronic-days", 42, "Time constant in days of the chronic training load")
+}
+
+func reportHandler(cmd *cobra.Command, args []string) {
+	if reportLoad {
+		loadReportHandler()
+		return
+	}
+	if !reportWeek {
+		fmt.Println("Please select a report period, e.g. --week or --load")
+		os.Exit(1)
+	}
+
+	// Default to the last complete week so a Monday run reports on the week just finished
+	day := time.Now().AddDate(0, 0, -7)
+	if reportDate != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", reportDate, time.Local)
+		if err != nil {
+			fmt.Printf("Invalid date %q: %v\n", reportDate, err)
+			os.Exit(1)
+		}
+		day = parsed
+	}
+
+	apiClient, err := newAPIClient()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	weekly, err := report.BuildWeekly(context.Background(), apiClient, day)
+	if err != nil {
+		fmt.Printf("Failed to build report: %v\n", err)
+		os.Exit(1)
+	}
+
+	out := reportOutputFile()
+	defer out.Close()
+
+	if err := weekly.Render(out, report.Format(reportFormat)); err != nil {
+		fmt.Printf("Failed to render report: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func loadReportHandler() {
+	end := time.Now().AddDate(0, 0, -1)
+	if reportDate != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", reportDate, time.Local)
+		if err != nil {
+			fmt.Printf("Invalid date %q: %v\n", reportDate, err)
+			os.Exit(1)
+		}
+		end = parsed
+	}
+
+	apiClient, err := newAPIClient()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	opts := analysis.LoadOptions{AcuteDays: reportAcute, ChronicDays: reportChronic}
+	load, err := report.BuildLoad(context.Background(), apiClient, end, reportDays, opts)
+	if err != nil {
+		fmt.Printf("Failed to build report: %v\n", err)
+		os.Exit(1)
+	}
+
+	out := reportOutputFile()
+	defer out.Close()
+
+	if err := load.Render(out, report.Format(reportFormat)); err != nil {
+		fmt.Printf("Failed to render report: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// reportOutputFile returns the writer selected by --output
+func reportOutputFile() *os.File {
+	if reportOutput == "" {
+		return os.Stdout
+	}
+	f, err := os.Create(reportOutput)
+	if err != nil {
+		fmt.Printf("Failed to create output file: %v\n", err)
+		os.Exit(1)
+	}
+	return f
+}
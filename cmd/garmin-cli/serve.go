@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/sstent/go-garminconnect/internal/grpcserver"
+	"github.com/sstent/go-garminconnect/internal/server"
+	"google.golang.org/grpc"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run local servers backed by your Garmin Connect account",
+	Long: `Run local servers backed by your Garmin Connect account.
+
+The servers listen on 127.0.0.1 by default. Listening on other addresses
+requires --token (or GARMIN_SERVE_TOKEN): HTTP clients then send it as an
+"Authorization: Bearer" header or an access_token query parameter, e.g. a
+calendar app subscribing to /calendar.ics?access_token=..., and gRPC clients
+as "authorization: Bearer" metadata.`,
+}
+
+var serveAPICmd = &cobra.Command{
+	Use:   "api",
+	Short: "Serve Garmin Connect data as a local JSON REST API",
+	Run:   serveAPIHandler,
+}
+
+var serveDashboardCmd = &cobra.Command{
+	Use:   "dashboard",
+	Short: "Serve a web dashboard together with the JSON API under /api",
+	Run:   serveDashboardHandler,
+}
+
+var serveICSCmd = &cobra.Command{
+	Use:   "ics",
+	Short: "Serve an iCalendar feed of activities and scheduled workouts",
+	Run:   serveICSHandler,
+}
+
+var serveGRPCCmd = &cobra.Command{
+	Use:   "grpc",
+	Short: "Serve activities, health data and sync events over gRPC",
+	Long: `Serve activities, daily health summaries and new-data events over gRPC,
+so services in other languages can use this client as a sidecar. The service
+definitions are in proto/garmin/v1/garmin.proto. The default address is
+127.0.0.1:50051 unless --addr is given.`,
+	Run: serveGRPCHandler,
+}
+
+var (
+	serveAddr     string
+	serveCacheTTL time.Duration
+	icsHistory    int
+	grpcAccount   string
+	serveToken    string
+	serveOffline  string
+	serveFallback string
+)
+
+func init() {
+	serveCmd.PersistentFlags().StringVar(&serveAddr, "addr", "127.0.0.1:8080", "Address to listen on")
+	serveCmd.PersistentFlags().StringVar(&serveToken, "token", "", "Bearer token clients must send (default $GARMIN_SERVE_TOKEN); required for non-loopback addresses")
+	serveCmd.PersistentFlags().DurationVar(&serveCacheTTL, "cache-ttl", 5*time.Minute, "How long responses are cached (0 disables caching)")
+	for _, cmd := range []*cobra.Command{serveAPICmd, serveDashboardCmd} {
+		cmd.Flags().StringVar(&serveOffline, "offline", "", "Serve this database written by 'export sqlite' instead of Garmin Connect; data it doesn't hold is answered with 404")
+		cmd.Flags().StringVar(&serveFallback, "fallback", "", "Serve this database written by 'export sqlite' when Garmin Connect is unreachable, rate limited or failing; such responses carry X-Data-Source: archive and X-Archived-At")
+	}
+	serveCmd.AddCommand(serveAPICmd)
+	serveCmd.AddCommand(serveDashboardCmd)
+
+	serveICSCmd.Flags().IntVar(&icsHistory, "history-days", 90, "Number of days of completed activities to include")
+	serveCmd.AddCommand(serveICSCmd)
+	serveGRPCCmd.Flags().StringVar(&grpcAccount, "account", "", "Account label added to every sync event")
+	serveCmd.AddCommand(serveGRPCCmd)
+}
+
+func serveAPIHandler(cmd *cobra.Command, args []string) {
+	backend, release := serveBackend()
+	defer release()
+
+	fmt.Println("OpenAPI document available at /openapi.json")
+	listen(server.NewServer(backend, serveCacheTTL))
+}
+
+func serveDashboardHandler(cmd *cobra.Command, args []string) {
+	backend, release := serveBackend()
+	defer release()
+
+	listen(server.NewDashboard(server.NewServer(backend, serveCacheTTL)))
+}
+
+// serveBackend returns the archive selected by --offline, or else the client
+// of the account, and a function releasing it
+func serveBackend() (server.Backend, func()) {
+	return openSource(serveOffline, serveFallback, func(archivedAt time.Time, err error) {
+		log.Printf("Garmin Connect failed, serving archived data from %s: %v", archiveAge(archivedAt), err)
+	})
+}
+
+func serveICSHandler(cmd *cobra.Command, args []string) {
+	apiClient, err := newAPIClient()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /calendar.ics", server.NewICSFeed(apiClient, icsHistory, serveCacheTTL))
+	fmt.Println("Calendar feed available at /calendar.ics")
+	listen(mux)
+}
+
+func serveGRPCHandler(cmd *cobra.Command, args []string) {
+	apiClient, err := newAPIClient()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	addr := serveAddr
+	if !cmd.Flags().Changed("addr") {
+		addr = "127.0.0.1:50051"
+	}
+	token, err := serveAuthToken(addr)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		fmt.Printf("Failed to listen: %v\n", err)
+		os.Exit(1)
+	}
+
+	var opts []grpc.ServerOption
+	if token != "" {
+		opts = grpcserver.TokenAuth(token)
+	}
+	g := grpc.NewServer(opts...)
+	srv := grpcserver.NewServer(apiClient)
+	srv.Account = grpcAccount
+	srv.Register(g)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		g.GracefulStop()
+	}()
+
+	fmt.Printf("gRPC server listening on %s\n", addr)
+	if err := g.Serve(lis); err != nil {
+		fmt.Printf("Server failed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// listen serves handler on the configured address until the server fails or
+// is interrupted, finishing in-flight requests before returning
+func listen(handler http.Handler) {
+	token, err := serveAuthToken(serveAddr)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if token != "" {
+		handler = server.RequireToken(token, handler)
+	}
+
+	srv := &http.Server{
+		Addr:              serveAddr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	shutdown := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		timeout, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		shutdown <- srv.Shutdown(timeout)
+	}()
+
+	fmt.Printf("Server listening on %s\n", serveAddr)
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		fmt.Printf("Server failed: %v\n", err)
+		os.Exit(1)
+	}
+	if err := <-shutdown; err != nil {
+		fmt.Printf("Shutdown failed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// serveAuthToken returns the configured token, refusing to serve an address
+// reachable from other hosts without one
+func serveAuthToken(addr string) (string, error) {
+	token := serveToken
+	if token == "" {
+		token = os.Getenv("GARMIN_SERVE_TOKEN")
+	}
+	if token == "" && !isLoopback(addr) {
+		return "", fmt.Errorf("refusing to serve %s without --token: the server exposes your Garmin Connect account to anyone who can reach it", addr)
+	}
+	return token, nil
+}
+
+// isLoopback reports whether addr only accepts connections from this host
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/gpx"
+	"github.com/sstent/go-garminconnect/internal/publish"
+	"github.com/sstent/go-garminconnect/internal/rules"
+	"github.com/sstent/go-garminconnect/internal/search"
+	"github.com/sstent/go-garminconnect/internal/watch"
+	"github.com/sstent/go-garminconnect/internal/watch/s3state"
+	"github.com/sstent/go-garminconnect/internal/watch/sqlitestate"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Poll Garmin Connect and print an event for each new activity or updated daily metric",
+	Long: `Poll Garmin Connect until interrupted and print one JSON object per line on
+stdout for each new activity and each daily metric (steps, resting heart rate,
+sleep) that appears or changes, e.g. for piping into jq or a message queue
+producer. Data already present at startup is not reported unless --backfill
+is given. Diagnostics go to stderr.
+
+Events can also be published to NATS (--nats-url, to <subject>.<event type>;
+tls:// URLs and servers requiring TLS are supported) or to a Kafka REST Proxy
+(--kafka-rest-url, Confluent v2 API). Kafka brokers are not contacted
+directly; run a REST Proxy in front of them. Events are keyed by --account so
+the events of many accounts can share one bus. Use --output none to only
+publish.
+
+--state keeps what has been reported in a file, so a restarted watcher, e.g.
+in a container with the file on a volume, reports only what changed while it
+was down instead of priming again. Paths ending in .db or .sqlite use SQLite,
+others JSON. s3://bucket/key keeps it as a JSON object in S3-compatible
+storage, for hosts without a volume; the endpoint, region and credentials come
+from AWS_ENDPOINT_URL (e.g. http://minio:9000, empty for AWS), AWS_REGION,
+AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.
+
+--rules runs the actions of a YAML rules file for matching events, e.g. to
+export every new run as GPX, POST it to a webhook and run a script:
+
+  rules:
+    - name: archive runs
+      on: new_activity        # or daily_metric
+      type: running           # activity type; daily_metric rules take metric
+      actions:
+        - export: gpx         # or fit, written to <dir>/<activity ID>.<format>
+          dir: ~/runs
+        - webhook: https://example.com/hooks/garmin
+          headers:
+            Authorization: Bearer secret
+        - run: [./upload.sh]  # event JSON on stdin, GARMIN_FILE is the export`,
+	Run: watchHandler,
+}
+
+var (
+	watchOutput   string
+	watchEvery    time.Duration
+	watchDays     int
+	watchBackfill bool
+	watchAccount  string
+	watchNATS     string
+	watchSubject  string
+	watchKafka    string
+	watchTopic    string
+	watchRules    string
+	watchIndex    bool
+	watchState    string
+)
+
+func init() {
+	watchCmd.Flags().StringVar(&watchOutput, "output", "jsonl", "Event output format: jsonl or none")
+	watchCmd.Flags().DurationVar(&watchEvery, "every", 5*time.Minute, "Interval between polls")
+	watchCmd.Flags().IntVar(&watchDays, "days", 2, "Number of days up to today checked by each poll")
+	watchCmd.Flags().BoolVar(&watchBackfill, "backfill", false, "Also report the data found by the first poll")
+	watchCmd.Flags().StringVar(&watchAccount, "account", "", "Account label added to every event")
+	watchCmd.Flags().StringVar(&watchNATS, "nats-url", "", "Publish events to this NATS server, e.g. nats://localhost:4222")
+	watchCmd.Flags().StringVar(&watchSubject, "nats-subject", "garmin.events", "NATS subject prefix")
+	watchCmd.Flags().StringVar(&watchKafka, "kafka-rest-url", "", "Publish events through this Kafka REST Proxy, e.g. http://localhost:8082")
+	watchCmd.Flags().StringVar(&watchTopic, "kafka-topic", "garmin-events", "Topic the Kafka REST Proxy produces to")
+	watchCmd.Flags().StringVar(&watchRules, "rules", "", "YAML file of actions to run for matching events")
+	watchCmd.Flags().BoolVar(&watchIndex, "index", false, "Add new activities to the search index used by 'activities search'")
+	watchCmd.Flags().StringVar(&watchState, "state", "", "File or s3://bucket/key URL keeping what has been reported across restarts (.db or .sqlite for SQLite, otherwise JSON)")
+}
+
+func watchHandler(cmd *cobra.Command, args []string) {
+	if watchOutput != "jsonl" && watchOutput != "none" {
+		fmt.Fprintf(os.Stderr, "Unsupported output format %q\n", watchOutput)
+		os.Exit(1)
+	}
+	apiClient, err := newAPIClient()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	engine := &rules.Engine{Export: watchExporter(apiClient), Output: os.Stderr}
+	if watchRules != "" {
+		engine.Rules, err = rules.Load(watchRules)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	var index *search.Index
+	if watchIndex {
+		index, err = search.Open(searchIndexPath())
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer index.Close()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var publishers publish.Multi
+	if watchNATS != "" {
+		p, err := publish.DialNATS(ctx, watchNATS, watchSubject)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		publishers = append(publishers, p)
+	}
+	if watchKafka != "" {
+		publishers = append(publishers, publish.NewKafkaRESTPublisher(watchKafka, watchTopic))
+	}
+	defer publishers.Close()
+	// events a publish failed to deliver are sent again after the next poll
+	retrying := &publish.Retrying{Publisher: publishers}
+
+	watcher := watch.NewWatcher(apiClient)
+	watcher.Account = watchAccount
+	restored := false
+	if watchState != "" {
+		state, closeState, err := openWatchState(watchState)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer closeState()
+		watcher.State = state
+		if restored, err = watcher.Restore(ctx); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	// priming polls record the data already there without reporting it; they
+	// continue until one succeeds, so a failed poll can't make the next one
+	// report everything. A restored state makes priming unnecessary.
+	priming := !watchBackfill && !restored
+	for {
+		events, err := watchPoll(ctx, apiClient, watcher)
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil && !api.IsPartial(err):
+			fmt.Fprintf(os.Stderr, "Poll failed: %v\n", err)
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		// Activities found while priming are indexed too
+		if index != nil {
+			if err := index.Add(ctx, eventActivities(events)); err != nil {
+				fmt.Fprintf(os.Stderr, "Indexing failed: %v\n", err)
+			}
+		}
+		if priming {
+			priming = err != nil
+		} else {
+			if watchOutput == "jsonl" {
+				if err := watch.WriteJSONL(os.Stdout, events); err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					os.Exit(1)
+				}
+			}
+			if err := retrying.Publish(ctx, events); err != nil {
+				fmt.Fprintf(os.Stderr, "Publishing failed: %v\n", err)
+			}
+			if err := engine.Handle(ctx, events); err != nil {
+				fmt.Fprintf(os.Stderr, "Rules failed: %v\n", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(watchEvery):
+		}
+	}
+}
+
+// watchPoll checks the configured number of days up to today
+func watchPoll(ctx context.Context, apiClient *api.Client, watcher *watch.Watcher) ([]watch.Event, error) {
+	today, err := apiClient.Today(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return watcher.Poll(ctx, today.AddDate(0, 0, 1-watchDays), today)
+}
+
+// openWatchState opens the watch state at path, an S3 object for s3:// URLs,
+// SQLite for .db and .sqlite files and JSON otherwise, and returns a function
+// closing it
+func openWatchState(path string) (watch.State, func() error, error) {
+	if strings.HasPrefix(path, "s3://") {
+		cfg, err := s3state.ConfigFromEnv(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		state, err := s3state.New(cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return state, func() error { return nil }, nil
+	}
+	switch filepath.Ext(path) {
+	case ".db", ".sqlite":
+		state, err := sqlitestate.Open(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		return state, state.Close, nil
+	}
+	return watch.FileState{Path: path}, func() error { return nil }, nil
+}
+
+// eventActivities returns the activities of the activity events
+func eventActivities(events []watch.Event) []api.Activity {
+	var activities []api.Activity
+	for _, e := range events {
+		if e.Activity != nil {
+			activities = append(activities, *e.Activity)
+		}
+	}
+	return activities
+}
+
+// watchExporter writes the activities exported by rules
+func watchExporter(apiClient *api.Client) func(ctx context.Context, activity *api.Activity, format, path string) error {
+	return func(ctx context.Context, activity *api.Activity, format, path string) error {
+		if format == rules.FormatFIT {
+			_, err := apiClient.DownloadActivityToFile(ctx, activity.ActivityID, path)
+			return err
+		}
+
+		points, err := apiClient.GetActivityTrack(ctx, activity.ActivityID)
+		if err != nil {
+			return err
+		}
+		out, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		if err := gpx.Encode(out, gpxTrack(&api.ActivityDetail{Activity: *activity}, points), gpx.ProfileStandard); err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	}
+}
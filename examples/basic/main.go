@@ -31,25 +31,27 @@ func main() {
 	}
 	fmt.Printf("User: %s (%s)\n", profile.FullName, profile.DisplayName)
 
-	// Get sleep data for today
-	today := time.Now()
-	sleepData, err := client.GetSleepData(context.Background(), today)
+	// Fetch today's wellness data concurrently; fields that failed are nil
+	snapshot, err := client.GetDailySnapshot(context.Background(), time.Now())
 	if err != nil {
-		log.Fatalf("Failed to get sleep data: %v", err)
+		log.Fatalf("Failed to get daily snapshot: %v", err)
 	}
-	fmt.Printf("Sleep duration: %s\n", time.Duration(sleepData.SleepTimeSeconds)*time.Second)
-
-	// Get stress data
-	stressData, err := client.GetStressData(context.Background(), today)
-	if err != nil {
-		log.Fatalf("Failed to get stress data: %v", err)
+	if err := snapshot.Err(); err != nil {
+		log.Printf("Warning: %v", err)
 	}
-	fmt.Printf("Daily stress level: %d\n", stressData.OverallStressLevel)
 
-	// Get steps data
-	stepsData, err := client.GetStepsData(context.Background(), today)
-	if err != nil {
-		log.Fatalf("Failed to get steps data: %v", err)
+	if snapshot.Sleep != nil && snapshot.Sleep.SleepTimeSeconds != nil {
+		fmt.Printf("Sleep duration: %s\n", time.Duration(*snapshot.Sleep.SleepTimeSeconds)*time.Second)
+	} else {
+		fmt.Println("No sleep recorded")
+	}
+	if snapshot.Stress != nil {
+		fmt.Printf("Daily stress level: %d\n", snapshot.Stress.OverallStressLevel)
+	}
+	if snapshot.Steps != nil {
+		fmt.Printf("Steps today: %d\n", snapshot.Steps.TotalSteps)
+	}
+	if snapshot.HeartRate != nil && snapshot.HeartRate.RestingHR != nil {
+		fmt.Printf("Resting heart rate: %d bpm\n", *snapshot.HeartRate.RestingHR)
 	}
-	fmt.Printf("Steps today: %d\n", stepsData.TotalSteps)
 }
@@ -0,0 +1,49 @@
+package garmintest
+
+import "testing"
+
+// AssertRequested fails the test unless endpoint received exactly n requests
+func (s *Server) AssertRequested(t testing.TB, endpoint Endpoint, n int) bool {
+	t.Helper()
+	if got := s.RequestCount(endpoint); got != n {
+		t.Errorf("expected %d requests to %s, got %d", n, endpoint, got)
+		return false
+	}
+	return true
+}
+
+// AssertNotRequested fails the test if endpoint received any request
+func (s *Server) AssertNotRequested(t testing.TB, endpoint Endpoint) bool {
+	t.Helper()
+	return s.AssertRequested(t, endpoint, 0)
+}
+
+// AssertHeader fails the test unless the last request to endpoint carried header key=value
+func (s *Server) AssertHeader(t testing.TB, endpoint Endpoint, key, value string) bool {
+	t.Helper()
+	req, ok := s.LastRequest(endpoint)
+	if !ok {
+		t.Errorf("expected a request to %s, got none", endpoint)
+		return false
+	}
+	if got := req.Header.Get(key); got != value {
+		t.Errorf("expected header %s=%q on %s, got %q", key, value, endpoint, got)
+		return false
+	}
+	return true
+}
+
+// AssertQuery fails the test unless the last request to endpoint had query parameter key=value
+func (s *Server) AssertQuery(t testing.TB, endpoint Endpoint, key, value string) bool {
+	t.Helper()
+	req, ok := s.LastRequest(endpoint)
+	if !ok {
+		t.Errorf("expected a request to %s, got none", endpoint)
+		return false
+	}
+	if got := req.Query.Get(key); got != value {
+		t.Errorf("expected query %s=%q on %s, got %q", key, value, endpoint, got)
+		return false
+	}
+	return true
+}
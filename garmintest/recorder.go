@@ -0,0 +1,235 @@
+package garmintest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"unicode/utf8"
+)
+
+// Mode selects whether a Recorder talks to the network
+type Mode int
+
+const (
+	// ModeReplay serves responses from the cassette and fails on unknown requests
+	ModeReplay Mode = iota
+	// ModeRecord forwards requests to the real transport and records the responses
+	ModeRecord
+)
+
+// Redacted replaces secrets in recorded interactions
+const Redacted = "REDACTED"
+
+// ErrNoInteraction is returned in replay mode when the cassette has no matching request
+var ErrNoInteraction = errors.New("garmintest: no recorded interaction matches request")
+
+// redactedHeaders are never written to cassettes
+var redactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "Di-Backend"}
+
+var (
+	// tokenJSONPattern matches token fields in JSON bodies
+	tokenJSONPattern = regexp.MustCompile(`"(access_token|refresh_token|oauth_token|oauth_token_secret|oauth2_token|oauth1_token|oauth1_secret|token|token_secret|password)"(\s*):(\s*)"[^"]*"`)
+	// tokenFormPattern matches token fields in form and query encoded strings
+	tokenFormPattern = regexp.MustCompile(`(^|[?&])(access_token|refresh_token|oauth_token|oauth_token_secret|oauth_verifier|ticket|token|token_secret|password)=[^&]*`)
+)
+
+// Cassette is the on-disk collection of recorded interactions
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request/response pair
+type Interaction struct {
+	Request  RecordedHTTP `json:"request"`
+	Response RecordedHTTP `json:"response"`
+}
+
+// RecordedHTTP holds one side of an interaction
+type RecordedHTTP struct {
+	Method string      `json:"method,omitempty"`
+	URL    string      `json:"url,omitempty"`
+	Status int         `json:"status,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+	// Base64 is set when Body holds base64 encoded binary data such as FIT files
+	Base64 bool `json:"base64,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that records real API traffic to a cassette
+// file and replays it deterministically
+type Recorder struct {
+	Mode Mode
+	Path string
+	// Transport performs real requests in record mode (default http.DefaultTransport)
+	Transport http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// NewRecorder creates a recorder for the cassette at path.
+// In replay mode the cassette must exist.
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{Mode: mode, Path: path}
+	if mode == ModeRecord {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	if err := json.Unmarshal(data, &r.cassette); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette: %w", err)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.Mode == ModeRecord {
+		return r.record(req)
+	}
+	return r.replay(req)
+}
+
+// Save writes the recorded interactions to the cassette file
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.Path), 0755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	return os.WriteFile(r.Path, data, 0644)
+}
+
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := Interaction{
+		Request: RecordedHTTP{
+			Method: req.Method,
+			URL:    redactString(requestKey(req)),
+			Header: redactHeader(req.Header),
+		},
+		Response: RecordedHTTP{
+			Status: resp.StatusCode,
+			Header: redactHeader(resp.Header),
+		},
+	}
+	interaction.Request.Body, interaction.Request.Base64 = encodeBody(reqBody)
+	interaction.Response.Body, interaction.Response.Base64 = encodeBody(respBody)
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	key := redactString(requestKey(req))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Repeated identical requests are answered in recording order
+	for i, in := range r.cassette.Interactions {
+		if r.used[i] || in.Request.Method != req.Method || in.Request.URL != key {
+			continue
+		}
+		r.used[i] = true
+
+		body := []byte(in.Response.Body)
+		if in.Response.Base64 {
+			var err error
+			if body, err = base64.StdEncoding.DecodeString(in.Response.Body); err != nil {
+				return nil, fmt.Errorf("corrupt cassette body: %w", err)
+			}
+		}
+		header := in.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.Status, http.StatusText(in.Response.Status)),
+			StatusCode:    in.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, key)
+}
+
+// requestKey identifies a request independently of the host it was sent to
+func requestKey(req *http.Request) string {
+	return req.URL.RequestURI()
+}
+
+// encodeBody stores text bodies verbatim (redacted) and binary bodies as base64
+func encodeBody(body []byte) (string, bool) {
+	if len(body) == 0 {
+		return "", false
+	}
+	if !utf8.Valid(body) {
+		return base64.StdEncoding.EncodeToString(body), true
+	}
+	return redactString(string(body)), false
+}
+
+func redactHeader(h http.Header) http.Header {
+	out := h.Clone()
+	for _, name := range redactedHeaders {
+		if out.Get(name) != "" {
+			out.Set(name, Redacted)
+		}
+	}
+	return out
+}
+
+func redactString(s string) string {
+	s = tokenJSONPattern.ReplaceAllString(s, `"$1"$2:$3"`+Redacted+`"`)
+	return tokenFormPattern.ReplaceAllString(s, "${1}${2}="+Redacted)
+}
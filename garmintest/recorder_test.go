@@ -0,0 +1,89 @@
+package garmintest
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorderRecordAndReplay(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.Handle(Download, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/fit")
+		w.Write([]byte{0x0e, 0x10, 0xff, 0xfe, '.', 'F', 'I', 'T'})
+	})
+	s.Handle(Profile, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "SESSION=abc")
+		w.Write([]byte(`{"displayName": "Mock User", "access_token": "secret-token"}`))
+	})
+
+	cassette := filepath.Join(t.TempDir(), "cassettes", "profile.json")
+	rec, err := NewRecorder(cassette, ModeRecord)
+	assert.NoError(t, err)
+	client := &http.Client{Transport: rec}
+
+	req, _ := http.NewRequest(http.MethodGet, s.URL()+"/userprofile-service/socialProfile?ticket=ST-123", nil)
+	req.Header.Set("Authorization", "Bearer live-token")
+	resp, err := client.Do(req)
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	// The caller still sees the live response
+	assert.Contains(t, string(body), "secret-token")
+
+	resp, err = client.Get(s.URL() + "/download-service/export/activity/1")
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.NoError(t, rec.Save())
+
+	data, err := os.ReadFile(cassette)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "live-token")
+	assert.NotContains(t, string(data), "secret-token")
+	assert.NotContains(t, string(data), "ST-123")
+	assert.NotContains(t, string(data), "SESSION=abc")
+	assert.Contains(t, string(data), `"base64": true`)
+
+	t.Run("replay", func(t *testing.T) {
+		s.Close() // replay must not need the network
+
+		replayer, err := NewRecorder(cassette, ModeReplay)
+		assert.NoError(t, err)
+		client := &http.Client{Transport: replayer}
+
+		resp, err := client.Get("http://garmin.invalid/userprofile-service/socialProfile?ticket=ST-999")
+		assert.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `{"displayName": "Mock User", "access_token": "REDACTED"}`, string(body))
+
+		resp, err = client.Get("http://garmin.invalid/download-service/export/activity/1")
+		assert.NoError(t, err)
+		body, _ = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, []byte{0x0e, 0x10, 0xff, 0xfe, '.', 'F', 'I', 'T'}, body)
+
+		// Each interaction is replayed once
+		_, err = client.Get("http://garmin.invalid/download-service/export/activity/1")
+		assert.ErrorIs(t, err, ErrNoInteraction)
+	})
+}
+
+func TestRecorderMissingCassette(t *testing.T) {
+	_, err := NewRecorder(filepath.Join(t.TempDir(), "missing.json"), ModeReplay)
+	assert.ErrorContains(t, err, "failed to read cassette")
+}
+
+func TestRedactString(t *testing.T) {
+	assert.Equal(t, `{"oauth_token": "REDACTED", "name": "x"}`, redactString(`{"oauth_token": "abc", "name": "x"}`))
+	assert.Equal(t, "oauth_token=REDACTED&oauth_token_secret=REDACTED&x=1", redactString("oauth_token=a&oauth_token_secret=b&x=1"))
+	assert.Equal(t, "/sso/signin?service=x&ticket=REDACTED", redactString("/sso/signin?service=x&ticket=ST-1"))
+	assert.False(t, strings.Contains(redactString(`"password":"hunter2"`), "hunter2"))
+}
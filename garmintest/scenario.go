@@ -0,0 +1,90 @@
+package garmintest
+
+import (
+	"net/http"
+	"time"
+)
+
+// Scenario bundles a reusable server configuration
+type Scenario struct {
+	// Latency is added to every response
+	Latency time.Duration
+	// Fixtures override the responses of individual endpoints
+	Fixtures map[Endpoint]Fixture
+	// Errors makes every request to an endpoint fail with the given status
+	Errors map[Endpoint]int
+	// Scripts queue ordered responses per endpoint, see Server.Script
+	Scripts map[Endpoint][]Fixture
+	// Bandwidth throttles response bodies to this many bytes per second
+	Bandwidth int
+}
+
+// Apply configures the server with a scenario on top of its current state
+func (s *Server) Apply(sc Scenario) {
+	if sc.Latency > 0 {
+		s.SetLatency(sc.Latency)
+	}
+	for endpoint, fixture := range sc.Fixtures {
+		s.SetFixture(endpoint, fixture)
+	}
+	for endpoint, status := range sc.Errors {
+		s.InjectError(endpoint, status, -1)
+	}
+	for endpoint, responses := range sc.Scripts {
+		s.Script(endpoint, responses...)
+	}
+	if sc.Bandwidth > 0 {
+		s.SetBandwidth(sc.Bandwidth)
+	}
+}
+
+// EmptyAccount simulates a new account without activities or recorded health data
+func EmptyAccount() Scenario {
+	notFound := Fixture{Status: http.StatusNotFound, Body: []byte(`{"error": "No data found"}`)}
+	return Scenario{
+		Fixtures: map[Endpoint]Fixture{
+			Activities:      {Status: http.StatusOK, Body: []byte(`{"activities": [], "pagination": {"page": 1, "pageSize": 10, "totalCount": 0}}`)},
+			BodyComposition: {Status: http.StatusOK, Body: []byte(`[]`)},
+			Calendar:        {Status: http.StatusOK, Body: []byte(`{"calendarItems": []}`)},
+			Sleep:           notFound,
+			Stress:          notFound,
+			Steps:           notFound,
+			HRV:             notFound,
+			BodyBattery:     notFound,
+		},
+	}
+}
+
+// Unauthorized simulates a revoked or expired token on every data endpoint
+func Unauthorized() Scenario {
+	errors := make(map[Endpoint]int, len(routes))
+	for _, r := range routes {
+		errors[r.endpoint] = http.StatusUnauthorized
+	}
+	return Scenario{Errors: errors}
+}
+
+// ExpiredToken simulates an access token that expires before the first request:
+// each endpoint answers 401 once and then serves its fixture, as it would after
+// a successful token refresh
+func ExpiredToken() Scenario {
+	scripts := make(map[Endpoint][]Fixture, len(routes))
+	for _, r := range routes {
+		scripts[r.endpoint] = []Fixture{{Status: http.StatusUnauthorized}}
+	}
+	return Scenario{Scripts: scripts}
+}
+
+// SlowNetwork simulates a high latency, low bandwidth connection
+func SlowNetwork(latency time.Duration, bytesPerSecond int) Scenario {
+	return Scenario{Latency: latency, Bandwidth: bytesPerSecond}
+}
+
+// Outage simulates Garmin returning server errors after a slow response
+func Outage(latency time.Duration) Scenario {
+	errors := make(map[Endpoint]int, len(routes))
+	for _, r := range routes {
+		errors[r.endpoint] = http.StatusServiceUnavailable
+	}
+	return Scenario{Latency: latency, Errors: errors}
+}
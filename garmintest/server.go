@@ -0,0 +1,413 @@
+// Package garmintest provides an in-process fake of the Garmin Connect API for
+// tests of code built on go-garminconnect.
+package garmintest
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed fixtures/*.json
+var defaultFixtures embed.FS
+
+// Endpoint names a group of Garmin Connect API paths served by the fake
+type Endpoint string
+
+const (
+	Activities      Endpoint = "activities"
+	Activity        Endpoint = "activity"
+	Upload          Endpoint = "upload"
+	Download        Endpoint = "download"
+	Profile         Endpoint = "profile"
+	Settings        Endpoint = "settings"
+	Stats           Endpoint = "stats"
+	Sleep           Endpoint = "sleep"
+	Stress          Endpoint = "stress"
+	Steps           Endpoint = "steps"
+	HRV             Endpoint = "hrv"
+	BodyBattery     Endpoint = "bodybattery"
+	BodyComposition Endpoint = "bodycomposition"
+	Gear            Endpoint = "gear"
+	Calendar        Endpoint = "calendar"
+	Unknown         Endpoint = "unknown"
+)
+
+// routes maps path prefixes to endpoints; the first match wins
+var routes = []struct {
+	prefix   string
+	endpoint Endpoint
+}{
+	{"/activitylist-service/activities", Activities},
+	{"/activity-service/activity/", Activity},
+	{"/upload-service/upload", Upload},
+	{"/download-service/", Download},
+	{"/userprofile-service/userprofile/settings", Settings},
+	{"/userprofile-service", Profile},
+	{"/stats-service", Stats},
+	{"/wellness-service/sleep", Sleep},
+	{"/wellness-service/stress", Stress},
+	{"/wellness-service/steps", Steps},
+	{"/hrv-service", HRV},
+	{"/bodybattery-service", BodyBattery},
+	{"/body-composition", BodyComposition},
+	{"/gear-service", Gear},
+	{"/calendar-service", Calendar},
+}
+
+// EndpointFor resolves the endpoint serving a request path
+func EndpointFor(p string) Endpoint {
+	for _, r := range routes {
+		if strings.HasPrefix(p, r.prefix) {
+			return r.endpoint
+		}
+	}
+	return Unknown
+}
+
+// Fixture is a canned response
+type Fixture struct {
+	Status  int
+	Header  http.Header
+	Body    []byte
+	Latency time.Duration
+}
+
+// RecordedRequest captures a request received by the server
+type RecordedRequest struct {
+	Endpoint Endpoint
+	Method   string
+	Path     string
+	Query    url.Values
+	Header   http.Header
+	Body     []byte
+}
+
+// injectedError is a pending error response for an endpoint
+type injectedError struct {
+	status    int
+	remaining int
+}
+
+// Server is a fake Garmin Connect API backed by httptest.Server
+type Server struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	fixtures map[Endpoint]Fixture
+	handlers map[Endpoint]http.HandlerFunc
+	all      http.HandlerFunc
+	errors   map[Endpoint]*injectedError
+	scripts  map[Endpoint][]Fixture
+	latency  time.Duration
+	// bandwidth limits response bodies to this many bytes per second; 0 is unlimited
+	bandwidth int
+	requests  []RecordedRequest
+}
+
+// NewServer starts a fake API serving the built-in fixtures
+func NewServer() *Server {
+	s := &Server{}
+	s.Reset()
+	s.server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Reset restores the state of a new server: the built-in fixtures only, with
+// no handlers, scripts, injected errors, latency, bandwidth limit or
+// recorded requests
+func (s *Server) Reset() {
+	s.mu.Lock()
+	s.fixtures = make(map[Endpoint]Fixture)
+	s.handlers = make(map[Endpoint]http.HandlerFunc)
+	s.all = nil
+	s.errors = make(map[Endpoint]*injectedError)
+	s.scripts = make(map[Endpoint][]Fixture)
+	s.latency, s.bandwidth = 0, 0
+	s.requests = nil
+	s.mu.Unlock()
+
+	sub, _ := fs.Sub(defaultFixtures, "fixtures")
+	if err := s.LoadFixtures(sub); err != nil {
+		// The default fixtures are embedded, so this only fails on a broken build
+		panic(err)
+	}
+}
+
+// URL returns the base URL to configure on the client under test
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Close shuts down the server
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// LoadFixtures loads <endpoint>.json files from fsys as 200 OK responses,
+// replacing existing fixtures for the same endpoints
+func (s *Server) LoadFixtures(fsys fs.FS) error {
+	files, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return err
+	}
+	for _, name := range files {
+		body, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("failed to read fixture %s: %w", name, err)
+		}
+		if !json.Valid(body) {
+			return fmt.Errorf("fixture %s is not valid JSON", name)
+		}
+		endpoint := Endpoint(strings.TrimSuffix(path.Base(name), ".json"))
+		s.SetFixture(endpoint, Fixture{Status: http.StatusOK, Body: body})
+	}
+	return nil
+}
+
+// SetFixture sets the canned response for an endpoint
+func (s *Server) SetFixture(endpoint Endpoint, fixture Fixture) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fixtures[endpoint] = fixture
+}
+
+// SetJSON sets a JSON response for an endpoint, encoding body
+func (s *Server) SetJSON(endpoint Endpoint, status int, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	s.SetFixture(endpoint, Fixture{Status: status, Body: data})
+	return nil
+}
+
+// Handle installs a custom handler for an endpoint, taking precedence over fixtures
+func (s *Server) Handle(endpoint Endpoint, handler http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[endpoint] = handler
+}
+
+// HandleAll installs a handler for every request, taking precedence over the
+// handlers and fixtures of individual endpoints, e.g. to serve paths without
+// an Endpoint. Requests are still recorded, and scripts and injected errors
+// still apply.
+func (s *Server) HandleAll(handler http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.all = handler
+}
+
+// SetLatency delays every response by d
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// SetBandwidth throttles every response body to bytesPerSecond.
+// Zero or a negative value removes the limit.
+func (s *Server) SetBandwidth(bytesPerSecond int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bandwidth = max(bytesPerSecond, 0)
+}
+
+// Script queues responses for endpoint that are served in order, one per
+// request, before falling back to the endpoint's handler or fixture. Calling
+// Script again appends to the queue, so Script(Activities, unauthorized, ok)
+// makes the first call fail with 401 and the second succeed.
+func (s *Server) Script(endpoint Endpoint, responses ...Fixture) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scripts[endpoint] = append(s.scripts[endpoint], responses...)
+}
+
+// Pending returns how many scripted responses are still queued for endpoint
+func (s *Server) Pending(endpoint Endpoint) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.scripts[endpoint])
+}
+
+// ClearScripts drops all queued scripted responses
+func (s *Server) ClearScripts() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scripts = make(map[Endpoint][]Fixture)
+}
+
+// InjectError makes the next count requests to endpoint fail with status.
+// A negative count fails every request until ClearErrors is called.
+func (s *Server) InjectError(endpoint Endpoint, status int, count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if count == 0 {
+		delete(s.errors, endpoint)
+		return
+	}
+	s.errors[endpoint] = &injectedError{status: status, remaining: count}
+}
+
+// ClearErrors removes all injected errors
+func (s *Server) ClearErrors() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors = make(map[Endpoint]*injectedError)
+}
+
+// Requests returns a copy of all recorded requests in arrival order
+func (s *Server) Requests() []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]RecordedRequest(nil), s.requests...)
+}
+
+// RequestCount returns how many requests an endpoint received
+func (s *Server) RequestCount(endpoint Endpoint) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, r := range s.requests {
+		if r.Endpoint == endpoint {
+			n++
+		}
+	}
+	return n
+}
+
+// LastRequest returns the most recent request to an endpoint
+func (s *Server) LastRequest(endpoint Endpoint) (RecordedRequest, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := len(s.requests) - 1; i >= 0; i-- {
+		if s.requests[i].Endpoint == endpoint {
+			return s.requests[i], true
+		}
+	}
+	return RecordedRequest{}, false
+}
+
+// ResetRequests clears the recorded requests
+func (s *Server) ResetRequests() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+// serve records the request and produces the configured response
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	endpoint := EndpointFor(r.URL.Path)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, RecordedRequest{
+		Endpoint: endpoint,
+		Method:   r.Method,
+		Path:     r.URL.Path,
+		Query:    r.URL.Query(),
+		Header:   r.Header.Clone(),
+		Body:     body,
+	})
+	latency := s.latency
+	bandwidth := s.bandwidth
+	handler := s.handlers[endpoint]
+	if s.all != nil {
+		handler = s.all
+	}
+	fixture, hasFixture := s.fixtures[endpoint]
+	errStatus := s.takeError(endpoint)
+	scripted, hasScript := s.takeScript(endpoint)
+	s.mu.Unlock()
+
+	if hasScript {
+		fixture, hasFixture, handler = scripted, true, nil
+	}
+	if bandwidth > 0 {
+		w = &throttledWriter{ResponseWriter: w, ctx: r.Context(), bytesPerSecond: bandwidth}
+	}
+
+	if latency > 0 || fixture.Latency > 0 {
+		select {
+		case <-time.After(latency + fixture.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	switch {
+	case errStatus != 0:
+		writeError(w, errStatus, http.StatusText(errStatus))
+	case handler != nil:
+		handler(w, r)
+	case hasFixture:
+		for k, v := range fixture.Header {
+			w.Header()[k] = v
+		}
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		status := fixture.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		w.WriteHeader(status)
+		if fixture.Body == nil && status >= http.StatusBadRequest {
+			json.NewEncoder(w).Encode(map[string]string{"error": http.StatusText(status)})
+			return
+		}
+		w.Write(fixture.Body)
+	default:
+		writeError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+// takeError consumes one injected error for endpoint; callers must hold s.mu
+func (s *Server) takeError(endpoint Endpoint) int {
+	e, ok := s.errors[endpoint]
+	if !ok {
+		return 0
+	}
+	if e.remaining > 0 {
+		e.remaining--
+		if e.remaining == 0 {
+			delete(s.errors, endpoint)
+		}
+	}
+	return e.status
+}
+
+// takeScript pops the next scripted response for endpoint; callers must hold s.mu.
+// Scripts are consumed even when an injected error answers the request, so a
+// script always describes the nth call to the endpoint.
+func (s *Server) takeScript(endpoint Endpoint) (Fixture, bool) {
+	queue := s.scripts[endpoint]
+	if len(queue) == 0 {
+		return Fixture{}, false
+	}
+	if len(queue) == 1 {
+		delete(s.scripts, endpoint)
+	} else {
+		s.scripts[endpoint] = queue[1:]
+	}
+	return queue[0], true
+}
+
+// writeError writes Garmin's alternative error format
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
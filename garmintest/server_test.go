@@ -0,0 +1,267 @@
+package garmintest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func get(t *testing.T, s *Server, path string) (int, string) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, s.URL()+path, nil)
+	req.Header.Set("Authorization", "Bearer test")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestDefaultFixtures(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	paths := map[string]string{
+		"/activitylist-service/activities/search?page=1": `"Morning Run"`,
+		"/activity-service/activity/1":                   `"calories": 500`,
+		"/userprofile-service/socialProfile":             `"displayName": "Mock User"`,
+		"/userprofile-service/userprofile/settings":      `"timeZone": "Europe/Berlin"`,
+		"/stats-service/stats/daily/2024-03-01":          `"totalSteps": 10000`,
+		"/wellness-service/sleep/daily/2024-03-01":       `"sleepTimeSeconds": 28800`,
+		"/wellness-service/stress/daily/2024-03-01":      `"overallStressLevel": 42`,
+		"/wellness-service/steps/daily/2024-03-01":       `"stepGoalAchieved": true`,
+		"/hrv-service/hrv/2024-03-01":                    `"hrvStatus": "BALANCED"`,
+		"/bodybattery-service/bodybattery/2024-03-01":    `"charged": 85`,
+		"/body-composition?startDate=2024-03-01":         `"boneMass": 2.8`,
+		"/gear-service/stats/test-gear-uuid":             `"name": "Test Gear"`,
+		"/calendar-service/year/2024/month/2":            `"title": "Threshold"`,
+	}
+	for path, want := range paths {
+		status, body := get(t, s, path)
+		assert.Equal(t, http.StatusOK, status, path)
+		assert.Contains(t, body, want, path)
+	}
+
+	status, _ := get(t, s, "/not-a-service")
+	assert.Equal(t, http.StatusNotFound, status)
+	s.AssertRequested(t, Unknown, 1)
+}
+
+func TestFixturesAndHandlers(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	t.Run("load from fs", func(t *testing.T) {
+		err := s.LoadFixtures(fstest.MapFS{"sleep.json": {Data: []byte(`{"sleepTimeSeconds": 1}`)}})
+		assert.NoError(t, err)
+		_, body := get(t, s, "/wellness-service/sleep/daily/2024-03-01")
+		assert.Equal(t, `{"sleepTimeSeconds": 1}`, body)
+
+		err = s.LoadFixtures(fstest.MapFS{"hrv.json": {Data: []byte(`{broken`)}})
+		assert.ErrorContains(t, err, "not valid JSON")
+	})
+
+	t.Run("set json", func(t *testing.T) {
+		assert.NoError(t, s.SetJSON(Profile, http.StatusOK, map[string]string{"displayName": "Other"}))
+		_, body := get(t, s, "/userprofile-service/socialProfile")
+		assert.JSONEq(t, `{"displayName": "Other"}`, body)
+	})
+
+	t.Run("custom handler", func(t *testing.T) {
+		s.Handle(Activity, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		})
+		status, _ := get(t, s, "/activity-service/activity/9")
+		assert.Equal(t, http.StatusTeapot, status)
+	})
+
+	t.Run("handle all", func(t *testing.T) {
+		s.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(r.URL.Path))
+		})
+		_, body := get(t, s, "/workout-service/workout/7")
+		assert.Equal(t, "/workout-service/workout/7", body)
+		_, body = get(t, s, "/activity-service/activity/9")
+		assert.Equal(t, "/activity-service/activity/9", body, "takes precedence over endpoint handlers")
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		s.Reset()
+		status, body := get(t, s, "/activity-service/activity/9")
+		assert.Equal(t, http.StatusOK, status)
+		assert.Contains(t, body, `"calories": 500`)
+		_, body = get(t, s, "/wellness-service/sleep/daily/2024-03-01")
+		assert.Contains(t, body, `"sleepTimeSeconds": 28800`, "fixtures are restored")
+		s.AssertRequested(t, Activity, 1)
+	})
+}
+
+func TestErrorInjection(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.InjectError(Sleep, http.StatusTooManyRequests, 2)
+	for i := 0; i < 2; i++ {
+		status, body := get(t, s, "/wellness-service/sleep/daily/2024-03-01")
+		assert.Equal(t, http.StatusTooManyRequests, status)
+
+		var errBody map[string]string
+		assert.NoError(t, json.Unmarshal([]byte(body), &errBody))
+		assert.Equal(t, "Too Many Requests", errBody["error"])
+	}
+	status, _ := get(t, s, "/wellness-service/sleep/daily/2024-03-01")
+	assert.Equal(t, http.StatusOK, status)
+
+	s.InjectError(HRV, http.StatusInternalServerError, -1)
+	for i := 0; i < 3; i++ {
+		status, _ := get(t, s, "/hrv-service/hrv/2024-03-01")
+		assert.Equal(t, http.StatusInternalServerError, status)
+	}
+	s.ClearErrors()
+	status, _ = get(t, s, "/hrv-service/hrv/2024-03-01")
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func TestLatency(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.SetLatency(50 * time.Millisecond)
+
+	start := time.Now()
+	get(t, s, "/userprofile-service/socialProfile")
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	// Clients giving up early are not kept waiting
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, s.URL()+"/userprofile-service/socialProfile", nil)
+	_, err := http.DefaultClient.Do(req)
+	assert.Error(t, err)
+}
+
+func TestScript(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.Script(Profile,
+		Fixture{Status: http.StatusUnauthorized},
+		Fixture{Status: http.StatusOK, Body: []byte(`{"displayName": "Refreshed"}`)},
+	)
+	assert.Equal(t, 2, s.Pending(Profile))
+
+	status, body := get(t, s, "/userprofile-service/socialProfile")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Contains(t, body, `"error"`)
+
+	_, body = get(t, s, "/userprofile-service/socialProfile")
+	assert.Contains(t, body, "Refreshed")
+
+	// Exhausted scripts fall back to the fixture
+	_, body = get(t, s, "/userprofile-service/socialProfile")
+	assert.Contains(t, body, "Mock User")
+	assert.Equal(t, 0, s.Pending(Profile))
+
+	s.Script(Stats, Fixture{Status: http.StatusTooManyRequests})
+	s.ClearScripts()
+	status, _ = get(t, s, "/stats-service/stats/daily/2024-03-01")
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func TestBandwidth(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.SetFixture(Download, Fixture{Body: []byte(strings.Repeat("x", 2000))})
+	s.SetBandwidth(10000)
+
+	start := time.Now()
+	_, body := get(t, s, "/download-service/files/activity/1")
+	assert.Len(t, body, 2000)
+	assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+
+	s.SetBandwidth(0)
+	start = time.Now()
+	get(t, s, "/download-service/files/activity/1")
+	assert.Less(t, time.Since(start), 150*time.Millisecond)
+}
+
+func TestScenarios(t *testing.T) {
+	t.Run("empty account", func(t *testing.T) {
+		s := NewServer()
+		defer s.Close()
+		s.Apply(EmptyAccount())
+
+		_, body := get(t, s, "/activitylist-service/activities/search")
+		assert.Contains(t, body, `"totalCount": 0`)
+		status, _ := get(t, s, "/wellness-service/sleep/daily/2024-03-01")
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		s := NewServer()
+		defer s.Close()
+		s.Apply(Unauthorized())
+
+		status, _ := get(t, s, "/userprofile-service/socialProfile")
+		assert.Equal(t, http.StatusUnauthorized, status)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		s := NewServer()
+		defer s.Close()
+		s.Apply(ExpiredToken())
+
+		status, _ := get(t, s, "/userprofile-service/socialProfile")
+		assert.Equal(t, http.StatusUnauthorized, status)
+		status, _ = get(t, s, "/userprofile-service/socialProfile")
+		assert.Equal(t, http.StatusOK, status)
+	})
+}
+
+func TestRequestAssertions(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	get(t, s, "/activitylist-service/activities/search?page=2&pageSize=50")
+	resp, err := http.Post(s.URL()+"/upload-service/upload/.fit", "application/octet-stream", strings.NewReader("FIT"))
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	s.AssertRequested(t, Activities, 1)
+	s.AssertNotRequested(t, Sleep)
+	s.AssertQuery(t, Activities, "pageSize", "50")
+	s.AssertHeader(t, Activities, "Authorization", "Bearer test")
+
+	upload, ok := s.LastRequest(Upload)
+	assert.True(t, ok)
+	assert.Equal(t, http.MethodPost, upload.Method)
+	assert.Equal(t, []byte("FIT"), upload.Body)
+
+	assert.Len(t, s.Requests(), 2)
+	s.ResetRequests()
+	assert.Empty(t, s.Requests())
+
+	// Failed assertions are reported on the provided TB
+	rec := &recordingT{TB: t}
+	assert.False(t, s.AssertRequested(rec, Activities, 1))
+	assert.True(t, rec.failed)
+}
+
+// recordingT captures assertion failures instead of failing the test
+type recordingT struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.failed = true
+}
@@ -0,0 +1,43 @@
+package garmintest
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// throttleInterval is how often a throttled response releases a chunk
+const throttleInterval = 50 * time.Millisecond
+
+// throttledWriter trickles response bodies to the client at a fixed rate to
+// simulate slow mobile connections
+type throttledWriter struct {
+	http.ResponseWriter
+	ctx            context.Context
+	bytesPerSecond int
+}
+
+// Write sends p in chunks, pausing between them to stay under the bandwidth limit
+func (w *throttledWriter) Write(p []byte) (int, error) {
+	chunk := max(w.bytesPerSecond*int(throttleInterval)/int(time.Second), 1)
+	written := 0
+	for written < len(p) {
+		end := min(written+chunk, len(p))
+		n, err := w.ResponseWriter.Write(p[written:end])
+		written += n
+		if err != nil {
+			return written, err
+		}
+		if f, ok := w.ResponseWriter.(http.Flusher); ok {
+			f.Flush()
+		}
+
+		delay := time.Duration(n) * time.Second / time.Duration(w.bytesPerSecond)
+		select {
+		case <-time.After(delay):
+		case <-w.ctx.Done():
+			return written, w.ctx.Err()
+		}
+	}
+	return written, nil
+}
@@ -0,0 +1,308 @@
+package analysis
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVolumeBySport(t *testing.T) {
+	activities := []api.Activity{
+		{Type: "running", Duration: 1800, Distance: 5000},
+		{Type: "cycling", Duration: 7200, Distance: 60000},
+		{Type: "running", Duration: 3600, Distance: 10000},
+		{Type: "", Duration: 600},
+	}
+
+	volumes := VolumeBySport(activities)
+	assert.Len(t, volumes, 3)
+	assert.Equal(t, SportVolume{Sport: "cycling", Count: 1, Duration: 2 * time.Hour, Distance: 60000}, volumes[0])
+	assert.Equal(t, SportVolume{Sport: "running", Count: 2, Duration: 90 * time.Minute, Distance: 15000}, volumes[1])
+	assert.Equal(t, "other", volumes[2].Sport)
+
+	total := TotalVolume(volumes)
+	assert.Equal(t, 4, total.Count)
+	assert.Equal(t, 3*time.Hour+40*time.Minute, total.Duration)
+	assert.Equal(t, float64(75000), total.Distance)
+}
+
+func TestSumHRZones(t *testing.T) {
+	zones := SumHRZones(
+		[]api.HRZone{{ZoneNumber: 2, SecsInZone: 600}, {ZoneNumber: 1, SecsInZone: 60}},
+		[]api.HRZone{{ZoneNumber: 2, SecsInZone: 300}, {ZoneNumber: 4, SecsInZone: 120}},
+	)
+
+	assert.Equal(t, []ZoneTime{
+		{Zone: 1, Duration: time.Minute},
+		{Zone: 2, Duration: 15 * time.Minute},
+		{Zone: 4, Duration: 2 * time.Minute},
+	}, zones)
+}
+
+func TestAverageSleep(t *testing.T) {
+	t.Run("skips nights without sleep", func(t *testing.T) {
+		summary := AverageSleep([]api.SleepData{
+			{SleepTimeSeconds: api.Ptr(28800), DeepSleepSeconds: api.Ptr(7200), RemSleepSeconds: api.Ptr(5400), LightSleepSeconds: api.Ptr(16200), SleepScore: api.Ptr(80)},
+			{SleepTimeSeconds: api.Ptr(25200), DeepSleepSeconds: api.Ptr(3600), RemSleepSeconds: api.Ptr(3600), LightSleepSeconds: api.Ptr(18000), SleepScore: api.Ptr(70)},
+			// Recorded sleep without a score only counts towards the durations
+			{SleepTimeSeconds: api.Ptr(27000), DeepSleepSeconds: api.Ptr(5400), RemSleepSeconds: api.Ptr(4500), LightSleepSeconds: api.Ptr(17100)},
+			{},
+		})
+
+		assert.Equal(t, 3, summary.Nights)
+		assert.Equal(t, 7*time.Hour+30*time.Minute, summary.AvgDuration)
+		assert.Equal(t, 90*time.Minute, summary.AvgDeepSleep)
+		assert.Equal(t, 75.0, summary.AvgScore)
+	})
+
+	t.Run("no data", func(t *testing.T) {
+		assert.Equal(t, SleepSummary{}, AverageSleep(nil))
+	})
+}
+
+// powerSeries builds a 1 Hz power series starting at a fixed time
+func powerSeries(watts ...float64) []api.PowerSample {
+	start := time.Date(2024, 3, 1, 7, 0, 0, 0, time.UTC)
+	samples := make([]api.PowerSample, len(watts))
+	for i, w := range watts {
+		samples[i] = api.PowerSample{Timestamp: api.NewGarminTime(start.Add(time.Duration(i) * time.Second)), Watts: api.Ptr(w)}
+	}
+	return samples
+}
+
+func TestPowerCurve(t *testing.T) {
+	samples := powerSeries(100, 400, 300, 100, 100)
+	curve := PowerCurve(samples, time.Second, 2*time.Second, 5*time.Second, time.Minute)
+	assert.Equal(t, []PowerCurvePoint{
+		{Duration: time.Second, Watts: 400},
+		{Duration: 2 * time.Second, Watts: 350},
+		{Duration: 5 * time.Second, Watts: 200},
+	}, curve)
+
+	// Gaps are held briefly, then count as stopped
+	gap := powerSeries(200, 200)
+	gap[1].Timestamp = api.NewGarminTime(gap[0].Timestamp.Add(8 * time.Second))
+	assert.Equal(t, []float64{200, 200, 200, 200, 200, 0, 0, 0, 200}, SecondlyPower(gap))
+}
+
+func TestNormalizedPower(t *testing.T) {
+	steady := make([]float64, 3600)
+	for i := range steady {
+		steady[i] = 250
+	}
+	np := NormalizedPower(powerSeries(steady...))
+	assert.InDelta(t, 250, np, 0.001)
+	assert.InDelta(t, 0.8333, IntensityFactor(np, 300), 0.001)
+	assert.InDelta(t, 69.44, TrainingStress(time.Hour, np, 300), 0.01)
+	assert.InDelta(t, 100, TrainingStress(time.Hour, 300, 300), 0.001)
+
+	assert.Zero(t, NormalizedPower(powerSeries(300, 300)))
+	assert.Zero(t, TrainingStress(time.Hour, 250, 0))
+}
+
+func TestGearRotation(t *testing.T) {
+	now := time.Date(2024, 3, 29, 12, 0, 0, 0, time.UTC)
+	run := func(daysAgo int, meters float64) api.GearActivity {
+		return api.GearActivity{StartTime: api.NewGarminTime(now.AddDate(0, 0, -daysAgo)), Distance: meters}
+	}
+	gear := []GearUsage{
+		{
+			Stats:      api.GearStats{UUID: "daily", Name: "Daily trainer", Distance: 600000},
+			Activities: []api.GearActivity{run(1, 10000), run(8, 10000), run(15, 10000), run(22, 10000), run(40, 50000)},
+		},
+		{
+			Stats:      api.GearStats{UUID: "racer", Name: "Racer", Distance: 150000},
+			Activities: []api.GearActivity{run(5, 21000)},
+			Lifespan:   400000,
+		},
+		{Stats: api.GearStats{UUID: "trail", Name: "Trail", Distance: 300000}},
+		{Stats: api.GearStats{UUID: "old", Name: "Old", Distance: 900000}, Activities: []api.GearActivity{run(60, 8000)}},
+	}
+
+	forecasts := ForecastGear(gear, now, 4)
+	assert.Len(t, forecasts, 4)
+
+	daily := forecasts[0]
+	assert.Equal(t, 10000.0, daily.WeeklyDistance)
+	assert.Equal(t, 100000.0, daily.Remaining)
+	assert.Equal(t, now.AddDate(0, 0, 70), daily.Replacement)
+	assert.Equal(t, now.AddDate(0, 0, -1), daily.LastUsed)
+
+	assert.Equal(t, 250000.0, forecasts[1].Remaining)
+	assert.True(t, forecasts[2].Replacement.IsZero())
+	assert.True(t, forecasts[3].WornOut())
+	assert.Equal(t, now, forecasts[3].Replacement)
+
+	// Unused gear first, then the longest rested; worn out gear is dropped
+	order := SuggestRotation(forecasts)
+	assert.Len(t, order, 3)
+	assert.Equal(t, "trail", order[0].UUID)
+	assert.Equal(t, "racer", order[1].UUID)
+	assert.Equal(t, "daily", order[2].UUID)
+}
+
+func TestWorkloadRatio(t *testing.T) {
+	activities := []api.Activity{
+		{StartTime: api.NewGarminTime(time.Date(2024, 3, 1, 7, 0, 0, 0, time.UTC)), TrainingLoad: api.Ptr(100.0)},
+		{StartTime: api.NewGarminTime(time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC)), TrainingLoad: api.Ptr(50.0)},
+		{StartTime: api.NewGarminTime(time.Date(2024, 3, 3, 7, 0, 0, 0, time.UTC))},
+		{StartTime: api.NewGarminTime(time.Date(2024, 2, 28, 7, 0, 0, 0, time.UTC)), TrainingLoad: api.Ptr(80.0)},
+	}
+	loads := LoadsByDay(activities)
+	assert.Equal(t, []DailyLoad{
+		{Date: time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC), Load: 80},
+		{Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Load: 150},
+	}, loads)
+
+	// Earlier loads warm up the averages but only the requested days are returned
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	points := WorkloadRatio(loads, start, start.AddDate(0, 0, 2), LoadOptions{AcuteDays: 1, ChronicDays: 2})
+	assert.Len(t, points, 3)
+	assert.Equal(t, start, points[0].Date)
+	assert.Equal(t, 150.0, points[0].Load)
+
+	acute := 80 * (1 - math.Exp(-1.0))
+	acute *= math.Exp(-1.0)
+	acute += (150 - acute) * (1 - math.Exp(-1.0))
+	assert.InDelta(t, acute, points[0].Acute, 1e-9)
+	assert.InDelta(t, points[0].Acute/points[0].Chronic, points[0].Ratio, 1e-9)
+
+	// Rest days decay the acute load faster than the chronic load
+	assert.Equal(t, 0.0, points[2].Load)
+	assert.Less(t, points[2].Acute, points[1].Acute)
+	assert.Less(t, points[2].Ratio, points[1].Ratio)
+
+	assert.Empty(t, WorkloadRatio(nil, start, start.AddDate(0, 0, -1), DefaultLoadOptions()))
+	assert.Equal(t, 0.0, WorkloadRatio(nil, start, start, DefaultLoadOptions())[0].Ratio)
+}
+
+func TestZonesByPeriod(t *testing.T) {
+	power := SumPowerZones([]api.PowerZone{{ZoneNumber: 3, SecsInZone: 600}, {ZoneNumber: 1, SecsInZone: 1200}})
+	assert.Equal(t, []ZoneTime{{Zone: 1, Duration: 20 * time.Minute}, {Zone: 3, Duration: 10 * time.Minute}}, power)
+
+	activities := []ActivityZones{
+		{Start: time.Date(2024, 3, 4, 18, 0, 0, 0, time.UTC), Zones: SumHRZones([]api.HRZone{{ZoneNumber: 2, SecsInZone: 1800}, {ZoneNumber: 4, SecsInZone: 600}})},
+		{Start: time.Date(2024, 3, 10, 23, 30, 0, 0, time.UTC), Zones: SumHRZones([]api.HRZone{{ZoneNumber: 2, SecsInZone: 1200}})},
+		{Start: time.Date(2024, 3, 20, 7, 0, 0, 0, time.UTC), Zones: power},
+	}
+	start := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	weeks := WeeklyZones(activities, start, start.AddDate(0, 0, 20))
+	assert.Len(t, weeks, 3)
+	assert.Equal(t, []ZoneTime{{Zone: 2, Duration: 50 * time.Minute}, {Zone: 4, Duration: 10 * time.Minute}}, weeks[0].Zones)
+	assert.Equal(t, time.Hour, weeks[0].Total)
+	assert.InDelta(t, 50.0/60, weeks[0].Share(2), 1e-9)
+	assert.Equal(t, 0.0, weeks[0].Share(5))
+	// Weeks without activities are kept empty
+	assert.Empty(t, weeks[1].Zones)
+	assert.Equal(t, 0.0, weeks[1].Share(2))
+	assert.Equal(t, 30*time.Minute, weeks[2].Total)
+
+	months := MonthlyZones(activities, time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC))
+	assert.Len(t, months, 2)
+	assert.Empty(t, months[0].Zones)
+	assert.Equal(t, 90*time.Minute, months[1].Total)
+}
+
+// steadyRun returns a run east along the equator at speed m/s with one fix
+// per second, a constant heart rate and power
+func steadyRun(seconds int, speed, hr, watts float64) ActivityData {
+	start := time.Date(2024, 3, 1, 7, 0, 0, 0, time.UTC)
+	var a ActivityData
+	for i := 0; i <= seconds; i++ {
+		at := api.NewGarminTime(start.Add(time.Duration(i) * time.Second))
+		a.Track = append(a.Track, api.TrackPoint{
+			Timestamp: at,
+			Lat:       api.Ptr(0.0),
+			Lon:       api.Ptr(float64(i) * speed / 111194.93),
+			HeartRate: api.Ptr(hr),
+		})
+		a.Power = append(a.Power, api.PowerSample{Timestamp: at, Watts: api.Ptr(watts)})
+	}
+	return a
+}
+
+func TestCompare(t *testing.T) {
+	fast := steadyRun(300, 4, 160, 250)
+	slow := steadyRun(400, 3.2, 150, 220)
+	slow.Power = nil
+
+	byDistance := Compare(fast, slow, CompareByDistance, 200)
+	assert.Len(t, byDistance.Points, 6) // up to the fast run's 1200 m
+	last := byDistance.Points[5]
+	assert.Equal(t, 1200.0, last.At)
+	assert.InDelta(t, 75, last.Gap, 0.5, "375 s against 300 s")
+	assert.InDelta(t, float64(-62500*time.Millisecond), float64(*last.PaceDelta), float64(time.Second))
+	assert.Equal(t, 10.0, *last.HeartRateDelta)
+	assert.Nil(t, last.PowerDelta, "the second activity has no power")
+
+	byTime := Compare(fast, fast, CompareByTime, 60)
+	assert.Len(t, byTime.Points, 5)
+	assert.InDelta(t, 0, byTime.Points[4].Gap, 1e-6)
+	assert.Equal(t, 0.0, *byTime.Points[4].PowerDelta)
+
+	gap := Compare(fast, slow, CompareByTime, 60).Points[4].Gap
+	assert.InDelta(t, 240, gap, 0.5, "1200 m against 960 m after 5 minutes")
+
+	assert.Empty(t, Compare(ActivityData{}, slow, CompareByTime, 60).Points)
+}
+
+func TestBestEfforts(t *testing.T) {
+	efforts := BestEfforts(1, "running", steadyRun(1500, 4, 150, 250))
+	if assert.Len(t, efforts, 3, "the 6 km run has no 10K") {
+		assert.Equal(t, "1K", efforts[0].Name)
+		assert.InDelta(t, 250, efforts[0].Time.Seconds(), 0.5)
+		assert.Equal(t, "5K", efforts[1].Name)
+		assert.InDelta(t, 1250, efforts[1].Time.Seconds(), 0.5)
+		assert.Equal(t, "20min power", efforts[2].Name)
+		assert.InDelta(t, 250, efforts[2].Watts, 1e-9)
+		assert.Equal(t, "running/5K", efforts[1].Key())
+	}
+
+	rides := BestEfforts(1, "cycling", steadyRun(1500, 4, 150, 250))
+	if assert.Len(t, rides, 1, "distance efforts are tracked for runs only") {
+		assert.Equal(t, "cycling", rides[0].Sport)
+		assert.Equal(t, "20min power", rides[0].Name)
+	}
+}
+
+func TestRecordBook(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.json")
+	book, err := OpenRecordBook(path)
+	assert.NoError(t, err)
+
+	first := BestEfforts(1, "running", steadyRun(300, 4, 150, 250))
+	fallen, err := book.Update(first)
+	assert.NoError(t, err)
+	assert.Len(t, fallen, 1)
+	assert.Nil(t, fallen[0].Previous)
+
+	fallen, err = book.Update(first)
+	assert.NoError(t, err)
+	assert.Empty(t, fallen, "re-checking an activity raises no records")
+
+	slower, faster := steadyRun(300, 3, 150, 250), steadyRun(300, 5, 150, 250)
+	fallen, err = book.Update(append(BestEfforts(2, "running", slower), BestEfforts(3, "running", faster)...))
+	assert.NoError(t, err)
+	if assert.Len(t, fallen, 1) {
+		assert.Equal(t, int64(3), fallen[0].ActivityID)
+		assert.Equal(t, int64(1), fallen[0].Previous.ActivityID)
+	}
+
+	// Records are kept per sport
+	fallen, err = book.Update(BestEfforts(4, "cycling", steadyRun(1500, 8, 130, 300)))
+	assert.NoError(t, err)
+	if assert.Len(t, fallen, 1, "rides have no distance efforts") {
+		assert.Equal(t, "cycling/20min power", fallen[0].Key())
+		assert.Nil(t, fallen[0].Previous)
+	}
+
+	reopened, err := OpenRecordBook(path)
+	assert.NoError(t, err)
+	assert.Equal(t, book.Records(), reopened.Records())
+	assert.Len(t, reopened.Records(), 2)
+}
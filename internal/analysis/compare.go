@@ -0,0 +1,171 @@
+package analysis
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/geo"
+)
+
+// CompareBy selects how two activities are lined up by Compare
+type CompareBy string
+
+const (
+	// CompareByDistance compares the activities at the same distance from the
+	// start, for efforts over the same route
+	CompareByDistance CompareBy = "distance"
+	// CompareByTime compares the activities at the same elapsed time, for
+	// efforts of the same duration
+	CompareByTime CompareBy = "time"
+)
+
+// ActivityData is the recorded data of one activity to compare
+type ActivityData struct {
+	Track []api.TrackPoint
+	Power []api.PowerSample // optional, from GetActivityPowerSeries
+}
+
+// ComparisonPoint compares two activities at one distance or elapsed time.
+// Deltas are the first activity minus the second; they are nil when either
+// activity lacks the data.
+type ComparisonPoint struct {
+	// At is meters from the start when comparing by distance, seconds from
+	// the start when comparing by time
+	At float64
+	// Gap is how far the first activity is ahead: seconds when comparing by
+	// distance, meters when comparing by time
+	Gap float64
+	// PaceDelta compares the pace over the step leading up to At; negative
+	// means the first activity was faster
+	PaceDelta      *time.Duration
+	HeartRateDelta *float64
+	PowerDelta     *float64
+}
+
+// Comparison lines up two activities, the basis of "race against yourself"
+type Comparison struct {
+	By     CompareBy
+	Points []ComparisonPoint
+}
+
+// series is an activity prepared for lookups by distance and elapsed time
+type series struct {
+	elapsed  []float64 // seconds since the first point
+	distance []float64 // meters since the first point
+	points   []api.TrackPoint
+	power    []api.PowerSample
+}
+
+func newSeries(a ActivityData) series {
+	s := series{
+		points:   a.Track,
+		distance: geo.CumulativeDistance(a.Track, geo.DefaultMaxSpeed),
+		elapsed:  make([]float64, len(a.Track)),
+		power:    a.Power,
+	}
+	for i, p := range a.Track {
+		s.elapsed[i] = p.Timestamp.Time.Sub(a.Track[0].Timestamp.Time).Seconds()
+	}
+	return s
+}
+
+// interpolate returns the ys value at x, where xs is non-decreasing
+func interpolate(xs, ys []float64, x float64) float64 {
+	i := sort.SearchFloat64s(xs, x)
+	switch {
+	case i == 0:
+		return ys[0]
+	case i >= len(xs):
+		return ys[len(ys)-1]
+	case xs[i] == xs[i-1]:
+		return ys[i]
+	}
+	share := (x - xs[i-1]) / (xs[i] - xs[i-1])
+	return ys[i-1] + share*(ys[i]-ys[i-1])
+}
+
+// heartRateAt returns the heart rate recorded last before seconds elapsed
+func (s series) heartRateAt(seconds float64) *float64 {
+	i := sort.Search(len(s.elapsed), func(i int) bool { return s.elapsed[i] > seconds }) - 1
+	for ; i >= 0; i-- {
+		if hr := s.points[i].HeartRate; hr != nil {
+			return hr
+		}
+	}
+	return nil
+}
+
+// powerAt returns the power recorded last before seconds elapsed
+func (s series) powerAt(seconds float64) *float64 {
+	if len(s.power) == 0 || len(s.points) == 0 {
+		return nil
+	}
+	at := s.points[0].Timestamp.Time.Add(time.Duration(seconds * float64(time.Second)))
+	i := sort.Search(len(s.power), func(i int) bool { return s.power[i].Timestamp.Time.After(at) }) - 1
+	if i < 0 {
+		return nil
+	}
+	return s.power[i].Watts
+}
+
+// delta returns a - b, or nil when either is missing
+func delta(a, b *float64) *float64 {
+	if a == nil || b == nil {
+		return nil
+	}
+	return api.Ptr(*a - *b)
+}
+
+// Compare lines up activities a and b every step meters (CompareByDistance)
+// or step seconds (CompareByTime), up to where the shorter one ends
+func Compare(a, b ActivityData, by CompareBy, step float64) Comparison {
+	result := Comparison{By: by}
+	if len(a.Track) == 0 || len(b.Track) == 0 || step <= 0 {
+		return result
+	}
+	sa, sb := newSeries(a), newSeries(b)
+
+	// position returns the elapsed seconds and distance of s at x
+	position := func(s series, x float64) (seconds, meters float64) {
+		if by == CompareByTime {
+			return x, interpolate(s.elapsed, s.distance, x)
+		}
+		return interpolate(s.distance, s.elapsed, x), x
+	}
+	// pace returns the time per kilometer over the step ending at x
+	pace := func(s series, x float64) *time.Duration {
+		t0, d0 := position(s, x-step)
+		t1, d1 := position(s, x)
+		if d1 <= d0 {
+			return nil
+		}
+		return api.Ptr(time.Duration((t1 - t0) / (d1 - d0) * 1000 * float64(time.Second)))
+	}
+
+	end := min(sa.distance[len(sa.distance)-1], sb.distance[len(sb.distance)-1])
+	if by == CompareByTime {
+		end = min(sa.elapsed[len(sa.elapsed)-1], sb.elapsed[len(sb.elapsed)-1])
+	}
+	// Allow for rounding so an activity ending exactly on a step keeps that point
+	end += step * 1e-6
+	for x := step; x <= end; x += step {
+		ta, da := position(sa, x)
+		tb, db := position(sb, x)
+		p := ComparisonPoint{
+			At:             x,
+			HeartRateDelta: delta(sa.heartRateAt(ta), sb.heartRateAt(tb)),
+			PowerDelta:     delta(sa.powerAt(ta), sb.powerAt(tb)),
+		}
+		if by == CompareByTime {
+			p.Gap = da - db
+		} else {
+			p.Gap = tb - ta
+		}
+		if pa, pb := pace(sa, x), pace(sb, x); pa != nil && pb != nil {
+			p.PaceDelta = api.Ptr(*pa - *pb)
+		}
+		result.Points = append(result.Points, p)
+	}
+	return result
+}
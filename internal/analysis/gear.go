@@ -0,0 +1,99 @@
+package analysis
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// DefaultShoeLifespan is the distance in meters after which running shoes are
+// usually replaced
+const DefaultShoeLifespan = 700000
+
+// GearUsage is a gear item together with its recent activities
+type GearUsage struct {
+	Stats      api.GearStats
+	Activities []api.GearActivity
+	Lifespan   float64 // retirement distance in meters; 0 uses DefaultShoeLifespan
+}
+
+// GearForecast projects when a gear item reaches the end of its lifespan
+type GearForecast struct {
+	UUID           string    `json:"uuid"`
+	Name           string    `json:"name"`
+	Distance       float64   `json:"distance"`       // lifetime distance in meters
+	Remaining      float64   `json:"remaining"`      // meters left before retirement, 0 when worn out
+	WeeklyDistance float64   `json:"weeklyDistance"` // average meters per week over the trend window
+	LastUsed       time.Time `json:"lastUsed"`       // zero when there are no activities
+	Replacement    time.Time `json:"replacement"`    // zero when the item is not in use
+}
+
+// WornOut reports whether the item has reached its lifespan
+func (f GearForecast) WornOut() bool {
+	return f.Remaining <= 0
+}
+
+// ForecastGear projects the replacement date of each gear item from its
+// average weekly distance over the weeks before now. Items that were not used
+// in that window get no replacement date.
+func ForecastGear(gear []GearUsage, now time.Time, weeks int) []GearForecast {
+	if weeks < 1 {
+		weeks = 1
+	}
+	windowStart := now.AddDate(0, 0, -7*weeks)
+
+	forecasts := make([]GearForecast, 0, len(gear))
+	for _, g := range gear {
+		lifespan := g.Lifespan
+		if lifespan <= 0 {
+			lifespan = DefaultShoeLifespan
+		}
+		f := GearForecast{
+			UUID:      g.Stats.UUID,
+			Name:      g.Stats.Name,
+			Distance:  g.Stats.Distance,
+			Remaining: max(lifespan-g.Stats.Distance, 0),
+		}
+
+		var recent float64
+		for _, a := range g.Activities {
+			if a.StartTime.After(f.LastUsed) {
+				f.LastUsed = a.StartTime.Time
+			}
+			if a.StartTime.After(windowStart) && !a.StartTime.After(now) {
+				recent += a.Distance
+			}
+		}
+		f.WeeklyDistance = recent / float64(weeks)
+
+		switch {
+		case f.WornOut():
+			f.Replacement = now
+		case f.WeeklyDistance > 0:
+			weeksLeft := f.Remaining / f.WeeklyDistance
+			f.Replacement = now.Add(time.Duration(weeksLeft * float64(7*24*time.Hour)))
+		}
+		forecasts = append(forecasts, f)
+	}
+	return forecasts
+}
+
+// SuggestRotation orders gear by which item to use next: the one that has
+// rested longest comes first, ties go to the item with more distance left.
+// Worn out items are left out.
+func SuggestRotation(forecasts []GearForecast) []GearForecast {
+	var usable []GearForecast
+	for _, f := range forecasts {
+		if !f.WornOut() {
+			usable = append(usable, f)
+		}
+	}
+	sort.SliceStable(usable, func(i, j int) bool {
+		if usable[i].LastUsed.Equal(usable[j].LastUsed) {
+			return usable[i].Remaining > usable[j].Remaining
+		}
+		return usable[i].LastUsed.Before(usable[j].LastUsed)
+	})
+	return usable
+}
@@ -0,0 +1,100 @@
+package analysis
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// LoadOptions sets the time constants, in days, of the exponentially weighted
+// acute (fatigue) and chronic (fitness) training load averages
+type LoadOptions struct {
+	AcuteDays   float64 `json:"acuteDays"`
+	ChronicDays float64 `json:"chronicDays"`
+}
+
+// DefaultLoadOptions returns the common 7 day acute and 42 day chronic windows
+func DefaultLoadOptions() LoadOptions {
+	return LoadOptions{AcuteDays: 7, ChronicDays: 42}
+}
+
+// DailyLoad is the training load accumulated on one calendar day
+type DailyLoad struct {
+	Date time.Time `json:"date"`
+	Load float64   `json:"load"`
+}
+
+// LoadPoint holds the acute and chronic load at the end of a day
+type LoadPoint struct {
+	Date    time.Time `json:"date"`
+	Load    float64   `json:"load"`
+	Acute   float64   `json:"acute"`   // ATL
+	Chronic float64   `json:"chronic"` // CTL
+	Ratio   float64   `json:"ratio"`   // ACWR, acute divided by chronic; 0 without chronic load
+}
+
+// LoadsByDay totals the training load of activities per local start date, in
+// date order. Activities recorded without a training load are skipped.
+func LoadsByDay(activities []api.Activity) []DailyLoad {
+	totals := make(map[time.Time]float64)
+	for _, a := range activities {
+		if a.TrainingLoad == nil {
+			continue
+		}
+		y, m, d := a.StartTime.Date()
+		totals[time.Date(y, m, d, 0, 0, 0, 0, time.UTC)] += *a.TrainingLoad
+	}
+
+	loads := make([]DailyLoad, 0, len(totals))
+	for day, load := range totals {
+		loads = append(loads, DailyLoad{Date: day, Load: load})
+	}
+	sort.Slice(loads, func(i, j int) bool { return loads[i].Date.Before(loads[j].Date) })
+	return loads
+}
+
+// WorkloadRatio computes the acute and chronic load and their ratio for every
+// day from start to end inclusive. Days without an entry in loads count as
+// rest days; loads before start warm up the averages but are not returned.
+func WorkloadRatio(loads []DailyLoad, start, end time.Time, opts LoadOptions) []LoadPoint {
+	if opts.AcuteDays <= 0 || opts.ChronicDays <= 0 {
+		opts = DefaultLoadOptions()
+	}
+	acuteDecay := 1 - math.Exp(-1/opts.AcuteDays)
+	chronicDecay := 1 - math.Exp(-1/opts.ChronicDays)
+
+	// Days are compared as calendar dates in start's location
+	loc := start.Location()
+	calendarDay := func(t time.Time) time.Time {
+		y, m, d := t.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, loc)
+	}
+	first := calendarDay(start)
+	byDay := make(map[string]float64, len(loads))
+	for _, l := range loads {
+		day := calendarDay(l.Date)
+		byDay[day.Format("2006-01-02")] += l.Load
+		if day.Before(first) {
+			first = day
+		}
+	}
+
+	var points []LoadPoint
+	var acute, chronic float64
+	for _, day := range api.Days(first, end) {
+		load := byDay[day.Format("2006-01-02")]
+		acute += (load - acute) * acuteDecay
+		chronic += (load - chronic) * chronicDecay
+		if day.Before(calendarDay(start)) {
+			continue
+		}
+		p := LoadPoint{Date: day, Load: load, Acute: acute, Chronic: chronic}
+		if chronic > 0 {
+			p.Ratio = acute / chronic
+		}
+		points = append(points, p)
+	}
+	return points
+}
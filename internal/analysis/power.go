@@ -0,0 +1,116 @@
+package analysis
+
+import (
+	"math"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// maxPowerHold is the longest gap between samples over which the last reading
+// is carried forward. Longer gaps are stops and count as zero power.
+const maxPowerHold = 5
+
+// DefaultPowerCurveDurations are the windows of a standard peak power curve
+var DefaultPowerCurveDurations = []time.Duration{
+	time.Second, 5 * time.Second, 10 * time.Second, 30 * time.Second,
+	time.Minute, 2 * time.Minute, 5 * time.Minute, 10 * time.Minute,
+	20 * time.Minute, 30 * time.Minute, time.Hour,
+}
+
+// PowerCurvePoint is the best average power held for one duration
+type PowerCurvePoint struct {
+	Duration time.Duration `json:"duration"`
+	Watts    float64       `json:"watts"`
+}
+
+// SecondlyPower resamples a power series to one value per second
+func SecondlyPower(samples []api.PowerSample) []float64 {
+	var out []float64
+	for i, s := range samples {
+		watts := api.Value(s.Watts)
+		hold := 1
+		if i+1 < len(samples) {
+			hold = int(samples[i+1].Timestamp.Sub(s.Timestamp.Time) / time.Second)
+		}
+		for j := 0; j < hold; j++ {
+			if j >= maxPowerHold {
+				watts = 0
+			}
+			out = append(out, watts)
+		}
+	}
+	return out
+}
+
+// PowerCurve returns the peak average power for each duration, defaulting to
+// DefaultPowerCurveDurations. Durations longer than the activity are omitted.
+func PowerCurve(samples []api.PowerSample, durations ...time.Duration) []PowerCurvePoint {
+	if len(durations) == 0 {
+		durations = DefaultPowerCurveDurations
+	}
+	watts := SecondlyPower(samples)
+
+	var curve []PowerCurvePoint
+	for _, d := range durations {
+		window := int(d / time.Second)
+		if window < 1 || window > len(watts) {
+			continue
+		}
+		var sum, best float64
+		for i, w := range watts {
+			sum += w
+			if i >= window {
+				sum -= watts[i-window]
+			}
+			if i >= window-1 && sum > best {
+				best = sum
+			}
+		}
+		curve = append(curve, PowerCurvePoint{Duration: d, Watts: best / float64(window)})
+	}
+	return curve
+}
+
+// NormalizedPower computes normalized power: the fourth root of the mean of the
+// fourth powers of the 30-second rolling average. Rides shorter than 30 seconds
+// return 0.
+func NormalizedPower(samples []api.PowerSample) float64 {
+	const window = 30
+	watts := SecondlyPower(samples)
+	if len(watts) < window {
+		return 0
+	}
+
+	var sum, total float64
+	var n int
+	for i, w := range watts {
+		sum += w
+		if i >= window {
+			sum -= watts[i-window]
+		}
+		if i >= window-1 {
+			total += math.Pow(sum/window, 4)
+			n++
+		}
+	}
+	return math.Pow(total/float64(n), 0.25)
+}
+
+// IntensityFactor returns normalized power relative to functional threshold power
+func IntensityFactor(normalizedPower, ftp float64) float64 {
+	if ftp <= 0 {
+		return 0
+	}
+	return normalizedPower / ftp
+}
+
+// TrainingStress returns the TSS-equivalent load of a ride, where 100 equals one
+// hour at functional threshold power
+func TrainingStress(duration time.Duration, normalizedPower, ftp float64) float64 {
+	if ftp <= 0 {
+		return 0
+	}
+	intensity := IntensityFactor(normalizedPower, ftp)
+	return duration.Seconds() * normalizedPower * intensity / (ftp * 3600) * 100
+}
@@ -0,0 +1,221 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/jsonfile"
+)
+
+// Effort is a best effort tracked as a personal record: the fastest time over
+// a distance, or the highest average power held for a duration. Records are
+// kept per sport, so a ride never beats a run.
+type Effort struct {
+	Name     string        `json:"name"`
+	Distance float64       `json:"distance,omitempty"` // meters
+	Duration time.Duration `json:"duration,omitempty"`
+	// Sports lists the activity types the effort is tracked for; empty means
+	// all of them
+	Sports []string `json:"sports,omitempty"`
+}
+
+// RunningSports are the activity types the default distance efforts are
+// tracked for
+var RunningSports = []string{"running", "trail_running", "treadmill_running", "track_running", "street_running", "virtual_run"}
+
+// DefaultEfforts are the efforts tracked when none are given
+var DefaultEfforts = []Effort{
+	{Name: "1K", Distance: 1000, Sports: RunningSports},
+	{Name: "5K", Distance: 5000, Sports: RunningSports},
+	{Name: "10K", Distance: 10000, Sports: RunningSports},
+	{Name: "20min power", Duration: 20 * time.Minute},
+}
+
+// tracks reports whether e is tracked for sport
+func (e Effort) tracks(sport string) bool {
+	if len(e.Sports) == 0 {
+		return true
+	}
+	for _, s := range e.Sports {
+		if s == sport {
+			return true
+		}
+	}
+	return false
+}
+
+// BestEffort is the best performance of one activity for an effort
+type BestEffort struct {
+	Effort
+	Sport      string        `json:"sport"` // the activity type, e.g. "running"
+	ActivityID int64         `json:"activityId"`
+	Date       time.Time     `json:"date"`
+	Time       time.Duration `json:"time,omitempty"`  // distance efforts
+	Watts      float64       `json:"watts,omitempty"` // power efforts
+}
+
+// Key identifies the record e competes for, e.g. "running/5K"
+func (e BestEffort) Key() string {
+	return e.Sport + "/" + e.Name
+}
+
+// Beats reports whether e is better than other for the same effort
+func (e BestEffort) Beats(other BestEffort) bool {
+	if e.Distance > 0 {
+		return e.Time < other.Time
+	}
+	return e.Watts > other.Watts
+}
+
+// BestEfforts finds the efforts, defaulting to DefaultEfforts, in the data of
+// one activity of the given sport. Efforts not tracked for the sport, longer
+// than the activity, or needing power it did not record, are omitted.
+func BestEfforts(activityID int64, sport string, data ActivityData, efforts ...Effort) []BestEffort {
+	if len(efforts) == 0 {
+		efforts = DefaultEfforts
+	}
+	var date time.Time
+	if len(data.Track) > 0 {
+		date = data.Track[0].Timestamp.Time
+	} else if len(data.Power) > 0 {
+		date = data.Power[0].Timestamp.Time
+	}
+
+	var s series
+	if len(data.Track) > 0 {
+		s = newSeries(data)
+	}
+	var best []BestEffort
+	for _, e := range efforts {
+		if !e.tracks(sport) {
+			continue
+		}
+		b := BestEffort{Effort: e, Sport: sport, ActivityID: activityID, Date: date}
+		switch {
+		case e.Distance > 0:
+			seconds, ok := s.fastest(e.Distance)
+			if !ok {
+				continue
+			}
+			b.Time = time.Duration(seconds * float64(time.Second))
+		case e.Duration > 0:
+			curve := PowerCurve(data.Power, e.Duration)
+			if len(curve) == 0 || curve[0].Watts == 0 {
+				continue
+			}
+			b.Watts = curve[0].Watts
+		default:
+			continue
+		}
+		best = append(best, b)
+	}
+	return best
+}
+
+// fastest returns the shortest time in seconds s took to cover meters, with the
+// start of each window interpolated between points
+func (s series) fastest(meters float64) (float64, bool) {
+	if len(s.distance) == 0 || s.distance[len(s.distance)-1] < meters {
+		return 0, false
+	}
+	best, found := 0.0, false
+	for j, d := range s.distance {
+		if d < meters {
+			continue
+		}
+		seconds := s.elapsed[j] - interpolate(s.distance, s.elapsed, d-meters)
+		if !found || seconds < best {
+			best, found = seconds, true
+		}
+	}
+	return best, found
+}
+
+// PersonalRecord is a best effort that beat the previous record, if any
+type PersonalRecord struct {
+	BestEffort
+	Previous *BestEffort
+}
+
+// RecordBook keeps the personal record for every sport and effort in a JSON
+// file, keyed by BestEffort.Key
+type RecordBook struct {
+	path string
+
+	mu      sync.Mutex
+	records map[string]BestEffort
+}
+
+// OpenRecordBook loads the records stored at path; a missing file has no
+// records. An empty path keeps the records in memory only.
+func OpenRecordBook(path string) (*RecordBook, error) {
+	b := &RecordBook{path: path, records: make(map[string]BestEffort)}
+	if path == "" {
+		return b, nil
+	}
+	var stored map[string]BestEffort
+	if err := jsonfile.Load(path, &stored); err != nil {
+		return nil, fmt.Errorf("failed to load record book: %w", err)
+	}
+	// Books written before records were kept per sport are keyed by effort
+	// name; their records keep an empty sport and no longer compete
+	for _, r := range stored {
+		b.records[r.Key()] = r
+	}
+	return b, nil
+}
+
+// Records returns the current records sorted by sport and effort name
+func (b *RecordBook) Records() []BestEffort {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	records := make([]BestEffort, 0, len(b.records))
+	for _, r := range b.records {
+		records = append(records, r)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Sport != records[j].Sport {
+			return records[i].Sport < records[j].Sport
+		}
+		return records[i].Name < records[j].Name
+	})
+	return records
+}
+
+// Update stores the efforts that beat the current records and returns them.
+// Checking the same activity again returns nothing, as it no longer beats
+// itself. The book is saved when a record fell.
+func (b *RecordBook) Update(efforts []BestEffort) ([]PersonalRecord, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var fallen []PersonalRecord
+	for _, e := range efforts {
+		pr := PersonalRecord{BestEffort: e}
+		if current, ok := b.records[e.Key()]; ok {
+			if !e.Beats(current) {
+				continue
+			}
+			pr.Previous = &current
+		}
+		b.records[e.Key()] = e
+		fallen = append(fallen, pr)
+	}
+	if len(fallen) == 0 {
+		return nil, nil
+	}
+	return fallen, b.saveLocked()
+}
+
+// saveLocked writes the book atomically. The caller must hold b.mu.
+func (b *RecordBook) saveLocked() error {
+	if b.path == "" {
+		return nil
+	}
+	if err := jsonfile.Save(b.path, b.records); err != nil {
+		return fmt.Errorf("failed to save record book: %w", err)
+	}
+	return nil
+}
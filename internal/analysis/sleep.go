@@ -0,0 +1,55 @@
+package analysis
+
+import (
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// SleepSummary holds nightly sleep averages over a period
+type SleepSummary struct {
+	Nights        int           `json:"nights"`
+	AvgDuration   time.Duration `json:"avgDuration"`
+	AvgDeepSleep  time.Duration `json:"avgDeepSleep"`
+	AvgLightSleep time.Duration `json:"avgLightSleep"`
+	AvgRemSleep   time.Duration `json:"avgRemSleep"`
+	AvgScore      float64       `json:"avgScore"`
+}
+
+// AverageSleep averages the nights that contain recorded sleep.
+// Nights without any sleep time are skipped so missing data doesn't drag averages
+// down, and the score is averaged over the nights that have one.
+func AverageSleep(nights []api.SleepData) SleepSummary {
+	var summary SleepSummary
+	var total, deep, light, rem, score, scored int
+	for _, n := range nights {
+		if api.Value(n.SleepTimeSeconds) <= 0 {
+			continue
+		}
+		summary.Nights++
+		total += *n.SleepTimeSeconds
+		deep += api.Value(n.DeepSleepSeconds)
+		light += api.Value(n.LightSleepSeconds)
+		rem += api.Value(n.RemSleepSeconds)
+		if n.SleepScore != nil {
+			score += *n.SleepScore
+			scored++
+		}
+	}
+
+	if summary.Nights == 0 {
+		return summary
+	}
+
+	avg := func(seconds int) time.Duration {
+		return time.Duration(seconds/summary.Nights) * time.Second
+	}
+	summary.AvgDuration = avg(total)
+	summary.AvgDeepSleep = avg(deep)
+	summary.AvgLightSleep = avg(light)
+	summary.AvgRemSleep = avg(rem)
+	if scored > 0 {
+		summary.AvgScore = float64(score) / float64(scored)
+	}
+	return summary
+}
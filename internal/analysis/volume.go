@@ -0,0 +1,59 @@
+package analysis
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// SportVolume holds the accumulated training volume for a single sport
+type SportVolume struct {
+	Sport    string        `json:"sport"`
+	Count    int           `json:"count"`
+	Duration time.Duration `json:"duration"`
+	Distance float64       `json:"distance"` // in meters
+}
+
+// VolumeBySport groups activities by type and totals their count, duration and distance.
+// Results are ordered by duration, longest first.
+func VolumeBySport(activities []api.Activity) []SportVolume {
+	totals := make(map[string]*SportVolume)
+	for _, a := range activities {
+		sport := a.Type
+		if sport == "" {
+			sport = "other"
+		}
+		v, ok := totals[sport]
+		if !ok {
+			v = &SportVolume{Sport: sport}
+			totals[sport] = v
+		}
+		v.Count++
+		v.Duration += time.Duration(a.Duration * float64(time.Second))
+		v.Distance += a.Distance
+	}
+
+	volumes := make([]SportVolume, 0, len(totals))
+	for _, v := range totals {
+		volumes = append(volumes, *v)
+	}
+	sort.Slice(volumes, func(i, j int) bool {
+		if volumes[i].Duration == volumes[j].Duration {
+			return volumes[i].Sport < volumes[j].Sport
+		}
+		return volumes[i].Duration > volumes[j].Duration
+	})
+	return volumes
+}
+
+// TotalVolume sums the volume across all sports
+func TotalVolume(volumes []SportVolume) SportVolume {
+	total := SportVolume{Sport: "total"}
+	for _, v := range volumes {
+		total.Count += v.Count
+		total.Duration += v.Duration
+		total.Distance += v.Distance
+	}
+	return total
+}
@@ -0,0 +1,115 @@
+package analysis
+
+import (
+	"sort"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/daterange"
+)
+
+// ZoneTime holds the total time spent in a single heart rate zone
+type ZoneTime struct {
+	Zone     int           `json:"zone"`
+	Duration time.Duration `json:"duration"`
+}
+
+// SumHRZones combines the per-activity heart rate zone data into one distribution,
+// ordered by zone number
+func SumHRZones(activities ...[]api.HRZone) []ZoneTime {
+	totals := make(map[int]time.Duration)
+	for _, zones := range activities {
+		for _, z := range zones {
+			totals[z.ZoneNumber] += time.Duration(z.SecsInZone * float64(time.Second))
+		}
+	}
+
+	result := make([]ZoneTime, 0, len(totals))
+	for zone, d := range totals {
+		result = append(result, ZoneTime{Zone: zone, Duration: d})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Zone < result[j].Zone })
+	return result
+}
+
+// SumPowerZones combines the per-activity power zone data into one
+// distribution, ordered by zone number
+func SumPowerZones(activities ...[]api.PowerZone) []ZoneTime {
+	hr := make([][]api.HRZone, 0, len(activities))
+	for _, zones := range activities {
+		converted := make([]api.HRZone, 0, len(zones))
+		for _, z := range zones {
+			converted = append(converted, api.HRZone{ZoneNumber: z.ZoneNumber, SecsInZone: z.SecsInZone})
+		}
+		hr = append(hr, converted)
+	}
+	return SumHRZones(hr...)
+}
+
+// ActivityZones is the time in zone recorded by one activity, from either
+// SumHRZones or SumPowerZones
+type ActivityZones struct {
+	Start time.Time
+	Zones []ZoneTime
+}
+
+// ZoneDistribution is the time in zone accumulated over one period
+type ZoneDistribution struct {
+	Start time.Time     `json:"start"`
+	End   time.Time     `json:"end"`
+	Zones []ZoneTime    `json:"zones"`
+	Total time.Duration `json:"total"`
+}
+
+// Share returns the fraction of the period's zone time spent in zone
+func (d ZoneDistribution) Share(zone int) float64 {
+	if d.Total == 0 {
+		return 0
+	}
+	for _, z := range d.Zones {
+		if z.Zone == zone {
+			return float64(z.Duration) / float64(d.Total)
+		}
+	}
+	return 0
+}
+
+// ZonesByPeriod totals the time in zone of the activities starting in each
+// period, e.g. from daterange.Weeks or daterange.Months. Periods without
+// activities are kept with no zones so charts show the gap.
+func ZonesByPeriod(activities []ActivityZones, periods []daterange.Range) []ZoneDistribution {
+	dists := make([]ZoneDistribution, 0, len(periods))
+	for _, p := range periods {
+		loc := p.Start.Location()
+		totals := make(map[int]time.Duration)
+		for _, a := range activities {
+			y, m, d := a.Start.Date()
+			day := time.Date(y, m, d, 0, 0, 0, 0, loc)
+			if day.Before(p.Start) || day.After(p.End) {
+				continue
+			}
+			for _, z := range a.Zones {
+				totals[z.Zone] += z.Duration
+			}
+		}
+
+		dist := ZoneDistribution{Start: p.Start, End: p.End, Zones: make([]ZoneTime, 0, len(totals))}
+		for zone, d := range totals {
+			dist.Zones = append(dist.Zones, ZoneTime{Zone: zone, Duration: d})
+			dist.Total += d
+		}
+		sort.Slice(dist.Zones, func(i, j int) bool { return dist.Zones[i].Zone < dist.Zones[j].Zone })
+		dists = append(dists, dist)
+	}
+	return dists
+}
+
+// WeeklyZones totals time in zone per Monday-to-Sunday week from start to end
+func WeeklyZones(activities []ActivityZones, start, end time.Time) []ZoneDistribution {
+	return ZonesByPeriod(activities, daterange.Weeks(start, end, time.Monday))
+}
+
+// MonthlyZones totals time in zone per calendar month from start to end
+func MonthlyZones(activities []ActivityZones, start, end time.Time) []ZoneDistribution {
+	return ZonesByPeriod(activities, daterange.Months(start, end))
+}
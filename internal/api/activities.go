@@ -3,12 +3,8 @@ package api
 import (
 	"bytes"
 	"context"
-	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
-	"net/url"
-	"strconv"
 	"time"
 
 	"github.com/sstent/go-garminconnect/internal/fit"
@@ -16,16 +12,25 @@ import (
 
 // Activity represents a Garmin Connect activity
 type Activity struct {
-	ActivityID int64     `json:"activityId"`
-	Name       string    `json:"activityName"`
-	Type       string    `json:"activityType"`
-	StartTime  time.Time `json:"startTimeLocal"`
-	Duration   float64   `json:"duration"`
-	Distance   float64   `json:"distance"`
+	ActivityID int64      `json:"activityId"`
+	Name       string     `json:"activityName"`
+	Type       string     `json:"activityType"`
+	StartTime  GarminTime `json:"startTimeLocal"`
+	Duration   float64    `json:"duration"`
+	Distance   float64    `json:"distance"`
+	// Description and Location are set by the user or from the start point,
+	// e.g. "Zurich"; both are often empty
+	Description string `json:"description,omitempty"`
+	Location    string `json:"locationName,omitempty"`
+	// TrainingLoad is the EPOC-based load used for acute/chronic load. The
+	// activity list returns it, so computing load needs no detail requests;
+	// it is nil for activities recorded without a compatible device.
+	TrainingLoad *float64 `json:"activityTrainingLoad,omitempty"`
 }
 
 // ActivityDetail represents comprehensive activity data
 type ActivityDetail struct {
+	RawJSON
 	Activity
 	Calories      float64         `json:"calories"`
 	AverageHR     int             `json:"averageHR"`
@@ -36,40 +41,29 @@ type ActivityDetail struct {
 	Weather       Weather         `json:"weather"`
 	Gear          Gear            `json:"gear"`
 	GPSTracks     []GPSTrackPoint `json:"gpsTracks"`
-}
-
-// garminTime implements custom JSON unmarshaling for Garmin's time format
-type garminTime struct {
-	time.Time
-}
-
-const garminTimeLayout = "2006-01-02T15:04:05"
-
-func (gt *garminTime) UnmarshalJSON(data []byte) error {
-	var s string
-	if err := json.Unmarshal(data, &s); err != nil {
-		return err
-	}
-	t, err := time.Parse(garminTimeLayout, s)
-	if err != nil {
-		return err
-	}
-	gt.Time = t
-	return nil
+	TrainingEffect
+	RunningDynamics
+	CyclingPower
+	SwimSummary
 }
 
 // ActivityResponse is used for JSON unmarshaling with custom time handling
 type ActivityResponse struct {
-	ActivityID int64      `json:"activityId"`
-	Name       string     `json:"activityName"`
-	Type       string     `json:"activityType"`
-	StartTime  garminTime `json:"startTimeLocal"`
-	Duration   float64    `json:"duration"`
-	Distance   float64    `json:"distance"`
+	ActivityID  int64      `json:"activityId"`
+	Name        string     `json:"activityName"`
+	Type        string     `json:"activityType"`
+	StartTime   GarminTime `json:"startTimeLocal"`
+	Duration    float64    `json:"duration"`
+	Distance    float64    `json:"distance"`
+	Description string     `json:"description,omitempty"`
+	Location    string     `json:"locationName,omitempty"`
+	// TrainingLoad is returned by both the list and the detail endpoint
+	TrainingLoad *float64 `json:"activityTrainingLoad,omitempty"`
 }
 
 // ActivityDetailResponse is used for JSON unmarshaling with custom time handling
 type ActivityDetailResponse struct {
+	RawJSON
 	ActivityResponse
 	Calories      float64         `json:"calories"`
 	AverageHR     int             `json:"averageHR"`
@@ -80,40 +74,57 @@ type ActivityDetailResponse struct {
 	Weather       Weather         `json:"weather"`
 	Gear          Gear            `json:"gear"`
 	GPSTracks     []GPSTrackPoint `json:"gpsTracks"`
+	TrainingEffect
+	RunningDynamics
+	CyclingPower
+	SwimSummary
 }
 
-// Convert to ActivityDetail
-func (adr *ActivityDetailResponse) ToActivityDetail() ActivityDetail {
+// ToActivityDetail converts the response, placing the local start time in loc,
+// the user's time zone; a nil loc keeps it as UTC wall clock
+func (adr *ActivityDetailResponse) ToActivityDetail(loc *time.Location) ActivityDetail {
 	return ActivityDetail{
+		RawJSON: adr.RawJSON,
 		Activity: Activity{
-			ActivityID: adr.ActivityID,
-			Name:       adr.Name,
-			Type:       adr.Type,
-			StartTime:  adr.StartTime.Time,
-			Duration:   adr.Duration,
-			Distance:   adr.Distance,
+			ActivityID:   adr.ActivityID,
+			Name:         adr.Name,
+			Type:         adr.Type,
+			StartTime:    adr.StartTime.Attach(loc),
+			Duration:     adr.Duration,
+			Distance:     adr.Distance,
+			Description:  adr.Description,
+			Location:     adr.Location,
+			TrainingLoad: adr.TrainingLoad,
 		},
-		Calories:      adr.Calories,
-		AverageHR:     adr.AverageHR,
-		MaxHR:         adr.MaxHR,
-		AverageTemp:   adr.AverageTemp,
-		ElevationGain: adr.ElevationGain,
-		ElevationLoss: adr.ElevationLoss,
-		Weather:       adr.Weather,
-		Gear:          adr.Gear,
-		GPSTracks:     adr.GPSTracks,
+		Calories:        adr.Calories,
+		AverageHR:       adr.AverageHR,
+		MaxHR:           adr.MaxHR,
+		AverageTemp:     adr.AverageTemp,
+		ElevationGain:   adr.ElevationGain,
+		ElevationLoss:   adr.ElevationLoss,
+		Weather:         adr.Weather,
+		Gear:            adr.Gear,
+		GPSTracks:       adr.GPSTracks,
+		TrainingEffect:  adr.TrainingEffect,
+		RunningDynamics: adr.RunningDynamics,
+		CyclingPower:    adr.CyclingPower,
+		SwimSummary:     adr.SwimSummary,
 	}
 }
 
-// Convert to Activity
-func (ar *ActivityResponse) ToActivity() Activity {
+// ToActivity converts the response, placing the local start time in loc, the
+// user's time zone; a nil loc keeps it as UTC wall clock
+func (ar *ActivityResponse) ToActivity(loc *time.Location) Activity {
 	return Activity{
-		ActivityID: ar.ActivityID,
-		Name:       ar.Name,
-		Type:       ar.Type,
-		StartTime:  ar.StartTime.Time,
-		Duration:   ar.Duration,
-		Distance:   ar.Distance,
+		ActivityID:   ar.ActivityID,
+		Name:         ar.Name,
+		Type:         ar.Type,
+		StartTime:    ar.StartTime.Attach(loc),
+		Duration:     ar.Duration,
+		Distance:     ar.Distance,
+		Description:  ar.Description,
+		Location:     ar.Location,
+		TrainingLoad: ar.TrainingLoad,
 	}
 }
 
@@ -132,34 +143,21 @@ type Gear struct {
 	Description string `json:"description"`
 }
 
-// GPSTrackPoint contains geo coordinates
-type GPSTrackPoint struct {
-	Lat       float64   `json:"lat"`
-	Lon       float64   `json:"lon"`
-	Ele       float64   `json:"ele"`
-	Timestamp time.Time `json:"timestamp"`
+// TrainingEffect describes the physiological impact of an activity. Fields are
+// nil for activities recorded without a compatible device.
+type TrainingEffect struct {
+	AerobicEffect       *float64 `json:"aerobicTrainingEffect,omitempty"`   // 0.0-5.0
+	AnaerobicEffect     *float64 `json:"anaerobicTrainingEffect,omitempty"` // 0.0-5.0
+	TrainingEffectLabel string   `json:"trainingEffectLabel,omitempty"`     // e.g. "TEMPO", "VO2MAX"
+	EPOC                *float64 `json:"epoc,omitempty"`                    // excess post-exercise oxygen consumption, ml/kg
 }
 
-func (gtp *GPSTrackPoint) UnmarshalJSON(data []byte) error {
-	type Alias GPSTrackPoint
-	aux := &struct {
-		Timestamp string `json:"timestamp"`
-		*Alias
-	}{
-		Alias: (*Alias)(gtp),
-	}
-	if err := json.Unmarshal(data, &aux); err != nil {
-		return err
-	}
-
-	if aux.Timestamp != "" {
-		t, err := time.Parse(garminTimeLayout, aux.Timestamp)
-		if err != nil {
-			return err
-		}
-		gtp.Timestamp = t
-	}
-	return nil
+// GPSTrackPoint contains geo coordinates
+type GPSTrackPoint struct {
+	Lat       float64    `json:"lat"`
+	Lon       float64    `json:"lon"`
+	Ele       float64    `json:"ele"`
+	Timestamp GarminTime `json:"timestamp"`
 }
 
 // ActivitiesResponse represents the response from the activities endpoint
@@ -175,45 +173,77 @@ type Pagination struct {
 	Page       int `json:"page"`
 }
 
-// GetActivities retrieves a list of activities with pagination
-func (c *Client) GetActivities(ctx context.Context, page int, pageSize int) ([]Activity, *Pagination, error) {
+// GetActivities retrieves one page of the activity list
+func (c *Client) GetActivities(ctx context.Context, req PageRequest, opts ...RequestOption) ([]Activity, *Pagination, error) {
 	path := "/activitylist-service/activities/search"
-	params := url.Values{}
-	params.Add("page", strconv.Itoa(page))
-	params.Add("pageSize", strconv.Itoa(pageSize))
 
 	var response ActivitiesResponse
-	err := c.Get(ctx, fmt.Sprintf("%s?%s", path, params.Encode()), &response)
+	err := c.Get(ctx, fmt.Sprintf("%s?%s", path, req.values().Encode()), &response, opts...)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get activities: %w", err)
 	}
 
 	// Convert response to Activity slice
+	loc := c.localZone(ctx)
 	activities := make([]Activity, len(response.Activities))
 	for i, ar := range response.Activities {
-		activities[i] = ar.ToActivity()
+		activities[i] = ar.ToActivity(loc)
 	}
 
-	// Return empty slice if no activities found, but don't treat as error
-	// The pagination info will indicate totalCount = 0
-	if len(activities) == 0 {
-		return activities, &response.Pagination, nil
+	// An empty page is not an error; the pagination info will indicate totalCount = 0
+	return activities, &response.Pagination, nil
+}
+
+// GetActivitiesByDate retrieves every activity started between start and end (inclusive),
+// following pagination until the result set is exhausted
+func (c *Client) GetActivitiesByDate(ctx context.Context, start, end time.Time, opts ...RequestOption) ([]Activity, error) {
+	loc := c.localZone(ctx)
+	var activities []Activity
+	err := activitySearchPages(start, end, func(path string, page PageRequest) (bool, error) {
+		var response ActivitiesResponse
+		if err := c.Get(ctx, path, &response, opts...); err != nil {
+			return false, fmt.Errorf("failed to get activities: %w", err)
+		}
+
+		for _, ar := range response.Activities {
+			activities = append(activities, ar.ToActivity(loc))
+		}
+
+		total := response.Pagination.TotalCount
+		return len(response.Activities) >= page.PageSize && (total == 0 || len(activities) < total), nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return activities, nil
+}
 
-	return activities, &response.Pagination, nil
+// activitySearchPages calls fetch with the path of each page of activities
+// started between start and end (inclusive) until it reports no more pages
+func activitySearchPages(start, end time.Time, fetch func(path string, page PageRequest) (more bool, err error)) error {
+	for page := FirstPage(MaxPageSize); ; page = page.Next() {
+		params := page.values()
+		params.Add("startDate", start.Format("2006-01-02"))
+		params.Add("endDate", end.Format("2006-01-02"))
+
+		more, err := fetch("/activitylist-service/activities/search?"+params.Encode(), page)
+		if err != nil || !more {
+			return err
+		}
+	}
 }
 
 // GetActivityDetails retrieves comprehensive data for a specific activity
-func (c *Client) GetActivityDetails(ctx context.Context, activityID int64) (*ActivityDetail, error) {
+func (c *Client) GetActivityDetails(ctx context.Context, activityID int64, opts ...RequestOption) (*ActivityDetail, error) {
 	path := fmt.Sprintf("/activity-service/activity/%d", activityID)
 
 	var response ActivityDetailResponse
-	err := c.Get(ctx, path, &response)
+	err := c.Get(ctx, path, &response, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get activity details: %w", err)
 	}
 
-	activityDetail := response.ToActivityDetail()
+	activityDetail := response.ToActivityDetail(c.localZone(ctx))
 
 	// Validate we received activity data
 	if activityDetail.ActivityID == 0 {
@@ -223,13 +253,68 @@ func (c *Client) GetActivityDetails(ctx context.Context, activityID int64) (*Act
 	return &activityDetail, nil
 }
 
-// UploadActivity handles FIT file uploads
-func (c *Client) UploadActivity(ctx context.Context, fitFile []byte) (int64, error) {
+// HRZone represents the time spent in a single heart rate zone during an activity
+type HRZone struct {
+	ZoneNumber      int     `json:"zoneNumber"`
+	SecsInZone      float64 `json:"secsInZone"`
+	ZoneLowBoundary int     `json:"zoneLowBoundary"` // bpm
+}
+
+// GetActivityHRZones retrieves the time spent in each heart rate zone for an activity
+func (c *Client) GetActivityHRZones(ctx context.Context, activityID int64, opts ...RequestOption) ([]HRZone, error) {
+	path := fmt.Sprintf("/activity-service/activity/%d/hrTimeInZones", activityID)
+
+	var zones []HRZone
+	if err := c.Get(ctx, path, &zones, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get activity HR zones: %w", err)
+	}
+	return zones, nil
+}
+
+// PowerZone represents the time spent in a single power zone during an activity
+type PowerZone struct {
+	ZoneNumber      int     `json:"zoneNumber"`
+	SecsInZone      float64 `json:"secsInZone"`
+	ZoneLowBoundary int     `json:"zoneLowBoundary"` // watts
+}
+
+// GetActivityPowerZones retrieves the time spent in each power zone for an
+// activity; it is empty for activities recorded without a power meter
+func (c *Client) GetActivityPowerZones(ctx context.Context, activityID int64, opts ...RequestOption) ([]PowerZone, error) {
+	path := fmt.Sprintf("/activity-service/activity/%d/powerTimeInZones", activityID)
+
+	var zones []PowerZone
+	if err := c.Get(ctx, path, &zones, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get activity power zones: %w", err)
+	}
+	return zones, nil
+}
+
+// DeleteActivity deletes an activity from the account. It can't be undone.
+func (c *Client) DeleteActivity(ctx context.Context, activityID int64, opts ...RequestOption) error {
+	path := fmt.Sprintf("/activity-service/activity/%d", activityID)
+	if err := c.send(ctx, http.MethodDelete, path, nil, nil, opts); err != nil {
+		return fmt.Errorf("failed to delete activity %d: %w", activityID, err)
+	}
+	return nil
+}
+
+// UploadActivity handles FIT file uploads. With WithUploadNaming the new
+// activity is renamed afterwards. With WithUploadLog a file that was uploaded
+// before returns the original activity ID without uploading again.
+func (c *Client) UploadActivity(ctx context.Context, fitFile []byte, opts ...RequestOption) (int64, error) {
 	// Validate FIT file
 	if err := fit.ValidateFIT(fitFile); err != nil {
 		return 0, fmt.Errorf("invalid FIT file: %w", err)
 	}
 
+	hash := UploadHash(fitFile)
+	if c.uploads != nil {
+		if id, ok := c.uploads.Lookup(hash); ok {
+			return id, nil
+		}
+	}
+
 	// Refresh token if needed
 	if err := c.refreshTokenIfNeeded(); err != nil {
 		return 0, err
@@ -237,8 +322,10 @@ func (c *Client) UploadActivity(ctx context.Context, fitFile []byte) (int64, err
 
 	path := "/upload-service/upload/.fit"
 
-	resp, err := c.HTTPClient.R().
-		SetContext(ctx).
+	req, cancel := c.newRequest(ctx, opts)
+	defer cancel()
+
+	resp, err := req.
 		SetFileReader("file", "activity.fit", bytes.NewReader(fitFile)).
 		SetHeader("Content-Type", "multipart/form-data").
 		Post(path)
@@ -248,26 +335,49 @@ func (c *Client) UploadActivity(ctx context.Context, fitFile []byte) (int64, err
 	}
 
 	if resp.StatusCode() == http.StatusUnauthorized {
-		return 0, errors.New("token expired, please reauthenticate")
+		return 0, unauthorizedError(resp)
+	}
+
+	// An earlier attempt that timed out may have been imported after all
+	if resp.StatusCode() == http.StatusConflict && c.uploads != nil {
+		if id, ok := duplicateActivityID(resp.Body(), c.codec); ok {
+			if err := c.uploads.Record(hash, id); err != nil {
+				return id, err
+			}
+			return id, nil
+		}
 	}
 
 	if resp.StatusCode() >= 400 {
-		return 0, handleAPIError(resp)
+		return 0, c.handleAPIError(resp)
 	}
 
 	// Parse response to get activity ID
 	var result struct {
 		ActivityID int64 `json:"activityId"`
 	}
-	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+	if err := c.codec.Unmarshal(resp.Body(), &result); err != nil {
 		return 0, err
 	}
 
+	if c.uploads != nil {
+		if err := c.uploads.Record(hash, result.ActivityID); err != nil {
+			return result.ActivityID, err
+		}
+	}
+
+	// A failed rename leaves the upload in place, so the ID is still returned
+	if len(c.naming) > 0 {
+		if err := c.renameUpload(ctx, result.ActivityID, opts); err != nil {
+			return result.ActivityID, fmt.Errorf("activity %d uploaded but renaming failed: %w", result.ActivityID, err)
+		}
+	}
+
 	return result.ActivityID, nil
 }
 
 // DownloadActivity retrieves a FIT file for an activity
-func (c *Client) DownloadActivity(ctx context.Context, activityID int64) ([]byte, error) {
+func (c *Client) DownloadActivity(ctx context.Context, activityID int64, opts ...RequestOption) ([]byte, error) {
 	// Refresh token if needed
 	if err := c.refreshTokenIfNeeded(); err != nil {
 		return nil, err
@@ -275,8 +385,10 @@ func (c *Client) DownloadActivity(ctx context.Context, activityID int64) ([]byte
 
 	path := fmt.Sprintf("/download-service/export/activity/%d", activityID)
 
-	resp, err := c.HTTPClient.R().
-		SetContext(ctx).
+	req, cancel := c.newRequest(ctx, opts)
+	defer cancel()
+
+	resp, err := req.
 		SetHeader("Accept", "application/fit").
 		Get(path)
 
@@ -285,11 +397,11 @@ func (c *Client) DownloadActivity(ctx context.Context, activityID int64) ([]byte
 	}
 
 	if resp.StatusCode() == http.StatusUnauthorized {
-		return nil, errors.New("token expired, please reauthenticate")
+		return nil, unauthorizedError(resp)
 	}
 
 	if resp.StatusCode() >= 400 {
-		return nil, handleAPIError(resp)
+		return nil, c.handleAPIError(resp)
 	}
 
 	return resp.Body(), nil
@@ -9,11 +9,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/sstent/go-garminconnect/garmintest"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestActivitiesEndpoints(t *testing.T) {
-	mockServer := NewMockServer()
+	mockServer := garmintest.NewServer()
 	defer mockServer.Close()
 	client := NewClientWithBaseURL(mockServer.URL())
 
@@ -25,7 +26,7 @@ func TestActivitiesEndpoints(t *testing.T) {
 		{
 			name: "GetActivitiesSuccess",
 			setup: func() {
-				mockServer.SetActivitiesHandler(func(w http.ResponseWriter, r *http.Request) {
+				mockServer.Handle(garmintest.Activities, func(w http.ResponseWriter, r *http.Request) {
 					// Create a properly formatted time string for Garmin format
 					timeStr := time.Now().Add(-24 * time.Hour).Format("2006-01-02T15:04:05")
 
@@ -54,7 +55,7 @@ func TestActivitiesEndpoints(t *testing.T) {
 				})
 			},
 			testFunc: func(t *testing.T) {
-				activities, pagination, err := client.GetActivities(context.Background(), 1, 10)
+				activities, pagination, err := client.GetActivities(context.Background(), FirstPage(10))
 				assert.NoError(t, err)
 				assert.Len(t, activities, 1)
 				assert.Equal(t, int64(1), activities[0].ActivityID)
@@ -66,7 +67,7 @@ func TestActivitiesEndpoints(t *testing.T) {
 		{
 			name: "GetActivityDetailsSuccess",
 			setup: func() {
-				mockServer.SetActivityDetailsHandler(func(w http.ResponseWriter, r *http.Request) {
+				mockServer.Handle(garmintest.Activity, func(w http.ResponseWriter, r *http.Request) {
 					pathParts := strings.Split(r.URL.Path, "/")
 					if len(pathParts) < 2 {
 						w.WriteHeader(http.StatusNotFound)
@@ -93,6 +94,11 @@ func TestActivitiesEndpoints(t *testing.T) {
 						"averageHR":      150,
 						"maxHR":          170,
 						"elevationGain":  100.0,
+
+						"aerobicTrainingEffect":   3.4,
+						"anaerobicTrainingEffect": 1.2,
+						"trainingEffectLabel":     "TEMPO",
+						"activityTrainingLoad":    142.8,
 					}
 
 					w.Header().Set("Content-Type", "application/json")
@@ -106,18 +112,23 @@ func TestActivitiesEndpoints(t *testing.T) {
 				assert.Equal(t, int64(1), activity.ActivityID)
 				assert.Equal(t, "Mock Activity", activity.Name)
 				assert.Equal(t, float64(500), activity.Calories)
+				assert.Equal(t, Ptr(3.4), activity.AerobicEffect)
+				assert.Equal(t, Ptr(1.2), activity.AnaerobicEffect)
+				assert.Equal(t, "TEMPO", activity.TrainingEffectLabel)
+				assert.Equal(t, Ptr(142.8), activity.TrainingLoad)
+				assert.Nil(t, activity.EPOC)
 			},
 		},
 		{
 			name: "GetActivitiesServerError",
 			setup: func() {
-				mockServer.SetActivitiesHandler(func(w http.ResponseWriter, r *http.Request) {
+				mockServer.Handle(garmintest.Activities, func(w http.ResponseWriter, r *http.Request) {
 					w.WriteHeader(http.StatusInternalServerError)
 					w.Write([]byte(`{"error": "Internal server error"}`))
 				})
 			},
 			testFunc: func(t *testing.T) {
-				_, _, err := client.GetActivities(context.Background(), 1, 10)
+				_, _, err := client.GetActivities(context.Background(), FirstPage(10))
 				assert.Error(t, err)
 				assert.Contains(t, err.Error(), "failed to get activities")
 			},
@@ -125,7 +136,7 @@ func TestActivitiesEndpoints(t *testing.T) {
 		{
 			name: "GetActivitiesEmptyResponse",
 			setup: func() {
-				mockServer.SetActivitiesHandler(func(w http.ResponseWriter, r *http.Request) {
+				mockServer.Handle(garmintest.Activities, func(w http.ResponseWriter, r *http.Request) {
 					w.Header().Set("Content-Type", "application/json")
 					w.WriteHeader(http.StatusOK)
 					json.NewEncoder(w).Encode(ActivitiesResponse{
@@ -135,7 +146,7 @@ func TestActivitiesEndpoints(t *testing.T) {
 				})
 			},
 			testFunc: func(t *testing.T) {
-				activities, pagination, err := client.GetActivities(context.Background(), 1, 10)
+				activities, pagination, err := client.GetActivities(context.Background(), FirstPage(10))
 				assert.NoError(t, err) // Should not error when no activities exist
 				assert.Len(t, activities, 0)
 				assert.NotNil(t, pagination)
@@ -145,7 +156,7 @@ func TestActivitiesEndpoints(t *testing.T) {
 		{
 			name: "UploadActivitySuccess",
 			setup: func() {
-				mockServer.SetUploadHandler(func(w http.ResponseWriter, r *http.Request) {
+				mockServer.Handle(garmintest.Upload, func(w http.ResponseWriter, r *http.Request) {
 					w.Header().Set("Content-Type", "application/json")
 					w.WriteHeader(http.StatusCreated)
 					json.NewEncoder(w).Encode(map[string]interface{}{"activityId": 12345})
@@ -162,10 +173,103 @@ func TestActivitiesEndpoints(t *testing.T) {
 				assert.Equal(t, int64(12345), id)
 			},
 		},
+		{
+			name: "DeleteActivity",
+			setup: func() {
+				mockServer.Handle(garmintest.Activity, func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusNoContent)
+				})
+			},
+			testFunc: func(t *testing.T) {
+				assert.NoError(t, client.DeleteActivity(context.Background(), 12345))
+				req, ok := mockServer.LastRequest(garmintest.Activity)
+				if assert.True(t, ok) {
+					assert.Equal(t, http.MethodDelete, req.Method)
+					assert.Equal(t, "/activity-service/activity/12345", req.Path)
+				}
+			},
+		},
+		{
+			name: "GetActivitiesByDatePaginates",
+			setup: func() {
+				mockServer.Handle(garmintest.Activities, func(w http.ResponseWriter, r *http.Request) {
+					assert.Equal(t, "2024-03-11", r.URL.Query().Get("startDate"))
+					assert.Equal(t, "2024-03-17", r.URL.Query().Get("endDate"))
+
+					// Serve one full page followed by a partial one
+					page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+					count := 100
+					if page > 1 {
+						count = 20
+					}
+					activities := make([]map[string]interface{}, count)
+					for i := range activities {
+						activities[i] = map[string]interface{}{
+							"activityId":     (page-1)*100 + i + 1,
+							"activityType":   "RUNNING",
+							"startTimeLocal": "2024-03-12T07:00:00",
+						}
+					}
+
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusOK)
+					json.NewEncoder(w).Encode(map[string]interface{}{
+						"activities": activities,
+						"pagination": map[string]interface{}{"page": page, "pageSize": 100, "totalCount": 120},
+					})
+				})
+			},
+			testFunc: func(t *testing.T) {
+				start := time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)
+				activities, err := client.GetActivitiesByDate(context.Background(), start, start.AddDate(0, 0, 6))
+				assert.NoError(t, err)
+				assert.Len(t, activities, 120)
+				assert.Equal(t, int64(120), activities[119].ActivityID)
+				assert.Equal(t, 2, mockServer.RequestCount(garmintest.Activities))
+			},
+		},
+		{
+			name: "GetActivityHRZonesSuccess",
+			setup: func() {
+				mockServer.Handle(garmintest.Activity, func(w http.ResponseWriter, r *http.Request) {
+					assert.True(t, strings.HasSuffix(r.URL.Path, "/activity/42/hrTimeInZones"))
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`[{"zoneNumber":1,"secsInZone":120.5,"zoneLowBoundary":98},{"zoneNumber":2,"secsInZone":1800,"zoneLowBoundary":117}]`))
+				})
+			},
+			testFunc: func(t *testing.T) {
+				zones, err := client.GetActivityHRZones(context.Background(), 42)
+				assert.NoError(t, err)
+				assert.Equal(t, []HRZone{
+					{ZoneNumber: 1, SecsInZone: 120.5, ZoneLowBoundary: 98},
+					{ZoneNumber: 2, SecsInZone: 1800, ZoneLowBoundary: 117},
+				}, zones)
+			},
+		},
+		{
+			name: "GetActivityPowerZonesSuccess",
+			setup: func() {
+				mockServer.Handle(garmintest.Activity, func(w http.ResponseWriter, r *http.Request) {
+					assert.True(t, strings.HasSuffix(r.URL.Path, "/activity/42/powerTimeInZones"))
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`[{"zoneNumber":1,"secsInZone":600,"zoneLowBoundary":0},{"zoneNumber":4,"secsInZone":240,"zoneLowBoundary":250}]`))
+				})
+			},
+			testFunc: func(t *testing.T) {
+				zones, err := client.GetActivityPowerZones(context.Background(), 42)
+				assert.NoError(t, err)
+				assert.Equal(t, []PowerZone{
+					{ZoneNumber: 1, SecsInZone: 600, ZoneLowBoundary: 0},
+					{ZoneNumber: 4, SecsInZone: 240, ZoneLowBoundary: 250},
+				}, zones)
+			},
+		},
 		{
 			name: "GetActivityDetailsNotFound",
 			setup: func() {
-				mockServer.SetActivityDetailsHandler(func(w http.ResponseWriter, r *http.Request) {
+				mockServer.Handle(garmintest.Activity, func(w http.ResponseWriter, r *http.Request) {
 					w.WriteHeader(http.StatusNotFound)
 					w.Write([]byte(`{"error": "Activity not found"}`))
 				})
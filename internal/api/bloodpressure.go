@@ -0,0 +1,33 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// BloodPressure is a single blood pressure reading logged in Garmin Connect,
+// either from an Index BPM monitor or entered manually
+type BloodPressure struct {
+	Timestamp GarminTime `json:"measurementTimestampGMT"`
+	Systolic  int        `json:"systolic"`  // mmHg
+	Diastolic int        `json:"diastolic"` // mmHg
+	Pulse     *int       `json:"pulse"`     // bpm
+	Notes     string     `json:"notes"`
+}
+
+// GetBloodPressure retrieves the blood pressure readings taken between start
+// and end inclusive, splitting ranges longer than the service accepts
+func (c *Client) GetBloodPressure(ctx context.Context, start, end time.Time, opts ...RequestOption) ([]BloodPressure, error) {
+	return fetchSpans(ctx, c, "/bloodpressure-service", start, end, func(ctx context.Context, start, end time.Time) ([]BloodPressure, error) {
+		var response struct {
+			Measurements []BloodPressure `json:"measurements"`
+		}
+		path := fmt.Sprintf("/bloodpressure-service/bloodpressure/range/%s/%s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+
+		if err := c.Get(ctx, path, &response, opts...); err != nil {
+			return nil, fmt.Errorf("failed to get blood pressure: %w", err)
+		}
+		return response.Measurements, nil
+	})
+}
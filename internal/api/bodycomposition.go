@@ -2,31 +2,62 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
+	"time"
 )
 
-// GetBodyComposition retrieves body composition data within a date range
-func (c *Client) GetBodyComposition(ctx context.Context, req BodyCompositionRequest) ([]BodyComposition, error) {
+// weightRange is the response of the weight service's dateRange endpoint
+type weightRange struct {
+	DateWeightList []WeighIn `json:"dateWeightList"`
+}
+
+// WithBodyCompositionFallback controls whether GetBodyComposition falls back
+// to the legacy /body-composition path when the weight service answers 404
+// Not Found, as some proxies and older deployments do. It is enabled by default.
+func WithBodyCompositionFallback(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.bodyCompFallback = enabled
+	}
+}
+
+// GetBodyComposition retrieves body composition data within a date range.
+// Ranges longer than the service accepts are split into several requests.
+func (c *Client) GetBodyComposition(ctx context.Context, req BodyCompositionRequest, opts ...RequestOption) ([]BodyComposition, error) {
 	// Validate date range
-	if req.StartDate.IsZero() || req.EndDate.IsZero() || req.StartDate.After(req.EndDate) {
+	if req.StartDate.IsZero() || req.EndDate.IsZero() || req.StartDate.After(req.EndDate.Time) {
 		return nil, fmt.Errorf("invalid date range: start %s to end %s",
 			req.StartDate.Format("2006-01-02"),
 			req.EndDate.Format("2006-01-02"))
 	}
 
-	// Build query parameters
-	params := url.Values{}
-	params.Add("startDate", req.StartDate.Format("2006-01-02"))
-	params.Add("endDate", req.EndDate.Format("2006-01-02"))
-	path := fmt.Sprintf("/body-composition?%s", params.Encode())
-
-	// Execute GET request
-	var results []BodyComposition
-	err := c.Get(ctx, path, &results)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get body composition: %w", err)
-	}
+	return fetchSpans(ctx, c, "/weight-service", req.StartDate.Time, req.EndDate.Time, func(ctx context.Context, start, end time.Time) ([]BodyComposition, error) {
+		// Build query parameters
+		params := url.Values{}
+		params.Add("startDate", start.Format("2006-01-02"))
+		params.Add("endDate", end.Format("2006-01-02"))
+
+		var weights weightRange
+		err := c.Get(ctx, "/weight-service/weight/dateRange?"+params.Encode(), &weights, opts...)
+		if err == nil {
+			results := make([]BodyComposition, 0, len(weights.DateWeightList))
+			for _, e := range weights.DateWeightList {
+				results = append(results, e.BodyComposition())
+			}
+			return results, nil
+		}
+		if !c.bodyCompFallback || !errors.Is(err, ErrNotFound{}) {
+			return nil, fmt.Errorf("failed to get body composition: %w", err)
+		}
+
+		// Execute GET request against the legacy path
+		var results []BodyComposition
+		err = c.Get(ctx, "/body-composition?"+params.Encode(), &results, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get body composition: %w", err)
+		}
 
-	return results, nil
+		return results, nil
+	})
 }
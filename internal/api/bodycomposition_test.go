@@ -3,17 +3,24 @@ package api
 import (
 	"context"
 	"net/http"
-	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/sstent/go-garminconnect/garmintest"
 	"github.com/sstent/go-garminconnect/internal/auth/garth"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestGetBodyComposition(t *testing.T) {
 	// Create test server for mocking API responses
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		// Only the legacy path is served, exercising the fallback
+		if r.URL.Path != "/body-composition" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
 		// Check for required parameters without enforcing order
 		startDate := r.URL.Query().Get("startDate")
 		endDate := r.URL.Query().Get("endDate")
@@ -44,7 +51,7 @@ func TestGetBodyComposition(t *testing.T) {
 				"timestamp": "2023-01-15T08:00:00Z"
 			}
 		]`))
-	}))
+	})
 	defer server.Close()
 
 	// Test cases
@@ -92,11 +99,11 @@ func TestGetBodyComposition(t *testing.T) {
 			mockAuth := NewMockAuthenticator()
 			client, err := NewClient(mockAuth, session, "")
 			assert.NoError(t, err)
-			client.HTTPClient.SetBaseURL(server.URL)
+			client.HTTPClient.SetBaseURL(server.URL())
 
 			results, err := client.GetBodyComposition(context.Background(), BodyCompositionRequest{
-				StartDate: Time(tc.start),
-				EndDate:   Time(tc.end),
+				StartDate: NewGarminTime(tc.start),
+				EndDate:   NewGarminTime(tc.end),
 			})
 
 			if tc.expectError {
@@ -117,3 +124,52 @@ func TestGetBodyComposition(t *testing.T) {
 		})
 	}
 }
+
+func TestGetBodyCompositionWeightService(t *testing.T) {
+	legacyCalls := 0
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/weight-service/weight/dateRange":
+			// April stands in for a deployment without the weight service
+			if r.URL.Query().Get("startDate") != "2024-03-01" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(`{"startDate": "2024-03-01", "endDate": "2024-03-07", "dateWeightList": [{
+				"samplePk": 1, "calendarDate": "2024-03-02", "weight": 72500.0, "bmi": 22.4,
+				"bodyFat": 15.3, "bodyWater": 58.7, "boneMass": 2800, "muscleMass": 55200,
+				"metabolicAge": 31, "visceralFat": null, "timestampGMT": 1709362800000}]}`))
+		case "/body-composition":
+			legacyCalls++
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+	req := BodyCompositionRequest{
+		StartDate: NewGarminTime(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)),
+		EndDate:   NewGarminTime(time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC)),
+	}
+
+	results, err := client.GetBodyComposition(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, 72500.0, results[0].Weight)
+	assert.Equal(t, 2800.0, results[0].BoneMass)
+	assert.Equal(t, 58.7, results[0].Hydration)
+	assert.Equal(t, 22.4, *results[0].BMI)
+	assert.Equal(t, 31, *results[0].MetabolicAge)
+	assert.Nil(t, results[0].VisceralFat)
+	assert.Equal(t, time.Date(2024, 3, 2, 7, 0, 0, 0, time.UTC), results[0].Timestamp.Time)
+	assert.Equal(t, 0, legacyCalls)
+
+	// Without the fallback a missing weight service is an error
+	req.StartDate = NewGarminTime(time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))
+	req.EndDate = req.StartDate
+	WithBodyCompositionFallback(false)(client)
+	_, err = client.GetBodyComposition(context.Background(), req)
+	assert.ErrorIs(t, err, ErrNotFound{})
+	assert.Equal(t, 0, legacyCalls)
+}
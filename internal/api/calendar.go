@@ -0,0 +1,38 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CalendarItem represents an entry on the Garmin Connect calendar
+type CalendarItem struct {
+	ID           int64   `json:"id"`
+	ItemType     string  `json:"itemType"` // e.g. "activity", "workout", "event"
+	Title        string  `json:"title"`
+	Date         string  `json:"date"` // Store as string in "YYYY-MM-DD" format
+	ActivityType string  `json:"activityTypeKey"`
+	Duration     float64 `json:"duration"` // in seconds
+	Distance     float64 `json:"distance"` // in meters
+	WorkoutID    int64   `json:"workoutId"`
+}
+
+// CalendarMonth represents the calendar service response for one month
+type CalendarMonth struct {
+	Year          int            `json:"year"`
+	Month         int            `json:"month"` // zero-based as returned by Garmin
+	CalendarItems []CalendarItem `json:"calendarItems"`
+}
+
+// GetCalendarMonth retrieves all calendar items (activities, scheduled workouts, events) for a month
+func (c *Client) GetCalendarMonth(ctx context.Context, year int, month time.Month, opts ...RequestOption) ([]CalendarItem, error) {
+	// The calendar service numbers months from zero
+	path := fmt.Sprintf("/calendar-service/year/%d/month/%d", year, int(month)-1)
+
+	var response CalendarMonth
+	if err := c.Get(ctx, path, &response, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get calendar: %w", err)
+	}
+	return response.CalendarItems, nil
+}
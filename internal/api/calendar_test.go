@@ -0,0 +1,42 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCalendarMonth(t *testing.T) {
+	srv := garmintest.NewServer()
+	srv.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/calendar-service/year/2024/month/2": // March, zero-based
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"year": 2024, "month": 2, "calendarItems": [
+				{"id": 11, "itemType": "workout", "title": "Threshold", "date": "2024-03-20", "activityTypeKey": "running", "workoutId": 500},
+				{"id": 12, "itemType": "activity", "title": "Lunch Run", "date": "2024-03-10", "duration": 1800.5, "distance": 5000}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "not found"}`))
+		}
+	})
+	defer srv.Close()
+
+	client := NewClientWithBaseURL(srv.URL())
+
+	items, err := client.GetCalendarMonth(context.Background(), 2024, time.March)
+	assert.NoError(t, err)
+	assert.Equal(t, []CalendarItem{
+		{ID: 11, ItemType: "workout", Title: "Threshold", Date: "2024-03-20", ActivityType: "running", WorkoutID: 500},
+		{ID: 12, ItemType: "activity", Title: "Lunch Run", Date: "2024-03-10", Duration: 1800.5, Distance: 5000},
+	}, items)
+
+	_, err = client.GetCalendarMonth(context.Background(), 2024, time.April)
+	assert.ErrorContains(t, err, "failed to get calendar")
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DailyCalories splits the day's energy expenditure into active and resting
+// (BMR) calories. Values are nil for days the device wasn't worn.
+type DailyCalories struct {
+	RawJSON
+	Date     Date `json:"calendarDate"`
+	Total    *int `json:"totalKilocalories"`
+	Active   *int `json:"activeKilocalories"`
+	BMR      *int `json:"bmrKilocalories"`      // resting calories
+	Consumed *int `json:"consumedKilocalories"` // logged food intake, nil unless tracked
+	Goal     *int `json:"netCalorieGoal"`       // active calorie goal
+}
+
+// GetCalories retrieves the calorie breakdown for a specific date
+func (c *Client) GetCalories(ctx context.Context, date time.Time, opts ...RequestOption) (*DailyCalories, error) {
+	var data DailyCalories
+	path := fmt.Sprintf("/usersummary-service/usersummary/daily/%s", date.Format("2006-01-02"))
+
+	if err := c.Get(ctx, path, &data, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get calories: %w", err)
+	}
+	return &data, nil
+}
+
+// GetCaloriesRange retrieves the calorie breakdown for every day from start to end inclusive
+func (c *Client) GetCaloriesRange(ctx context.Context, start, end time.Time, opts ...RequestOption) ([]DailyCalories, error) {
+	return FetchRange(ctx, start, end, c.rangeOpts, func(ctx context.Context, day time.Time) (DailyCalories, error) {
+		data, err := c.GetCalories(ctx, day, opts...)
+		if err != nil {
+			return DailyCalories{}, err
+		}
+		return *data, nil
+	})
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCalories(t *testing.T) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/usersummary-service/usersummary/daily/2024-03-01":
+			w.Write([]byte(`{"calendarDate": "2024-03-01", "totalKilocalories": 2650, "activeKilocalories": 820,
+				"bmrKilocalories": 1830, "netCalorieGoal": 600}`))
+		default:
+			w.Write([]byte(`{"calendarDate": "2024-03-02"}`))
+		}
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	calories, err := client.GetCalories(context.Background(), start)
+	assert.NoError(t, err)
+	assert.Equal(t, 820, *calories.Active)
+	assert.Equal(t, 1830, *calories.BMR)
+	assert.Equal(t, *calories.Total, *calories.Active+*calories.BMR)
+	assert.Equal(t, 600, *calories.Goal)
+	assert.Nil(t, calories.Consumed)
+
+	days, err := client.GetCaloriesRange(context.Background(), start, start.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	assert.Len(t, days, 2)
+	assert.Nil(t, days[1].Total)
+}
@@ -6,12 +6,17 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/sstent/go-garminconnect/internal/auth/garth"
 )
 
+// DefaultBaseURL is the Garmin Connect API proxy used when no base URL is configured
+const DefaultBaseURL = "https://connect.garmin.com/modern/proxy"
+
 // Authenticator defines the method required for token refresh
 type Authenticator interface {
 	RefreshToken(oauth1Token, oauth1Secret string) (string, error)
@@ -22,10 +27,49 @@ type Client struct {
 	sessionPath string
 	session     *garth.Session
 	auth        Authenticator // Use interface for token refresh
+	rangeOpts   RangeOptions
+	validators  *ValidatorCache
+	keepRaw     bool
+	strict      bool
+	codec       Codec
+	router      *router
+
+	// bodyCompFallback retries body composition on the legacy path
+	bodyCompFallback bool
+	// naming renames uploads, see WithUploadNaming
+	naming NamingTemplates
+	// creds logs in again when tokens are revoked, see WithCredentials
+	creds CredentialsProvider
+	// uploads records uploaded files, see WithUploadLog
+	uploads *UploadLog
+	// drift collects unknown response fields, see WithDriftReporter
+	drift *DriftReporter
+	// classifiers map failed responses to typed errors, see WithErrorClassifier
+	classifiers []ErrorClassifier
+	// validate checks responses against validator tags, see
+	// WithResponseValidation; onInvalid receives failures instead of Get
+	validate  bool
+	onInvalid func(*ValidationError)
+
+	// loc is the user's time zone, loaded lazily by Location
+	loc   *time.Location
+	locMu sync.Mutex
+	// ecg records that a registered device records ECGs, once checkECG has
+	// seen one
+	ecg   bool
+	ecgMu sync.Mutex
+
+	// token is the bearer token sent with every request; it is kept per
+	// client so clones made by WithSession can share HTTPClient
+	token string
+
+	// mu guards session and token while requests run concurrently
+	mu sync.Mutex
 }
 
-// NewClient creates a new API client with session management
-func NewClient(auth Authenticator, session *garth.Session, sessionPath string) (*Client, error) {
+// NewClient creates a new API client with session management.
+// All requests share one resty client and connection pool.
+func NewClient(auth Authenticator, session *garth.Session, sessionPath string, opts ...ClientOption) (*Client, error) {
 	// Try to load session from file if not provided
 	if session == nil && sessionPath != "" {
 		if loadedSession, err := garth.LoadSession(sessionPath); err == nil {
@@ -38,31 +82,90 @@ func NewClient(auth Authenticator, session *garth.Session, sessionPath string) (
 	}
 
 	client := resty.New()
+	client.SetTransport(NewTransport(DefaultTransportConfig()))
+	client.SetBaseURL(DefaultBaseURL)
 	client.SetTimeout(30 * time.Second)
-	client.SetHeader("Authorization", "Bearer "+session.OAuth2Token)
 	client.SetHeader("User-Agent", "go-garminconnect/1.0")
 	client.SetHeader("Content-Type", "application/json")
 	client.SetHeader("Accept", "application/json")
 
-	return &Client{
+	c := &Client{
 		HTTPClient:  client,
 		sessionPath: sessionPath,
 		session:     session,
+		token:       session.OAuth2Token,
 		auth:        auth,
-	}, nil
+		rangeOpts:   DefaultRangeOptions(),
+		codec:       StdCodec,
+		router:      &router{routes: Routes{}},
+
+		bodyCompFallback: true,
+	}
+	client.OnBeforeRequest(c.router.middleware)
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.validators != nil {
+		// Wrap last so the cache sits in front of any custom transport
+		client.SetTransport(c.validators.Transport(client.GetClient().Transport))
+	}
+	return c, nil
+}
+
+// WithSession returns a client for another user that shares this client's
+// HTTPClient, connection pool, validator cache, routes and options but
+// authenticates with session. Servers handling many Garmin users can keep one
+// root client and derive a cheap clone per request. Refreshed tokens of the
+// clone are not persisted; read them back with Session. A nil session gives a
+// client without a token, whose requests fail with Garmin's 401.
+func (c *Client) WithSession(session *garth.Session) *Client {
+	var token string
+	if session != nil {
+		token = session.OAuth2Token
+	}
+	return &Client{
+		HTTPClient: c.HTTPClient,
+		session:    session,
+		token:      token,
+		auth:       c.auth,
+		rangeOpts:  c.rangeOpts,
+		validators: c.validators,
+		keepRaw:    c.keepRaw,
+		strict:     c.strict,
+		codec:      c.codec,
+		router:     c.router,
+
+		bodyCompFallback: c.bodyCompFallback,
+		naming:           c.naming,
+		drift:            c.drift,
+		classifiers:      c.classifiers,
+		validate:         c.validate,
+		onInvalid:        c.onInvalid,
+		// Credentials, the upload log, the time zone and the ECG capability
+		// belong to the user; the latter two are loaded again on demand
+	}
+}
+
+// Session returns the client's current session, including refreshed tokens
+func (c *Client) Session() *garth.Session {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.session
 }
 
 // Get performs a GET request with automatic token refresh
-func (c *Client) Get(ctx context.Context, path string, v interface{}) error {
+func (c *Client) Get(ctx context.Context, path string, v interface{}, opts ...RequestOption) error {
 	// Refresh token if needed
 	if err := c.refreshTokenIfNeeded(); err != nil {
 		return err
 	}
 
-	resp, err := c.HTTPClient.R().
-		SetContext(ctx).
-		SetResult(v).
-		Get(path)
+	req, cancel := c.newRequest(ctx, opts)
+	defer cancel()
+	if !c.strict {
+		req.SetResult(v)
+	}
+	resp, err := req.Get(path)
 
 	if err != nil {
 		return err
@@ -70,29 +173,68 @@ func (c *Client) Get(ctx context.Context, path string, v interface{}) error {
 
 	// Handle unmarshaling errors for successful responses
 	if resp.IsSuccess() && resp.Error() != nil {
-		return handleAPIError(resp)
+		return c.handleAPIError(resp)
 	}
 
 	if resp.StatusCode() == http.StatusUnauthorized {
+		// Log in again and retry once when credentials are available
+		if c.canRelogin() && !retriedAfterLogin(ctx) {
+			stale := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+			if err := c.relogin(ctx, stale); err != nil {
+				return fmt.Errorf("%w: %w", ErrLoginRequired, err)
+			}
+			return c.Get(context.WithValue(ctx, reloginKey{}, true), path, v, opts...)
+		}
+
 		// Force token refresh on next attempt
+		c.mu.Lock()
 		c.session = nil
-		return errors.New("token expired, please reauthenticate")
+		c.mu.Unlock()
+		return unauthorizedError(resp)
 	}
 
 	if resp.StatusCode() >= 400 {
-		return handleAPIError(resp)
+		return c.handleAPIError(resp)
+	}
+
+	if c.drift != nil {
+		c.drift.inspect(path, resp.Body(), v)
+	}
+
+	if c.strict {
+		if err := decodeStrict(resp.Body(), v); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if err := c.checkResponse(path, v, applyRequestOptions(opts)); err != nil {
+		return err
 	}
 
+	if r, ok := v.(rawSetter); ok && c.keepRawFor(ctx) {
+		r.setRaw(resp.Body())
+	}
 	return nil
 }
 
 // Post performs a POST request
-func (c *Client) Post(ctx context.Context, path string, body interface{}, v interface{}) error {
-	resp, err := c.HTTPClient.R().
-		SetContext(ctx).
-		SetBody(body).
-		SetResult(v).
-		Post(path)
+func (c *Client) Post(ctx context.Context, path string, body interface{}, v interface{}, opts ...RequestOption) error {
+	return c.send(ctx, http.MethodPost, path, body, v, opts)
+}
+
+// Put performs a PUT request, typically to replace a setting
+func (c *Client) Put(ctx context.Context, path string, body interface{}, v interface{}, opts ...RequestOption) error {
+	return c.send(ctx, http.MethodPut, path, body, v, opts)
+}
+
+// send performs a request carrying a JSON body
+func (c *Client) send(ctx context.Context, method, path string, body interface{}, v interface{}, opts []RequestOption) error {
+	req, cancel := c.newRequest(ctx, opts)
+	defer cancel()
+
+	if v != nil {
+		req.SetResult(v)
+	}
+	resp, err := req.SetBody(body).Execute(method, path)
 
 	if err != nil {
 		return err
@@ -100,11 +242,11 @@ func (c *Client) Post(ctx context.Context, path string, body interface{}, v inte
 
 	// Handle unmarshaling errors for successful responses
 	if resp.IsSuccess() && resp.Error() != nil {
-		return handleAPIError(resp)
+		return c.handleAPIError(resp)
 	}
 
 	if resp.StatusCode() >= 400 {
-		return handleAPIError(resp)
+		return c.handleAPIError(resp)
 	}
 
 	return nil
@@ -112,6 +254,9 @@ func (c *Client) Post(ctx context.Context, path string, body interface{}, v inte
 
 // refreshTokenIfNeeded refreshes the token if expired
 func (c *Client) refreshTokenIfNeeded() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.session == nil || !c.session.IsExpired() {
 		return nil
 	}
@@ -123,13 +268,17 @@ func (c *Client) refreshTokenIfNeeded() error {
 	// Refresh OAuth2 token using OAuth1 credentials
 	newToken, err := c.auth.RefreshToken(c.session.OAuth1Token, c.session.OAuth1Secret)
 	if err != nil {
+		// A revoked OAuth1 token can only be replaced by logging in again
+		if c.canRelogin() {
+			return c.loginLocked(context.Background())
+		}
 		return fmt.Errorf("token refresh failed: %w", err)
 	}
 
 	// Update session and extend expiration
 	c.session.OAuth2Token = newToken
 	c.session.ExpiresAt = time.Now().Add(8 * time.Hour)
-	c.HTTPClient.SetHeader("Authorization", "Bearer "+newToken)
+	c.token = newToken
 
 	// Persist updated session
 	if c.sessionPath != "" {
@@ -141,15 +290,52 @@ func (c *Client) refreshTokenIfNeeded() error {
 	return nil
 }
 
-// handleAPIError processes API errors including JSON unmarshaling issues
-func handleAPIError(resp *resty.Response) error {
+// requestIDHeaders are the response headers that identify a request in
+// Garmin's logs, in order of preference
+var requestIDHeaders = []string{"X-Request-Id", "Cf-Ray"}
+
+// newAPIError returns an APIError for resp carrying the request method, path
+// and request id
+func newAPIError(resp *resty.Response, message string) *APIError {
+	e := &APIError{StatusCode: resp.StatusCode(), Message: message}
+	e.Method, e.Path = requestTarget(resp)
+	for _, h := range requestIDHeaders {
+		if id := resp.Header().Get(h); id != "" {
+			e.RequestID = id
+			break
+		}
+	}
+	return e
+}
+
+// requestTarget returns the method and path of the request behind resp
+func requestTarget(resp *resty.Response) (method, path string) {
+	req := resp.Request
+	if req == nil {
+		return "", ""
+	}
+	if req.RawRequest != nil {
+		return req.Method, req.RawRequest.URL.RequestURI()
+	}
+	return req.Method, req.URL
+}
+
+// unauthorizedError reports an expired or revoked token
+func unauthorizedError(resp *resty.Response) error {
+	return newAPIError(resp, "token expired, please reauthenticate")
+}
+
+// parseAPIError processes API errors including JSON unmarshaling issues
+func parseAPIError(resp *resty.Response) error {
 	// First try to parse as standard Garmin error format
 	standardError := struct {
 		Code    int    `json:"code"`
 		Message string `json:"message"`
 	}{}
 	if err := json.Unmarshal(resp.Body(), &standardError); err == nil && standardError.Code != 0 {
-		return fmt.Errorf("API error %d: %s", standardError.Code, standardError.Message)
+		e := newAPIError(resp, standardError.Message)
+		e.Code = standardError.Code
+		return e
 	}
 
 	// Try to parse as alternative error format
@@ -157,13 +343,13 @@ func handleAPIError(resp *resty.Response) error {
 		Error string `json:"error"`
 	}{}
 	if err := json.Unmarshal(resp.Body(), &altError); err == nil && altError.Error != "" {
-		return fmt.Errorf("API error %d: %s", resp.StatusCode(), altError.Error)
+		return newAPIError(resp, altError.Error)
 	}
 
 	// Check for unmarshaling errors in successful responses
 	if resp.IsSuccess() {
-		return fmt.Errorf("failed to parse successful response: %s", resp.String())
+		return newAPIError(resp, "failed to parse successful response: "+resp.String())
 	}
 
-	return fmt.Errorf("unexpected status code: %d - %s", resp.StatusCode(), resp.String())
+	return newAPIError(resp, "unexpected response: "+resp.String())
 }
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/sstent/go-garminconnect/internal/auth/garth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithSession(t *testing.T) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		switch r.Header.Get("Authorization") {
+		case "Bearer alice-token":
+			w.Write([]byte(`{"displayName": "alice"}`))
+		case "Bearer bob-token", "Bearer bob-refreshed":
+			w.Write([]byte(`{"displayName": "bob"}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+	defer server.Close()
+
+	auth := NewMockAuthenticatorWithFunc(func(string, string) (string, error) {
+		return "bob-refreshed", nil
+	})
+	sessionPath := filepath.Join(t.TempDir(), "session.json")
+	alice := &garth.Session{OAuth2Token: "alice-token", ExpiresAt: time.Now().Add(time.Hour)}
+	assert.NoError(t, alice.Save(sessionPath))
+
+	cache := NewValidatorCache(0)
+	root, err := NewClient(auth, alice, sessionPath, WithValidatorCache(cache))
+	assert.NoError(t, err)
+	root.HTTPClient.SetBaseURL(server.URL())
+
+	bobSession := &garth.Session{OAuth2Token: "bob-token", ExpiresAt: time.Now().Add(time.Hour)}
+	bob := root.WithSession(bobSession)
+	assert.Same(t, root.HTTPClient, bob.HTTPClient)
+
+	profile := func(c *Client) string {
+		var v struct {
+			DisplayName string `json:"displayName"`
+		}
+		assert.NoError(t, c.Get(context.Background(), "/userprofile-service/socialProfile", &v))
+		return v.DisplayName
+	}
+
+	// The shared cache answers each user with their own response
+	assert.Equal(t, "alice", profile(root))
+	assert.Equal(t, "bob", profile(bob))
+	assert.Equal(t, "alice", profile(root))
+	assert.Equal(t, "bob", profile(bob))
+	assert.Equal(t, 2, cache.Len())
+
+	// Refreshing the clone's token leaves the root client and its session file alone
+	bobSession.ExpiresAt = time.Now().Add(-time.Minute)
+	assert.Equal(t, "bob", profile(bob))
+	assert.Equal(t, "bob-refreshed", bob.Session().OAuth2Token)
+	assert.Equal(t, "alice", profile(root))
+
+	saved, err := garth.LoadSession(sessionPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice-token", saved.OAuth2Token)
+
+	// Without a session the clone has no token and gets the normal auth error
+	anonymous := root.WithSession(nil)
+	assert.Nil(t, anonymous.Session())
+	var v struct{}
+	err = anonymous.Get(context.Background(), "/userprofile-service/socialProfile", &v)
+	var apiErr *APIError
+	if assert.ErrorAs(t, err, &apiErr) {
+		assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	}
+}
@@ -0,0 +1,31 @@
+package api
+
+import "encoding/json"
+
+// Codec encodes request bodies and decodes response bodies. It lets
+// high-volume callers swap encoding/json for a faster drop-in such as
+// jsoniter or sonic.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// StdCodec is the default Codec backed by encoding/json
+var StdCodec Codec = stdCodec{}
+
+type stdCodec struct{}
+
+func (stdCodec) Marshal(v interface{}) ([]byte, error) { return json.Marshal(v) }
+
+func (stdCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+
+// WithCodec makes the client use codec for all JSON request and response
+// bodies. Strict decoding and streaming always use encoding/json, since they
+// rely on its decoder.
+func WithCodec(codec Codec) ClientOption {
+	return func(c *Client) {
+		c.codec = codec
+		c.HTTPClient.SetJSONMarshaler(codec.Marshal)
+		c.HTTPClient.SetJSONUnmarshaler(codec.Unmarshal)
+	}
+}
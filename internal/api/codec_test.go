@@ -0,0 +1,53 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/sstent/go-garminconnect/internal/auth/garth"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingCodec wraps encoding/json and counts calls
+type countingCodec struct {
+	marshal, unmarshal int32
+}
+
+func (c *countingCodec) Marshal(v interface{}) ([]byte, error) {
+	atomic.AddInt32(&c.marshal, 1)
+	return json.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v interface{}) error {
+	atomic.AddInt32(&c.unmarshal, 1)
+	return json.Unmarshal(data, v)
+}
+
+func TestWithCodec(t *testing.T) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"displayName": "Codec User"}`))
+	})
+	defer server.Close()
+
+	codec := &countingCodec{}
+	session := &garth.Session{OAuth2Token: "mock-token", ExpiresAt: time.Now().Add(time.Hour)}
+	client, err := NewClient(NewMockAuthenticator(), session, "", WithBaseURL(server.URL()), WithCodec(codec))
+	assert.NoError(t, err)
+
+	profile, err := client.GetUserProfile(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "Codec User", profile.DisplayName)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&codec.unmarshal))
+
+	var out map[string]string
+	assert.NoError(t, client.Post(context.Background(), "/echo", map[string]int{"a": 1}, &out))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&codec.marshal))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&codec.unmarshal))
+}
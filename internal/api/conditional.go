@@ -0,0 +1,181 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// cachedResponse is a response body stored together with its cache validators
+type cachedResponse struct {
+	header http.Header
+	body   []byte
+}
+
+// ValidatorCache remembers ETag and Last-Modified validators of GET responses
+// and revalidates repeat requests with If-None-Match/If-Modified-Since. A 304
+// reply is answered from the cache, so pollers only download data that changed.
+// A cache may be shared by several clients, including clients of different
+// users; entries are keyed by credentials as well as URL.
+type ValidatorCache struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*cachedResponse
+	order   []string
+}
+
+// NewValidatorCache creates a cache holding at most maxEntries responses;
+// the oldest entry is evicted first. A non-positive maxEntries means unbounded.
+func NewValidatorCache(maxEntries int) *ValidatorCache {
+	return &ValidatorCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*cachedResponse),
+	}
+}
+
+// WithValidatorCache enables conditional requests backed by cache
+func WithValidatorCache(cache *ValidatorCache) ClientOption {
+	return func(c *Client) {
+		c.validators = cache
+	}
+}
+
+// Len returns the number of cached responses
+func (vc *ValidatorCache) Len() int {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	return len(vc.entries)
+}
+
+// Transport wraps next so GET requests are made conditional
+func (vc *ValidatorCache) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &conditionalTransport{cache: vc, next: next}
+}
+
+type conditionalTransport struct {
+	cache *ValidatorCache
+	next  http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *conditionalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return t.next.RoundTrip(req)
+	}
+
+	key := cacheKey(req)
+	var cached *cachedResponse
+	// Requests made WithNoCache skip revalidation but refresh the entry
+	if !strings.Contains(req.Header.Get("Cache-Control"), "no-cache") {
+		cached = t.cache.get(key)
+	}
+	if cached != nil {
+		req = req.Clone(req.Context())
+		if etag := cached.header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if modified := cached.header.Get("Last-Modified"); modified != "" {
+			req.Header.Set("If-Modified-Since", modified)
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		resp.Body.Close()
+		return cachedHTTPResponse(req, cached), nil
+	case resp.StatusCode == http.StatusOK && cacheable(resp):
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		t.cache.set(key, &cachedResponse{header: resp.Header.Clone(), body: body})
+	case resp.StatusCode == http.StatusOK:
+		// The resource no longer carries validators
+		t.cache.remove(key)
+	}
+	return resp, nil
+}
+
+// cacheKey identifies a cached response by URL and credentials, so clients of
+// different users sharing a cache never see each other's responses
+func cacheKey(req *http.Request) string {
+	auth := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	return req.URL.String() + " " + hex.EncodeToString(auth[:8])
+}
+
+// cacheable reports whether resp is a JSON document carrying validators.
+// File downloads are never buffered in memory.
+func cacheable(resp *http.Response) bool {
+	if !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		return false
+	}
+	return resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != ""
+}
+
+func cachedHTTPResponse(req *http.Request, cached *cachedResponse) *http.Response {
+	header := cached.header.Clone()
+	header.Set("Content-Length", strconv.Itoa(len(cached.body)))
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(cached.body)),
+		ContentLength: int64(len(cached.body)),
+		Request:       req,
+	}
+}
+
+func (vc *ValidatorCache) get(key string) *cachedResponse {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	return vc.entries[key]
+}
+
+func (vc *ValidatorCache) set(key string, entry *cachedResponse) {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	if _, ok := vc.entries[key]; !ok {
+		vc.order = append(vc.order, key)
+	}
+	vc.entries[key] = entry
+	for vc.maxEntries > 0 && len(vc.entries) > vc.maxEntries {
+		oldest := vc.order[0]
+		vc.order = vc.order[1:]
+		delete(vc.entries, oldest)
+	}
+}
+
+func (vc *ValidatorCache) remove(key string) {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	if _, ok := vc.entries[key]; !ok {
+		return
+	}
+	delete(vc.entries, key)
+	for i, k := range vc.order {
+		if k == key {
+			vc.order = append(vc.order[:i], vc.order[i+1:]...)
+			break
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/sstent/go-garminconnect/internal/auth/garth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConditionalRequests(t *testing.T) {
+	var full, notModified int32
+	steps := `{"calendarDate": "2024-03-01T00:00:00Z", "totalSteps": 1234}`
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/wellness-service/steps/daily/2024-03-01":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				atomic.AddInt32(&notModified, 1)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+		case "/wellness-service/steps/daily/2024-03-02":
+			assert.Empty(t, r.Header.Get("If-None-Match"))
+		}
+		atomic.AddInt32(&full, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(steps))
+	})
+	defer server.Close()
+
+	cache := NewValidatorCache(10)
+	session := &garth.Session{OAuth2Token: "mock-token", ExpiresAt: time.Now().Add(time.Hour)}
+	client, err := NewClient(NewMockAuthenticator(), session, "",
+		WithBaseURL(server.URL()),
+		WithValidatorCache(cache),
+	)
+	assert.NoError(t, err)
+
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		data, err := client.GetStepsData(context.Background(), day)
+		assert.NoError(t, err)
+		assert.Equal(t, 1234, data.TotalSteps)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&full))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&notModified))
+
+	// Responses without validators are not cached
+	_, err = client.GetStepsData(context.Background(), day.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	_, err = client.GetStepsData(context.Background(), day.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestValidatorCacheEviction(t *testing.T) {
+	cache := NewValidatorCache(2)
+	cache.set("a", &cachedResponse{header: http.Header{}})
+	cache.set("b", &cachedResponse{header: http.Header{}})
+	cache.set("c", &cachedResponse{header: http.Header{}})
+	assert.Equal(t, 2, cache.Len())
+	assert.Nil(t, cache.get("a"))
+	assert.NotNil(t, cache.get("c"))
+
+	cache.remove("b")
+	assert.Equal(t, []string{"c"}, cache.order)
+}
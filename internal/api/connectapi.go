@@ -0,0 +1,34 @@
+package api
+
+import "strings"
+
+// Headers the Garmin Connect mobile app sends to connectapi.garmin.com
+const (
+	DIBackendHeader = "DI-Backend"
+	connectAPIHost  = "connectapi.garmin.com"
+	// mobileUserAgent identifies requests as coming from the Connect mobile app
+	mobileUserAgent = "com.garmin.android.apps.connectmobile"
+)
+
+// WithConnectAPI sends requests to connectapi.garmin.com with the headers the
+// mobile app uses instead of the legacy connect.garmin.com/modern proxy. The
+// mobile API accepts the OAuth2 bearer token directly and changes less often.
+func WithConnectAPI() ClientOption {
+	return func(c *Client) {
+		c.HTTPClient.SetBaseURL(ConnectAPIURL)
+		c.HTTPClient.SetHeaders(connectAPIHeaders())
+	}
+}
+
+// connectAPIHeaders returns the headers required by the connectapi host
+func connectAPIHeaders() map[string]string {
+	return map[string]string{
+		DIBackendHeader: connectAPIHost,
+		"User-Agent":    mobileUserAgent,
+	}
+}
+
+// isConnectAPI reports whether host is the connectapi host
+func isConnectAPI(host string) bool {
+	return strings.Contains(host, "://"+connectAPIHost)
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/sstent/go-garminconnect/internal/auth/garth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithConnectAPI(t *testing.T) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "connectapi.garmin.com", r.Header.Get(DIBackendHeader))
+		assert.Equal(t, "Bearer mobile-token", r.Header.Get("Authorization"))
+		assert.Equal(t, mobileUserAgent, r.Header.Get("User-Agent"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	})
+	defer server.Close()
+
+	session := &garth.Session{OAuth2Token: "mobile-token", ExpiresAt: time.Now().Add(time.Hour)}
+	client, err := NewClient(NewMockAuthenticator(), session, "", WithConnectAPI())
+	assert.NoError(t, err)
+	assert.Equal(t, ConnectAPIURL, client.HTTPClient.BaseURL)
+
+	client.HTTPClient.SetBaseURL(server.URL())
+	var v map[string]interface{}
+	assert.NoError(t, client.Get(context.Background(), "/userprofile-service/socialProfile", &v))
+
+	assert.True(t, isConnectAPI(ConnectAPIURL))
+	assert.False(t, isConnectAPI(DefaultBaseURL))
+}
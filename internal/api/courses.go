@@ -0,0 +1,42 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// Course is a saved route the user can follow on a device
+type Course struct {
+	ID           int64         `json:"courseId"`
+	Name         string        `json:"courseName"`
+	Distance     float64       `json:"distanceInMeters"`
+	ActivityType string        `json:"activityTypeKey,omitempty"`
+	Points       []CoursePoint `json:"geoPoints,omitempty"` // only set by GetCourse
+}
+
+// CoursePoint is one position along a course
+type CoursePoint struct {
+	Lat       float64  `json:"latitude"`
+	Lon       float64  `json:"longitude"`
+	Elevation *float64 `json:"elevation,omitempty"` // meters
+	Distance  float64  `json:"distance"`            // meters from the start
+}
+
+// GetCourses lists the user's saved courses without their points
+func (c *Client) GetCourses(ctx context.Context, opts ...RequestOption) ([]Course, error) {
+	var courses []Course
+	if err := c.Get(ctx, "/course-service/course", &courses, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get courses: %w", err)
+	}
+	return courses, nil
+}
+
+// GetCourse retrieves a course with its points
+func (c *Client) GetCourse(ctx context.Context, courseID int64, opts ...RequestOption) (*Course, error) {
+	var course Course
+	path := fmt.Sprintf("/course-service/course/%d", courseID)
+	if err := c.Get(ctx, path, &course, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get course: %w", err)
+	}
+	return &course, nil
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCourses(t *testing.T) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/course-service/course":
+			w.Write([]byte(`[{"courseId": 7, "courseName": "River Loop", "distanceInMeters": 8200.5, "activityTypeKey": "running"}]`))
+		case "/course-service/course/7":
+			w.Write([]byte(`{"courseId": 7, "courseName": "River Loop", "geoPoints": [
+				{"latitude": 52.5, "longitude": 13.4, "elevation": 34.2, "distance": 0},
+				{"latitude": 52.501, "longitude": 13.4, "distance": 111.2}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+
+	courses, err := client.GetCourses(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []Course{{ID: 7, Name: "River Loop", Distance: 8200.5, ActivityType: "running"}}, courses)
+
+	course, err := client.GetCourse(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.Len(t, course.Points, 2)
+	assert.Equal(t, Ptr(34.2), course.Points[0].Elevation)
+	assert.Equal(t, 111.2, course.Points[1].Distance)
+
+	_, err = client.GetCourse(context.Background(), 8)
+	assert.ErrorIs(t, err, ErrNotFound{})
+}
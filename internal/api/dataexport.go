@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Data export states
+const (
+	DataExportPending  = "PENDING"
+	DataExportComplete = "COMPLETE"
+	DataExportExpired  = "EXPIRED"
+	DataExportFailed   = "FAILED"
+)
+
+// ErrDataExportUnavailable is returned when an export failed or its archive has
+// expired and a new export must be requested
+var ErrDataExportUnavailable = errors.New("data export is no longer available")
+
+// DataExport is a full account data export (GDPR) request
+type DataExport struct {
+	RequestID  string     `json:"requestId"`
+	Status     string     `json:"status"` // one of the DataExport* states
+	Requested  GarminTime `json:"requestDate"`
+	Expiration GarminTime `json:"expirationDate"` // zero until the archive is ready
+	Size       int64      `json:"fileSize"`       // archive size in bytes once ready
+}
+
+// IsReady reports whether the archive can be downloaded
+func (e DataExport) IsReady() bool {
+	return e.Status == DataExportComplete
+}
+
+// RequestDataExport asks Garmin to prepare an archive of all account data.
+// Preparing the archive can take several days.
+func (c *Client) RequestDataExport(ctx context.Context, opts ...RequestOption) (*DataExport, error) {
+	var export DataExport
+	if err := c.Post(ctx, "/data-export-service/export", nil, &export, opts...); err != nil {
+		return nil, fmt.Errorf("failed to request data export: %w", err)
+	}
+	return &export, nil
+}
+
+// GetDataExport retrieves the current state of an export request
+func (c *Client) GetDataExport(ctx context.Context, requestID string, opts ...RequestOption) (*DataExport, error) {
+	var export DataExport
+	path := fmt.Sprintf("/data-export-service/export/%s", requestID)
+	if err := c.Get(ctx, path, &export, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get data export %s: %w", requestID, err)
+	}
+	return &export, nil
+}
+
+// WaitForDataExport polls the export every interval until its archive is ready,
+// returning ErrDataExportUnavailable if it failed or expired
+func (c *Client) WaitForDataExport(ctx context.Context, requestID string, interval time.Duration, opts ...RequestOption) (*DataExport, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		export, err := c.GetDataExport(ctx, requestID, opts...)
+		if err != nil {
+			return nil, err
+		}
+		switch export.Status {
+		case DataExportComplete:
+			return export, nil
+		case DataExportExpired, DataExportFailed:
+			return export, fmt.Errorf("data export %s %s: %w", requestID, export.Status, ErrDataExportUnavailable)
+		}
+
+		select {
+		case <-ctx.Done():
+			return export, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// DownloadDataExport saves the archive of a completed export to dest, resuming
+// partial downloads like DownloadActivityToFile
+func (c *Client) DownloadDataExport(ctx context.Context, requestID, dest string, opts ...RequestOption) (int64, error) {
+	path := fmt.Sprintf("/data-export-service/export/%s/download", requestID)
+	n, err := c.downloadToFile(ctx, path, "application/zip", dest, opts...)
+	if err != nil {
+		return n, fmt.Errorf("failed to download data export %s: %w", requestID, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataExport(t *testing.T) {
+	var polls atomic.Int32
+	var status atomic.Value
+	status.Store(DataExportComplete)
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/data-export-service/export":
+			w.Write([]byte(`{"requestId": "abc", "status": "PENDING", "requestDate": "2024-03-01T10:00:00Z"}`))
+		case r.URL.Path == "/data-export-service/export/abc":
+			if polls.Add(1) < 3 {
+				w.Write([]byte(`{"requestId": "abc", "status": "PENDING"}`))
+				return
+			}
+			w.Write([]byte(`{"requestId": "abc", "status": "` + status.Load().(string) + `", "fileSize": 7, "expirationDate": "2024-03-20T10:00:00Z"}`))
+		case r.URL.Path == "/data-export-service/export/abc/download":
+			assert.Equal(t, "application/zip", r.Header.Get("Accept"))
+			w.Header().Set("Content-Type", "application/zip")
+			w.Write([]byte("PK-data"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+	ctx := context.Background()
+
+	export, err := client.RequestDataExport(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", export.RequestID)
+	assert.False(t, export.IsReady())
+
+	export, err = client.WaitForDataExport(ctx, "abc", time.Millisecond)
+	assert.NoError(t, err)
+	assert.True(t, export.IsReady())
+	assert.Equal(t, int32(3), polls.Load())
+	assert.Equal(t, int64(7), export.Size)
+
+	dest := filepath.Join(t.TempDir(), "export.zip")
+	n, err := client.DownloadDataExport(ctx, "abc", dest)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), n)
+	data, err := os.ReadFile(dest)
+	assert.NoError(t, err)
+	assert.Equal(t, "PK-data", string(data))
+
+	status.Store(DataExportExpired)
+	_, err = client.WaitForDataExport(ctx, "abc", time.Millisecond)
+	assert.ErrorIs(t, err, ErrDataExportUnavailable)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = client.WaitForDataExport(canceled, "abc", time.Hour)
+	assert.Error(t, err)
+}
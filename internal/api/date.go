@@ -0,0 +1,70 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// dateLayout is the format of calendar dates in Garmin Connect
+const dateLayout = "2006-01-02"
+
+// Date is a calendar day without a time of day, the key of Garmin's daily
+// summaries. It is stored as midnight UTC and encoded as "2006-01-02".
+type Date struct {
+	time.Time
+}
+
+// NewDate returns the calendar day of t's wall clock
+func NewDate(t time.Time) Date {
+	if t.IsZero() {
+		return Date{}
+	}
+	return Date{Time: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+}
+
+// ParseDate parses a "2006-01-02" calendar date
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date %q: %w", s, err)
+	}
+	return Date{Time: t}, nil
+}
+
+// String formats the date as "2006-01-02"
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(dateLayout)
+}
+
+// In returns midnight of the date in loc
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Besides plain dates it accepts the
+// timestamp formats of GarminTime and keeps their calendar day.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*d = Date{}
+		return nil
+	}
+	var t GarminTime
+	if err := t.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*d = NewDate(t.Time)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateJSON(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{`"2024-03-01"`, "2024-03-01", false},
+		{`"2024-03-01T23:30:00"`, "2024-03-01", false},
+		{`"2024-03-01T23:30:00-05:00"`, "2024-03-01", false},
+		{`null`, "", false},
+		{`"March 1st"`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var d Date
+			err := json.Unmarshal([]byte(tt.input), &d)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, d.String())
+		})
+	}
+
+	data, err := json.Marshal(struct {
+		Day   Date `json:"day"`
+		Empty Date `json:"empty"`
+	}{Day: NewDate(time.Date(2024, 3, 1, 18, 0, 0, 0, time.FixedZone("EST", -5*3600)))})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"day": "2024-03-01", "empty": null}`, string(data))
+}
+
+func TestUserStatsDecoding(t *testing.T) {
+	var stats UserStats
+	err := json.Unmarshal([]byte(`{"totalSteps": 8000, "date": "2024-03-01"}`), &stats)
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-03-01", stats.Date.String())
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), stats.Date.Time)
+
+	tokyo := time.FixedZone("JST", 9*3600)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, tokyo), stats.Date.In(tokyo))
+
+	_, err = ParseDate("2024-13-01")
+	assert.Error(t, err)
+}
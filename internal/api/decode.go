@@ -0,0 +1,37 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// WithStrictDecoding makes Get fail on response fields the target model does not
+// declare and, as WithResponseValidation does, on fields breaking the model's
+// validator tags. Lenient decoding, which ignores unknown fields, is the
+// default; strict mode is meant for tests and development to notice changes in
+// Garmin's schema early.
+func WithStrictDecoding() ClientOption {
+	return func(c *Client) {
+		c.strict = true
+	}
+}
+
+// validatable is implemented by models with validator tags
+type validatable interface {
+	Validate() error
+}
+
+// decodeStrict decodes body into v rejecting unknown fields
+func decodeStrict(body []byte, v interface{}) error {
+	if v == nil || len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("strict decoding failed: %w", err)
+	}
+	return nil
+}
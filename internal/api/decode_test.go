@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrictDecoding(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		strictErr string
+	}{
+		{
+			name: "known fields",
+			body: `{"calendarDate": "2024-03-01", "totalSteps": 9000, "goal": 8000}`,
+		},
+		{
+			name:      "unknown field",
+			body:      `{"calendarDate": "2024-03-01", "totalSteps": 9000, "floorsClimbed": 4}`,
+			strictErr: `unknown field "floorsClimbed"`,
+		},
+		{
+			name:      "validation failure",
+			body:      `{"calendarDate": "2024-03-01", "totalSteps": -5}`,
+			strictErr: "failed validation",
+		},
+	}
+
+	mockServer := garmintest.NewServer()
+	defer mockServer.Close()
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handleHealth(mockServer, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.body))
+			})
+
+			lenient := NewClientWithBaseURL(mockServer.URL())
+			_, err := lenient.GetStepsData(context.Background(), date)
+			assert.NoError(t, err)
+
+			strict := NewClientWithBaseURL(mockServer.URL())
+			WithStrictDecoding()(strict)
+			steps, err := strict.GetStepsData(context.Background(), date)
+			if tt.strictErr == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, 9000, steps.TotalSteps)
+				return
+			}
+			assert.ErrorContains(t, err, tt.strictErr)
+			assert.ErrorContains(t, err, "/wellness-service/steps/daily/2024-03-01")
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// Device is a Garmin device registered to the user's account
+type Device struct {
+	DeviceID      int64  `json:"deviceId"`
+	Name          string `json:"productDisplayName"`
+	SerialNumber  string `json:"serialNumber"`
+	Firmware      string `json:"currentFirmwareVersion"`
+	PrimaryDevice bool   `json:"primaryActivityTrackerIndicator"`
+	// ECGCapable is set for watches with the ECG app enabled
+	ECGCapable bool `json:"ecgCapable"`
+}
+
+// GetDevices retrieves the devices registered to the user's account
+func (c *Client) GetDevices(ctx context.Context, opts ...RequestOption) ([]Device, error) {
+	var devices []Device
+	if err := c.Get(ctx, "/device-service/deviceregistration/devices", &devices, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get devices: %w", err)
+	}
+	return devices, nil
+}
@@ -0,0 +1,213 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sstent/go-garminconnect/internal/jsonfile"
+)
+
+// partSuffix marks a download that has not completed yet
+const partSuffix = ".part"
+
+// validatorSuffix marks the file recording which version of the resource a
+// .part file holds
+const validatorSuffix = ".part.validator"
+
+// partValidator identifies the version of a resource held by a .part file, so
+// a resumed download can't splice two versions of the file together
+type partValidator struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// ifRange returns the If-Range value for v, empty when v can't be used: weak
+// ETags are not allowed there
+func (v partValidator) ifRange() string {
+	if v.ETag != "" && !strings.HasPrefix(v.ETag, "W/") {
+		return v.ETag
+	}
+	return v.LastModified
+}
+
+// DownloadActivityToFile saves the FIT file of an activity to dest and returns
+// its size. Interrupted downloads leave dest+".part" behind and are resumed with
+// a Range request on the next call. The ETag or Last-Modified of the response
+// is kept next to the part file and sent as If-Range, so the download restarts
+// if the file changed in between; without one it always restarts.
+func (c *Client) DownloadActivityToFile(ctx context.Context, activityID int64, dest string, opts ...RequestOption) (int64, error) {
+	path := fmt.Sprintf("/download-service/export/activity/%d", activityID)
+	n, err := c.downloadToFile(ctx, path, "application/fit", dest, opts...)
+	if err != nil {
+		return n, fmt.Errorf("failed to download activity %d: %w", activityID, err)
+	}
+	return n, nil
+}
+
+// ExportCourse saves the FIT file of a course to dest, resuming partial downloads
+// like DownloadActivityToFile
+func (c *Client) ExportCourse(ctx context.Context, courseID int64, dest string, opts ...RequestOption) (int64, error) {
+	path := fmt.Sprintf("/course-service/course/fit/%d/0", courseID)
+	n, err := c.downloadToFile(ctx, path, "application/fit", dest, opts...)
+	if err != nil {
+		return n, fmt.Errorf("failed to export course %d: %w", courseID, err)
+	}
+	return n, nil
+}
+
+// downloadToFile streams path into dest via a .part file, continuing from the
+// bytes already present in it when they belong to the same version
+func (c *Client) downloadToFile(ctx context.Context, path, accept, dest string, opts ...RequestOption) (int64, error) {
+	if err := c.refreshTokenIfNeeded(); err != nil {
+		return 0, err
+	}
+
+	part := dest + partSuffix
+	f, err := os.OpenFile(part, os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open partial file: %w", err)
+	}
+	defer f.Close()
+
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, fmt.Errorf("failed to seek partial file: %w", err)
+	}
+	var validator partValidator
+	if offset > 0 {
+		if err := jsonfile.Load(dest+validatorSuffix, &validator); err != nil {
+			return 0, err
+		}
+		if validator.ifRange() == "" {
+			// Bytes of an unknown version can't be resumed safely
+			if err := restartPart(f); err != nil {
+				return 0, err
+			}
+			offset = 0
+		}
+	}
+
+	req, cancel := c.newRequest(ctx, opts)
+	defer cancel()
+	req.SetDoNotParseResponse(true).SetHeader("Accept", accept)
+	if offset > 0 {
+		req.SetHeader("Range", fmt.Sprintf("bytes=%d-", offset))
+		req.SetHeader("If-Range", validator.ifRange())
+	}
+	resp, err := req.Get(path)
+	if err != nil {
+		return offset, err
+	}
+	body := resp.RawBody()
+	defer body.Close()
+
+	switch resp.StatusCode() {
+	case http.StatusPartialContent:
+		if start := contentRangeStart(resp.Header().Get("Content-Range")); start != offset {
+			return offset, fmt.Errorf("server resumed at byte %d, expected %d", start, offset)
+		}
+	case http.StatusOK:
+		// A fresh download, or the file changed or the server ignored the
+		// Range header, and the whole file follows
+		if err := restartPart(f); err != nil {
+			return 0, err
+		}
+		offset = 0
+		if err := saveValidator(dest, resp.Header()); err != nil {
+			return 0, err
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		// Nothing left to fetch if the part file already holds the whole resource
+		if offset == 0 || completeLength(resp.Header().Get("Content-Range")) != offset {
+			return offset, fmt.Errorf("server rejected resume at byte %d", offset)
+		}
+		return offset, finishDownload(f, part, dest)
+	case http.StatusUnauthorized:
+		return offset, unauthorizedError(resp)
+	default:
+		data, _ := io.ReadAll(io.LimitReader(body, maxErrorBody))
+		return offset, c.handleAPIError(resp.SetBody(data))
+	}
+
+	n, err := io.Copy(f, body)
+	if err != nil {
+		return offset + n, fmt.Errorf("download interrupted after %d bytes: %w", offset+n, err)
+	}
+	return offset + n, finishDownload(f, part, dest)
+}
+
+// restartPart empties the partial file
+func restartPart(f *os.File) error {
+	if err := f.Truncate(0); err != nil {
+		return fmt.Errorf("failed to truncate partial file: %w", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek partial file: %w", err)
+	}
+	return nil
+}
+
+// saveValidator records the version of the file whose download starts with
+// header, or removes a stale record when the server sent no usable validator
+func saveValidator(dest string, header http.Header) error {
+	v := partValidator{ETag: header.Get("ETag"), LastModified: header.Get("Last-Modified")}
+	if v.ifRange() == "" {
+		if err := os.Remove(dest + validatorSuffix); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove download validator: %w", err)
+		}
+		return nil
+	}
+	return jsonfile.Save(dest+validatorSuffix, v)
+}
+
+// finishDownload flushes the partial file and moves it into place
+func finishDownload(f *os.File, part, dest string) error {
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("failed to sync partial file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close partial file: %w", err)
+	}
+	if err := os.Rename(part, dest); err != nil {
+		return fmt.Errorf("failed to move download into place: %w", err)
+	}
+	if err := os.Remove(dest + validatorSuffix); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove download validator: %w", err)
+	}
+	return nil
+}
+
+// contentRangeStart parses the first byte position of "bytes 100-199/200"
+func contentRangeStart(header string) int64 {
+	spec, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return -1
+	}
+	first, _, ok := strings.Cut(spec, "-")
+	if !ok {
+		return -1
+	}
+	n, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// completeLength parses the total size of "bytes */200"
+func completeLength(header string) int64 {
+	_, size, ok := strings.Cut(header, "/")
+	if !ok {
+		return -1
+	}
+	n, err := strconv.ParseInt(size, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownloadActivityToFile(t *testing.T) {
+	file := bytes.Repeat([]byte("FIT-DATA"), 1000)
+
+	tests := []struct {
+		name        string
+		partial     []byte
+		validator   string // ETag recorded for the partial file
+		handler     http.HandlerFunc
+		wantRange   string
+		wantIfRange string
+		wantErr     bool
+		wantPartial int
+	}{
+		{
+			name: "fresh download",
+		},
+		{
+			name:        "resumes partial file",
+			partial:     file[:3000],
+			validator:   `"v1"`,
+			wantRange:   "bytes=3000-",
+			wantIfRange: `"v1"`,
+		},
+		{
+			name:        "file changed since the partial download",
+			partial:     []byte("old version"),
+			validator:   `"v0"`,
+			wantRange:   "bytes=11-",
+			wantIfRange: `"v0"`,
+		},
+		{
+			name:    "partial file without validator restarts",
+			partial: []byte("unknown version"),
+		},
+		{
+			name:      "server ignores range",
+			partial:   []byte("stale bytes"),
+			validator: `"v1"`,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write(file)
+			},
+			wantRange:   "bytes=11-",
+			wantIfRange: `"v1"`,
+		},
+		{
+			name:        "already complete",
+			partial:     file,
+			validator:   `"v1"`,
+			wantRange:   "bytes=8000-",
+			wantIfRange: `"v1"`,
+		},
+		{
+			name: "interrupted download keeps part file",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("ETag", `"v1"`)
+				w.Header().Set("Content-Length", strconv.Itoa(len(file)))
+				w.Write(file[:5000])
+			},
+			wantErr:     true,
+			wantPartial: 5000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRange, gotIfRange string
+			server := garmintest.NewServer()
+			server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/download-service/export/activity/42", r.URL.Path)
+				gotRange, gotIfRange = r.Header.Get("Range"), r.Header.Get("If-Range")
+				if tt.handler != nil {
+					tt.handler(w, r)
+					return
+				}
+				w.Header().Set("ETag", `"v1"`)
+				http.ServeContent(w, r, "activity.fit", time.Time{}, bytes.NewReader(file))
+			})
+			defer server.Close()
+			client := NewClientWithBaseURL(server.URL())
+
+			dest := filepath.Join(t.TempDir(), "42.fit")
+			if tt.partial != nil {
+				assert.NoError(t, os.WriteFile(dest+partSuffix, tt.partial, 0644))
+			}
+			if tt.validator != "" {
+				assert.NoError(t, os.WriteFile(dest+validatorSuffix, []byte(`{"etag": `+strconv.Quote(tt.validator)+`}`), 0600))
+			}
+
+			n, err := client.DownloadActivityToFile(context.Background(), 42, dest)
+			assert.Equal(t, tt.wantRange, gotRange)
+			assert.Equal(t, tt.wantIfRange, gotIfRange)
+			if tt.wantErr {
+				assert.Error(t, err)
+				part, _ := os.ReadFile(dest + partSuffix)
+				assert.Len(t, part, tt.wantPartial)
+				assert.NoFileExists(t, dest)
+				validator, _ := os.ReadFile(dest + validatorSuffix)
+				assert.Contains(t, string(validator), `"etag": "\"v1\""`, "the version of the part file is kept")
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, int64(len(file)), n)
+			got, _ := os.ReadFile(dest)
+			assert.Equal(t, file, got)
+			assert.NoFileExists(t, dest+partSuffix)
+			assert.NoFileExists(t, dest+validatorSuffix)
+		})
+	}
+}
+
+func TestContentRange(t *testing.T) {
+	assert.Equal(t, int64(100), contentRangeStart("bytes 100-199/200"))
+	assert.Equal(t, int64(-1), contentRangeStart("bytes */200"))
+	assert.Equal(t, int64(200), completeLength("bytes */200"))
+	assert.Equal(t, int64(-1), completeLength("bytes 0-1/*"))
+}
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DriftReporter collects response fields that the target model does not
+// declare, aggregated per endpoint, so changes to Garmin's schema can be
+// tracked in production. Unlike WithStrictDecoding it never fails a request.
+type DriftReporter struct {
+	onNew func(endpoint, field string)
+
+	mu     sync.Mutex
+	fields map[string]map[string]int
+}
+
+// SchemaDrift lists the unknown fields seen on one endpoint with how many
+// responses carried each of them
+type SchemaDrift struct {
+	Endpoint string
+	Fields   map[string]int
+}
+
+// NewDriftReporter creates a reporter. onNew, if not nil, is called the first
+// time a field is seen on an endpoint, e.g. to log it.
+func NewDriftReporter(onNew func(endpoint, field string)) *DriftReporter {
+	return &DriftReporter{onNew: onNew, fields: make(map[string]map[string]int)}
+}
+
+// WithDriftReporter reports unknown response fields of every Get to r
+func WithDriftReporter(r *DriftReporter) ClientOption {
+	return func(c *Client) {
+		c.drift = r
+	}
+}
+
+// Report returns the fields seen so far, sorted by endpoint
+func (r *DriftReporter) Report() []SchemaDrift {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := make([]SchemaDrift, 0, len(r.fields))
+	for endpoint, fields := range r.fields {
+		counts := make(map[string]int, len(fields))
+		for f, n := range fields {
+			counts[f] = n
+		}
+		report = append(report, SchemaDrift{Endpoint: endpoint, Fields: counts})
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].Endpoint < report[j].Endpoint })
+	return report
+}
+
+// inspect records the fields of body that v does not declare
+func (r *DriftReporter) inspect(path string, body []byte, v interface{}) {
+	if v == nil || len(bytes.TrimSpace(body)) == 0 {
+		return
+	}
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return
+	}
+	unknown := map[string]bool{}
+	unknownFields(doc, reflect.TypeOf(v), "", unknown)
+	if len(unknown) == 0 {
+		return
+	}
+
+	endpoint := driftEndpoint(path)
+	var fresh []string
+	r.mu.Lock()
+	fields := r.fields[endpoint]
+	if fields == nil {
+		fields = make(map[string]int)
+		r.fields[endpoint] = fields
+	}
+	for f := range unknown {
+		if fields[f] == 0 {
+			fresh = append(fresh, f)
+		}
+		fields[f]++
+	}
+	r.mu.Unlock()
+
+	if r.onNew != nil {
+		sort.Strings(fresh)
+		for _, f := range fresh {
+			r.onNew(endpoint, f)
+		}
+	}
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// unknownFields adds the dotted paths of fields in doc that t has no place for.
+// Elements of arrays are written as "[]" and map values as "*".
+func unknownFields(doc interface{}, t reflect.Type, prefix string, unknown map[string]bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	// Types decoding themselves are trusted to handle their input
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return
+	}
+
+	switch val := doc.(type) {
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Struct:
+			fields := jsonFields(t)
+			for key, child := range val {
+				ft, ok := fields[key]
+				if !ok {
+					ft, ok = fields[strings.ToLower(key)]
+				}
+				if !ok {
+					unknown[joinField(prefix, key)] = true
+					continue
+				}
+				unknownFields(child, ft, joinField(prefix, key), unknown)
+			}
+		case reflect.Map:
+			for _, child := range val {
+				unknownFields(child, t.Elem(), joinField(prefix, "*"), unknown)
+			}
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for _, child := range val {
+				unknownFields(child, t.Elem(), prefix+"[]", unknown)
+			}
+		}
+	}
+}
+
+// jsonFields maps the JSON names of t's fields, as encoding/json would decode
+// them, to their types. Names are also stored lowercased for case-insensitive
+// matching.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for n, t := range jsonFields(ft) {
+					if _, ok := fields[n]; !ok {
+						fields[n] = t
+					}
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+		fields[strings.ToLower(name)] = f.Type
+	}
+	return fields
+}
+
+func joinField(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+var (
+	driftDate = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	driftID   = regexp.MustCompile(`^\d+$`)
+)
+
+// driftEndpoint strips the query and replaces dates and IDs in path, so one
+// endpoint aggregates the responses for every day or activity
+func driftEndpoint(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		switch {
+		case driftDate.MatchString(s):
+			segments[i] = "{date}"
+		case driftID.MatchString(s):
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDriftReporter(t *testing.T) {
+	mockServer := garmintest.NewServer()
+	defer mockServer.Close()
+	handleHealth(mockServer, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"calendarDate": "2024-03-01", "TotalSteps": 9000, "floorsClimbed": 4,
+			"intensity": {"vigorous": 10}}`))
+	})
+
+	var logged []string
+	reporter := NewDriftReporter(func(endpoint, field string) {
+		logged = append(logged, endpoint+" "+field)
+	})
+	client := NewClientWithBaseURL(mockServer.URL())
+	WithDriftReporter(reporter)(client)
+
+	for day := 1; day <= 2; day++ {
+		steps, err := client.GetStepsData(context.Background(), time.Date(2024, 3, day, 0, 0, 0, 0, time.UTC))
+		assert.NoError(t, err)
+		assert.Equal(t, 9000, steps.TotalSteps)
+	}
+
+	endpoint := "/wellness-service/steps/daily/{date}"
+	assert.Equal(t, []string{endpoint + " floorsClimbed", endpoint + " intensity"}, logged)
+	assert.Equal(t, []SchemaDrift{{
+		Endpoint: endpoint,
+		Fields:   map[string]int{"floorsClimbed": 2, "intensity": 2},
+	}}, reporter.Report())
+}
+
+func TestUnknownFieldsNested(t *testing.T) {
+	type lap struct {
+		Distance float64 `json:"distance"`
+	}
+	type activity struct {
+		ID      int64          `json:"activityId"`
+		Laps    []lap          `json:"laps"`
+		Summary map[string]lap `json:"summary"`
+		Start   GarminTime     `json:"startTimeGMT"`
+		Ignored string         `json:"-"`
+	}
+
+	doc := map[string]interface{}{
+		"activityId":   1.0,
+		"startTimeGMT": map[string]interface{}{"custom": true},
+		"Ignored":      "x",
+		"laps":         []interface{}{map[string]interface{}{"distance": 1.0, "elevation": 2.0}},
+		"summary":      map[string]interface{}{"run": map[string]interface{}{"pace": 3.0}},
+	}
+	unknown := map[string]bool{}
+	unknownFields(doc, typeOf[*[]activity](), "", unknown)
+	assert.Empty(t, unknown, "a single object is not decoded into a slice")
+
+	unknownFields([]interface{}{doc}, typeOf[*[]activity](), "", unknown)
+	assert.Equal(t, map[string]bool{
+		"[].Ignored":          true,
+		"[].laps[].elevation": true,
+		"[].summary.*.pace":   true,
+	}, unknown)
+}
+
+func TestDriftEndpoint(t *testing.T) {
+	assert.Equal(t, "/activity-service/activity/{id}/splits", driftEndpoint("/activity-service/activity/123/splits"))
+	assert.Equal(t, "/hrv-service/hrv/daily/{date}/{date}", driftEndpoint("/hrv-service/hrv/daily/2024-03-01/2024-03-07?x=1"))
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// ECG rhythm classifications
+const (
+	RhythmSinus        = "SINUS_RHYTHM"
+	RhythmAFib         = "ATRIAL_FIBRILLATION"
+	RhythmInconclusive = "INCONCLUSIVE"
+	RhythmPoorSignal   = "POOR_RECORDING"
+)
+
+// ErrECGUnsupported is returned by the ECG methods when none of the user's
+// devices can record an ECG
+var ErrECGUnsupported = errors.New("no ECG capable device registered")
+
+// ECGRecording is a single ECG taken with the watch's ECG app
+type ECGRecording struct {
+	ID              string     `json:"ecgId"`
+	StartTime       GarminTime `json:"startTimeGMT"`
+	Rhythm          string     `json:"rhythmClassification"` // one of the Rhythm* values
+	AvgHeartRate    int        `json:"averageHeartRate"`
+	DurationSeconds int        `json:"durationInSeconds"`
+	DeviceName      string     `json:"deviceName"`
+}
+
+// ECGWaveform holds the raw samples of a recording
+type ECGWaveform struct {
+	SampleRate float64   `json:"sampleRate"` // samples per second
+	Samples    []float64 `json:"samples"`    // microvolts
+}
+
+// ListECGRecordings retrieves the ECG recordings taken between start and end inclusive
+func (c *Client) ListECGRecordings(ctx context.Context, start, end time.Time, opts ...RequestOption) ([]ECGRecording, error) {
+	if err := c.checkECG(ctx, opts); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Add("startDate", start.Format("2006-01-02"))
+	params.Add("endDate", end.Format("2006-01-02"))
+
+	var recordings []ECGRecording
+	if err := c.Get(ctx, fmt.Sprintf("/ecg-service/ecg/recordings?%s", params.Encode()), &recordings, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list ECG recordings: %w", err)
+	}
+	return recordings, nil
+}
+
+// GetECGWaveform retrieves the samples of a recording
+func (c *Client) GetECGWaveform(ctx context.Context, id string, opts ...RequestOption) (*ECGWaveform, error) {
+	if err := c.checkECG(ctx, opts); err != nil {
+		return nil, err
+	}
+
+	var waveform ECGWaveform
+	path := fmt.Sprintf("/ecg-service/ecg/recordings/%s/waveform", url.PathEscape(id))
+	if err := c.Get(ctx, path, &waveform, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get ECG waveform %s: %w", id, err)
+	}
+	return &waveform, nil
+}
+
+// DownloadECGReport saves the PDF report of a recording to dest, resuming
+// partial downloads like DownloadActivityToFile
+func (c *Client) DownloadECGReport(ctx context.Context, id, dest string, opts ...RequestOption) (int64, error) {
+	if err := c.checkECG(ctx, opts); err != nil {
+		return 0, err
+	}
+
+	path := fmt.Sprintf("/ecg-service/ecg/recordings/%s/pdf", url.PathEscape(id))
+	n, err := c.downloadToFile(ctx, path, "application/pdf", dest, opts...)
+	if err != nil {
+		return n, fmt.Errorf("failed to download ECG report %s: %w", id, err)
+	}
+	return n, nil
+}
+
+// checkECG returns ErrECGUnsupported unless a registered device records ECGs.
+// Only a capable device is cached; without one the devices are fetched again
+// on the next call, so a newly paired watch is picked up.
+func (c *Client) checkECG(ctx context.Context, opts []RequestOption) error {
+	c.ecgMu.Lock()
+	capable := c.ecg
+	c.ecgMu.Unlock()
+	if capable {
+		return nil
+	}
+
+	devices, err := c.GetDevices(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	for _, d := range devices {
+		capable = capable || d.ECGCapable
+	}
+	if !capable {
+		return ErrECGUnsupported
+	}
+	c.ecgMu.Lock()
+	c.ecg = true
+	c.ecgMu.Unlock()
+	return nil
+}
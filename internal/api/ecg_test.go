@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestECG(t *testing.T) {
+	devices := `[{"deviceId": 1, "productDisplayName": "Venu 2", "ecgCapable": false},
+		{"deviceId": 2, "productDisplayName": "Venu 3", "ecgCapable": true}]`
+	deviceRequests := 0
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/device-service/deviceregistration/devices":
+			deviceRequests++
+			w.Write([]byte(devices))
+		case "/ecg-service/ecg/recordings":
+			assert.Equal(t, "2024-03-01", r.URL.Query().Get("startDate"))
+			assert.Equal(t, "2024-03-31", r.URL.Query().Get("endDate"))
+			w.Write([]byte(`[{"ecgId": "e1", "startTimeGMT": "2024-03-05T08:00:00Z", "rhythmClassification": "SINUS_RHYTHM",
+				"averageHeartRate": 62, "durationInSeconds": 30, "deviceName": "Venu 3"}]`))
+		case "/ecg-service/ecg/recordings/e1/waveform":
+			w.Write([]byte(`{"sampleRate": 512, "samples": [12.5, -3.1, 40.2]}`))
+		case "/ecg-service/ecg/recordings/e1/pdf":
+			assert.Equal(t, "application/pdf", r.Header.Get("Accept"))
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Write([]byte("%PDF-1.7"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+	ctx := context.Background()
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	recordings, err := client.ListECGRecordings(ctx, start, start.AddDate(0, 0, 30))
+	assert.NoError(t, err)
+	assert.Len(t, recordings, 1)
+	assert.Equal(t, RhythmSinus, recordings[0].Rhythm)
+
+	waveform, err := client.GetECGWaveform(ctx, "e1")
+	assert.NoError(t, err)
+	assert.Equal(t, 512.0, waveform.SampleRate)
+	assert.Len(t, waveform.Samples, 3)
+
+	dest := filepath.Join(t.TempDir(), "ecg.pdf")
+	_, err = client.DownloadECGReport(ctx, "e1", dest)
+	assert.NoError(t, err)
+	data, _ := os.ReadFile(dest)
+	assert.Equal(t, "%PDF-1.7", string(data))
+	assert.Equal(t, 1, deviceRequests, "the ECG capability is cached")
+
+	devices = `[{"deviceId": 1, "productDisplayName": "Venu 2"}]`
+	client = NewClientWithBaseURL(server.URL())
+	_, err = client.ListECGRecordings(ctx, start, start)
+	assert.ErrorIs(t, err, ErrECGUnsupported)
+	_, err = client.DownloadECGReport(ctx, "e1", dest)
+	assert.ErrorIs(t, err, ErrECGUnsupported)
+	assert.Equal(t, 3, deviceRequests, "a missing capability is not cached")
+
+	// A newly paired ECG capable watch is picked up
+	devices = `[{"deviceId": 2, "productDisplayName": "Venu 3", "ecgCapable": true}]`
+	_, err = client.ListECGRecordings(ctx, start, start.AddDate(0, 0, 30))
+	assert.NoError(t, err)
+	_, err = client.GetECGWaveform(ctx, "e1")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, deviceRequests)
+}
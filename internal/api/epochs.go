@@ -0,0 +1,44 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Movement intensities of a wellness epoch
+const (
+	IntensitySedentary    = "SEDENTARY"
+	IntensityActive       = "ACTIVE"
+	IntensityHighlyActive = "HIGHLY_ACTIVE"
+)
+
+// WellnessEpoch is one 15-minute interval of the all-day activity record that
+// Garmin's activity graph is drawn from
+type WellnessEpoch struct {
+	Start          GarminTime `json:"startGMT"`
+	End            GarminTime `json:"endGMT"`
+	Steps          int        `json:"steps"`
+	DistanceMeters float64    `json:"distanceInMeters"`
+	ActiveCalories float64    `json:"activeKilocalories"`
+	ActiveSeconds  int        `json:"activeTimeInSeconds"`
+	Intensity      string     `json:"intensity"` // one of the Intensity* values
+	MeanMET        *float64   `json:"meanMET"`
+	MaxMET         *float64   `json:"maxMET"`
+}
+
+// Duration returns the length of the epoch
+func (e WellnessEpoch) Duration() time.Duration {
+	return e.End.Sub(e.Start.Time)
+}
+
+// GetWellnessEpochs retrieves the day's wellness epochs in chronological order
+func (c *Client) GetWellnessEpochs(ctx context.Context, date time.Time, opts ...RequestOption) ([]WellnessEpoch, error) {
+	var epochs []WellnessEpoch
+	path := fmt.Sprintf("/wellness-service/wellness/epochs/%s", date.Format("2006-01-02"))
+
+	if err := c.Get(ctx, path, &epochs, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get wellness epochs: %w", err)
+	}
+	return epochs, nil
+}
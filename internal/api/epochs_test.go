@@ -0,0 +1,35 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWellnessEpochs(t *testing.T) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/wellness-service/wellness/epochs/2024-03-01", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"startGMT": "2024-03-01T07:00:00Z", "endGMT": "2024-03-01T07:15:00Z", "steps": 0, "intensity": "SEDENTARY", "meanMET": 1.0},
+			{"startGMT": "2024-03-01T07:15:00Z", "endGMT": "2024-03-01T07:30:00Z", "steps": 1820, "distanceInMeters": 1500.5,
+			 "activeKilocalories": 95.5, "activeTimeInSeconds": 900, "intensity": "HIGHLY_ACTIVE", "meanMET": 7.2, "maxMET": 9.8}
+		]`))
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+
+	epochs, err := client.GetWellnessEpochs(context.Background(), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Len(t, epochs, 2)
+	assert.Equal(t, 15*time.Minute, epochs[0].Duration())
+	assert.Nil(t, epochs[0].MaxMET)
+	assert.Equal(t, IntensityHighlyActive, epochs[1].Intensity)
+	assert.Equal(t, 7.2, *epochs[1].MeanMET)
+	assert.Equal(t, 1820, epochs[1].Steps)
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// ErrorResponse is a failed response as seen by an ErrorClassifier
+type ErrorResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Method     string
+	Path       string
+}
+
+// ErrorClassifier maps a failed response to a typed error, e.g. a proxy's
+// block page to a sentinel the application can handle. It returns nil for
+// responses it doesn't recognise, leaving them to the next classifier and
+// finally to the built-in handling, which returns an *APIError.
+type ErrorClassifier func(resp *ErrorResponse) error
+
+// WithErrorClassifier adds classify to the classifiers consulted, in the order
+// they were added, before a failed response becomes an *APIError
+func WithErrorClassifier(classify ErrorClassifier) ClientOption {
+	return func(c *Client) {
+		c.classifiers = append(c.classifiers, classify)
+	}
+}
+
+// handleAPIError turns a failed response into an error, giving the registered
+// classifiers the first say
+func (c *Client) handleAPIError(resp *resty.Response) error {
+	if len(c.classifiers) > 0 {
+		er := &ErrorResponse{
+			StatusCode: resp.StatusCode(),
+			Header:     resp.Header(),
+			Body:       resp.Body(),
+		}
+		er.Method, er.Path = requestTarget(resp)
+		for _, classify := range c.classifiers {
+			if err := classify(er); err != nil {
+				return err
+			}
+		}
+	}
+	return parseAPIError(resp)
+}
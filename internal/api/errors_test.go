@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIErrorContext(t *testing.T) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-123")
+		switch r.URL.Path {
+		case "/userprofile-service/socialProfile":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/gear-service/stats/missing":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code": 1004, "message": "gear not found"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`not json`))
+		}
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+	ctx := context.Background()
+
+	_, err := client.GetGearStats(ctx, "missing")
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, 1004, apiErr.Code)
+	assert.Equal(t, http.MethodGet, apiErr.Method)
+	assert.Equal(t, "/gear-service/stats/missing", apiErr.Path)
+	assert.Equal(t, "req-123", apiErr.RequestID)
+	assert.ErrorIs(t, err, ErrNotFound{})
+	assert.ErrorContains(t, err, "API error 1004: gear not found (GET /gear-service/stats/missing, request-id req-123)")
+
+	_, err = client.GetSleepData(ctx, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrBadRequest{})
+	assert.ErrorContains(t, err, "/wellness-service/sleep/daily/2024-03-01")
+
+	_, err = client.GetUserProfile(ctx)
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	assert.ErrorContains(t, err, "token expired")
+}
+
+// errBlocked is what a deployment might map its proxy's block page to
+var errBlocked = errors.New("blocked by proxy")
+
+func TestErrorClassifier(t *testing.T) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gear-service/stats/blocked" {
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`<html><title>Access denied</title></html>`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code": 1004, "message": "gear not found"}`))
+	})
+	defer server.Close()
+
+	var seen []string
+	client := NewClientWithBaseURL(server.URL())
+	WithErrorClassifier(func(resp *ErrorResponse) error {
+		seen = append(seen, resp.Method+" "+resp.Path)
+		if resp.StatusCode == http.StatusForbidden && strings.Contains(string(resp.Body), "Access denied") {
+			return errBlocked
+		}
+		return nil
+	})(client)
+	ctx := context.Background()
+
+	_, err := client.GetGearStats(ctx, "blocked")
+	assert.ErrorIs(t, err, errBlocked)
+
+	// Unrecognised responses keep the built-in handling
+	_, err = client.GetGearStats(ctx, "missing")
+	assert.ErrorIs(t, err, ErrNotFound{})
+	assert.Equal(t, []string{"GET /gear-service/stats/blocked", "GET /gear-service/stats/missing"}, seen)
+}
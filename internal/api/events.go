@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// RaceEvent is a race or event on the Garmin calendar. Upcoming events drive
+// the race widget and adaptive training plans.
+type RaceEvent struct {
+	ID             int64    `json:"id,omitempty"`
+	Name           string   `json:"eventName"`
+	Date           Date     `json:"date"`
+	SportType      string   `json:"eventType"` // e.g. "running", "cycling", "triathlon"
+	DistanceMeters float64  `json:"distance"`
+	GoalSeconds    *float64 `json:"goalTimeInSeconds,omitempty"`
+	Location       string   `json:"location,omitempty"`
+	URL            string   `json:"url,omitempty"`
+	// Primary marks the event the training plan and race widget count down to
+	Primary bool `json:"isPrimaryEvent"`
+}
+
+// GoalPace returns the pace in seconds per kilometer needed to reach the goal
+// time, or zero when no goal or distance is set
+func (e RaceEvent) GoalPace() float64 {
+	if e.GoalSeconds == nil || e.DistanceMeters <= 0 {
+		return 0
+	}
+	return *e.GoalSeconds / (e.DistanceMeters / 1000)
+}
+
+// GetRaceEvents retrieves the race events between start and end inclusive
+func (c *Client) GetRaceEvents(ctx context.Context, start, end time.Time, opts ...RequestOption) ([]RaceEvent, error) {
+	params := url.Values{}
+	params.Add("startDate", start.Format("2006-01-02"))
+	params.Add("endDate", end.Format("2006-01-02"))
+
+	var events []RaceEvent
+	if err := c.Get(ctx, fmt.Sprintf("/calendar-service/events?%s", params.Encode()), &events, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get race events: %w", err)
+	}
+	return events, nil
+}
+
+// CreateRaceEvent adds event to the calendar and returns it with its ID
+func (c *Client) CreateRaceEvent(ctx context.Context, event RaceEvent, opts ...RequestOption) (*RaceEvent, error) {
+	switch {
+	case event.Name == "":
+		return nil, errors.New("race event requires a name")
+	case event.Date.IsZero():
+		return nil, errors.New("race event requires a date")
+	case event.DistanceMeters < 0:
+		return nil, errors.New("race event distance cannot be negative")
+	}
+
+	var created RaceEvent
+	if err := c.Post(ctx, "/calendar-service/event", event, &created, opts...); err != nil {
+		return nil, fmt.Errorf("failed to create race event: %w", err)
+	}
+	return &created, nil
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRaceEvents(t *testing.T) {
+	var events []RaceEvent
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/calendar-service/event":
+			var e RaceEvent
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&e))
+			e.ID = int64(len(events) + 1)
+			events = append(events, e)
+			json.NewEncoder(w).Encode(e)
+		case r.URL.Path == "/calendar-service/events":
+			assert.Equal(t, "2024-01-01", r.URL.Query().Get("startDate"))
+			json.NewEncoder(w).Encode(events)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+	ctx := context.Background()
+
+	created, err := client.CreateRaceEvent(ctx, RaceEvent{
+		Name:           "City Marathon",
+		Date:           NewDate(time.Date(2024, 10, 13, 0, 0, 0, 0, time.UTC)),
+		SportType:      "running",
+		DistanceMeters: 42195,
+		GoalSeconds:    Ptr(3 * 3600.0),
+		Primary:        true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), created.ID)
+	assert.InDelta(t, 255.9, created.GoalPace(), 0.1)
+
+	list, err := client.GetRaceEvents(ctx, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+	assert.Equal(t, "2024-10-13", list[0].Date.String())
+	assert.True(t, list[0].Primary)
+
+	_, err = client.CreateRaceEvent(ctx, RaceEvent{Name: "No date"})
+	assert.Error(t, err)
+	assert.Zero(t, RaceEvent{DistanceMeters: 5000}.GoalPace())
+}
@@ -0,0 +1,160 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/daterange"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/time/rate"
+)
+
+// RangeOptions controls how FetchRange fans out per-day requests
+type RangeOptions struct {
+	// Concurrency bounds the number of requests in flight (default 4)
+	Concurrency int
+	// RequestsPerSecond limits the request rate; zero means unlimited
+	RequestsPerSecond float64
+	// MaxSpans overrides DefaultMaxSpans, the longest range in days a service
+	// accepts per request; zero disables splitting for that service
+	MaxSpans map[string]int
+}
+
+// DefaultRangeOptions returns the fan-out settings used by the *Range methods
+func DefaultRangeOptions() RangeOptions {
+	return RangeOptions{Concurrency: 4, RequestsPerSecond: 5}
+}
+
+// WithRangeOptions sets the fan-out settings used by the *Range methods
+func WithRangeOptions(opts RangeOptions) ClientOption {
+	return func(c *Client) {
+		c.rangeOpts = opts
+	}
+}
+
+// DayError is the failure of a single day in a range
+type DayError struct {
+	Date time.Time
+	Err  error
+}
+
+func (e DayError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Date.Format("2006-01-02"), e.Err)
+}
+
+func (e DayError) Unwrap() error {
+	return e.Err
+}
+
+// RangeError reports the days of a range that failed. It is returned together
+// with the results of the days that succeeded.
+type RangeError struct {
+	Failed []DayError
+}
+
+func (e *RangeError) Error() string {
+	msgs := make([]string, len(e.Failed))
+	for i, f := range e.Failed {
+		msgs[i] = f.Error()
+	}
+	return fmt.Sprintf("failed to fetch %d day(s): %s", len(e.Failed), strings.Join(msgs, "; "))
+}
+
+// Unwrap exposes the per-day errors to errors.Is and errors.As
+func (e *RangeError) Unwrap() []error {
+	errs := make([]error, len(e.Failed))
+	for i, f := range e.Failed {
+		errs[i] = f
+	}
+	return errs
+}
+
+// Days returns every calendar day from start to end inclusive, at midnight in start's location
+func Days(start, end time.Time) []time.Time {
+	return daterange.Days(start, end)
+}
+
+// FetchRange calls fetch for every day from start to end inclusive, concurrently
+// within the limits of opts. Results are returned in date order. Days that fail
+// are left out of the results and reported in a *RangeError; cancelling ctx
+// aborts the whole range and returns ctx.Err().
+func FetchRange[T any](ctx context.Context, start, end time.Time, opts RangeOptions, fetch func(ctx context.Context, day time.Time) (T, error)) ([]T, error) {
+	days := Days(start, end)
+	if len(days) == 0 {
+		return nil, nil
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultRangeOptions().Concurrency
+	}
+	var limiter *rate.Limiter
+	if opts.RequestsPerSecond > 0 {
+		limiter = rate.NewLimiter(rate.Limit(opts.RequestsPerSecond), 1)
+	}
+
+	results := make([]T, len(days))
+	ok := make([]bool, len(days))
+	var (
+		mu     sync.Mutex
+		failed []DayError
+	)
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+	for i, day := range days {
+		if gctx.Err() != nil {
+			break
+		}
+		g.Go(func() error {
+			if limiter != nil {
+				if err := limiter.Wait(gctx); err != nil {
+					return err
+				}
+			}
+			v, err := fetch(gctx, day)
+			if err != nil {
+				// Only cancellation stops the range; other failures are partial results
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				mu.Lock()
+				failed = append(failed, DayError{Date: day, Err: err})
+				mu.Unlock()
+				return nil
+			}
+			results[i], ok[i] = v, true
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	out := make([]T, 0, len(days))
+	for i := range days {
+		if ok[i] {
+			out = append(out, results[i])
+		}
+	}
+	if len(failed) > 0 {
+		sort.Slice(failed, func(i, j int) bool { return failed[i].Date.Before(failed[j].Date) })
+		return out, &RangeError{Failed: failed}
+	}
+	return out, nil
+}
+
+// IsPartial reports whether err only describes failed days of a range, in which
+// case the accompanying results are still usable
+func IsPartial(err error) bool {
+	var rangeErr *RangeError
+	return errors.As(err, &rangeErr)
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDays(t *testing.T) {
+	start := time.Date(2024, 2, 28, 15, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 3, 1, 1, 0, 0, 0, time.UTC)
+	days := Days(start, end)
+	assert.Len(t, days, 3)
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), days[1])
+	assert.Empty(t, Days(end, start))
+}
+
+func TestFetchRange(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 9)
+	errMissing := errors.New("no data")
+
+	t.Run("bounded concurrency and partial results", func(t *testing.T) {
+		var inFlight, peak int32
+		results, err := FetchRange(context.Background(), start, end, RangeOptions{Concurrency: 3},
+			func(ctx context.Context, day time.Time) (int, error) {
+				n := atomic.AddInt32(&inFlight, 1)
+				defer atomic.AddInt32(&inFlight, -1)
+				for {
+					p := atomic.LoadInt32(&peak)
+					if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				if day.Day() == 4 {
+					return 0, errMissing
+				}
+				return day.Day(), nil
+			})
+
+		assert.LessOrEqual(t, int(peak), 3)
+		assert.Equal(t, []int{1, 2, 3, 5, 6, 7, 8, 9, 10}, results)
+		assert.True(t, IsPartial(err))
+		assert.ErrorIs(t, err, errMissing)
+
+		var rangeErr *RangeError
+		assert.True(t, errors.As(err, &rangeErr))
+		assert.Len(t, rangeErr.Failed, 1)
+		assert.Equal(t, 4, rangeErr.Failed[0].Date.Day())
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		begin := time.Now()
+		_, err := FetchRange(context.Background(), start, start.AddDate(0, 0, 4), RangeOptions{Concurrency: 5, RequestsPerSecond: 50},
+			func(ctx context.Context, day time.Time) (int, error) { return 0, nil })
+		assert.NoError(t, err)
+		// Five requests at 50/s need at least four 20ms intervals
+		assert.GreaterOrEqual(t, time.Since(begin), 70*time.Millisecond)
+	})
+
+	t.Run("cancellation aborts", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		results, err := FetchRange(ctx, start, end, RangeOptions{Concurrency: 1},
+			func(ctx context.Context, day time.Time) (int, error) {
+				cancel()
+				return 0, ctx.Err()
+			})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.False(t, IsPartial(err))
+		assert.Nil(t, results)
+	})
+}
+
+func TestGetStepsDataRange(t *testing.T) {
+	mockServer := garmintest.NewServer()
+	defer mockServer.Close()
+	handleHealth(mockServer, func(w http.ResponseWriter, r *http.Request) {
+		date := path.Base(r.URL.Path)
+		if date == "2024-03-02" {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error": "boom"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"calendarDate": "%sT00:00:00Z", "totalSteps": 1000}`, date)
+	})
+
+	client := NewClientWithBaseURL(mockServer.URL())
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	steps, err := client.GetStepsDataRange(context.Background(), start, start.AddDate(0, 0, 2))
+
+	assert.True(t, IsPartial(err))
+	assert.Contains(t, err.Error(), "2024-03-02")
+	if assert.Len(t, steps, 2) {
+		assert.Equal(t, 1, steps[0].CalendarDate.Day())
+		assert.Equal(t, 3, steps[1].CalendarDate.Day())
+	}
+}
@@ -5,11 +5,11 @@ import (
 	"fmt"
 	"net/url"
 	"strconv"
-	"time"
 )
 
 // GearStats represents detailed statistics for a gear item
 type GearStats struct {
+	RawJSON
 	UUID            string  `json:"uuid"`            // Unique identifier for the gear item
 	Name            string  `json:"name"`            // Display name of the gear item
 	Distance        float64 `json:"distance"`        // in meters
@@ -22,19 +22,19 @@ type GearStats struct {
 
 // GearActivity represents a simplified activity linked to a gear item
 type GearActivity struct {
-	ActivityID   int64     `json:"activityId"`     // Activity identifier
-	ActivityName string    `json:"activityName"`   // Name of the activity
-	StartTime    time.Time `json:"startTimeLocal"` // Local start time of the activity
-	Duration     int       `json:"duration"`       // Duration in seconds
-	Distance     float64   `json:"distance"`       // Distance in meters
+	ActivityID   int64      `json:"activityId"`     // Activity identifier
+	ActivityName string     `json:"activityName"`   // Name of the activity
+	StartTime    GarminTime `json:"startTimeLocal"` // Local start time of the activity
+	Duration     int        `json:"duration"`       // Duration in seconds
+	Distance     float64    `json:"distance"`       // Distance in meters
 }
 
 // GetGearStats retrieves statistics for a specific gear item by its UUID
-func (c *Client) GetGearStats(ctx context.Context, gearUUID string) (GearStats, error) {
+func (c *Client) GetGearStats(ctx context.Context, gearUUID string, opts ...RequestOption) (GearStats, error) {
 	endpoint := fmt.Sprintf("/gear-service/stats/%s", gearUUID)
 
 	var stats GearStats
-	err := c.Get(ctx, endpoint, &stats)
+	err := c.Get(ctx, endpoint, &stats, opts...)
 	if err != nil {
 		return GearStats{}, err
 	}
@@ -42,18 +42,23 @@ func (c *Client) GetGearStats(ctx context.Context, gearUUID string) (GearStats,
 	return stats, nil
 }
 
-// GetGearActivities retrieves paginated activities associated with a gear item
-func (c *Client) GetGearActivities(ctx context.Context, gearUUID string, start, limit int) ([]GearActivity, error) {
+// GetGearActivities retrieves one page of the activities associated with a gear item
+func (c *Client) GetGearActivities(ctx context.Context, gearUUID string, req PageRequest, opts ...RequestOption) ([]GearActivity, error) {
 	path := fmt.Sprintf("/gear-service/activities/%s", gearUUID)
+	// The gear service pages by offset and limit rather than page number
 	params := url.Values{}
-	params.Add("start", strconv.Itoa(start))
-	params.Add("limit", strconv.Itoa(limit))
+	params.Add("start", strconv.Itoa(req.Offset()))
+	params.Add("limit", strconv.Itoa(req.normalize().PageSize))
 
 	var activities []GearActivity
-	err := c.Get(ctx, fmt.Sprintf("%s?%s", path, params.Encode()), &activities)
+	err := c.Get(ctx, fmt.Sprintf("%s?%s", path, params.Encode()), &activities, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get gear activities: %w", err)
 	}
 
+	loc := c.localZone(ctx)
+	for i := range activities {
+		activities[i].StartTime = activities[i].StartTime.Attach(loc)
+	}
 	return activities, nil
 }
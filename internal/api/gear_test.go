@@ -5,18 +5,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"net/http/httptest"
 	"strconv"
 	"testing"
 	"time"
 
+	"github.com/sstent/go-garminconnect/garmintest"
 	"github.com/sstent/go-garminconnect/internal/auth/garth"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestGearService(t *testing.T) {
 	// Create test server
-	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	srv := garmintest.NewServer()
+	srv.HandleAll(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 
 		switch r.URL.Path {
@@ -39,8 +40,8 @@ func TestGearService(t *testing.T) {
 			limit, _ := strconv.Atoi(limitStr)
 
 			activities := []GearActivity{
-				{ActivityID: 1, ActivityName: "Run 1", StartTime: time.Now(), Duration: 1800, Distance: 5000},
-				{ActivityID: 2, ActivityName: "Run 2", StartTime: time.Now().Add(-24 * time.Hour), Duration: 3600, Distance: 10000},
+				{ActivityID: 1, ActivityName: "Run 1", StartTime: NewGarminTime(time.Now()), Duration: 1800, Distance: 5000},
+				{ActivityID: 2, ActivityName: "Run 2", StartTime: NewGarminTime(time.Now().Add(-24 * time.Hour)), Duration: 3600, Distance: 10000},
 			}
 
 			// Simulate pagination
@@ -64,7 +65,7 @@ func TestGearService(t *testing.T) {
 		default:
 			w.WriteHeader(http.StatusNotFound)
 		}
-	}))
+	})
 	defer srv.Close()
 
 	t.Run("GetGearStats success", func(t *testing.T) {
@@ -79,7 +80,7 @@ func TestGearService(t *testing.T) {
 		mockAuth := NewMockAuthenticator()
 		client, err := NewClient(mockAuth, session, "")
 		assert.NoError(t, err)
-		client.HTTPClient.SetBaseURL(srv.URL)
+		client.HTTPClient.SetBaseURL(srv.URL())
 
 		stats, err := client.GetGearStats(context.Background(), "valid-uuid")
 		assert.NoError(t, err)
@@ -99,7 +100,7 @@ func TestGearService(t *testing.T) {
 		mockAuth := NewMockAuthenticator()
 		client, err := NewClient(mockAuth, session, "")
 		assert.NoError(t, err)
-		client.HTTPClient.SetBaseURL(srv.URL)
+		client.HTTPClient.SetBaseURL(srv.URL())
 
 		_, err = client.GetGearStats(context.Background(), "invalid-uuid")
 		assert.Error(t, err)
@@ -119,19 +120,19 @@ func TestGearService(t *testing.T) {
 		mockAuth := NewMockAuthenticator()
 		client, err := NewClient(mockAuth, session, "")
 		assert.NoError(t, err)
-		client.HTTPClient.SetBaseURL(srv.URL)
+		client.HTTPClient.SetBaseURL(srv.URL())
 
-		activities, err := client.GetGearActivities(context.Background(), "valid-uuid", 0, 1)
+		activities, err := client.GetGearActivities(context.Background(), "valid-uuid", FirstPage(1))
 		assert.NoError(t, err)
 		assert.Len(t, activities, 1)
 		assert.Equal(t, "Run 1", activities[0].ActivityName)
 
-		activities, err = client.GetGearActivities(context.Background(), "valid-uuid", 1, 1)
+		activities, err = client.GetGearActivities(context.Background(), "valid-uuid", FirstPage(1).Next())
 		assert.NoError(t, err)
 		assert.Len(t, activities, 1)
 		assert.Equal(t, "Run 2", activities[0].ActivityName)
 
-		_, err = client.GetGearActivities(context.Background(), "invalid-uuid", 0, 10)
+		_, err = client.GetGearActivities(context.Background(), "invalid-uuid", FirstPage(10))
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to get gear activities")
 	})
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// dailyGoalsPath is the endpoint for reading and updating daily goals
+const dailyGoalsPath = "/userprofile-service/userprofile/daily-goals"
+
+// DailyGoals holds the user's activity targets
+type DailyGoals struct {
+	Steps int `json:"stepGoal"`
+	// AutoSteps is set when Garmin adjusts the step goal from recent activity
+	AutoSteps              bool `json:"autoStepGoal"`
+	WeeklyIntensityMinutes int  `json:"weeklyIntensityMinutesGoal"` // Garmin tracks intensity minutes per week
+	Floors                 int  `json:"floorsClimbedGoal"`
+}
+
+// DailyGoalsUpdate changes the goals that are set and leaves nil fields untouched.
+// Setting Steps turns off the automatic step goal unless AutoSteps says otherwise.
+type DailyGoalsUpdate struct {
+	Steps                  *int  `json:"stepGoal,omitempty" validate:"omitempty,min=1,max=100000"`
+	AutoSteps              *bool `json:"autoStepGoal,omitempty"`
+	WeeklyIntensityMinutes *int  `json:"weeklyIntensityMinutesGoal,omitempty" validate:"omitempty,min=1,max=10080"`
+	Floors                 *int  `json:"floorsClimbedGoal,omitempty" validate:"omitempty,min=1,max=1000"`
+}
+
+// Validate ensures the requested goals are within the ranges Garmin accepts
+func (u *DailyGoalsUpdate) Validate() error {
+	validate := newValidator()
+	return validate.Struct(u)
+}
+
+// GetDailyGoals retrieves the user's step, intensity minutes and floors goals
+func (c *Client) GetDailyGoals(ctx context.Context, opts ...RequestOption) (*DailyGoals, error) {
+	var goals DailyGoals
+	if err := c.Get(ctx, dailyGoalsPath, &goals, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get daily goals: %w", err)
+	}
+	return &goals, nil
+}
+
+// UpdateDailyGoals changes the goals set in update and returns the resulting goals
+func (c *Client) UpdateDailyGoals(ctx context.Context, update DailyGoalsUpdate, opts ...RequestOption) (*DailyGoals, error) {
+	if err := update.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid daily goals: %w", err)
+	}
+	if update.Steps != nil && update.AutoSteps == nil {
+		update.AutoSteps = Ptr(false)
+	}
+
+	var goals DailyGoals
+	if err := c.Put(ctx, dailyGoalsPath, update, &goals, opts...); err != nil {
+		return nil, fmt.Errorf("failed to update daily goals: %w", err)
+	}
+	return &goals, nil
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDailyGoals(t *testing.T) {
+	goals := DailyGoals{Steps: 8000, AutoSteps: true, WeeklyIntensityMinutes: 150, Floors: 10}
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/userprofile-service/userprofile/daily-goals", r.URL.Path)
+		if r.Method == http.MethodPut {
+			var update map[string]interface{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&update))
+			assert.Equal(t, map[string]interface{}{"stepGoal": 12000.0, "autoStepGoal": false}, update)
+			goals.Steps = 12000
+			goals.AutoSteps = false
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(goals)
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+	ctx := context.Background()
+
+	current, err := client.GetDailyGoals(ctx)
+	assert.NoError(t, err)
+	assert.True(t, current.AutoSteps)
+	assert.Equal(t, 150, current.WeeklyIntensityMinutes)
+
+	updated, err := client.UpdateDailyGoals(ctx, DailyGoalsUpdate{Steps: Ptr(12000)})
+	assert.NoError(t, err)
+	assert.Equal(t, 12000, updated.Steps)
+	assert.False(t, updated.AutoSteps)
+	assert.Equal(t, 10, updated.Floors)
+
+	_, err = client.UpdateDailyGoals(ctx, DailyGoalsUpdate{Floors: Ptr(0)})
+	assert.Error(t, err)
+}
@@ -6,78 +6,137 @@ import (
 	"time"
 )
 
-// HRVData represents Heart Rate Variability data
+// HRVData represents Heart Rate Variability data.
+// Metrics are nil when no HRV was measured, e.g. without a compatible device.
 type HRVData struct {
-	Date               time.Time `json:"date"`
-	RestingHrv         float64   `json:"restingHrv"`
-	WeeklyAvg          float64   `json:"weeklyAvg"`
-	LastNightAvg       float64   `json:"lastNightAvg"`
-	HrvStatus          string    `json:"hrvStatus"`
-	HrvStatusMessage   string    `json:"hrvStatusMessage"`
-	BaselineHrv        int       `json:"baselineHrv"`
-	ChangeFromBaseline int       `json:"changeFromBaseline"`
+	RawJSON
+	Date               Date     `json:"date"`
+	RestingHrv         *float64 `json:"restingHrv"`
+	WeeklyAvg          *float64 `json:"weeklyAvg"`
+	LastNightAvg       *float64 `json:"lastNightAvg"`
+	HrvStatus          string   `json:"hrvStatus"`
+	HrvStatusMessage   string   `json:"hrvStatusMessage"`
+	BaselineHrv        *int     `json:"baselineHrv"`
+	ChangeFromBaseline *int     `json:"changeFromBaseline"`
 }
 
-// BodyBatteryData represents Garmin's Body Battery energy metric
+// BodyBatteryData represents Garmin's Body Battery energy metric.
+// Values are nil for days the device wasn't worn.
 type BodyBatteryData struct {
-	Date    time.Time `json:"date"`
-	Charged int       `json:"charged"` // 0-100 scale
-	Drained int       `json:"drained"` // 0-100 scale
-	Highest int       `json:"highest"` // highest value of the day
-	Lowest  int       `json:"lowest"`  // lowest value of the day
+	RawJSON
+	Date    Date `json:"date"`
+	Charged *int `json:"charged"` // 0-100 scale
+	Drained *int `json:"drained"` // 0-100 scale
+	Highest *int `json:"highest"` // highest value of the day
+	Lowest  *int `json:"lowest"`  // lowest value of the day
 }
 
 // GetSleepData retrieves sleep data for a specific date
-func (c *Client) GetSleepData(ctx context.Context, date time.Time) (*SleepData, error) {
+func (c *Client) GetSleepData(ctx context.Context, date time.Time, opts ...RequestOption) (*SleepData, error) {
 	var data SleepData
 	path := fmt.Sprintf("/wellness-service/sleep/daily/%s", date.Format("2006-01-02"))
 
-	if err := c.Get(ctx, path, &data); err != nil {
+	if err := c.Get(ctx, path, &data, opts...); err != nil {
 		return nil, fmt.Errorf("failed to get sleep data: %w", err)
 	}
 	return &data, nil
 }
 
 // GetHRVData retrieves Heart Rate Variability data for a specific date
-func (c *Client) GetHRVData(ctx context.Context, date time.Time) (*HRVData, error) {
+func (c *Client) GetHRVData(ctx context.Context, date time.Time, opts ...RequestOption) (*HRVData, error) {
 	var data HRVData
 	path := fmt.Sprintf("/hrv-service/hrv/%s", date.Format("2006-01-02"))
 
-	if err := c.Get(ctx, path, &data); err != nil {
+	if err := c.Get(ctx, path, &data, opts...); err != nil {
 		return nil, fmt.Errorf("failed to get HRV data: %w", err)
 	}
 	return &data, nil
 }
 
 // GetStressData retrieves stress data for a specific date
-func (c *Client) GetStressData(ctx context.Context, date time.Time) (*DailyStress, error) {
+func (c *Client) GetStressData(ctx context.Context, date time.Time, opts ...RequestOption) (*DailyStress, error) {
 	var data DailyStress
 	path := fmt.Sprintf("/wellness-service/stress/daily/%s", date.Format("2006-01-02"))
 
-	if err := c.Get(ctx, path, &data); err != nil {
+	if err := c.Get(ctx, path, &data, opts...); err != nil {
 		return nil, fmt.Errorf("failed to get stress data: %w", err)
 	}
 	return &data, nil
 }
 
 // GetStepsData retrieves step count data for a specific date
-func (c *Client) GetStepsData(ctx context.Context, date time.Time) (*DailySteps, error) {
+func (c *Client) GetStepsData(ctx context.Context, date time.Time, opts ...RequestOption) (*DailySteps, error) {
 	var data DailySteps
 	path := fmt.Sprintf("/wellness-service/steps/daily/%s", date.Format("2006-01-02"))
 
-	if err := c.Get(ctx, path, &data); err != nil {
+	if err := c.Get(ctx, path, &data, opts...); err != nil {
 		return nil, fmt.Errorf("failed to get steps data: %w", err)
 	}
 	return &data, nil
 }
 
 // GetBodyBatteryData retrieves Body Battery data for a specific date
-func (c *Client) GetBodyBatteryData(ctx context.Context, date time.Time) (*BodyBatteryData, error) {
+func (c *Client) GetBodyBatteryData(ctx context.Context, date time.Time, opts ...RequestOption) (*BodyBatteryData, error) {
 	var data BodyBatteryData
 	path := fmt.Sprintf("/bodybattery-service/bodybattery/%s", date.Format("2006-01-02"))
 
-	if err := c.Get(ctx, path, &data); err != nil {
+	if err := c.Get(ctx, path, &data, opts...); err != nil {
 		return nil, fmt.Errorf("failed to get Body Battery data: %w", err)
 	}
 	return &data, nil
 }
+
+// GetSleepDataRange retrieves sleep data for every day from start to end inclusive
+func (c *Client) GetSleepDataRange(ctx context.Context, start, end time.Time, opts ...RequestOption) ([]SleepData, error) {
+	return FetchRange(ctx, start, end, c.rangeOpts, func(ctx context.Context, day time.Time) (SleepData, error) {
+		data, err := c.GetSleepData(ctx, day, opts...)
+		if err != nil {
+			return SleepData{}, err
+		}
+		return *data, nil
+	})
+}
+
+// GetHRVDataRange retrieves HRV data for every day from start to end inclusive
+func (c *Client) GetHRVDataRange(ctx context.Context, start, end time.Time, opts ...RequestOption) ([]HRVData, error) {
+	return FetchRange(ctx, start, end, c.rangeOpts, func(ctx context.Context, day time.Time) (HRVData, error) {
+		data, err := c.GetHRVData(ctx, day, opts...)
+		if err != nil {
+			return HRVData{}, err
+		}
+		return *data, nil
+	})
+}
+
+// GetStressDataRange retrieves stress data for every day from start to end inclusive
+func (c *Client) GetStressDataRange(ctx context.Context, start, end time.Time, opts ...RequestOption) ([]DailyStress, error) {
+	return FetchRange(ctx, start, end, c.rangeOpts, func(ctx context.Context, day time.Time) (DailyStress, error) {
+		data, err := c.GetStressData(ctx, day, opts...)
+		if err != nil {
+			return DailyStress{}, err
+		}
+		return *data, nil
+	})
+}
+
+// GetStepsDataRange retrieves step counts for every day from start to end inclusive
+func (c *Client) GetStepsDataRange(ctx context.Context, start, end time.Time, opts ...RequestOption) ([]DailySteps, error) {
+	return FetchRange(ctx, start, end, c.rangeOpts, func(ctx context.Context, day time.Time) (DailySteps, error) {
+		data, err := c.GetStepsData(ctx, day, opts...)
+		if err != nil {
+			return DailySteps{}, err
+		}
+		return *data, nil
+	})
+}
+
+// GetBodyBatteryDataRange retrieves Body Battery data for every day from start to end inclusive
+func (c *Client) GetBodyBatteryDataRange(ctx context.Context, start, end time.Time, opts ...RequestOption) ([]BodyBatteryData, error) {
+	return FetchRange(ctx, start, end, c.rangeOpts, func(ctx context.Context, day time.Time) (BodyBatteryData, error) {
+		data, err := c.GetBodyBatteryData(ctx, day, opts...)
+		if err != nil {
+			return BodyBatteryData{}, err
+		}
+		return *data, nil
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/sstent/go-garminconnect/garmintest"
 	"github.com/sstent/go-garminconnect/internal/auth/garth"
 	"github.com/stretchr/testify/assert"
 )
@@ -18,11 +19,11 @@ func BenchmarkGetSleepData(b *testing.B) {
 	testDate := now.Format(time.RFC3339)
 
 	// Create test server
-	mockServer := NewMockServer()
+	mockServer := garmintest.NewServer()
 	defer mockServer.Close()
 
 	// Setup handler for health endpoint
-	mockServer.SetHealthHandler(func(w http.ResponseWriter, r *http.Request) {
+	handleHealth(mockServer, func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode(map[string]interface{}{
@@ -59,11 +60,11 @@ func BenchmarkGetHRVData(b *testing.B) {
 	testDate := now.Format(time.RFC3339)
 
 	// Create test server
-	mockServer := NewMockServer()
+	mockServer := garmintest.NewServer()
 	defer mockServer.Close()
 
 	// Setup handler for health endpoint
-	mockServer.SetHealthHandler(func(w http.ResponseWriter, r *http.Request) {
+	handleHealth(mockServer, func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode(map[string]interface{}{
@@ -89,11 +90,11 @@ func BenchmarkGetBodyBatteryData(b *testing.B) {
 	testDate := now.Format(time.RFC3339)
 
 	// Create test server
-	mockServer := NewMockServer()
+	mockServer := garmintest.NewServer()
 	defer mockServer.Close()
 
 	// Setup handler for health endpoint
-	mockServer.SetHealthHandler(func(w http.ResponseWriter, r *http.Request) {
+	handleHealth(mockServer, func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode(map[string]interface{}{
@@ -148,21 +149,14 @@ func TestGetSleepData(t *testing.T) {
 			},
 			mockStatus: http.StatusOK,
 			expected: &SleepData{
-				CalendarDate:      now.Truncate(time.Second), // Truncate to avoid precision issues
-				SleepTimeSeconds:  28800,
-				DeepSleepSeconds:  7200,
-				LightSleepSeconds: 14400,
-				RemSleepSeconds:   7200,
-				AwakeSeconds:      1800,
-				SleepScore:        85,
-				SleepScores: struct {
-					Overall  int `json:"overall"`
-					Duration int `json:"duration"`
-					Deep     int `json:"deep"`
-					Rem      int `json:"rem"`
-					Light    int `json:"light"`
-					Awake    int `json:"awake"`
-				}{
+				CalendarDate:      NewDate(now),
+				SleepTimeSeconds:  Ptr(28800),
+				DeepSleepSeconds:  Ptr(7200),
+				LightSleepSeconds: Ptr(14400),
+				RemSleepSeconds:   Ptr(7200),
+				AwakeSeconds:      Ptr(1800),
+				SleepScore:        Ptr(85),
+				SleepScores: &SleepScores{
 					Overall:  85,
 					Duration: 90,
 					Deep:     80,
@@ -183,7 +177,7 @@ func TestGetSleepData(t *testing.T) {
 		},
 	}
 
-	mockServer := NewMockServer()
+	mockServer := garmintest.NewServer()
 	defer mockServer.Close()
 
 	for _, tt := range tests {
@@ -200,7 +194,7 @@ func TestGetSleepData(t *testing.T) {
 			client.HTTPClient.SetBaseURL(mockServer.URL())
 
 			mockServer.Reset()
-			mockServer.SetHealthHandler(func(w http.ResponseWriter, r *http.Request) {
+			handleHealth(mockServer, func(w http.ResponseWriter, r *http.Request) {
 				// Only handle sleep data requests
 				if strings.Contains(r.URL.Path, "sleep/daily") {
 					w.Header().Set("Content-Type", "application/json")
@@ -265,10 +259,10 @@ func TestGetHRVData(t *testing.T) {
 			},
 			mockStatus: http.StatusOK,
 			expected: &HRVData{
-				Date:         now.Truncate(time.Second),
-				RestingHrv:   65.0,
-				WeeklyAvg:    62.0,
-				LastNightAvg: 68.0,
+				Date:         NewDate(now),
+				RestingHrv:   Ptr(65.0),
+				WeeklyAvg:    Ptr(62.0),
+				LastNightAvg: Ptr(68.0),
 			},
 		},
 		{
@@ -282,7 +276,7 @@ func TestGetHRVData(t *testing.T) {
 		},
 	}
 
-	mockServer := NewMockServer()
+	mockServer := garmintest.NewServer()
 	defer mockServer.Close()
 
 	for _, tt := range tests {
@@ -299,7 +293,7 @@ func TestGetHRVData(t *testing.T) {
 			client.HTTPClient.SetBaseURL(mockServer.URL())
 
 			mockServer.Reset()
-			mockServer.SetHealthHandler(func(w http.ResponseWriter, r *http.Request) {
+			handleHealth(mockServer, func(w http.ResponseWriter, r *http.Request) {
 				// Only handle HRV data requests
 				if strings.Contains(r.URL.Path, "hrv/") {
 					w.Header().Set("Content-Type", "application/json")
@@ -354,11 +348,11 @@ func TestGetBodyBatteryData(t *testing.T) {
 			},
 			mockStatus: http.StatusOK,
 			expected: &BodyBatteryData{
-				Date:    now.Truncate(time.Second),
-				Charged: 85,
-				Drained: 45,
-				Highest: 95,
-				Lowest:  30,
+				Date:    NewDate(now),
+				Charged: Ptr(85),
+				Drained: Ptr(45),
+				Highest: Ptr(95),
+				Lowest:  Ptr(30),
 			},
 		},
 		{
@@ -372,7 +366,7 @@ func TestGetBodyBatteryData(t *testing.T) {
 		},
 	}
 
-	mockServer := NewMockServer()
+	mockServer := garmintest.NewServer()
 	defer mockServer.Close()
 
 	for _, tt := range tests {
@@ -389,7 +383,7 @@ func TestGetBodyBatteryData(t *testing.T) {
 			client.HTTPClient.SetBaseURL(mockServer.URL())
 
 			mockServer.Reset()
-			mockServer.SetHealthHandler(func(w http.ResponseWriter, r *http.Request) {
+			handleHealth(mockServer, func(w http.ResponseWriter, r *http.Request) {
 				// Only handle body battery requests
 				if strings.Contains(r.URL.Path, "bodybattery/") {
 					w.Header().Set("Content-Type", "application/json")
@@ -420,3 +414,33 @@ func TestGetBodyBatteryData(t *testing.T) {
 		})
 	}
 }
+
+// TestMissingMetricsAreNil ensures absent and null metrics are distinguishable from zeros
+func TestMissingMetricsAreNil(t *testing.T) {
+	mockServer := garmintest.NewServer()
+	defer mockServer.Close()
+	handleHealth(mockServer, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"calendarDate": "2024-03-01", "date": "2024-03-01", "sleepTimeSeconds": 0, "sleepScore": null, "lastNightAvg": null, "charged": 0}`))
+	})
+	client := NewClientWithBaseURL(mockServer.URL())
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	sleep, err := client.GetSleepData(context.Background(), date)
+	assert.NoError(t, err)
+	assert.Equal(t, Ptr(0), sleep.SleepTimeSeconds)
+	assert.Nil(t, sleep.SleepScore)
+	assert.Nil(t, sleep.DeepSleepSeconds)
+	assert.Nil(t, sleep.SleepScores)
+	assert.NoError(t, sleep.Validate())
+
+	hrv, err := client.GetHRVData(context.Background(), date)
+	assert.NoError(t, err)
+	assert.Nil(t, hrv.LastNightAvg)
+	assert.Equal(t, 0.0, Value(hrv.WeeklyAvg))
+
+	battery, err := client.GetBodyBatteryData(context.Background(), date)
+	assert.NoError(t, err)
+	assert.Equal(t, Ptr(0), battery.Charged)
+	assert.Nil(t, battery.Highest)
+}
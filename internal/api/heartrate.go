@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// HeartRateData is the all-day heart rate of a single day. Summary values are
+// nil for days the device wasn't worn.
+type HeartRateData struct {
+	RawJSON
+	Date              Date         `json:"calendarDate"`
+	RestingHR         *int         `json:"restingHeartRate"`
+	MinHR             *int         `json:"minHeartRate"`
+	MaxHR             *int         `json:"maxHeartRate"`
+	SevenDayRestingHR *int         `json:"lastSevenDaysAvgRestingHeartRate"`
+	Values            [][]*float64 `json:"heartRateValues"` // [epoch milliseconds, bpm or null] pairs
+}
+
+// HeartRateSample is a single all-day heart rate reading
+type HeartRateSample struct {
+	Time time.Time
+	BPM  int
+}
+
+// Samples returns the day's readings, skipping gaps where no heart rate was measured
+func (d HeartRateData) Samples() []HeartRateSample {
+	var samples []HeartRateSample
+	for _, v := range d.Values {
+		if s, ok := heartRateSample(v); ok {
+			samples = append(samples, s)
+		}
+	}
+	return samples
+}
+
+// heartRateSample converts a [epoch milliseconds, bpm] pair, reporting false
+// for gaps where no heart rate was measured
+func heartRateSample(v []*float64) (HeartRateSample, bool) {
+	if len(v) < 2 || v[0] == nil || v[1] == nil {
+		return HeartRateSample{}, false
+	}
+	return HeartRateSample{Time: time.UnixMilli(int64(*v[0])).UTC(), BPM: int(*v[1])}, true
+}
+
+// GetHeartRateData retrieves all-day heart rate data for a specific date
+func (c *Client) GetHeartRateData(ctx context.Context, date time.Time, opts ...RequestOption) (*HeartRateData, error) {
+	var data HeartRateData
+	path := fmt.Sprintf("/wellness-service/wellness/dailyHeartRate/%s", date.Format("2006-01-02"))
+
+	if err := c.Get(ctx, path, &data, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get heart rate data: %w", err)
+	}
+	return &data, nil
+}
+
+// StreamHeartRateSamples calls fn for each of the day's all-day heart rate
+// readings as it is decoded, skipping gaps, without holding the day's series
+// in memory. Returning an error from fn stops the stream and returns that error.
+func (c *Client) StreamHeartRateSamples(ctx context.Context, date time.Time, fn func(HeartRateSample) error, opts ...RequestOption) error {
+	path := fmt.Sprintf("/wellness-service/wellness/dailyHeartRate/%s", date.Format("2006-01-02"))
+
+	err := c.GetStream(ctx, path, func(body io.Reader) error {
+		return StreamArray(body, "heartRateValues", func(v []*float64) error {
+			if s, ok := heartRateSample(v); ok {
+				return fn(s)
+			}
+			return nil
+		})
+	}, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to stream heart rate data: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// ActivityFilter narrows activity searches; zero fields are not filtered on
+type ActivityFilter struct {
+	Start time.Time // first day, inclusive
+	End   time.Time // last day, inclusive
+	Type  string    // activity type key, e.g. "running"
+}
+
+// values encodes the filter as activity search query parameters
+func (f ActivityFilter) values() url.Values {
+	params := url.Values{}
+	if !f.Start.IsZero() {
+		params.Add("startDate", f.Start.Format("2006-01-02"))
+	}
+	if !f.End.IsZero() {
+		params.Add("endDate", f.End.Format("2006-01-02"))
+	}
+	if f.Type != "" {
+		params.Add("activityType", f.Type)
+	}
+	return params
+}
+
+// GetActivityCount returns the number of activities matching filter, letting
+// full-history jobs size their work before iterating pages
+func (c *Client) GetActivityCount(ctx context.Context, filter ActivityFilter, opts ...RequestOption) (int, error) {
+	response, err := c.searchActivities(ctx, filter, FirstPage(1), opts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count activities: %w", err)
+	}
+	return response.Pagination.TotalCount, nil
+}
+
+// GetLastActivity returns the most recent activity matching filter, or nil
+// when there is none
+func (c *Client) GetLastActivity(ctx context.Context, filter ActivityFilter, opts ...RequestOption) (*Activity, error) {
+	response, err := c.searchActivities(ctx, filter, FirstPage(1), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last activity: %w", err)
+	}
+	if len(response.Activities) == 0 {
+		return nil, nil
+	}
+	activity := response.Activities[0].ToActivity(c.localZone(ctx))
+	return &activity, nil
+}
+
+// GetFirstActivity returns the oldest activity matching filter, or nil when
+// there is none
+func (c *Client) GetFirstActivity(ctx context.Context, filter ActivityFilter, opts ...RequestOption) (*Activity, error) {
+	count, err := c.GetActivityCount(ctx, filter, opts...)
+	if err != nil || count == 0 {
+		return nil, err
+	}
+
+	// Results are sorted newest first, so the oldest is alone on the last page
+	response, err := c.searchActivities(ctx, filter, PageRequest{Page: count, PageSize: 1}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get first activity: %w", err)
+	}
+	if len(response.Activities) == 0 {
+		return nil, nil
+	}
+	activity := response.Activities[0].ToActivity(c.localZone(ctx))
+	return &activity, nil
+}
+
+// GetFirstActivityDate returns the start time of the user's oldest activity,
+// or the zero time when no activities have been recorded
+func (c *Client) GetFirstActivityDate(ctx context.Context, opts ...RequestOption) (time.Time, error) {
+	activity, err := c.GetFirstActivity(ctx, ActivityFilter{}, opts...)
+	if err != nil || activity == nil {
+		return time.Time{}, err
+	}
+	return activity.StartTime.Time, nil
+}
+
+// searchActivities fetches one page of the activities matching filter
+func (c *Client) searchActivities(ctx context.Context, filter ActivityFilter, page PageRequest, opts []RequestOption) (*ActivitiesResponse, error) {
+	params := page.values()
+	for key, values := range filter.values() {
+		params[key] = values
+	}
+
+	var response ActivitiesResponse
+	path := fmt.Sprintf("/activitylist-service/activities/search?%s", params.Encode())
+	if err := c.Get(ctx, path, &response, opts...); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActivityHistory(t *testing.T) {
+	// Newest first, as returned by Garmin
+	all := []map[string]interface{}{
+		{"activityId": 3, "activityType": "running", "startTimeLocal": "2024-03-01T07:00:00"},
+		{"activityId": 2, "activityType": "cycling", "startTimeLocal": "2023-06-10T09:00:00"},
+		{"activityId": 1, "activityType": "running", "startTimeLocal": "2019-04-02T18:30:00"},
+	}
+	var empty bool
+	server := garmintest.NewServer()
+	server.Handle(garmintest.Activities, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		var matched []map[string]interface{}
+		for _, a := range all {
+			if !empty && (q.Get("activityType") == "" || a["activityType"] == q.Get("activityType")) {
+				matched = append(matched, a)
+			}
+		}
+		page, _ := strconv.Atoi(q.Get("page"))
+		size, _ := strconv.Atoi(q.Get("pageSize"))
+		from := min((page-1)*size, len(matched))
+		to := min(from+size, len(matched))
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"activities": matched[from:to],
+			"pagination": map[string]int{"page": page, "pageSize": size, "totalCount": len(matched)},
+		})
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+	ctx := context.Background()
+
+	count, err := client.GetActivityCount(ctx, ActivityFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	count, err = client.GetActivityCount(ctx, ActivityFilter{Type: "running"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	last, err := client.GetLastActivity(ctx, ActivityFilter{Type: "cycling"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), last.ActivityID)
+
+	// Local start times are placed in the profile zone of the settings fixture
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	first, err := client.GetFirstActivityDate(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2019, 4, 2, 18, 30, 0, 0, berlin), first)
+
+	empty = true
+	first, err = client.GetFirstActivityDate(ctx)
+	assert.NoError(t, err)
+	assert.True(t, first.IsZero())
+	last, err = client.GetLastActivity(ctx, ActivityFilter{})
+	assert.NoError(t, err)
+	assert.Nil(t, last)
+}
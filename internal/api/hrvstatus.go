@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// HRV statuses reported against the personal baseline
+const (
+	HRVStatusBalanced   = "BALANCED"
+	HRVStatusUnbalanced = "UNBALANCED"
+	HRVStatusLow        = "LOW"
+	HRVStatusPoor       = "POOR"
+	HRVStatusNone       = "NONE" // baseline still being established
+)
+
+// HRVStatus is one day of the HRV status history
+type HRVStatus struct {
+	Date         Date         `json:"calendarDate"`
+	Status       string       `json:"status"` // one of the HRVStatus* constants
+	WeeklyAvg    *int         `json:"weeklyAvg"`
+	LastNightAvg *int         `json:"lastNightAvg"`
+	LastNightMax *int         `json:"lastNight5MinHigh"`
+	Baseline     *HRVBaseline `json:"baseline"` // nil until a baseline is established
+}
+
+// HRVBaseline holds the personal HRV ranges, in milliseconds, that the
+// weekly average is compared against
+type HRVBaseline struct {
+	LowUpper      int `json:"lowUpper"`      // averages below this are LOW
+	BalancedLow   int `json:"balancedLow"`   // lower bound of the balanced range
+	BalancedUpper int `json:"balancedUpper"` // upper bound of the balanced range
+}
+
+// InBalancedRange reports whether the weekly average lies inside the baseline's balanced range
+func (s HRVStatus) InBalancedRange() bool {
+	if s.Baseline == nil || s.WeeklyAvg == nil {
+		return false
+	}
+	return *s.WeeklyAvg >= s.Baseline.BalancedLow && *s.WeeklyAvg <= s.Baseline.BalancedUpper
+}
+
+// GetHRVStatusRange retrieves the daily HRV status and baseline from start to
+// end inclusive, in date order. Days without a reading are left out.
+func (c *Client) GetHRVStatusRange(ctx context.Context, start, end time.Time, opts ...RequestOption) ([]HRVStatus, error) {
+	return fetchSpans(ctx, c, "/hrv-service", start, end, func(ctx context.Context, start, end time.Time) ([]HRVStatus, error) {
+		var response struct {
+			Summaries []HRVStatus `json:"hrvSummaries"`
+		}
+		path := fmt.Sprintf("/hrv-service/hrv/daily/%s/%s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+		if err := c.Get(ctx, path, &response, opts...); err != nil {
+			return nil, fmt.Errorf("failed to get HRV status: %w", err)
+		}
+		return response.Summaries, nil
+	})
+}
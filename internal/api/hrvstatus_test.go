@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHRVStatusRange(t *testing.T) {
+	var paths []string
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/hrv-service/hrv/daily/2024-03-01/2024-03-28" {
+			w.Write([]byte(`{"hrvSummaries": [{"calendarDate": "2024-03-29", "status": "NONE", "lastNightAvg": 40}]}`))
+			return
+		}
+		w.Write([]byte(`{"hrvSummaries": [
+			{"calendarDate": "2024-03-01", "status": "BALANCED", "weeklyAvg": 52, "lastNightAvg": 49, "lastNight5MinHigh": 71,
+				"baseline": {"lowUpper": 41, "balancedLow": 46, "balancedUpper": 58, "markerValue": 0.4}},
+			{"calendarDate": "2024-03-02", "status": "UNBALANCED", "weeklyAvg": 60, "lastNightAvg": 66,
+				"baseline": {"lowUpper": 41, "balancedLow": 46, "balancedUpper": 58}}
+		]}`))
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	history, err := client.GetHRVStatusRange(context.Background(), start, start.AddDate(0, 0, 29))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/hrv-service/hrv/daily/2024-03-01/2024-03-28", "/hrv-service/hrv/daily/2024-03-29/2024-03-30"}, paths)
+
+	assert.Len(t, history, 3)
+	assert.Equal(t, HRVStatusBalanced, history[0].Status)
+	assert.Equal(t, 58, history[0].Baseline.BalancedUpper)
+	assert.Equal(t, 71, *history[0].LastNightMax)
+	assert.True(t, history[0].InBalancedRange())
+	assert.False(t, history[1].InBalancedRange())
+	assert.Equal(t, HRVStatusNone, history[2].Status)
+	assert.Nil(t, history[2].Baseline)
+	assert.False(t, history[2].InBalancedRange())
+}
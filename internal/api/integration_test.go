@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/sstent/go-garminconnect/garmintest"
 	"github.com/sstent/go-garminconnect/internal/auth/garth"
 	"github.com/stretchr/testify/assert"
 )
@@ -14,11 +15,11 @@ import (
 // TestIntegrationHealthMetrics tests end-to-end retrieval of all health metrics
 func TestIntegrationHealthMetrics(t *testing.T) {
 	// Create test server
-	mockServer := NewMockServer()
+	mockServer := garmintest.NewServer()
 	defer mockServer.Close()
 
 	// Setup mock responses
-	mockServer.SetHealthHandler(func(w http.ResponseWriter, r *http.Request) {
+	handleHealth(mockServer, func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		switch {
 		case strings.Contains(r.URL.Path, "sleep/daily"):
@@ -95,8 +96,8 @@ func TestIntegrationHealthMetrics(t *testing.T) {
 		data, err := client.GetSleepData(ctx, date)
 		assert.NoError(t, err)
 		assert.NotNil(t, data)
-		assert.Equal(t, 28800, data.SleepTimeSeconds)
-		assert.Equal(t, 85, data.SleepScore)
+		assert.Equal(t, Ptr(28800), data.SleepTimeSeconds)
+		assert.Equal(t, Ptr(85), data.SleepScore)
 	})
 
 	t.Run("RetrieveStressData", func(t *testing.T) {
@@ -119,7 +120,7 @@ func TestIntegrationHealthMetrics(t *testing.T) {
 		data, err := client.GetHRVData(ctx, date)
 		assert.NoError(t, err)
 		assert.NotNil(t, data)
-		assert.Equal(t, 65.0, data.RestingHrv)
+		assert.Equal(t, Ptr(65.0), data.RestingHrv)
 		assert.Equal(t, "Balanced", data.HrvStatus)
 	})
 }
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"golang.org/x/time/rate"
+)
+
+// WithRetries retries failed GET and HEAD requests up to n times with
+// exponential backoff. Network errors, 429 and 5xx responses are retried;
+// requests that change data are never repeated.
+func WithRetries(n int) ClientOption {
+	return func(c *Client) {
+		c.HTTPClient.
+			SetRetryCount(n).
+			SetRetryWaitTime(500 * time.Millisecond).
+			SetRetryMaxWaitTime(10 * time.Second).
+			AddRetryCondition(retryable)
+	}
+}
+
+// retryable reports whether a request is safe and worth repeating
+func retryable(resp *resty.Response, err error) bool {
+	if resp == nil || resp.Request == nil {
+		return false
+	}
+	if method := resp.Request.Method; method != http.MethodGet && method != http.MethodHead {
+		return false
+	}
+	if err != nil {
+		// Cancelled or expired contexts are not retried
+		return resp.Request.Context().Err() == nil
+	}
+	return resp.StatusCode() == http.StatusTooManyRequests || resp.StatusCode() >= 500
+}
+
+// WithRateLimit limits the client, and clones made with WithSession, to
+// requestsPerSecond requests; retries count against the limit
+func WithRateLimit(requestsPerSecond float64) ClientOption {
+	return func(c *Client) {
+		if requestsPerSecond <= 0 {
+			return
+		}
+		limiter := rate.NewLimiter(rate.Limit(requestsPerSecond), 1)
+		c.HTTPClient.OnBeforeRequest(func(_ *resty.Client, req *resty.Request) error {
+			return limiter.Wait(req.Context())
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithRetries(t *testing.T) {
+	var gets, posts atomic.Int32
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			posts.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if gets.Add(1) < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	})
+	defer server.Close()
+
+	client := NewClientWithBaseURL(server.URL())
+	WithRetries(3)(client)
+	client.HTTPClient.SetRetryWaitTime(time.Millisecond).SetRetryMaxWaitTime(time.Millisecond)
+
+	var v map[string]interface{}
+	assert.NoError(t, client.Get(context.Background(), "/stats-service/stats", &v))
+	assert.Equal(t, int32(3), gets.Load())
+
+	assert.Error(t, client.Post(context.Background(), "/stats-service/stats", nil, nil))
+	assert.Equal(t, int32(1), posts.Load())
+}
+
+func TestWithRateLimit(t *testing.T) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	})
+	defer server.Close()
+
+	client := NewClientWithBaseURL(server.URL())
+	WithRateLimit(20)(client)
+
+	start := time.Now()
+	var v map[string]interface{}
+	for range 5 {
+		assert.NoError(t, client.Get(context.Background(), "/stats-service/stats", &v))
+	}
+	// The first request passes immediately, the other four wait 50ms each
+	assert.GreaterOrEqual(t, time.Since(start), 190*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, client.Get(ctx, "/stats-service/stats", &v))
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// LiveTrack session states
+const (
+	LiveTrackActive = "ACTIVE"
+	LiveTrackEnded  = "ENDED"
+)
+
+// LiveTrackSession is a shared real-time tracking session
+type LiveTrackSession struct {
+	SessionID    string     `json:"sessionId"`
+	Name         string     `json:"sessionName"`
+	Status       string     `json:"status"` // LiveTrackActive or LiveTrackEnded
+	ActivityType string     `json:"activityType"`
+	StartTime    GarminTime `json:"start"`
+	EndTime      GarminTime `json:"end"` // zero while the session is active
+	ShareURL     string     `json:"viewableUrl"`
+}
+
+// IsActive reports whether the session is currently tracking an activity
+func (s LiveTrackSession) IsActive() bool {
+	return s.Status == LiveTrackActive
+}
+
+// GetLiveTrackSessions retrieves the user's active and past LiveTrack sessions,
+// most recent first
+func (c *Client) GetLiveTrackSessions(ctx context.Context, opts ...RequestOption) ([]LiveTrackSession, error) {
+	var response struct {
+		Sessions []LiveTrackSession `json:"sessions"`
+	}
+	if err := c.Get(ctx, "/livetrack-service/sessions", &response, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get LiveTrack sessions: %w", err)
+	}
+	return response.Sessions, nil
+}
+
+// GetActiveLiveTrackSession returns the session currently in progress, or nil
+// when the user is not being tracked
+func (c *Client) GetActiveLiveTrackSession(ctx context.Context, opts ...RequestOption) (*LiveTrackSession, error) {
+	sessions, err := c.GetLiveTrackSessions(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		if sessions[i].IsActive() {
+			return &sessions[i], nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLiveTrackSessions(t *testing.T) {
+	body := `{"sessions": [
+		{"sessionId": "b", "sessionName": "Evening Ride", "status": "ACTIVE", "start": "2024-03-01T18:00:00Z", "viewableUrl": "https://livetrack.garmin.com/session/b/token/x"},
+		{"sessionId": "a", "sessionName": "Morning Run", "status": "ENDED", "start": "2024-03-01T07:00:00Z", "end": "2024-03-01T08:00:00Z"}
+	]}`
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+
+	sessions, err := client.GetLiveTrackSessions(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 2)
+	assert.True(t, sessions[1].EndTime.After(sessions[1].StartTime.Time))
+
+	active, err := client.GetActiveLiveTrackSession(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "Evening Ride", active.Name)
+	assert.Contains(t, active.ShareURL, "/session/b/")
+	assert.True(t, active.EndTime.IsZero())
+
+	body = `{"sessions": []}`
+	active, err = client.GetActiveLiveTrackSession(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, active)
+}
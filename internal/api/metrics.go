@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// activityMetrics is the column-oriented time series returned by the activity
+// details endpoint. Each row holds one sample; metricDescriptors map metric
+// keys to columns.
+type activityMetrics struct {
+	MetricDescriptors []struct {
+		MetricsIndex int    `json:"metricsIndex"`
+		Key          string `json:"key"`
+	} `json:"metricDescriptors"`
+	ActivityDetailMetrics []struct {
+		Metrics []*float64 `json:"metrics"`
+	} `json:"activityDetailMetrics"`
+}
+
+// getActivityMetrics retrieves the sample series of an activity
+func (c *Client) getActivityMetrics(ctx context.Context, activityID int64, opts ...RequestOption) (*activityMetrics, error) {
+	var metrics activityMetrics
+	path := fmt.Sprintf("/activity-service/activity/%d/details", activityID)
+	if err := c.Get(ctx, path, &metrics, opts...); err != nil {
+		return nil, err
+	}
+	return &metrics, nil
+}
+
+// columns returns the column index of each metric key
+func (m *activityMetrics) columns() map[string]int {
+	cols := make(map[string]int, len(m.MetricDescriptors))
+	for _, d := range m.MetricDescriptors {
+		cols[d.Key] = d.MetricsIndex
+	}
+	return cols
+}
+
+// samples returns the number of samples
+func (m *activityMetrics) samples() int {
+	return len(m.ActivityDetailMetrics)
+}
+
+// value returns metric key of sample i, or nil when the activity lacks it
+func (m *activityMetrics) value(cols map[string]int, i int, key string) *float64 {
+	col, ok := cols[key]
+	if !ok {
+		return nil
+	}
+	row := m.ActivityDetailMetrics[i].Metrics
+	if col < 0 || col >= len(row) {
+		return nil
+	}
+	return row[col]
+}
+
+// timestamp returns the time of sample i
+func (m *activityMetrics) timestamp(cols map[string]int, i int) GarminTime {
+	ms := m.value(cols, i, "directTimestamp")
+	if ms == nil {
+		return GarminTime{}
+	}
+	return NewGarminTime(time.UnixMilli(int64(*ms)).UTC())
+}
+
+// meanOf averages the non-nil values picked from samples, returning nil when
+// there are none
+func meanOf[S any](samples []S, pick func(S) *float64) *float64 {
+	var sum float64
+	var n int
+	for _, s := range samples {
+		if v := pick(s); v != nil {
+			sum += *v
+			n++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	return Ptr(sum / float64(n))
+}
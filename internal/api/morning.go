@@ -0,0 +1,53 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// MorningReport is the daily insights summary newer watches show on waking.
+// Sections are nil when the device does not provide them.
+type MorningReport struct {
+	RawJSON
+	Date              Date              `json:"calendarDate"`
+	Sleep             *ReportSleep      `json:"sleep"`
+	HRV               *ReportHRV        `json:"hrv"`
+	TrainingReadiness *int              `json:"trainingReadinessScore"`
+	BodyBattery       *int              `json:"morningBodyBattery"` // Body Battery on waking
+	RestingHR         *int              `json:"restingHeartRate"`
+	Yesterday         *ReportYesterday  `json:"yesterday"`
+	SuggestedWorkout  *SuggestedWorkout `json:"suggestedWorkout"`
+}
+
+// ReportSleep summarizes last night's sleep
+type ReportSleep struct {
+	DurationSeconds int    `json:"sleepTimeSeconds"`
+	Score           *int   `json:"sleepScore"`
+	Qualifier       string `json:"sleepScoreQualifier"` // e.g. "GOOD", "FAIR"
+}
+
+// ReportHRV summarizes last night's heart rate variability
+type ReportHRV struct {
+	Status       string   `json:"hrvStatus"` // e.g. "BALANCED", "UNBALANCED", "LOW"
+	LastNightAvg *float64 `json:"lastNightAvg"`
+	WeeklyAvg    *float64 `json:"weeklyAvg"`
+}
+
+// ReportYesterday summarizes the previous day's activity
+type ReportYesterday struct {
+	Steps            *int `json:"totalSteps"`
+	ActiveCalories   *int `json:"activeKilocalories"`
+	IntensityMinutes *int `json:"intensityMinutes"`
+	Activities       int  `json:"activityCount"`
+}
+
+// GetMorningReport retrieves the morning report for the given date
+func (c *Client) GetMorningReport(ctx context.Context, date time.Time, opts ...RequestOption) (*MorningReport, error) {
+	var report MorningReport
+	path := fmt.Sprintf("/wellness-service/wellness/morningReport/%s", date.Format("2006-01-02"))
+	if err := c.Get(ctx, path, &report, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get morning report: %w", err)
+	}
+	return &report, nil
+}
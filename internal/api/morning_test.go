@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMorningReport(t *testing.T) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/wellness-service/wellness/morningReport/2024-03-02", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"calendarDate": "2024-03-02",
+			"sleep": {"sleepTimeSeconds": 27000, "sleepScore": 82, "sleepScoreQualifier": "GOOD"},
+			"hrv": {"hrvStatus": "BALANCED", "lastNightAvg": 48, "weeklyAvg": 51},
+			"trainingReadinessScore": 74,
+			"morningBodyBattery": 88,
+			"yesterday": {"totalSteps": 11200, "activeKilocalories": 640, "activityCount": 1},
+			"suggestedWorkout": {"sportTypeKey": "running", "workoutName": "Base", "estimatedDurationInSecs": 2700}
+		}`))
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+
+	report, err := client.GetMorningReport(context.Background(), time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, 82, *report.Sleep.Score)
+	assert.Equal(t, "BALANCED", report.HRV.Status)
+	assert.Equal(t, 74, *report.TrainingReadiness)
+	assert.Nil(t, report.RestingHR)
+	assert.Nil(t, report.Yesterday.IntensityMinutes)
+	assert.Equal(t, 1, report.Yesterday.Activities)
+	assert.Equal(t, "running", report.SuggestedWorkout.SportType)
+	assert.Equal(t, 2700.0, *report.SuggestedWorkout.DurationSeconds)
+}
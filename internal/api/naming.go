@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ActivityUpdate holds the activity fields to change; nil fields are left as they are
+type ActivityUpdate struct {
+	Name        *string `json:"activityName,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// UpdateActivity changes the name or description of an activity
+func (c *Client) UpdateActivity(ctx context.Context, activityID int64, update ActivityUpdate, opts ...RequestOption) error {
+	if update.Name != nil && strings.TrimSpace(*update.Name) == "" {
+		return fmt.Errorf("activity name cannot be empty")
+	}
+	body := struct {
+		ActivityID int64 `json:"activityId"`
+		ActivityUpdate
+	}{activityID, update}
+
+	path := fmt.Sprintf("/activity-service/activity/%d", activityID)
+	if err := c.Put(ctx, path, body, nil, opts...); err != nil {
+		return fmt.Errorf("failed to update activity %d: %w", activityID, err)
+	}
+	return nil
+}
+
+// NamingTemplates maps an activity type such as "running" to the template its
+// uploads are renamed with; the empty key applies to all other types.
+// Templates may use these placeholders:
+//
+//	{name}      the name Garmin gave the activity, e.g. "Helsinki Running"
+//	{type}      the activity type, e.g. "Trail Running"
+//	{weekday}   the local start weekday, e.g. "Tuesday"
+//	{date}      the local start date, e.g. "2024-03-05"
+//	{time}      the local start time, e.g. "07:30"
+//	{timeofday} "Morning", "Afternoon", "Evening" or "Night"
+//	{distance}  the distance in kilometers, e.g. "10.2 km"
+//	{duration}  the moving time, e.g. "1h05m" or "42m"
+type NamingTemplates map[string]string
+
+// WithUploadNaming renames activities after UploadActivity using the template
+// for their type
+func WithUploadNaming(templates NamingTemplates) ClientOption {
+	return func(c *Client) {
+		c.naming = templates
+	}
+}
+
+// Template returns the template for an activity type, or "" when it has none
+func (t NamingTemplates) Template(activityType string) string {
+	if tmpl, ok := t[activityType]; ok {
+		return tmpl
+	}
+	return t[""]
+}
+
+// RenderActivityName fills the placeholders of tmpl from the activity
+func RenderActivityName(tmpl string, a ActivityDetail) string {
+	start := a.StartTime.Time
+	duration := int(a.Duration+30) / 60 // minutes, rounded
+	durationText := fmt.Sprintf("%dm", duration)
+	if duration >= 60 {
+		durationText = fmt.Sprintf("%dh%02dm", duration/60, duration%60)
+	}
+
+	r := strings.NewReplacer(
+		"{name}", a.Name,
+		"{type}", activityTypeTitle(a.Type),
+		"{weekday}", start.Weekday().String(),
+		"{date}", start.Format("2006-01-02"),
+		"{time}", start.Format("15:04"),
+		"{timeofday}", timeOfDay(start.Hour()),
+		"{distance}", fmt.Sprintf("%.1f km", a.Distance/1000),
+		"{duration}", durationText,
+	)
+	return strings.TrimSpace(r.Replace(tmpl))
+}
+
+// activityTypeTitle turns a type key like "trail_running" into "Trail Running"
+func activityTypeTitle(key string) string {
+	words := strings.Fields(strings.ReplaceAll(key, "_", " "))
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + strings.ToLower(w[1:])
+	}
+	return strings.Join(words, " ")
+}
+
+func timeOfDay(hour int) string {
+	switch {
+	case hour >= 5 && hour < 12:
+		return "Morning"
+	case hour >= 12 && hour < 17:
+		return "Afternoon"
+	case hour >= 17 && hour < 21:
+		return "Evening"
+	default:
+		return "Night"
+	}
+}
+
+// renameUpload applies the naming template for a freshly uploaded activity
+func (c *Client) renameUpload(ctx context.Context, activityID int64, opts []RequestOption) error {
+	detail, err := c.GetActivityDetails(ctx, activityID, opts...)
+	if err != nil {
+		return err
+	}
+	tmpl := c.naming.Template(detail.Type)
+	if tmpl == "" {
+		return nil
+	}
+	name := RenderActivityName(tmpl, *detail)
+	if name == "" || name == detail.Name {
+		return nil
+	}
+	return c.UpdateActivity(ctx, activityID, ActivityUpdate{Name: &name}, opts...)
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderActivityName(t *testing.T) {
+	a := ActivityDetail{Activity: Activity{
+		Name:      "Helsinki Running",
+		Type:      "trail_running",
+		StartTime: NewGarminTime(time.Date(2024, 3, 5, 7, 30, 0, 0, time.UTC)),
+		Duration:  3900,
+		Distance:  10240,
+	}}
+	assert.Equal(t, "Tuesday Trail Running – 10.2 km", RenderActivityName("{weekday} {type} – {distance}", a))
+	assert.Equal(t, "Morning run 1h05m (was Helsinki Running)", RenderActivityName("{timeofday} run {duration} (was {name})", a))
+	assert.Equal(t, "2024-03-05 07:30", RenderActivityName("{date} {time}", a))
+
+	templates := NamingTemplates{"running": "{type}", "": "{weekday}"}
+	assert.Equal(t, "{type}", templates.Template("running"))
+	assert.Equal(t, "{weekday}", templates.Template("cycling"))
+	assert.Equal(t, "", NamingTemplates{"running": "x"}.Template("cycling"))
+}
+
+func TestUploadNaming(t *testing.T) {
+	var renamed map[string]interface{}
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/upload-service/upload/.fit":
+			w.Write([]byte(`{"activityId": 501}`))
+		case r.URL.Path == "/activity-service/activity/501" && r.Method == http.MethodGet:
+			w.Write([]byte(`{"activityId": 501, "activityName": "Helsinki Cycling", "activityType": "cycling",
+				"startTimeLocal": "2024-03-09T18:10:00", "duration": 5400, "distance": 42000}`))
+		case r.URL.Path == "/activity-service/activity/501" && r.Method == http.MethodPut:
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&renamed))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+	WithUploadNaming(NamingTemplates{"cycling": "{timeofday} ride – {distance}"})(client)
+
+	fitData := make([]byte, 20)
+	fitData[0] = 14
+	copy(fitData[8:12], []byte(".FIT"))
+
+	id, err := client.UploadActivity(context.Background(), fitData)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(501), id)
+	assert.Equal(t, map[string]interface{}{"activityId": 501.0, "activityName": "Evening ride – 42.0 km"}, renamed)
+
+	assert.Error(t, client.UpdateActivity(context.Background(), 501, ActivityUpdate{Name: Ptr(" ")}))
+}
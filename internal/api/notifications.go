@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// notificationSettingsPath is the endpoint for reading and updating alert settings
+const notificationSettingsPath = "/userprofile-service/userprofile/notification-settings"
+
+// NotificationSettings holds the safety and health alerts configured for the user
+type NotificationSettings struct {
+	HighHRAlert       HRAlert           `json:"highHeartRateAlert"`
+	LowHRAlert        HRAlert           `json:"lowHeartRateAlert"`
+	IncidentDetection IncidentDetection `json:"incidentDetection"`
+}
+
+// HRAlert warns when the resting heart rate stays above or below a threshold
+type HRAlert struct {
+	Enabled   bool `json:"enabled"`
+	Threshold int  `json:"thresholdBpm" validate:"omitempty,min=30,max=200"`
+}
+
+// IncidentDetection notifies emergency contacts after a detected crash or fall
+type IncidentDetection struct {
+	Enabled  bool               `json:"enabled"`
+	Contacts []EmergencyContact `json:"emergencyContacts" validate:"max=3,dive"`
+}
+
+// EmergencyContact is a person notified by incident detection and assistance
+type EmergencyContact struct {
+	Name  string `json:"name" validate:"required"`
+	Phone string `json:"phoneNumber" validate:"required_without=Email"`
+	Email string `json:"email" validate:"omitempty,email"`
+}
+
+// Validate ensures the settings are accepted by Garmin
+func (s *NotificationSettings) Validate() error {
+	validate := newValidator()
+	if err := validate.Struct(s); err != nil {
+		return err
+	}
+	if s.HighHRAlert.Enabled && s.HighHRAlert.Threshold < 100 {
+		return errors.New("high heart rate alert threshold must be at least 100 bpm")
+	}
+	if s.LowHRAlert.Enabled && s.LowHRAlert.Threshold > 60 {
+		return errors.New("low heart rate alert threshold must be at most 60 bpm")
+	}
+	if s.IncidentDetection.Enabled && len(s.IncidentDetection.Contacts) == 0 {
+		return errors.New("incident detection requires an emergency contact")
+	}
+	return nil
+}
+
+// GetNotificationSettings retrieves the heart rate alert and incident detection settings
+func (c *Client) GetNotificationSettings(ctx context.Context, opts ...RequestOption) (*NotificationSettings, error) {
+	var settings NotificationSettings
+	if err := c.Get(ctx, notificationSettingsPath, &settings, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get notification settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// UpdateNotificationSettings replaces the alert settings and returns the stored result
+func (c *Client) UpdateNotificationSettings(ctx context.Context, settings NotificationSettings, opts ...RequestOption) (*NotificationSettings, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid notification settings: %w", err)
+	}
+
+	var updated NotificationSettings
+	if err := c.Put(ctx, notificationSettingsPath, settings, &updated, opts...); err != nil {
+		return nil, fmt.Errorf("failed to update notification settings: %w", err)
+	}
+	return &updated, nil
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationSettings(t *testing.T) {
+	stored := NotificationSettings{
+		HighHRAlert: HRAlert{Enabled: true, Threshold: 120},
+		LowHRAlert:  HRAlert{Enabled: false, Threshold: 40},
+	}
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/userprofile-service/userprofile/notification-settings", r.URL.Path)
+		if r.Method == http.MethodPut {
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&stored))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(stored)
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+	ctx := context.Background()
+
+	settings, err := client.GetNotificationSettings(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 120, settings.HighHRAlert.Threshold)
+	assert.False(t, settings.IncidentDetection.Enabled)
+
+	settings.IncidentDetection = IncidentDetection{
+		Enabled:  true,
+		Contacts: []EmergencyContact{{Name: "Sam", Phone: "+15551234567"}},
+	}
+	updated, err := client.UpdateNotificationSettings(ctx, *settings)
+	assert.NoError(t, err)
+	assert.Equal(t, "Sam", updated.IncidentDetection.Contacts[0].Name)
+	assert.Equal(t, *settings, stored)
+
+	invalid := []NotificationSettings{
+		{HighHRAlert: HRAlert{Enabled: true, Threshold: 90}},
+		{LowHRAlert: HRAlert{Enabled: true, Threshold: 70}},
+		{IncidentDetection: IncidentDetection{Enabled: true}},
+		{IncidentDetection: IncidentDetection{Contacts: []EmergencyContact{{Name: "Sam"}}}},
+		{IncidentDetection: IncidentDetection{Contacts: []EmergencyContact{{Name: "Sam", Email: "not-an-email"}}}},
+	}
+	for _, s := range invalid {
+		_, err := client.UpdateNotificationSettings(ctx, s)
+		assert.Error(t, err)
+	}
+}
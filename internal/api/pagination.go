@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/url"
+	"strconv"
+)
+
+const (
+	// DefaultPageSize is used when a PageRequest does not set a page size
+	DefaultPageSize = 20
+	// MaxPageSize is the largest page Garmin's list endpoints return
+	MaxPageSize = 100
+)
+
+// PageRequest selects one page of a list endpoint. Pages are numbered from 1.
+type PageRequest struct {
+	Page     int
+	PageSize int
+}
+
+// FirstPage returns a request for the first page of size items
+func FirstPage(size int) PageRequest {
+	return PageRequest{Page: 1, PageSize: size}.normalize()
+}
+
+// Next returns the request for the following page
+func (p PageRequest) Next() PageRequest {
+	p = p.normalize()
+	p.Page++
+	return p
+}
+
+// Offset returns the index of the first item on the page
+func (p PageRequest) Offset() int {
+	p = p.normalize()
+	return (p.Page - 1) * p.PageSize
+}
+
+// normalize applies defaults and clamps the page size to what Garmin serves
+func (p PageRequest) normalize() PageRequest {
+	if p.Page < 1 {
+		p.Page = 1
+	}
+	if p.PageSize <= 0 {
+		p.PageSize = DefaultPageSize
+	}
+	if p.PageSize > MaxPageSize {
+		p.PageSize = MaxPageSize
+	}
+	return p
+}
+
+// values encodes the request as page/pageSize query parameters
+func (p PageRequest) values() url.Values {
+	p = p.normalize()
+	params := url.Values{}
+	params.Add("page", strconv.Itoa(p.Page))
+	params.Add("pageSize", strconv.Itoa(p.PageSize))
+	return params
+}
+
+// TotalPages returns the number of pages needed for all items
+func (p *Pagination) TotalPages() int {
+	if p.PageSize <= 0 {
+		return 0
+	}
+	return (p.TotalCount + p.PageSize - 1) / p.PageSize
+}
+
+// HasNext reports whether another page follows this one
+func (p *Pagination) HasNext() bool {
+	return p.Page < p.TotalPages()
+}
+
+// NextPage returns the request for the page after this one
+func (p *Pagination) NextPage() PageRequest {
+	return PageRequest{Page: p.Page + 1, PageSize: p.PageSize}.normalize()
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPagination(t *testing.T) {
+	p := &Pagination{Page: 1, PageSize: 20, TotalCount: 45}
+	assert.Equal(t, 3, p.TotalPages())
+	assert.True(t, p.HasNext())
+	assert.Equal(t, PageRequest{Page: 2, PageSize: 20}, p.NextPage())
+
+	p.Page = 3
+	assert.False(t, p.HasNext())
+
+	empty := &Pagination{}
+	assert.Equal(t, 0, empty.TotalPages())
+	assert.False(t, empty.HasNext())
+}
+
+func TestPageRequest(t *testing.T) {
+	assert.Equal(t, PageRequest{Page: 1, PageSize: DefaultPageSize}, FirstPage(0))
+	assert.Equal(t, MaxPageSize, FirstPage(500).PageSize)
+
+	req := FirstPage(10).Next().Next()
+	assert.Equal(t, 3, req.Page)
+	assert.Equal(t, 20, req.Offset())
+	assert.Equal(t, 0, PageRequest{}.Offset())
+	assert.Equal(t, "page=1&pageSize=20", PageRequest{}.values().Encode())
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// CyclingPower holds the power summary of a ride. Fields are nil for activities
+// recorded without a power meter; intensity factor and training stress also
+// require an FTP in the user's profile.
+type CyclingPower struct {
+	AvgPower            *float64 `json:"avgPower,omitempty"`            // watts
+	MaxPower            *float64 `json:"maxPower,omitempty"`            // watts
+	NormalizedPower     *float64 `json:"normPower,omitempty"`           // watts
+	IntensityFactor     *float64 `json:"intensityFactor,omitempty"`     // normalized power / FTP
+	TrainingStressScore *float64 `json:"trainingStressScore,omitempty"` // TSS-equivalent
+	LeftBalance         *float64 `json:"avgLeftBalance,omitempty"`      // percent of power from the left leg
+}
+
+// PowerSample is one point of the power series of an activity
+type PowerSample struct {
+	Timestamp GarminTime `json:"timestamp"`
+	Watts     *float64   `json:"watts,omitempty"`
+}
+
+// GetActivityPowerSeries retrieves the recorded power of an activity, for
+// computing peak power curves and normalized power with the analysis package
+func (c *Client) GetActivityPowerSeries(ctx context.Context, activityID int64, opts ...RequestOption) ([]PowerSample, error) {
+	metrics, err := c.getActivityMetrics(ctx, activityID, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get power series: %w", err)
+	}
+
+	cols := metrics.columns()
+	samples := make([]PowerSample, metrics.samples())
+	for i := range samples {
+		samples[i] = PowerSample{
+			Timestamp: metrics.timestamp(cols, i),
+			Watts:     metrics.value(cols, i, "directPower"),
+		}
+	}
+	return samples, nil
+}
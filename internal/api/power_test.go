@@ -0,0 +1,36 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetActivityPowerSeries(t *testing.T) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"metricDescriptors": [
+				{"metricsIndex": 1, "key": "directTimestamp"},
+				{"metricsIndex": 0, "key": "directPower"}
+			],
+			"activityDetailMetrics": [
+				{"metrics": [210, 1709276400000]},
+				{"metrics": [null, 1709276401000]}
+			]
+		}`))
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+
+	samples, err := client.GetActivityPowerSeries(context.Background(), 9)
+	assert.NoError(t, err)
+	assert.Len(t, samples, 2)
+	assert.Equal(t, Ptr(210.0), samples[0].Watts)
+	assert.Nil(t, samples[1].Watts)
+	assert.Equal(t, int64(1709276401000), samples[1].Timestamp.UnixMilli())
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// RawJSON is embedded in response models to carry the undecoded response body
+// when raw retention is enabled, giving access to fields the models don't cover
+type RawJSON struct {
+	raw json.RawMessage
+}
+
+// Raw returns the response body the value was decoded from, or nil when raw
+// retention was not enabled for the request
+func (r RawJSON) Raw() json.RawMessage {
+	return r.raw
+}
+
+func (r *RawJSON) setRaw(body []byte) {
+	r.raw = append(json.RawMessage(nil), body...)
+}
+
+// rawSetter is implemented by models embedding RawJSON
+type rawSetter interface {
+	setRaw(body []byte)
+}
+
+type keepRawKey struct{}
+
+// KeepRaw returns a context that makes requests made with it retain the raw
+// response body on returned models, regardless of the client-wide setting
+func KeepRaw(ctx context.Context) context.Context {
+	return context.WithValue(ctx, keepRawKey{}, true)
+}
+
+// WithRawResponses retains the raw response body on every returned model
+func WithRawResponses() ClientOption {
+	return func(c *Client) {
+		c.keepRaw = true
+	}
+}
+
+// keepRawFor reports whether the request made with ctx retains raw bodies
+func (c *Client) keepRawFor(ctx context.Context) bool {
+	if c.keepRaw {
+		return true
+	}
+	keep, _ := ctx.Value(keepRawKey{}).(bool)
+	return keep
+}
+
+// GetRaw performs a GET request and returns the undecoded JSON body, for
+// endpoints that have no typed model yet
+func (c *Client) GetRaw(ctx context.Context, path string, opts ...RequestOption) (json.RawMessage, error) {
+	var raw json.RawMessage
+	if err := c.Get(ctx, path, &raw, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", path, err)
+	}
+	return raw, nil
+}
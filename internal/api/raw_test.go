@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRawResponses(t *testing.T) {
+	mockServer := garmintest.NewServer()
+	defer mockServer.Close()
+	handleHealth(mockServer, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"calendarDate": "2024-03-01", "sleepTimeSeconds": 28800, "sleepNeed": {"baseline": 480}}`))
+	})
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("disabled by default", func(t *testing.T) {
+		client := NewClientWithBaseURL(mockServer.URL())
+		sleep, err := client.GetSleepData(context.Background(), date)
+		assert.NoError(t, err)
+		assert.Nil(t, sleep.Raw())
+	})
+
+	t.Run("per call", func(t *testing.T) {
+		client := NewClientWithBaseURL(mockServer.URL())
+		sleep, err := client.GetSleepData(KeepRaw(context.Background()), date)
+		assert.NoError(t, err)
+		assert.Equal(t, Ptr(28800), sleep.SleepTimeSeconds)
+		assert.Contains(t, string(sleep.Raw()), `"sleepNeed"`)
+	})
+
+	t.Run("client wide", func(t *testing.T) {
+		client := NewClientWithBaseURL(mockServer.URL())
+		WithRawResponses()(client)
+		hrv, err := client.GetHRVData(context.Background(), date)
+		assert.NoError(t, err)
+		assert.Contains(t, string(hrv.Raw()), `"baseline": 480`)
+	})
+
+	t.Run("untyped endpoint", func(t *testing.T) {
+		client := NewClientWithBaseURL(mockServer.URL())
+		raw, err := client.GetRaw(context.Background(), "/wellness-service/sleep/daily/2024-03-01")
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"calendarDate": "2024-03-01", "sleepTimeSeconds": 28800, "sleepNeed": {"baseline": 480}}`, string(raw))
+	})
+}
+
+func TestRawActivityDetails(t *testing.T) {
+	mockServer := garmintest.NewServer()
+	defer mockServer.Close()
+	client := NewClientWithBaseURL(mockServer.URL())
+
+	detail, err := client.GetActivityDetails(KeepRaw(context.Background()), 1)
+	assert.NoError(t, err)
+	assert.Contains(t, string(detail.Raw()), `"activityTrainingLoad": 142.8`)
+}
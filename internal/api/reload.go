@@ -0,0 +1,28 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RequestReload asks Garmin to reprocess the wellness data uploaded for date,
+// e.g. after a device sync fixed missing or corrupt data
+func (c *Client) RequestReload(ctx context.Context, date time.Time, opts ...RequestOption) error {
+	path := fmt.Sprintf("/wellness-service/wellness/epoch/request/%s", date.Format("2006-01-02"))
+	if err := c.Post(ctx, path, nil, nil, opts...); err != nil {
+		return fmt.Errorf("failed to request reload of %s: %w", date.Format("2006-01-02"), err)
+	}
+	return nil
+}
+
+// RequestReloadRange requests a reload of every day from start to end inclusive
+// and returns the days that were accepted. Failed days are reported in a *RangeError.
+func (c *Client) RequestReloadRange(ctx context.Context, start, end time.Time, opts ...RequestOption) ([]time.Time, error) {
+	return FetchRange(ctx, start, end, c.rangeOpts, func(ctx context.Context, day time.Time) (time.Time, error) {
+		if err := c.RequestReload(ctx, day, opts...); err != nil {
+			return time.Time{}, err
+		}
+		return day, nil
+	})
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestReloadRange(t *testing.T) {
+	var mu sync.Mutex
+	var reloaded []string
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		if r.URL.Path == "/wellness-service/wellness/epoch/request/2024-03-02" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		reloaded = append(reloaded, r.URL.Path)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	days, err := client.RequestReloadRange(context.Background(), start, start.AddDate(0, 0, 2))
+	assert.True(t, IsPartial(err))
+	assert.ErrorIs(t, err, ErrBadRequest{})
+	assert.Equal(t, []time.Time{start, start.AddDate(0, 0, 2)}, days)
+
+	mu.Lock()
+	defer mu.Unlock()
+	sort.Strings(reloaded)
+	assert.Equal(t, []string{
+		"/wellness-service/wellness/epoch/request/2024-03-01",
+		"/wellness-service/wellness/epoch/request/2024-03-03",
+	}, reloaded)
+}
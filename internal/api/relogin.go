@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/auth/garth"
+)
+
+// CredentialsProvider supplies the username and password used to log in
+// again when the stored OAuth tokens have been revoked
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (username, password string, err error)
+}
+
+// CredentialsFunc adapts a function to a CredentialsProvider
+type CredentialsFunc func(ctx context.Context) (username, password string, err error)
+
+// Credentials calls f
+func (f CredentialsFunc) Credentials(ctx context.Context) (string, string, error) {
+	return f(ctx)
+}
+
+// Loginer is implemented by authenticators that can run a full login.
+// garth.GarthAuthenticator satisfies it; set its MFAPrompter to a
+// garth.TOTPPrompter so accounts with MFA can log in unattended.
+type Loginer interface {
+	Login(username, password string) (*garth.Session, error)
+}
+
+// WithCredentials lets the client log in again with credentials from provider
+// when a token refresh fails or a request is rejected as unauthorized, instead
+// of returning an error. The client's authenticator must implement Loginer.
+func WithCredentials(provider CredentialsProvider) ClientOption {
+	return func(c *Client) {
+		c.creds = provider
+	}
+}
+
+// canRelogin reports whether a failed refresh can be recovered by logging in
+func (c *Client) canRelogin() bool {
+	if c.creds == nil {
+		return false
+	}
+	_, ok := c.auth.(Loginer)
+	return ok
+}
+
+// loginLocked replaces the session with one from a full login.
+// The caller must hold c.mu.
+func (c *Client) loginLocked(ctx context.Context) error {
+	loginer, ok := c.auth.(Loginer)
+	if !ok || c.creds == nil {
+		return errors.New("credentials not configured for login")
+	}
+	username, password, err := c.creds.Credentials(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get credentials: %w", err)
+	}
+	session, err := loginer.Login(username, password)
+	if err != nil {
+		return fmt.Errorf("re-login failed: %w", err)
+	}
+	if session.ExpiresAt.IsZero() {
+		session.ExpiresAt = time.Now().Add(8 * time.Hour)
+	}
+
+	c.session = session
+	c.token = session.OAuth2Token
+	if c.sessionPath != "" {
+		if err := session.Save(c.sessionPath); err != nil {
+			return fmt.Errorf("failed to save session: %w", err)
+		}
+	}
+	return nil
+}
+
+// relogin logs in again after the token sent as stale was rejected. When
+// another request already replaced that token, the login is skipped.
+func (c *Client) relogin(ctx context.Context, stale string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != stale {
+		return nil
+	}
+	return c.loginLocked(ctx)
+}
+
+// reloginKey marks a request that is being retried after a re-login
+type reloginKey struct{}
+
+// retriedAfterLogin reports whether ctx belongs to a retry after re-login
+func retriedAfterLogin(ctx context.Context) bool {
+	retried, _ := ctx.Value(reloginKey{}).(bool)
+	return retried
+}
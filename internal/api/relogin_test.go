@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/sstent/go-garminconnect/internal/auth/garth"
+	"github.com/stretchr/testify/assert"
+)
+
+// loginAuthenticator is a mock authenticator that can also log in
+type loginAuthenticator struct {
+	MockAuthenticator
+	logins int
+}
+
+func (a *loginAuthenticator) Login(username, password string) (*garth.Session, error) {
+	a.logins++
+	if password != "secret" {
+		return nil, errors.New("bad credentials")
+	}
+	return &garth.Session{OAuth1Token: "o1", OAuth2Token: "fresh-token", ExpiresAt: time.Now().Add(time.Hour)}, nil
+}
+
+func testCredentials(password string) CredentialsProvider {
+	return CredentialsFunc(func(context.Context) (string, string, error) {
+		return "runner@example.com", password, nil
+	})
+}
+
+func TestReloginOnRefreshFailure(t *testing.T) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer fresh-token", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"ok": "yes"})
+	})
+	defer server.Close()
+
+	auth := &loginAuthenticator{}
+	auth.RefreshTokenFunc = func(string, string) (string, error) {
+		return "", errors.New("oauth1 token revoked")
+	}
+	session := &garth.Session{OAuth2Token: "stale", ExpiresAt: time.Now().Add(-time.Hour)}
+	client, err := NewClient(auth, session, "", WithBaseURL(server.URL()), WithCredentials(testCredentials("secret")))
+	assert.NoError(t, err)
+
+	var out map[string]string
+	assert.NoError(t, client.Get(context.Background(), "/userprofile-service/socialProfile", &out))
+	assert.Equal(t, 1, auth.logins)
+	assert.Equal(t, "fresh-token", client.Session().OAuth2Token)
+}
+
+func TestReloginOnUnauthorized(t *testing.T) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer fresh-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":"yes"}`))
+	})
+	defer server.Close()
+
+	session := &garth.Session{OAuth2Token: "revoked", ExpiresAt: time.Now().Add(time.Hour)}
+
+	auth := &loginAuthenticator{}
+	client, err := NewClient(auth, session, "", WithBaseURL(server.URL()), WithCredentials(testCredentials("secret")))
+	assert.NoError(t, err)
+	var out map[string]string
+	assert.NoError(t, client.Get(context.Background(), "/x", &out))
+	assert.Equal(t, "yes", out["ok"])
+	assert.Equal(t, 1, auth.logins)
+
+	// A failed login is reported rather than retried
+	auth = &loginAuthenticator{}
+	client, err = NewClient(auth, session, "", WithBaseURL(server.URL()), WithCredentials(testCredentials("wrong")))
+	assert.NoError(t, err)
+	err = client.Get(context.Background(), "/x", &out)
+	assert.ErrorIs(t, err, ErrLoginRequired)
+	assert.ErrorContains(t, err, "re-login failed")
+	assert.Equal(t, 1, auth.logins)
+
+	// Without credentials the request fails as before
+	client, err = NewClient(auth, session, "", WithBaseURL(server.URL()))
+	assert.NoError(t, err)
+	assert.ErrorContains(t, client.Get(context.Background(), "/x", &out), "please reauthenticate")
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// RequestOption adjusts a single request made by an endpoint method
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	timeout time.Duration
+	header  http.Header
+	noCache bool
+	// noValidation skips response validation, see WithoutValidation
+	noValidation bool
+}
+
+// WithRequestTimeout bounds a single request. It can only shorten the
+// client-wide timeout set with WithTimeout.
+func WithRequestTimeout(d time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.timeout = d
+	}
+}
+
+// WithHeader sets a header on a single request, replacing any client default
+func WithHeader(key, value string) RequestOption {
+	return func(o *requestOptions) {
+		o.header.Set(key, value)
+	}
+}
+
+// WithNoCache makes a single request skip the validator cache and fetch the
+// full response; the fresh response still updates the cache
+func WithNoCache() RequestOption {
+	return func(o *requestOptions) {
+		o.noCache = true
+	}
+}
+
+// applyRequestOptions collects opts
+func applyRequestOptions(opts []RequestOption) requestOptions {
+	o := requestOptions{header: http.Header{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// newRequest builds a resty request for ctx with opts applied. The returned
+// cancel func must be called once the response body is no longer needed.
+func (c *Client) newRequest(ctx context.Context, opts []RequestOption) (*resty.Request, context.CancelFunc) {
+	o := applyRequestOptions(opts)
+
+	cancel := context.CancelFunc(func() {})
+	if o.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, o.timeout)
+	}
+
+	c.mu.Lock()
+	token := c.token
+	c.mu.Unlock()
+
+	req := c.HTTPClient.R().SetContext(ctx).SetHeader("Authorization", "Bearer "+token)
+	for key, values := range o.header {
+		req.SetHeaderMultiValues(map[string][]string{key: values})
+	}
+	if o.noCache {
+		req.SetHeader("Cache-Control", "no-cache")
+	}
+	return req, cancel
+}
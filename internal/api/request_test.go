@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/sstent/go-garminconnect/internal/auth/garth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestOptions(t *testing.T) {
+	var full int32
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow":
+			select {
+			case <-time.After(time.Second):
+			case <-r.Context().Done():
+			}
+			return
+		case "/userprofile-service/socialProfile":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			atomic.AddInt32(&full, 1)
+			w.Header().Set("ETag", `"v1"`)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"displayName": "` + r.Header.Get("X-Trace") + `"}`))
+	})
+	defer server.Close()
+
+	session := &garth.Session{OAuth2Token: "mock-token", ExpiresAt: time.Now().Add(time.Hour)}
+	client, err := NewClient(NewMockAuthenticator(), session, "",
+		WithBaseURL(server.URL()),
+		WithValidatorCache(NewValidatorCache(10)),
+	)
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("header", func(t *testing.T) {
+		profile, err := client.GetUserProfile(ctx, WithHeader("X-Trace", "abc"))
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", profile.DisplayName)
+	})
+
+	t.Run("no cache", func(t *testing.T) {
+		_, err := client.GetUserProfile(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&full), "revalidated from cache")
+
+		_, err = client.GetUserProfile(ctx, WithNoCache())
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&full))
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		start := time.Now()
+		err := client.Get(ctx, "/slow", nil, WithRequestTimeout(20*time.Millisecond))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+}
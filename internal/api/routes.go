@@ -0,0 +1,143 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// ConnectAPIURL is the host serving Garmin Connect services without the
+// /modern/proxy prefix
+const ConnectAPIURL = "https://connectapi.garmin.com"
+
+// hostProbePath is a cheap endpoint every account can read, used to detect
+// which host serves the API
+const hostProbePath = "/userprofile-service/socialProfile"
+
+// Routes maps a service, the first path segment such as "activity-service",
+// to the prefix its requests are sent to. A prefix is either a path placed in
+// front of the request path ("/proxy/v2") or an absolute URL replacing the
+// base URL ("https://connectapi.garmin.com").
+type Routes map[string]string
+
+// LoadRoutes reads a JSON object of service to prefix overrides from path
+func LoadRoutes(path string) (Routes, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read routes: %w", err)
+	}
+	var routes Routes
+	if err := json.Unmarshal(data, &routes); err != nil {
+		return nil, fmt.Errorf("failed to parse routes: %w", err)
+	}
+	return routes, nil
+}
+
+// router rewrites request paths according to a routing table that can change
+// while requests are running
+type router struct {
+	mu     sync.RWMutex
+	routes Routes
+}
+
+// resolve returns the URL a request for path is sent to
+func (r *router) resolve(path string) string {
+	// Absolute URLs bypass routing
+	if !strings.HasPrefix(path, "/") {
+		return path
+	}
+	service, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+
+	r.mu.RLock()
+	prefix, ok := r.routes[service]
+	r.mu.RUnlock()
+	if !ok {
+		return path
+	}
+	prefix = strings.TrimSuffix(prefix, "/")
+	if prefix != "" && !strings.Contains(prefix, "://") && !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	return prefix + path
+}
+
+// middleware applies the routing table before resty resolves the base URL
+func (r *router) middleware(_ *resty.Client, req *resty.Request) error {
+	req.URL = r.resolve(req.URL)
+	return nil
+}
+
+// WithRoutes overrides the path prefix of individual services
+func WithRoutes(routes Routes) ClientOption {
+	return func(c *Client) {
+		for service, prefix := range routes {
+			c.SetRoute(service, prefix)
+		}
+	}
+}
+
+// SetRoute sends requests for service to prefix from now on; an empty prefix
+// restores the default of the client's base URL
+func (c *Client) SetRoute(service, prefix string) {
+	c.router.mu.Lock()
+	defer c.router.mu.Unlock()
+	if prefix == "" {
+		delete(c.router.routes, service)
+		return
+	}
+	c.router.routes[service] = prefix
+}
+
+// Routes returns a copy of the current routing overrides
+func (c *Client) Routes() Routes {
+	c.router.mu.RLock()
+	defer c.router.mu.RUnlock()
+	routes := make(Routes, len(c.router.routes))
+	for service, prefix := range c.router.routes {
+		routes[service] = prefix
+	}
+	return routes
+}
+
+// DetectHost checks whether the account is served by the configured base URL
+// or by connectapi.garmin.com and switches the client to the host that answers
+// with JSON, returning it. Switching to connectapi adds the headers set by
+// WithConnectAPI. Other candidates replace the two defaults. Call it
+// before issuing other requests.
+func (c *Client) DetectHost(ctx context.Context, candidates ...string) (string, error) {
+	if err := c.refreshTokenIfNeeded(); err != nil {
+		return "", err
+	}
+	if len(candidates) == 0 {
+		candidates = []string{c.HTTPClient.BaseURL, ConnectAPIURL}
+	}
+
+	var lastErr error
+	for _, host := range candidates {
+		req, cancel := c.newRequest(ctx, nil)
+		if isConnectAPI(host) {
+			req.SetHeaders(connectAPIHeaders())
+		}
+		resp, err := req.Get(strings.TrimSuffix(host, "/") + hostProbePath)
+		cancel()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		// The legacy proxy answers moved services with a sign-in page
+		if resp.IsSuccess() && strings.Contains(resp.Header().Get("Content-Type"), "json") {
+			c.HTTPClient.SetBaseURL(host)
+			if isConnectAPI(host) {
+				c.HTTPClient.SetHeaders(connectAPIHeaders())
+			}
+			return host, nil
+		}
+		lastErr = newAPIError(resp, "host probe failed")
+	}
+	return "", fmt.Errorf("failed to detect API host: %w", lastErr)
+}
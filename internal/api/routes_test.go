@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouterResolve(t *testing.T) {
+	r := &router{routes: Routes{
+		"activity-service": "/proxy/v2",
+		"hrv-service":      "https://connectapi.garmin.com/",
+		"stats-service":    "legacy",
+	}}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/activity-service/activity/1", "/proxy/v2/activity-service/activity/1"},
+		{"/hrv-service/hrv/2024-03-01", "https://connectapi.garmin.com/hrv-service/hrv/2024-03-01"},
+		{"/stats-service/stats/daily/2024-03-01", "/legacy/stats-service/stats/daily/2024-03-01"},
+		{"/wellness-service/sleep/daily/2024-03-01", "/wellness-service/sleep/daily/2024-03-01"},
+		{"https://example.com/activity-service/x", "https://example.com/activity-service/x"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, r.resolve(tt.path), tt.path)
+	}
+}
+
+func TestClientRoutes(t *testing.T) {
+	var paths []string
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	})
+	defer server.Close()
+
+	file := filepath.Join(t.TempDir(), "routes.json")
+	assert.NoError(t, os.WriteFile(file, []byte(`{"user-service": "/v2"}`), 0644))
+	routes, err := LoadRoutes(file)
+	assert.NoError(t, err)
+
+	client := NewClientWithBaseURL(server.URL())
+	WithRoutes(routes)(client)
+	client.SetRoute("stats-service", "/proxy")
+
+	var v map[string]interface{}
+	assert.NoError(t, client.Get(context.Background(), "/user-service/user", &v))
+	assert.NoError(t, client.Get(context.Background(), "/stats-service/stats", &v))
+	client.SetRoute("stats-service", "")
+	assert.NoError(t, client.Get(context.Background(), "/stats-service/stats", &v))
+
+	assert.Equal(t, []string{"/v2/user-service/user", "/proxy/stats-service/stats", "/stats-service/stats"}, paths)
+	assert.Equal(t, Routes{"user-service": "/v2"}, client.Routes())
+}
+
+func TestDetectHost(t *testing.T) {
+	// A legacy proxy that no longer serves the API answers with a sign-in page
+	legacy := garmintest.NewServer()
+	legacy.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html>sign in</html>"))
+	})
+	defer legacy.Close()
+
+	modern := garmintest.NewServer()
+	modern.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, hostProbePath, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"displayName": "runner"}`))
+	})
+	defer modern.Close()
+
+	client := NewClientWithBaseURL(legacy.URL())
+	host, err := client.DetectHost(context.Background(), legacy.URL(), modern.URL())
+	assert.NoError(t, err)
+	assert.Equal(t, modern.URL(), host)
+	assert.Equal(t, modern.URL(), client.HTTPClient.BaseURL)
+
+	client = NewClientWithBaseURL(legacy.URL())
+	_, err = client.DetectHost(context.Background(), legacy.URL())
+	assert.Error(t, err)
+	assert.Equal(t, legacy.URL(), client.HTTPClient.BaseURL)
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// RunningDynamics holds the activity averages of running form metrics. Fields
+// are nil for activities recorded without a running dynamics sensor.
+type RunningDynamics struct {
+	AvgCadence             *float64 `json:"averageRunningCadenceInStepsPerMinute,omitempty"` // steps per minute
+	AvgGroundContactTime   *float64 `json:"avgGroundContactTime,omitempty"`                  // milliseconds
+	AvgVerticalOscillation *float64 `json:"avgVerticalOscillation,omitempty"`                // centimeters
+	AvgVerticalRatio       *float64 `json:"avgVerticalRatio,omitempty"`                      // percent
+	AvgStrideLength        *float64 `json:"avgStrideLength,omitempty"`                       // centimeters
+}
+
+// RunningDynamicsSample is one point of the running dynamics series
+type RunningDynamicsSample struct {
+	Timestamp           GarminTime `json:"timestamp"`
+	Cadence             *float64   `json:"cadence,omitempty"`             // steps per minute
+	GroundContactTime   *float64   `json:"groundContactTime,omitempty"`   // milliseconds
+	VerticalOscillation *float64   `json:"verticalOscillation,omitempty"` // centimeters
+	VerticalRatio       *float64   `json:"verticalRatio,omitempty"`       // percent
+	StrideLength        *float64   `json:"strideLength,omitempty"`        // centimeters
+}
+
+// RunningDynamicsSeries is the running dynamics series of an activity along
+// with averages computed over it
+type RunningDynamicsSeries struct {
+	RunningDynamics
+	Samples []RunningDynamicsSample `json:"samples"`
+}
+
+// GetActivityRunningDynamics retrieves the cadence, ground contact time,
+// vertical oscillation, vertical ratio and stride length series of a run
+func (c *Client) GetActivityRunningDynamics(ctx context.Context, activityID int64, opts ...RequestOption) (*RunningDynamicsSeries, error) {
+	metrics, err := c.getActivityMetrics(ctx, activityID, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get running dynamics: %w", err)
+	}
+
+	cols := metrics.columns()
+	samples := make([]RunningDynamicsSample, metrics.samples())
+	for i := range samples {
+		cadence := metrics.value(cols, i, "directDoubleCadence")
+		if cadence == nil {
+			// Single cadence counts one foot only
+			if single := metrics.value(cols, i, "directRunCadence"); single != nil {
+				cadence = Ptr(*single * 2)
+			}
+		}
+		samples[i] = RunningDynamicsSample{
+			Timestamp:           metrics.timestamp(cols, i),
+			Cadence:             cadence,
+			GroundContactTime:   metrics.value(cols, i, "directGroundContactTime"),
+			VerticalOscillation: metrics.value(cols, i, "directVerticalOscillation"),
+			VerticalRatio:       metrics.value(cols, i, "directVerticalRatio"),
+			StrideLength:        metrics.value(cols, i, "directStrideLength"),
+		}
+	}
+
+	return &RunningDynamicsSeries{
+		RunningDynamics: RunningDynamics{
+			AvgCadence:             meanOf(samples, func(s RunningDynamicsSample) *float64 { return s.Cadence }),
+			AvgGroundContactTime:   meanOf(samples, func(s RunningDynamicsSample) *float64 { return s.GroundContactTime }),
+			AvgVerticalOscillation: meanOf(samples, func(s RunningDynamicsSample) *float64 { return s.VerticalOscillation }),
+			AvgVerticalRatio:       meanOf(samples, func(s RunningDynamicsSample) *float64 { return s.VerticalRatio }),
+			AvgStrideLength:        meanOf(samples, func(s RunningDynamicsSample) *float64 { return s.StrideLength }),
+		},
+		Samples: samples,
+	}, nil
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetActivityRunningDynamics(t *testing.T) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/activity-service/activity/7/details", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"metricDescriptors": [
+				{"metricsIndex": 0, "key": "directTimestamp"},
+				{"metricsIndex": 1, "key": "directRunCadence"},
+				{"metricsIndex": 2, "key": "directGroundContactTime"},
+				{"metricsIndex": 3, "key": "directStrideLength"}
+			],
+			"activityDetailMetrics": [
+				{"metrics": [1709276400000, 85, 240, 120]},
+				{"metrics": [1709276401000, 87, 250, null]}
+			]
+		}`))
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+
+	series, err := client.GetActivityRunningDynamics(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.Len(t, series.Samples, 2)
+
+	first := series.Samples[0]
+	assert.True(t, first.Timestamp.Equal(time.Date(2024, 3, 1, 7, 0, 0, 0, time.UTC)))
+	assert.Equal(t, Ptr(170.0), first.Cadence)
+	assert.Equal(t, Ptr(240.0), first.GroundContactTime)
+	assert.Nil(t, first.VerticalOscillation)
+	assert.Nil(t, series.Samples[1].StrideLength)
+
+	assert.Equal(t, Ptr(172.0), series.AvgCadence)
+	assert.Equal(t, Ptr(245.0), series.AvgGroundContactTime)
+	assert.Equal(t, Ptr(120.0), series.AvgStrideLength)
+	assert.Nil(t, series.AvgVerticalRatio)
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// SegmentEffort is one attempt at a Garmin segment within an activity
+type SegmentEffort struct {
+	SegmentID      int64      `json:"segmentId"`
+	SegmentName    string     `json:"segmentName"`
+	StartTime      GarminTime `json:"startTimeGMT"`
+	ElapsedTime    float64    `json:"elapsedDuration"` // seconds
+	Distance       float64    `json:"distance"`        // meters
+	Rank           *int       `json:"rank,omitempty"`  // leaderboard position, when ranked
+	PersonalRecord bool       `json:"personalRecord"`
+}
+
+// LeaderboardEntry is one ranked effort on a segment leaderboard
+type LeaderboardEntry struct {
+	Rank        int        `json:"rank"`
+	DisplayName string     `json:"displayName"`
+	ActivityID  int64      `json:"activityId"`
+	StartTime   GarminTime `json:"startTimeGMT"`
+	ElapsedTime float64    `json:"elapsedDuration"` // seconds
+}
+
+// SegmentLeaderboard is one page of the ranked efforts on a segment
+type SegmentLeaderboard struct {
+	SegmentID   int64              `json:"segmentId"`
+	SegmentName string             `json:"segmentName"`
+	Distance    float64            `json:"distance"` // meters
+	Entries     []LeaderboardEntry `json:"leaderboardEntries"`
+	Pagination  Pagination         `json:"pagination"`
+}
+
+// GetActivitySegmentEfforts retrieves the segment efforts recorded during an activity
+func (c *Client) GetActivitySegmentEfforts(ctx context.Context, activityID int64, opts ...RequestOption) ([]SegmentEffort, error) {
+	var efforts []SegmentEffort
+	path := fmt.Sprintf("/segment-service/activity/%d/efforts", activityID)
+	if err := c.Get(ctx, path, &efforts, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get segment efforts: %w", err)
+	}
+	return efforts, nil
+}
+
+// GetSegmentLeaderboard retrieves one page of a segment's leaderboard
+func (c *Client) GetSegmentLeaderboard(ctx context.Context, segmentID int64, req PageRequest, opts ...RequestOption) (*SegmentLeaderboard, error) {
+	var board SegmentLeaderboard
+	path := fmt.Sprintf("/segment-service/segment/%d/leaderboard?%s", segmentID, req.values().Encode())
+	if err := c.Get(ctx, path, &board, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get segment leaderboard: %w", err)
+	}
+	return &board, nil
+}
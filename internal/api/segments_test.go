@@ -0,0 +1,45 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSegments(t *testing.T) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/segment-service/activity/5/efforts":
+			w.Write([]byte(`[{"segmentId": 42, "segmentName": "Hill Climb", "elapsedDuration": 312.5, "rank": 3, "personalRecord": true}]`))
+		case "/segment-service/segment/42/leaderboard":
+			assert.Equal(t, "2", r.URL.Query().Get("page"))
+			w.Write([]byte(`{"segmentId": 42, "segmentName": "Hill Climb", "leaderboardEntries": [
+				{"rank": 11, "displayName": "rider", "activityId": 5, "elapsedDuration": 300}
+			], "pagination": {"page": 2, "pageSize": 10, "totalCount": 25}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+
+	efforts, err := client.GetActivitySegmentEfforts(context.Background(), 5)
+	assert.NoError(t, err)
+	assert.Len(t, efforts, 1)
+	assert.Equal(t, "Hill Climb", efforts[0].SegmentName)
+	assert.Equal(t, Ptr(3), efforts[0].Rank)
+	assert.True(t, efforts[0].PersonalRecord)
+
+	board, err := client.GetSegmentLeaderboard(context.Background(), 42, FirstPage(10).Next())
+	assert.NoError(t, err)
+	assert.Equal(t, 11, board.Entries[0].Rank)
+	assert.True(t, board.Pagination.HasNext())
+
+	_, err = client.GetSegmentLeaderboard(context.Background(), 1, FirstPage(10))
+	assert.ErrorIs(t, err, ErrNotFound{})
+}
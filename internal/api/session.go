@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/sstent/go-garminconnect/internal/auth/garth"
+)
+
+// ErrLoginRequired reports that the stored session can no longer be used and
+// the user has to log in again
+var ErrLoginRequired = errors.New("interactive login required")
+
+// LoadSession reads the session saved at path and returns a client for it
+// once the tokens have been checked against Garmin. Missing, unreadable or
+// rejected sessions return an error wrapping ErrLoginRequired, so callers can
+// prompt for credentials before starting long-running work.
+func LoadSession(ctx context.Context, auth Authenticator, path string, opts ...ClientOption) (*Client, error) {
+	session, err := garth.LoadSession(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrLoginRequired, err)
+	}
+	if session.OAuth2Token == "" && session.OAuth1Token == "" {
+		return nil, fmt.Errorf("%w: session at %s holds no tokens", ErrLoginRequired, path)
+	}
+
+	client, err := NewClient(auth, session, path, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create API client: %w", err)
+	}
+	if err := client.ValidateSession(ctx); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// ValidateSession checks the client's tokens with a cheap profile request,
+// refreshing an expired token first. A rejected token or failed refresh
+// returns an error wrapping ErrLoginRequired; network failures and server
+// errors are returned as-is so an outage isn't mistaken for a revoked session.
+func (c *Client) ValidateSession(ctx context.Context) error {
+	if err := c.refreshTokenIfNeeded(); err != nil {
+		return fmt.Errorf("%w: %w", ErrLoginRequired, err)
+	}
+
+	var profile json.RawMessage
+	err := c.Get(ctx, hostProbePath, &profile, WithNoCache())
+	if err == nil {
+		return nil
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) &&
+		(apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+		return fmt.Errorf("%w: %w", ErrLoginRequired, err)
+	}
+	return fmt.Errorf("failed to validate session: %w", err)
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/sstent/go-garminconnect/internal/auth/garth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadSession(t *testing.T) {
+	status := http.StatusOK
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, hostProbePath, r.URL.Path)
+		assert.Equal(t, "Bearer saved-token", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(`{"displayName":"runner"}`))
+	})
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "session.json")
+	session := &garth.Session{OAuth2Token: "saved-token", ExpiresAt: time.Now().Add(time.Hour)}
+	assert.NoError(t, session.Save(path))
+
+	ctx := context.Background()
+	client, err := LoadSession(ctx, NewMockAuthenticator(), path, WithBaseURL(server.URL()))
+	assert.NoError(t, err)
+	assert.NotNil(t, client)
+
+	status = http.StatusUnauthorized
+	_, err = LoadSession(ctx, NewMockAuthenticator(), path, WithBaseURL(server.URL()))
+	assert.ErrorIs(t, err, ErrLoginRequired)
+
+	status = http.StatusInternalServerError
+	_, err = LoadSession(ctx, NewMockAuthenticator(), path, WithBaseURL(server.URL()))
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrLoginRequired)
+}
+
+func TestLoadSessionMissing(t *testing.T) {
+	_, err := LoadSession(context.Background(), NewMockAuthenticator(), filepath.Join(t.TempDir(), "none.json"))
+	assert.ErrorIs(t, err, ErrLoginRequired)
+}
+
+func TestValidateSessionRefreshFails(t *testing.T) {
+	auth := NewMockAuthenticatorWithFunc(func(string, string) (string, error) {
+		return "", errors.New("oauth1 token revoked")
+	})
+	session := &garth.Session{OAuth2Token: "stale", ExpiresAt: time.Now().Add(-time.Hour)}
+	client, err := NewClient(auth, session, "")
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, client.ValidateSession(context.Background()), ErrLoginRequired)
+}
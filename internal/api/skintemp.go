@@ -0,0 +1,42 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SkinTemperature is the nightly wrist temperature recorded by supported
+// devices, reported as a deviation from the user's personal baseline.
+// Values are nil until the device has established a baseline.
+type SkinTemperature struct {
+	RawJSON
+	Date         Date     `json:"calendarDate"`
+	AvgDeviation *float64 `json:"avgDeviationCelsius"` // last night's average minus the baseline
+	MinDeviation *float64 `json:"minDeviationCelsius"`
+	MaxDeviation *float64 `json:"maxDeviationCelsius"`
+	Baseline     *float64 `json:"baselineCelsius"`
+}
+
+// GetSkinTemperature retrieves the skin temperature deviation for the night
+// ending on the given date
+func (c *Client) GetSkinTemperature(ctx context.Context, date time.Time, opts ...RequestOption) (*SkinTemperature, error) {
+	var data SkinTemperature
+	path := fmt.Sprintf("/wellness-service/wellness/daily/skinTemp/%s", date.Format("2006-01-02"))
+
+	if err := c.Get(ctx, path, &data, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get skin temperature: %w", err)
+	}
+	return &data, nil
+}
+
+// GetSkinTemperatureRange retrieves skin temperature for every day from start to end inclusive
+func (c *Client) GetSkinTemperatureRange(ctx context.Context, start, end time.Time, opts ...RequestOption) ([]SkinTemperature, error) {
+	return FetchRange(ctx, start, end, c.rangeOpts, func(ctx context.Context, day time.Time) (SkinTemperature, error) {
+		data, err := c.GetSkinTemperature(ctx, day, opts...)
+		if err != nil {
+			return SkinTemperature{}, err
+		}
+		return *data, nil
+	})
+}
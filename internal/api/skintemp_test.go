@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSkinTemperature(t *testing.T) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/wellness-service/wellness/daily/skinTemp/2024-03-01":
+			w.Write([]byte(`{"calendarDate": "2024-03-01", "avgDeviationCelsius": -0.3, "minDeviationCelsius": -0.8, "maxDeviationCelsius": 0.2, "baselineCelsius": 33.9}`))
+		case "/wellness-service/wellness/daily/skinTemp/2024-03-02":
+			// No baseline yet
+			w.Write([]byte(`{"calendarDate": "2024-03-02"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	data, err := client.GetSkinTemperature(context.Background(), start)
+	assert.NoError(t, err)
+	assert.Equal(t, -0.3, *data.AvgDeviation)
+	assert.Equal(t, 33.9, *data.Baseline)
+
+	days, err := client.GetSkinTemperatureRange(context.Background(), start, start.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	assert.Len(t, days, 2)
+	assert.Nil(t, days[1].AvgDeviation)
+	assert.Equal(t, "2024-03-02", days[1].Date.Format("2006-01-02"))
+}
@@ -1,32 +1,34 @@
 package api
 
-import (
-	"time"
-
-	"github.com/go-playground/validator/v10"
-)
-
-// SleepData represents sleep metrics from Garmin Connect
+// SleepData represents sleep metrics from Garmin Connect.
+// Metrics are nil when Garmin has no value for them, e.g. on nights without a
+// recorded sleep, so they can be told apart from genuine zeros.
 type SleepData struct {
-	CalendarDate      time.Time `json:"calendarDate" validate:"required"`
-	SleepTimeSeconds  int       `json:"sleepTimeSeconds" validate:"min=0"`
-	DeepSleepSeconds  int       `json:"deepSleepSeconds" validate:"min=0"`
-	LightSleepSeconds int       `json:"lightSleepSeconds" validate:"min=0"`
-	RemSleepSeconds   int       `json:"remSleepSeconds" validate:"min=0"`
-	AwakeSeconds      int       `json:"awakeSeconds" validate:"min=0"`
-	SleepScore        int       `json:"sleepScore" validate:"min=0,max=100"`
-	SleepScores       struct {
-		Overall  int `json:"overall"`
-		Duration int `json:"duration"`
-		Deep     int `json:"deep"`
-		Rem      int `json:"rem"`
-		Light    int `json:"light"`
-		Awake    int `json:"awake"`
-	} `json:"sleepScores"`
+	RawJSON
+	CalendarDate      Date         `json:"calendarDate" validate:"required"`
+	SleepTimeSeconds  *int         `json:"sleepTimeSeconds" validate:"omitempty,min=0"`
+	DeepSleepSeconds  *int         `json:"deepSleepSeconds" validate:"omitempty,min=0"`
+	LightSleepSeconds *int         `json:"lightSleepSeconds" validate:"omitempty,min=0"`
+	RemSleepSeconds   *int         `json:"remSleepSeconds" validate:"omitempty,min=0"`
+	AwakeSeconds      *int         `json:"awakeSeconds" validate:"omitempty,min=0"`
+	SleepScore        *int         `json:"sleepScore" validate:"omitempty,min=0,max=100"`
+	SleepScores       *SleepScores `json:"sleepScores"`
+	SleepStart        GarminTime   `json:"sleepStartTimestampGMT"` // zero when no sleep was recorded
+	SleepEnd          GarminTime   `json:"sleepEndTimestampGMT"`
+}
+
+// SleepScores breaks the overall sleep score down into its components
+type SleepScores struct {
+	Overall  int `json:"overall"`
+	Duration int `json:"duration"`
+	Deep     int `json:"deep"`
+	Rem      int `json:"rem"`
+	Light    int `json:"light"`
+	Awake    int `json:"awake"`
 }
 
 // Validate ensures SleepData fields meet requirements
 func (s *SleepData) Validate() error {
-	validate := validator.New()
+	validate := newValidator()
 	return validate.Struct(s)
 }
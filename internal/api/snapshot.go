@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Snapshot field names used as keys of DailySnapshot.Errors
+const (
+	SnapshotSleep       = "sleep"
+	SnapshotSteps       = "steps"
+	SnapshotStress      = "stress"
+	SnapshotHRV         = "hrv"
+	SnapshotBodyBattery = "bodyBattery"
+	SnapshotHeartRate   = "heartRate"
+)
+
+// DailySnapshot gathers the wellness data of one day. A field is nil when its
+// request failed; the failure is recorded in Errors under the field's name.
+type DailySnapshot struct {
+	Date        time.Time
+	Sleep       *SleepData
+	Steps       *DailySteps
+	Stress      *DailyStress
+	HRV         *HRVData
+	BodyBattery *BodyBatteryData
+	HeartRate   *HeartRateData
+	Errors      map[string]error
+}
+
+// Err returns nil when every field was fetched, otherwise an error listing the
+// failed fields that unwraps to their errors
+func (s *DailySnapshot) Err() error {
+	if len(s.Errors) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(s.Errors))
+	for name := range s.Errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	errs := make([]error, len(names))
+	for i, name := range names {
+		errs[i] = s.Errors[name]
+	}
+	return fmt.Errorf("incomplete snapshot (%s): %w", strings.Join(names, ", "), errors.Join(errs...))
+}
+
+// GetDailySnapshot fetches sleep, steps, stress, HRV, Body Battery and heart
+// rate for date concurrently. Individual failures are reported per field in
+// the snapshot; an error is only returned when ctx is cancelled.
+func (c *Client) GetDailySnapshot(ctx context.Context, date time.Time, opts ...RequestOption) (*DailySnapshot, error) {
+	snapshot := &DailySnapshot{Date: date, Errors: make(map[string]error)}
+	var mu sync.Mutex
+
+	var g errgroup.Group
+	g.SetLimit(max(c.rangeOpts.Concurrency, 1))
+	fetch := func(name string, get func() error) {
+		g.Go(func() error {
+			if err := get(); err != nil {
+				mu.Lock()
+				snapshot.Errors[name] = err
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+
+	fetch(SnapshotSleep, func() (err error) {
+		snapshot.Sleep, err = c.GetSleepData(ctx, date, opts...)
+		return err
+	})
+	fetch(SnapshotSteps, func() (err error) {
+		snapshot.Steps, err = c.GetStepsData(ctx, date, opts...)
+		return err
+	})
+	fetch(SnapshotStress, func() (err error) {
+		snapshot.Stress, err = c.GetStressData(ctx, date, opts...)
+		return err
+	})
+	fetch(SnapshotHRV, func() (err error) {
+		snapshot.HRV, err = c.GetHRVData(ctx, date, opts...)
+		return err
+	})
+	fetch(SnapshotBodyBattery, func() (err error) {
+		snapshot.BodyBattery, err = c.GetBodyBatteryData(ctx, date, opts...)
+		return err
+	})
+	fetch(SnapshotHeartRate, func() (err error) {
+		snapshot.HeartRate, err = c.GetHeartRateData(ctx, date, opts...)
+		return err
+	})
+	g.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDailySnapshot(t *testing.T) {
+	responses := map[string]string{
+		"/wellness-service/sleep/daily/2024-03-01":    `{"calendarDate": "2024-03-01", "sleepTimeSeconds": 27000}`,
+		"/wellness-service/steps/daily/2024-03-01":    `{"calendarDate": "2024-03-01", "totalSteps": 9500}`,
+		"/wellness-service/stress/daily/2024-03-01":   `{"calendarDate": "2024-03-01", "overallStressLevel": 28}`,
+		"/bodybattery-service/bodybattery/2024-03-01": `{"date": "2024-03-01", "highest": 92}`,
+		"/wellness-service/wellness/dailyHeartRate/2024-03-01": `{"calendarDate": "2024-03-01", "restingHeartRate": 52,
+			"heartRateValues": [[1709251200000, 55], [1709251320000, null], [1709251440000, 61]]}`,
+	}
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+
+	snapshot, err := client.GetDailySnapshot(context.Background(), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, 27000, *snapshot.Sleep.SleepTimeSeconds)
+	assert.Equal(t, 9500, snapshot.Steps.TotalSteps)
+	assert.Equal(t, 28, snapshot.Stress.OverallStressLevel)
+	assert.Equal(t, 92, *snapshot.BodyBattery.Highest)
+	assert.Equal(t, 52, *snapshot.HeartRate.RestingHR)
+
+	samples := snapshot.HeartRate.Samples()
+	assert.Len(t, samples, 2)
+	assert.Equal(t, 61, samples[1].BPM)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 4, 0, 0, time.UTC), samples[1].Time)
+
+	// HRV has no data for the day
+	assert.Nil(t, snapshot.HRV)
+	assert.Len(t, snapshot.Errors, 1)
+	assert.ErrorIs(t, snapshot.Errors[SnapshotHRV], ErrNotFound{})
+	assert.ErrorIs(t, snapshot.Err(), ErrNotFound{})
+	assert.True(t, strings.Contains(snapshot.Err().Error(), "(hrv)"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.GetDailySnapshot(ctx, time.Now())
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/daterange"
+)
+
+// DefaultMaxSpans is the longest date range in days that services taking a
+// start and end date accept in one request. Longer ranges are rejected with
+// 400 Bad Request, so they are split and the results merged.
+var DefaultMaxSpans = map[string]int{
+	"body-composition":      31,
+	"weight-service":        31,
+	"hrv-service":           28,
+	"bloodpressure-service": 31,
+}
+
+// maxSpan returns the longest range in days accepted by the service of path,
+// or 0 when ranges of any length are accepted
+func (c *Client) maxSpan(path string) int {
+	service, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	service, _, _ = strings.Cut(service, "?")
+	if days, ok := c.rangeOpts.MaxSpans[service]; ok {
+		return days
+	}
+	return DefaultMaxSpans[service]
+}
+
+// fetchSpans calls fetch for consecutive ranges covering start to end that fit
+// the maximum span of the service of path, and concatenates the results in
+// date order. The first failing range aborts the fetch.
+func fetchSpans[T any](ctx context.Context, c *Client, path string, start, end time.Time, fetch func(ctx context.Context, start, end time.Time) ([]T, error)) ([]T, error) {
+	maxDays := c.maxSpan(path)
+	if maxDays <= 0 {
+		return fetch(ctx, start, end)
+	}
+
+	var results []T
+	for _, span := range daterange.Chunks(start, end, maxDays) {
+		chunk, err := fetch(ctx, span.Start, span.End)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, chunk...)
+	}
+	return results, nil
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchSpans(t *testing.T) {
+	var ranges []string
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		start, end := r.URL.Query().Get("startDate"), r.URL.Query().Get("endDate")
+		ranges = append(ranges, start+"/"+end)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"dateWeightList": [{"weight": 70000, "timestampGMT": "%sT07:00:00Z"}]}`, start)
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+	ctx := context.Background()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	req := BodyCompositionRequest{StartDate: NewGarminTime(start), EndDate: NewGarminTime(start.AddDate(0, 0, 69))}
+	results, err := client.GetBodyComposition(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2024-01-01/2024-01-31", "2024-02-01/2024-03-02", "2024-03-03/2024-03-10"}, ranges)
+	assert.Len(t, results, 3)
+	assert.Equal(t, time.Date(2024, 3, 3, 7, 0, 0, 0, time.UTC), results[2].Timestamp.UTC())
+
+	// Overrides change the span and zero disables splitting
+	ranges = nil
+	WithRangeOptions(RangeOptions{MaxSpans: map[string]int{"weight-service": 0}})(client)
+	_, err = client.GetBodyComposition(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2024-01-01/2024-03-10"}, ranges)
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SpO2Data is the blood oxygen saturation measured by the pulse oximeter on a
+// single day. Values are nil for days without readings.
+type SpO2Data struct {
+	RawJSON
+	Date         Date     `json:"calendarDate"`
+	Average      *float64 `json:"averageSpO2"` // percent
+	Lowest       *int     `json:"lowestSpO2"`
+	LatestValue  *int     `json:"latestSpO2"`
+	SleepAverage *float64 `json:"avgSleepSpO2"`
+}
+
+// GetSpO2Data retrieves pulse oximeter data for a specific date
+func (c *Client) GetSpO2Data(ctx context.Context, date time.Time, opts ...RequestOption) (*SpO2Data, error) {
+	var data SpO2Data
+	path := fmt.Sprintf("/wellness-service/wellness/daily/spo2/%s", date.Format("2006-01-02"))
+
+	if err := c.Get(ctx, path, &data, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get SpO2 data: %w", err)
+	}
+	return &data, nil
+}
+
+// GetSpO2DataRange retrieves pulse oximeter data for every day from start to end inclusive
+func (c *Client) GetSpO2DataRange(ctx context.Context, start, end time.Time, opts ...RequestOption) ([]SpO2Data, error) {
+	return FetchRange(ctx, start, end, c.rangeOpts, func(ctx context.Context, day time.Time) (SpO2Data, error) {
+		data, err := c.GetSpO2Data(ctx, day, opts...)
+		if err != nil {
+			return SpO2Data{}, err
+		}
+		return *data, nil
+	})
+}
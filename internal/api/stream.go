@@ -0,0 +1,151 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"time"
+)
+
+// maxErrorBody bounds how much of a failed streamed response is read for the error message
+const maxErrorBody = 64 << 10
+
+// errStopStream ends a stream early without reporting an error to the caller
+var errStopStream = errors.New("stream stopped")
+
+// GetStream performs a GET request and hands the undecoded response body to fn,
+// so large responses can be processed without buffering them in memory
+func (c *Client) GetStream(ctx context.Context, path string, fn func(body io.Reader) error, opts ...RequestOption) error {
+	if err := c.refreshTokenIfNeeded(); err != nil {
+		return err
+	}
+
+	req, cancel := c.newRequest(ctx, opts)
+	defer cancel()
+
+	resp, err := req.
+		SetDoNotParseResponse(true).
+		Get(path)
+	if err != nil {
+		return err
+	}
+	body := resp.RawBody()
+	defer body.Close()
+
+	if resp.StatusCode() == http.StatusUnauthorized {
+		c.mu.Lock()
+		c.session = nil
+		c.mu.Unlock()
+		return unauthorizedError(resp)
+	}
+	if resp.StatusCode() >= 400 {
+		data, _ := io.ReadAll(io.LimitReader(body, maxErrorBody))
+		return c.handleAPIError(resp.SetBody(data))
+	}
+
+	return fn(body)
+}
+
+// StreamArray decodes a JSON array element by element and calls fn for each one.
+// With an empty key the document itself must be an array; otherwise the array is
+// taken from that top-level field of an object and all other fields are skipped.
+// A missing or null array yields no elements.
+func StreamArray[T any](r io.Reader, key string, fn func(T) error) error {
+	dec := json.NewDecoder(r)
+
+	if key != "" {
+		if err := expectDelim(dec, '{'); err != nil {
+			return err
+		}
+		for {
+			if !dec.More() {
+				return nil
+			}
+			tok, err := dec.Token()
+			if err != nil {
+				return fmt.Errorf("failed to read JSON field: %w", err)
+			}
+			if tok == key {
+				break
+			}
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return fmt.Errorf("failed to skip JSON field %v: %w", tok, err)
+			}
+		}
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("failed to read JSON array: %w", err)
+	}
+	if tok == nil {
+		return nil
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '[' {
+		return fmt.Errorf("expected JSON array, got %v", tok)
+	}
+
+	for dec.More() {
+		var item T
+		if err := dec.Decode(&item); err != nil {
+			return fmt.Errorf("failed to decode array element: %w", err)
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token()
+	return err
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("failed to read JSON: %w", err)
+	}
+	if d, ok := tok.(json.Delim); !ok || d != want {
+		return fmt.Errorf("expected JSON %q, got %v", want, tok)
+	}
+	return nil
+}
+
+// StreamActivitiesByDate calls fn for every activity started between start and
+// end (inclusive) as it is decoded, one page at a time. Returning an error from
+// fn stops the stream and returns that error.
+func (c *Client) StreamActivitiesByDate(ctx context.Context, start, end time.Time, fn func(Activity) error, opts ...RequestOption) error {
+	loc := c.localZone(ctx)
+	return activitySearchPages(start, end, func(path string, page PageRequest) (bool, error) {
+		count := 0
+		err := c.GetStream(ctx, path, func(body io.Reader) error {
+			return StreamArray(body, "activities", func(ar ActivityResponse) error {
+				count++
+				return fn(ar.ToActivity(loc))
+			})
+		}, opts...)
+		if err != nil {
+			return false, fmt.Errorf("failed to stream activities: %w", err)
+		}
+		return count >= page.PageSize, nil
+	})
+}
+
+// ActivitiesByDate iterates over the activities started between start and end
+// (inclusive) without loading the whole list. Iteration stops after the first error.
+func (c *Client) ActivitiesByDate(ctx context.Context, start, end time.Time, opts ...RequestOption) iter.Seq2[Activity, error] {
+	return func(yield func(Activity, error) bool) {
+		err := c.StreamActivitiesByDate(ctx, start, end, func(a Activity) error {
+			if !yield(a, nil) {
+				return errStopStream
+			}
+			return nil
+		}, opts...)
+		if err != nil && !errors.Is(err, errStopStream) {
+			yield(Activity{}, err)
+		}
+	}
+}
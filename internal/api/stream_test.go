@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamArray(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		key     string
+		want    []int
+		wantErr bool
+	}{
+		{name: "top-level array", input: `[1, 2, 3]`, want: []int{1, 2, 3}},
+		{name: "keyed array after other fields", input: `{"meta": {"a": [9]}, "items": [4, 5], "tail": true}`, key: "items", want: []int{4, 5}},
+		{name: "missing key", input: `{"other": [1]}`, key: "items"},
+		{name: "null array", input: `{"items": null}`, key: "items"},
+		{name: "not an array", input: `{"items": 7}`, key: "items", wantErr: true},
+		{name: "truncated", input: `[1, 2`, want: []int{1, 2}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			err := StreamArray(strings.NewReader(tt.input), tt.key, func(v int) error {
+				got = append(got, v)
+				return nil
+			})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	errStop := errors.New("stop")
+	var seen int
+	err := StreamArray(strings.NewReader(`[1, 2, 3]`), "", func(v int) error {
+		seen++
+		return errStop
+	})
+	assert.ErrorIs(t, err, errStop)
+	assert.Equal(t, 1, seen)
+}
+
+func TestStreamActivitiesByDate(t *testing.T) {
+	mockServer := garmintest.NewServer()
+	defer mockServer.Close()
+	client := NewClientWithBaseURL(mockServer.URL())
+
+	pages := func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		count := 100
+		if page > 1 {
+			count = 5
+		}
+		activities := make([]map[string]interface{}, count)
+		for i := range activities {
+			activities[i] = map[string]interface{}{
+				"activityId":     (page-1)*100 + i + 1,
+				"startTimeLocal": "2024-03-12T07:00:00",
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"activities": activities})
+	}
+	mockServer.Handle(garmintest.Activities, pages)
+
+	start := time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 6)
+
+	var ids []int64
+	err := client.StreamActivitiesByDate(context.Background(), start, end, func(a Activity) error {
+		ids = append(ids, a.ActivityID)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, ids, 105)
+	assert.Equal(t, int64(105), ids[104])
+	assert.Equal(t, 2, mockServer.RequestCount(garmintest.Activities))
+
+	// Breaking out of the iterator stops before the next page is requested
+	mockServer.Reset()
+	mockServer.Handle(garmintest.Activities, pages)
+	n := 0
+	for a, err := range client.ActivitiesByDate(context.Background(), start, end) {
+		assert.NoError(t, err)
+		n++
+		if a.ActivityID == 10 {
+			break
+		}
+	}
+	assert.Equal(t, 10, n)
+	assert.Equal(t, 1, mockServer.RequestCount(garmintest.Activities))
+}
+
+func TestStreamActivitiesError(t *testing.T) {
+	mockServer := garmintest.NewServer()
+	defer mockServer.Close()
+	client := NewClientWithBaseURL(mockServer.URL())
+
+	mockServer.Handle(garmintest.Activities, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"code": 500, "message": "Internal error"}`))
+	})
+
+	var got error
+	for _, err := range client.ActivitiesByDate(context.Background(), time.Now(), time.Now()) {
+		got = err
+	}
+	assert.ErrorContains(t, got, "API error 500: Internal error")
+}
+
+func TestStreamHeartRateSamples(t *testing.T) {
+	mockServer := garmintest.NewServer()
+	defer mockServer.Close()
+	client := NewClientWithBaseURL(mockServer.URL())
+
+	mockServer.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/wellness-service/wellness/dailyHeartRate/2024-03-01", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"calendarDate": "2024-03-01", "restingHeartRate": 52,
+			"heartRateValues": [[1709251200000, 55], [1709251320000, null], [1709251440000, 61]]}`))
+	})
+
+	var samples []HeartRateSample
+	err := client.StreamHeartRateSamples(context.Background(), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), func(s HeartRateSample) error {
+		samples = append(samples, s)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []HeartRateSample{
+		{Time: time.UnixMilli(1709251200000).UTC(), BPM: 55},
+		{Time: time.UnixMilli(1709251440000).UTC(), BPM: 61},
+	}, samples, "gaps are skipped")
+}
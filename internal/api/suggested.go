@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Workout step types
+const (
+	StepWarmup   = "warmup"
+	StepInterval = "interval"
+	StepRecovery = "recovery"
+	StepRest     = "rest"
+	StepCooldown = "cooldown"
+	StepRepeat   = "repeat"
+)
+
+// Suggested workout states
+const (
+	SuggestionPending  = "PENDING"
+	SuggestionAccepted = "ACCEPTED"
+	SuggestionDeclined = "DECLINED"
+)
+
+// SuggestedWorkout is the workout Garmin suggests for a day
+type SuggestedWorkout struct {
+	RawJSON
+	Date            Date          `json:"calendarDate"`
+	SportType       string        `json:"sportTypeKey"` // e.g. "running", "cycling"
+	Name            string        `json:"workoutName"`
+	Description     string        `json:"description"`
+	DurationSeconds *float64      `json:"estimatedDurationInSecs"`
+	TrainingEffect  string        `json:"trainingEffectLabel"` // e.g. "BASE", "TEMPO", "VO2MAX"
+	Status          string        `json:"status"`              // one of the Suggestion* states
+	Steps           []WorkoutStep `json:"workoutSteps"`
+}
+
+// WorkoutStep is one step of a structured workout. Repeat steps hold the
+// repeated steps in Steps and run them Iterations times.
+type WorkoutStep struct {
+	Order int    `json:"stepOrder"`
+	Type  string `json:"stepType"` // one of the Step* types
+	// EndCondition is "time" (seconds), "distance" (meters) or "lap.button"
+	EndCondition      string   `json:"endCondition"`
+	EndConditionValue *float64 `json:"endConditionValue"`
+	// TargetType is e.g. "no.target", "heart.rate.zone", "pace.zone" or
+	// "power.zone"; pace targets are in meters per second
+	TargetType string        `json:"targetType"`
+	TargetLow  *float64      `json:"targetValueOne"`
+	TargetHigh *float64      `json:"targetValueTwo"`
+	Iterations int           `json:"numberOfIterations"`
+	Steps      []WorkoutStep `json:"workoutSteps"`
+}
+
+// FlatSteps returns the workout's steps with repeat groups expanded in order
+func (w SuggestedWorkout) FlatSteps() []WorkoutStep {
+	return flattenSteps(w.Steps)
+}
+
+func flattenSteps(steps []WorkoutStep) []WorkoutStep {
+	var flat []WorkoutStep
+	for _, step := range steps {
+		if step.Type != StepRepeat {
+			flat = append(flat, step)
+			continue
+		}
+		inner := flattenSteps(step.Steps)
+		for range step.Iterations {
+			flat = append(flat, inner...)
+		}
+	}
+	return flat
+}
+
+// GetDailySuggestedWorkout retrieves Garmin's suggested workout for the given date
+func (c *Client) GetDailySuggestedWorkout(ctx context.Context, date time.Time, opts ...RequestOption) (*SuggestedWorkout, error) {
+	var workout SuggestedWorkout
+	path := fmt.Sprintf("/workout-service/suggested/%s", date.Format("2006-01-02"))
+	if err := c.Get(ctx, path, &workout, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get suggested workout: %w", err)
+	}
+	return &workout, nil
+}
+
+// AcceptSuggestedWorkout adds the date's suggested workout to the calendar
+func (c *Client) AcceptSuggestedWorkout(ctx context.Context, date time.Time, opts ...RequestOption) error {
+	return c.respondSuggestedWorkout(ctx, date, "accept", opts)
+}
+
+// DeclineSuggestedWorkout dismisses the date's suggested workout
+func (c *Client) DeclineSuggestedWorkout(ctx context.Context, date time.Time, opts ...RequestOption) error {
+	return c.respondSuggestedWorkout(ctx, date, "decline", opts)
+}
+
+func (c *Client) respondSuggestedWorkout(ctx context.Context, date time.Time, action string, opts []RequestOption) error {
+	path := fmt.Sprintf("/workout-service/suggested/%s/%s", date.Format("2006-01-02"), action)
+	if err := c.Post(ctx, path, nil, nil, opts...); err != nil {
+		return fmt.Errorf("failed to %s suggested workout: %w", action, err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDailySuggestedWorkout(t *testing.T) {
+	var responses []string
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			responses = append(responses, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		assert.Equal(t, "/workout-service/suggested/2024-03-02", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"calendarDate": "2024-03-02",
+			"sportTypeKey": "running",
+			"workoutName": "VO2 Max",
+			"trainingEffectLabel": "VO2MAX",
+			"status": "PENDING",
+			"workoutSteps": [
+				{"stepOrder": 1, "stepType": "warmup", "endCondition": "time", "endConditionValue": 600, "targetType": "no.target"},
+				{"stepOrder": 2, "stepType": "repeat", "numberOfIterations": 3, "workoutSteps": [
+					{"stepOrder": 3, "stepType": "interval", "endCondition": "time", "endConditionValue": 180,
+					 "targetType": "pace.zone", "targetValueOne": 4.2, "targetValueTwo": 4.5},
+					{"stepOrder": 4, "stepType": "recovery", "endCondition": "time", "endConditionValue": 120}
+				]},
+				{"stepOrder": 5, "stepType": "cooldown", "endCondition": "lap.button"}
+			]
+		}`))
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+	day := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	workout, err := client.GetDailySuggestedWorkout(context.Background(), day)
+	assert.NoError(t, err)
+	assert.Equal(t, SuggestionPending, workout.Status)
+	assert.Len(t, workout.Steps, 3)
+	assert.Equal(t, 4.5, *workout.Steps[1].Steps[0].TargetHigh)
+
+	flat := workout.FlatSteps()
+	assert.Len(t, flat, 8)
+	assert.Equal(t, StepWarmup, flat[0].Type)
+	assert.Equal(t, StepRecovery, flat[6].Type)
+	assert.Equal(t, StepCooldown, flat[7].Type)
+	assert.Nil(t, flat[7].EndConditionValue)
+
+	assert.NoError(t, client.AcceptSuggestedWorkout(context.Background(), day))
+	assert.NoError(t, client.DeclineSuggestedWorkout(context.Background(), day))
+	assert.Equal(t, []string{
+		"/workout-service/suggested/2024-03-02/accept",
+		"/workout-service/suggested/2024-03-02/decline",
+	}, responses)
+}
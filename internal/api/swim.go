@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Swim stroke types reported for pool lengths
+const (
+	StrokeFreestyle    = "FREESTYLE"
+	StrokeBackstroke   = "BACKSTROKE"
+	StrokeBreaststroke = "BREASTSTROKE"
+	StrokeButterfly    = "BUTTERFLY"
+	StrokeDrill        = "DRILL"
+	StrokeMixed        = "MIXED"
+)
+
+// SwimSummary holds the pool swim totals of an activity. Fields are nil for
+// activities other than pool swims.
+type SwimSummary struct {
+	PoolLength    *float64 `json:"poolLength,omitempty"`           // meters
+	ActiveLengths *int     `json:"activeLengths,omitempty"`        // lengths swum, excluding rest
+	TotalStrokes  *int     `json:"totalNumberOfStrokes,omitempty"` // strokes over all lengths
+	AvgStrokes    *float64 `json:"averageStrokes,omitempty"`       // strokes per length
+	AvgSWOLF      *float64 `json:"averageSwolf,omitempty"`         // seconds plus strokes per length
+}
+
+// SwimLength is one length of the pool, or a rest when LengthType is "IDLE"
+type SwimLength struct {
+	Index      int        `json:"lengthIndex"`
+	StartTime  GarminTime `json:"startTimeGMT"`
+	LengthType string     `json:"lengthType"` // "ACTIVE" or "IDLE"
+	Stroke     string     `json:"swimStroke"`
+	Distance   float64    `json:"distance"` // meters
+	Duration   float64    `json:"duration"` // seconds
+	Strokes    int        `json:"totalNumberOfStrokes"`
+	SWOLF      *float64   `json:"averageSwolf,omitempty"`
+}
+
+// IsRest reports whether the length is a rest at the wall
+func (l SwimLength) IsRest() bool {
+	return l.LengthType == "IDLE" || l.Distance == 0
+}
+
+// Swolf returns the SWOLF score of the length, computing it from duration and
+// stroke count when Garmin did not report one
+func (l SwimLength) Swolf() float64 {
+	if l.SWOLF != nil {
+		return *l.SWOLF
+	}
+	if l.IsRest() {
+		return 0
+	}
+	return math.Round(l.Duration) + float64(l.Strokes)
+}
+
+// SwimInterval is one interval (lap) of a pool swim with its lengths
+type SwimInterval struct {
+	Index     int          `json:"lapIndex"`
+	StartTime GarminTime   `json:"startTimeGMT"`
+	Distance  float64      `json:"distance"` // meters
+	Duration  float64      `json:"duration"` // seconds
+	Lengths   []SwimLength `json:"lengthDTOs"`
+}
+
+// IsRest reports whether the interval is a rest between sets
+func (i SwimInterval) IsRest() bool {
+	for _, l := range i.Lengths {
+		if !l.IsRest() {
+			return false
+		}
+	}
+	return i.Distance == 0
+}
+
+// SwimData holds the intervals and lengths of a pool swim
+type SwimData struct {
+	Intervals []SwimInterval `json:"lapDTOs"`
+}
+
+// Lengths returns the swum lengths of all intervals in order, excluding rests
+func (s *SwimData) Lengths() []SwimLength {
+	var lengths []SwimLength
+	for _, i := range s.Intervals {
+		for _, l := range i.Lengths {
+			if !l.IsRest() {
+				lengths = append(lengths, l)
+			}
+		}
+	}
+	return lengths
+}
+
+// RestTime returns the total time spent resting between lengths and intervals
+func (s *SwimData) RestTime() time.Duration {
+	var rest float64
+	for _, i := range s.Intervals {
+		if i.IsRest() {
+			rest += i.Duration
+			continue
+		}
+		for _, l := range i.Lengths {
+			if l.IsRest() {
+				rest += l.Duration
+			}
+		}
+	}
+	return time.Duration(rest * float64(time.Second))
+}
+
+// GetActivitySwimData retrieves the intervals and lengths of a pool swim
+func (c *Client) GetActivitySwimData(ctx context.Context, activityID int64, opts ...RequestOption) (*SwimData, error) {
+	var data SwimData
+	path := fmt.Sprintf("/activity-service/activity/%d/splits", activityID)
+	if err := c.Get(ctx, path, &data, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get swim data: %w", err)
+	}
+	return &data, nil
+}
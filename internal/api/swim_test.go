@@ -0,0 +1,42 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetActivitySwimData(t *testing.T) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/activity-service/activity/3/splits", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"lapDTOs": [
+			{"lapIndex": 1, "distance": 50, "duration": 70, "lengthDTOs": [
+				{"lengthIndex": 1, "lengthType": "ACTIVE", "swimStroke": "FREESTYLE", "distance": 25, "duration": 24.6, "totalNumberOfStrokes": 14, "averageSwolf": 39},
+				{"lengthIndex": 2, "lengthType": "ACTIVE", "swimStroke": "BREASTSTROKE", "distance": 25, "duration": 30.2, "totalNumberOfStrokes": 12},
+				{"lengthIndex": 3, "lengthType": "IDLE", "distance": 0, "duration": 15}
+			]},
+			{"lapIndex": 2, "distance": 0, "duration": 45, "lengthDTOs": []}
+		]}`))
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+
+	data, err := client.GetActivitySwimData(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Len(t, data.Intervals, 2)
+	assert.False(t, data.Intervals[0].IsRest())
+	assert.True(t, data.Intervals[1].IsRest())
+
+	lengths := data.Lengths()
+	assert.Len(t, lengths, 2)
+	assert.Equal(t, StrokeFreestyle, lengths[0].Stroke)
+	assert.Equal(t, float64(39), lengths[0].Swolf())
+	assert.Equal(t, float64(42), lengths[1].Swolf(), "computed from duration and strokes")
+	assert.Equal(t, 60*time.Second, data.RestTime())
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/sstent/go-garminconnect/internal/auth/garth"
+)
+
+// NewClientWithBaseURL creates a test client that sends its requests to
+// baseURL, usually a garmintest server
+func NewClientWithBaseURL(baseURL string) *Client {
+	session := &garth.Session{
+		OAuth2Token: "mock-token",
+		ExpiresAt:   time.Now().Add(8 * time.Hour),
+	}
+
+	client, err := NewClient(NewMockAuthenticator(), session, "")
+	if err != nil {
+		panic("failed to create test client: " + err.Error())
+	}
+	client.HTTPClient.SetBaseURL(baseURL)
+	return client
+}
+
+// handleHealth installs handler for the sleep, stress, steps, HRV and body
+// battery endpoints of s
+func handleHealth(s *garmintest.Server, handler http.HandlerFunc) {
+	for _, e := range []garmintest.Endpoint{garmintest.Sleep, garmintest.Stress, garmintest.Steps, garmintest.HRV, garmintest.BodyBattery} {
+		s.Handle(e, handler)
+	}
+}
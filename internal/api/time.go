@@ -0,0 +1,123 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// garminTimeLayouts are the timestamp formats found in Garmin Connect responses,
+// tried in order. Layouts without a zone are Garmin's "local" wall-clock fields.
+var garminTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// GarminTime is a timestamp decoded from any of the formats Garmin Connect uses:
+// RFC 3339, zone-less local date-times ("2006-01-02T15:04:05"), GMT date-times
+// with a space separator, plain dates and epoch milliseconds. Values without a
+// zone are decoded as UTC wall clock; the client places the local start times
+// of activities in the user's profile zone with Attach.
+type GarminTime struct {
+	time.Time
+}
+
+// NewGarminTime wraps t
+func NewGarminTime(t time.Time) GarminTime {
+	return GarminTime{Time: t}
+}
+
+// ParseGarminTime parses s using the known Garmin layouts
+func ParseGarminTime(s string) (GarminTime, error) {
+	for _, layout := range garminTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return GarminTime{Time: t}, nil
+		}
+	}
+	return GarminTime{}, fmt.Errorf("unrecognized Garmin time %q", s)
+}
+
+// Attach returns the same wall-clock time in loc. It is meant for Garmin's
+// zone-less "local" fields, which are decoded as UTC.
+func (t GarminTime) Attach(loc *time.Location) GarminTime {
+	if t.IsZero() || loc == nil {
+		return t
+	}
+	return GarminTime{Time: time.Date(t.Year(), t.Month(), t.Day(),
+		t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)}
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Strings are parsed with the known
+// layouts, numbers as epoch milliseconds, and null or "" leave the zero time.
+func (t *GarminTime) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*t = GarminTime{}
+		return nil
+	}
+
+	if len(data) > 0 && data[0] != '"' {
+		ms, err := strconv.ParseInt(string(data), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid Garmin timestamp %s: %w", data, err)
+		}
+		*t = GarminTime{Time: time.UnixMilli(ms).UTC()}
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		*t = GarminTime{}
+		return nil
+	}
+	parsed, err := ParseGarminTime(s)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, encoding RFC 3339 so the value
+// round-trips through UnmarshalJSON
+func (t GarminTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Time.Format(time.RFC3339Nano))
+}
+
+// newValidator returns a validator that checks GarminTime and Date fields like
+// time.Time, so "required" rejects zero values. Errors name fields by their
+// JSON names.
+func newValidator() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			return ""
+		case "":
+			return f.Name
+		}
+		return name
+	})
+	validate.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		switch v := field.Interface().(type) {
+		case GarminTime:
+			return v.Time
+		case Date:
+			return v.Time
+		}
+		return nil
+	}, GarminTime{}, Date{})
+	return validate
+}
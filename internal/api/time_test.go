@@ -0,0 +1,74 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGarminTimeUnmarshal(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Time
+		wantErr  bool
+	}{
+		{`"2024-03-01T07:30:00"`, time.Date(2024, 3, 1, 7, 30, 0, 0, time.UTC), false},
+		{`"2024-03-01T07:30:00.500"`, time.Date(2024, 3, 1, 7, 30, 0, 500e6, time.UTC), false},
+		{`"2024-03-01T07:30:00.0Z"`, time.Date(2024, 3, 1, 7, 30, 0, 0, time.UTC), false},
+		{`"2024-03-01T07:30:00+01:00"`, time.Date(2024, 3, 1, 6, 30, 0, 0, time.UTC), false},
+		{`"2024-03-01 06:30:00"`, time.Date(2024, 3, 1, 6, 30, 0, 0, time.UTC), false},
+		{`"2024-03-01"`, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), false},
+		{`1709278200000`, time.Date(2024, 3, 1, 7, 30, 0, 0, time.UTC), false},
+		{`null`, time.Time{}, false},
+		{`""`, time.Time{}, false},
+		{`"yesterday"`, time.Time{}, true},
+		{`true`, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var gt GarminTime
+			err := json.Unmarshal([]byte(tt.input), &gt)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, tt.expected.Equal(gt.Time), "got %v", gt.Time)
+		})
+	}
+}
+
+func TestGarminTimeRoundTrip(t *testing.T) {
+	berlin := time.FixedZone("CET", 3600)
+	in := NewGarminTime(time.Date(2024, 3, 1, 7, 30, 0, 0, berlin))
+
+	data, err := json.Marshal(in)
+	assert.NoError(t, err)
+	assert.Equal(t, `"2024-03-01T07:30:00+01:00"`, string(data))
+
+	var out GarminTime
+	assert.NoError(t, json.Unmarshal(data, &out))
+	assert.True(t, in.Equal(out.Time))
+}
+
+func TestGarminTimeAttach(t *testing.T) {
+	berlin := time.FixedZone("CET", 3600)
+	local, err := ParseGarminTime("2024-03-01T07:30:00")
+	assert.NoError(t, err)
+
+	attached := local.Attach(berlin)
+	assert.Equal(t, 7, attached.Hour())
+	assert.Equal(t, berlin, attached.Location())
+	assert.True(t, attached.Equal(time.Date(2024, 3, 1, 6, 30, 0, 0, time.UTC)))
+	assert.True(t, GarminTime{}.Attach(berlin).IsZero())
+}
+
+func TestValidateRequiredGarminTime(t *testing.T) {
+	var sleep SleepData
+	assert.Error(t, sleep.Validate())
+	sleep.CalendarDate = NewDate(time.Now())
+	assert.NoError(t, sleep.Validate())
+}
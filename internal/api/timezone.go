@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// UserSettings holds the account settings that affect how data is reported
+type UserSettings struct {
+	RawJSON
+	TimeZone          string `json:"timeZone"`          // IANA zone name, e.g. "Europe/Berlin"
+	MeasurementSystem string `json:"measurementSystem"` // "metric" or "statute_us"
+	FirstDayOfWeek    string `json:"firstDayOfWeek"`
+}
+
+// GetUserSettings retrieves the account settings of the user
+func (c *Client) GetUserSettings(ctx context.Context, opts ...RequestOption) (*UserSettings, error) {
+	var settings UserSettings
+	if err := c.Get(ctx, "/userprofile-service/userprofile/settings", &settings, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get user settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// WithLocation fixes the user's time zone instead of reading it from the profile
+func WithLocation(loc *time.Location) ClientOption {
+	return func(c *Client) {
+		c.loc = loc
+	}
+}
+
+// Location returns the time zone configured in the user's Garmin profile. It is
+// fetched once and cached; profiles without a zone fall back to time.Local.
+func (c *Client) Location(ctx context.Context) (*time.Location, error) {
+	c.locMu.Lock()
+	defer c.locMu.Unlock()
+	if c.loc != nil {
+		return c.loc, nil
+	}
+
+	settings, err := c.GetUserSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	loc := time.Local
+	if settings.TimeZone != "" {
+		if loc, err = time.LoadLocation(settings.TimeZone); err != nil {
+			return nil, fmt.Errorf("failed to load profile time zone: %w", err)
+		}
+	}
+	c.loc = loc
+	return loc, nil
+}
+
+// localZone returns the zone of Garmin's zone-less local times such as
+// startTimeLocal: the profile zone, or nil when it can't be loaded, which
+// leaves those times as UTC wall clock rather than failing the request
+func (c *Client) localZone(ctx context.Context) *time.Location {
+	loc, err := c.Location(ctx)
+	if err != nil {
+		return nil
+	}
+	return loc
+}
+
+// Today returns the current calendar day in the user's time zone, which is the
+// day Garmin files today's daily data under
+func (c *Client) Today(ctx context.Context) (time.Time, error) {
+	loc, err := c.Location(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return NormalizeDate(time.Now(), loc), nil
+}
+
+// NormalizeDate returns midnight of the calendar day that t falls on in loc.
+// Daily endpoints format the date of the time.Time they are given, so instants
+// should be normalized to the user's zone before being passed to them.
+func NormalizeDate(t time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.Local
+	}
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeDate(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*3600)
+	// 23:30 UTC is already the next morning in Tokyo
+	instant := time.Date(2024, 3, 1, 23, 30, 0, 0, time.UTC)
+
+	got := NormalizeDate(instant, tokyo)
+	assert.Equal(t, time.Date(2024, 3, 2, 0, 0, 0, 0, tokyo), got)
+	assert.Equal(t, "2024-03-02", got.Format("2006-01-02"))
+	assert.Equal(t, "2024-03-01", NormalizeDate(instant, time.UTC).Format("2006-01-02"))
+}
+
+func TestClientLocation(t *testing.T) {
+	mockServer := garmintest.NewServer()
+	defer mockServer.Close()
+	mockServer.Handle(garmintest.Settings, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"timeZone": "Asia/Tokyo", "measurementSystem": "metric"}`))
+	})
+	client := NewClientWithBaseURL(mockServer.URL())
+
+	for i := 0; i < 2; i++ {
+		loc, err := client.Location(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "Asia/Tokyo", loc.String())
+	}
+	mockServer.AssertRequested(t, garmintest.Settings, 1)
+
+	today, err := client.Today(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", today.Location().String())
+	assert.Equal(t, 0, today.Hour())
+
+	// A fixed location never touches the API
+	mockServer.ResetRequests()
+	WithLocation(time.UTC)(client)
+	loc, err := client.Location(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, time.UTC, loc)
+	mockServer.AssertNotRequested(t, garmintest.Settings)
+}
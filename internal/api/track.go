@@ -0,0 +1,46 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// TrackPoint is one recorded sample of an activity with its position and
+// sensor readings. Readings the device did not record are nil.
+type TrackPoint struct {
+	Timestamp   GarminTime `json:"timestamp"`
+	Lat         *float64   `json:"lat,omitempty"`
+	Lon         *float64   `json:"lon,omitempty"`
+	Elevation   *float64   `json:"elevation,omitempty"`   // meters
+	HeartRate   *float64   `json:"heartRate,omitempty"`   // beats per minute
+	Cadence     *float64   `json:"cadence,omitempty"`     // rpm; one leg for runs, as in FIT files
+	Temperature *float64   `json:"temperature,omitempty"` // degrees Celsius
+}
+
+// GetActivityTrack retrieves the position, heart rate, cadence and temperature
+// series of an activity
+func (c *Client) GetActivityTrack(ctx context.Context, activityID int64, opts ...RequestOption) ([]TrackPoint, error) {
+	metrics, err := c.getActivityMetrics(ctx, activityID, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get activity track: %w", err)
+	}
+
+	cols := metrics.columns()
+	points := make([]TrackPoint, metrics.samples())
+	for i := range points {
+		cadence := metrics.value(cols, i, "directBikeCadence")
+		if cadence == nil {
+			cadence = metrics.value(cols, i, "directRunCadence")
+		}
+		points[i] = TrackPoint{
+			Timestamp:   metrics.timestamp(cols, i),
+			Lat:         metrics.value(cols, i, "directLatitude"),
+			Lon:         metrics.value(cols, i, "directLongitude"),
+			Elevation:   metrics.value(cols, i, "directElevation"),
+			HeartRate:   metrics.value(cols, i, "directHeartRate"),
+			Cadence:     cadence,
+			Temperature: metrics.value(cols, i, "directAirTemperature"),
+		}
+	}
+	return points, nil
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetActivityTrack(t *testing.T) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"metricDescriptors": [
+				{"metricsIndex": 0, "key": "directTimestamp"},
+				{"metricsIndex": 1, "key": "directLatitude"},
+				{"metricsIndex": 2, "key": "directLongitude"},
+				{"metricsIndex": 3, "key": "directHeartRate"},
+				{"metricsIndex": 4, "key": "directRunCadence"}
+			],
+			"activityDetailMetrics": [
+				{"metrics": [1709276400000, 52.52, 13.405, 142, 86]},
+				{"metrics": [1709276401000, null, null, 143, 87]}
+			]
+		}`))
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+
+	points, err := client.GetActivityTrack(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, points, 2)
+	assert.Equal(t, Ptr(52.52), points[0].Lat)
+	assert.Equal(t, Ptr(86.0), points[0].Cadence)
+	assert.Nil(t, points[0].Temperature)
+	assert.Nil(t, points[1].Lat)
+	assert.Equal(t, Ptr(143.0), points[1].HeartRate)
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+)
+
+// TransportConfig tunes the HTTP connection pool shared by all requests of a Client
+type TransportConfig struct {
+	// MaxIdleConns limits idle connections across all hosts
+	MaxIdleConns int
+	// MaxIdleConnsPerHost limits idle connections kept open to each host.
+	// Bulk syncs hit a single host, so this should cover the sync concurrency.
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout closes connections that stay idle for longer
+	IdleConnTimeout time.Duration
+	// DisableHTTP2 forces HTTP/1.1 for proxies that mishandle HTTP/2
+	DisableHTTP2 bool
+}
+
+// DefaultTransportConfig returns the pool settings used by NewClient.
+// net/http keeps only two idle connections per host by default, which makes
+// concurrent syncs reconnect on almost every request.
+func DefaultTransportConfig() TransportConfig {
+	return TransportConfig{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 16,
+		IdleConnTimeout:     90 * time.Second,
+	}
+}
+
+// NewTransport builds an http.Transport from cfg on top of the net/http defaults
+func NewTransport(cfg TransportConfig) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConns = cfg.MaxIdleConns
+	t.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	t.IdleConnTimeout = cfg.IdleConnTimeout
+	if cfg.DisableHTTP2 {
+		t.ForceAttemptHTTP2 = false
+		// A non-nil empty map disables the automatic HTTP/2 upgrade
+		t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	} else {
+		t.ForceAttemptHTTP2 = true
+	}
+	return t
+}
+
+// ClientOption customizes a Client created by NewClient
+type ClientOption func(*Client)
+
+// WithTransportConfig replaces the connection pool settings
+func WithTransportConfig(cfg TransportConfig) ClientOption {
+	return func(c *Client) {
+		c.HTTPClient.SetTransport(NewTransport(cfg))
+	}
+}
+
+// WithTransport routes all requests through rt, e.g. a recording transport in tests
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.HTTPClient.SetTransport(rt)
+	}
+}
+
+// WithTimeout sets the timeout of each request
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.HTTPClient.SetTimeout(d)
+	}
+}
+
+// WithBaseURL points the client at a different API host
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.HTTPClient.SetBaseURL(url)
+	}
+}
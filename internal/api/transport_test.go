@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/auth/garth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTransport(t *testing.T) {
+	tr := NewTransport(DefaultTransportConfig())
+	assert.Equal(t, 16, tr.MaxIdleConnsPerHost)
+	assert.Equal(t, 90*time.Second, tr.IdleConnTimeout)
+	assert.True(t, tr.ForceAttemptHTTP2)
+	assert.Nil(t, tr.TLSNextProto)
+
+	tr = NewTransport(TransportConfig{MaxIdleConnsPerHost: 4, DisableHTTP2: true})
+	assert.Equal(t, 4, tr.MaxIdleConnsPerHost)
+	assert.False(t, tr.ForceAttemptHTTP2)
+	assert.NotNil(t, tr.TLSNextProto)
+	assert.Empty(t, tr.TLSNextProto)
+}
+
+func TestClientReusesConnections(t *testing.T) {
+	var conns int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	session := &garth.Session{OAuth2Token: "mock-token", ExpiresAt: time.Now().Add(time.Hour)}
+	client, err := NewClient(NewMockAuthenticator(), session, "",
+		WithBaseURL(server.URL),
+		WithTimeout(5*time.Second),
+	)
+	assert.NoError(t, err)
+
+	const workers = 8
+	for round := 0; round < 5; round++ {
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var v map[string]interface{}
+				assert.NoError(t, client.Get(context.Background(), "/stats-service/stats", &v))
+			}()
+		}
+		wg.Wait()
+	}
+
+	// The default transport would keep only two connections and redial the rest
+	assert.LessOrEqual(t, int(atomic.LoadInt32(&conns)), workers)
+}
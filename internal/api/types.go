@@ -1,19 +1,45 @@
 package api
 
 import (
-	"encoding/json"
 	"fmt"
-	"time"
+	"net/http"
 )
 
-// APIError represents an error returned by the API
+// APIError represents an error returned by the API, along with the request
+// that caused it
 type APIError struct {
 	StatusCode int
+	Code       int // Garmin's own error code, when the body carries one
 	Message    string
+	Method     string
+	Path       string
+	RequestID  string
 }
 
 func (e *APIError) Error() string {
-	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, e.Message)
+	code := e.Code
+	if code == 0 {
+		code = e.StatusCode
+	}
+	msg := fmt.Sprintf("API error %d: %s", code, e.Message)
+	if e.Method == "" && e.Path == "" {
+		return msg
+	}
+	if e.RequestID != "" {
+		return fmt.Sprintf("%s (%s %s, request-id %s)", msg, e.Method, e.Path, e.RequestID)
+	}
+	return fmt.Sprintf("%s (%s %s)", msg, e.Method, e.Path)
+}
+
+// Is lets errors.Is match an APIError against ErrNotFound and ErrBadRequest
+func (e *APIError) Is(target error) bool {
+	switch target.(type) {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	}
+	return false
 }
 
 // Error types for API responses
@@ -25,73 +51,22 @@ type ErrBadRequest struct{}
 
 func (e ErrBadRequest) Error() string { return "bad request" }
 
-// Time represents a Garmin Connect time value
-type Time time.Time
-
-// IsZero checks if the time is zero value
-func (t Time) IsZero() bool {
-	return time.Time(t).IsZero()
-}
-
-// After reports whether t is after u
-func (t Time) After(u Time) bool {
-	return time.Time(t).After(time.Time(u))
-}
-
-// Format formats the time using the provided layout
-func (t Time) Format(layout string) string {
-	return time.Time(t).Format(layout)
-}
-
-// UnmarshalJSON implements json.Unmarshaler interface
-func (t *Time) UnmarshalJSON(data []byte) error {
-	var s string
-	if err := json.Unmarshal(data, &s); err != nil {
-		return err
-	}
-
-	// Try multiple time formats that Garmin might use
-	formats := []string{
-		"2006-01-02T15:04:05.000Z",
-		"2006-01-02T15:04:05Z",
-		"2006-01-02T15:04:05.000",
-		"2006-01-02T15:04:05",
-		time.RFC3339,
-		time.RFC3339Nano,
-	}
-
-	for _, format := range formats {
-		if parsedTime, err := time.Parse(format, s); err == nil {
-			*t = Time(parsedTime)
-			return nil
-		}
-	}
-
-	// If none of the formats work, try parsing as RFC3339
-	parsedTime, err := time.Parse(time.RFC3339, s)
-	if err != nil {
-		return err
-	}
-	*t = Time(parsedTime)
-	return nil
-}
-
-// MarshalJSON implements json.Marshaler interface
-func (t Time) MarshalJSON() ([]byte, error) {
-	return json.Marshal(time.Time(t).Format(time.RFC3339))
-}
-
 // BodyComposition represents body composition metrics from Garmin Connect
 type BodyComposition struct {
-	BoneMass   float64 `json:"boneMass"`   // Grams
-	MuscleMass float64 `json:"muscleMass"` // Grams
-	BodyFat    float64 `json:"bodyFat"`    // Percentage
-	Hydration  float64 `json:"hydration"`  // Percentage
-	Timestamp  Time    `json:"timestamp"`  // Measurement time
+	Weight     float64    `json:"weight"`     // Grams
+	BoneMass   float64    `json:"boneMass"`   // Grams
+	MuscleMass float64    `json:"muscleMass"` // Grams
+	BodyFat    float64    `json:"bodyFat"`    // Percentage
+	Hydration  float64    `json:"hydration"`  // Percentage
+	Timestamp  GarminTime `json:"timestamp"`  // Measurement time
+
+	BMI          *float64 `json:"bmi,omitempty"`          // kg/m²
+	MetabolicAge *int     `json:"metabolicAge,omitempty"` // Years
+	VisceralFat  *int     `json:"visceralFat,omitempty"`  // Rating, 1-59
 }
 
 // BodyCompositionRequest defines parameters for body composition API requests
 type BodyCompositionRequest struct {
-	StartDate Time `json:"startDate"`
-	EndDate   Time `json:"endDate"`
+	StartDate GarminTime `json:"startDate"`
+	EndDate   GarminTime `json:"endDate"`
 }
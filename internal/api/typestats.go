@@ -0,0 +1,29 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// ActivityTypeStats are the lifetime totals of one sport
+type ActivityTypeStats struct {
+	Type            string  `json:"activityType"`       // e.g. "running", "cycling"
+	Count           int     `json:"count"`              // number of activities
+	DistanceMeters  float64 `json:"totalDistance"`      // in meters
+	DurationSeconds float64 `json:"totalDuration"`      // moving time in seconds
+	ElevationGain   float64 `json:"totalElevationGain"` // in meters
+	Calories        float64 `json:"totalCalories"`
+}
+
+// GetActivityTypeStats retrieves lifetime totals per sport, most frequent first
+func (c *Client) GetActivityTypeStats(ctx context.Context, opts ...RequestOption) ([]ActivityTypeStats, error) {
+	var stats []ActivityTypeStats
+	if err := c.Get(ctx, "/stats-service/activities/lifetime/byType", &stats, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get activity type stats: %w", err)
+	}
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].Count > stats[j].Count
+	})
+	return stats, nil
+}
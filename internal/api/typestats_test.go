@@ -0,0 +1,33 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetActivityTypeStats(t *testing.T) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/stats-service/activities/lifetime/byType", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"activityType": "cycling", "count": 120, "totalDistance": 4850000, "totalDuration": 612000, "totalElevationGain": 41000},
+			{"activityType": "running", "count": 410, "totalDistance": 3900000, "totalDuration": 1188000, "totalElevationGain": 28500},
+			{"activityType": "lap_swimming", "count": 35, "totalDistance": 70000, "totalDuration": 75600}
+		]`))
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+
+	stats, err := client.GetActivityTypeStats(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, stats, 3)
+	assert.Equal(t, "running", stats[0].Type)
+	assert.Equal(t, 3900000.0, stats[0].DistanceMeters)
+	assert.Equal(t, "lap_swimming", stats[2].Type)
+	assert.Zero(t, stats[2].ElevationGain)
+}
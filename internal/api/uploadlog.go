@@ -0,0 +1,103 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/sstent/go-garminconnect/internal/jsonfile"
+)
+
+// UploadLog records the SHA-256 of every uploaded file with the activity it
+// created, so an upload retried after a timeout resolves to the original
+// activity instead of creating a duplicate. A log belongs to one account.
+type UploadLog struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[string]int64
+}
+
+// OpenUploadLog loads the log stored at path; a missing file is an empty log.
+// An empty path keeps the log in memory only.
+func OpenUploadLog(path string) (*UploadLog, error) {
+	l := &UploadLog{path: path, entries: make(map[string]int64)}
+	if path == "" {
+		return l, nil
+	}
+	if err := jsonfile.Load(path, &l.entries); err != nil {
+		return nil, fmt.Errorf("failed to load upload log: %w", err)
+	}
+	return l, nil
+}
+
+// WithUploadLog makes UploadActivity skip files already recorded in log
+func WithUploadLog(log *UploadLog) ClientOption {
+	return func(c *Client) {
+		c.uploads = log
+	}
+}
+
+// UploadHash returns the key a file is recorded under
+func UploadHash(file []byte) string {
+	sum := sha256.Sum256(file)
+	return hex.EncodeToString(sum[:])
+}
+
+// Lookup returns the activity created from the file with hash
+func (l *UploadLog) Lookup(hash string) (int64, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	id, ok := l.entries[hash]
+	return id, ok
+}
+
+// Record stores the activity created from the file with hash and saves the log
+func (l *UploadLog) Record(hash string, activityID int64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries[hash] = activityID
+	return l.saveLocked()
+}
+
+// Forget removes hash, e.g. after its activity was deleted, so the file can be
+// uploaded again
+func (l *UploadLog) Forget(hash string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.entries, hash)
+	return l.saveLocked()
+}
+
+// saveLocked writes the log atomically. The caller must hold l.mu.
+func (l *UploadLog) saveLocked() error {
+	if l.path == "" {
+		return nil
+	}
+	if err := jsonfile.Save(l.path, l.entries); err != nil {
+		return fmt.Errorf("failed to save upload log: %w", err)
+	}
+	return nil
+}
+
+// duplicateActivityID extracts the existing activity from Garmin's 409 reply
+// to a file it has already imported
+func duplicateActivityID(body []byte, codec Codec) (int64, bool) {
+	var result struct {
+		DetailedImportResult struct {
+			Failures []struct {
+				InternalID int64 `json:"internalId"`
+			} `json:"failures"`
+		} `json:"detailedImportResult"`
+	}
+	if err := codec.Unmarshal(body, &result); err != nil {
+		return 0, false
+	}
+	for _, f := range result.DetailedImportResult.Failures {
+		if f.InternalID != 0 {
+			return f.InternalID, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUploadLogSkipsRecordedFiles(t *testing.T) {
+	uploads := 0
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		uploads++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"activityId": 777}`))
+	})
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "uploads.json")
+	log, err := OpenUploadLog(path)
+	assert.NoError(t, err)
+
+	client := NewClientWithBaseURL(server.URL())
+	WithUploadLog(log)(client)
+
+	fitData := []byte("0123456789ABCDEF")
+	id, err := client.UploadActivity(context.Background(), fitData)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(777), id)
+
+	id, err = client.UploadActivity(context.Background(), fitData)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(777), id)
+	assert.Equal(t, 1, uploads)
+
+	// The log survives a restart
+	reopened, err := OpenUploadLog(path)
+	assert.NoError(t, err)
+	id, ok := reopened.Lookup(UploadHash(fitData))
+	assert.True(t, ok)
+	assert.Equal(t, int64(777), id)
+
+	assert.NoError(t, reopened.Forget(UploadHash(fitData)))
+	_, ok = reopened.Lookup(UploadHash(fitData))
+	assert.False(t, ok)
+}
+
+func TestUploadLogResolvesDuplicate(t *testing.T) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"detailedImportResult": {"failures": [
+			{"internalId": 555, "messages": [{"code": 202, "content": "Duplicate Activity."}]}
+		]}}`))
+	})
+	defer server.Close()
+
+	log, err := OpenUploadLog("")
+	assert.NoError(t, err)
+	client := NewClientWithBaseURL(server.URL())
+
+	fitData := []byte("0123456789ABCDEF")
+	_, err = client.UploadActivity(context.Background(), fitData)
+	assert.Error(t, err, "duplicates are errors without a log")
+
+	WithUploadLog(log)(client)
+	id, err := client.UploadActivity(context.Background(), fitData)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(555), id)
+	recorded, ok := log.Lookup(UploadHash(fitData))
+	assert.True(t, ok)
+	assert.Equal(t, int64(555), recorded)
+}
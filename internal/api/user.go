@@ -8,6 +8,7 @@ import (
 
 // UserProfile represents a Garmin Connect user profile
 type UserProfile struct {
+	RawJSON
 	DisplayName  string  `json:"displayName"`
 	FullName     string  `json:"fullName"`
 	EmailAddress string  `json:"emailAddress"`
@@ -21,30 +22,32 @@ type UserProfile struct {
 	Birthdate    string  `json:"birthDate"`
 }
 
-// UserStats represents fitness statistics for a user
+// UserStats represents fitness statistics for a user.
+// Metrics are nil when Garmin reports no value for the day.
 type UserStats struct {
-	TotalSteps    int     `json:"totalSteps"`
-	TotalDistance float64 `json:"totalDistance"` // in meters
-	TotalCalories int     `json:"totalCalories"`
-	ActiveMinutes int     `json:"activeMinutes"`
-	RestingHR     int     `json:"restingHeartRate"`
-	Date          string  `json:"date"` // Store as string in "YYYY-MM-DD" format
+	RawJSON
+	TotalSteps    *int     `json:"totalSteps"`
+	TotalDistance *float64 `json:"totalDistance"` // in meters
+	TotalCalories *int     `json:"totalCalories"`
+	ActiveMinutes *int     `json:"activeMinutes"`
+	RestingHR     *int     `json:"restingHeartRate"`
+	Date          Date     `json:"date"`
 }
 
 // GetUserProfile retrieves the user's profile information
-func (c *Client) GetUserProfile(ctx context.Context) (*UserProfile, error) {
+func (c *Client) GetUserProfile(ctx context.Context, opts ...RequestOption) (*UserProfile, error) {
 	var profile UserProfile
-	if err := c.Get(ctx, "/userprofile-service/socialProfile", &profile); err != nil {
+	if err := c.Get(ctx, "/userprofile-service/socialProfile", &profile, opts...); err != nil {
 		return nil, fmt.Errorf("failed to get user profile: %w", err)
 	}
 	return &profile, nil
 }
 
 // GetUserStats retrieves fitness statistics for a user for a specific date
-func (c *Client) GetUserStats(ctx context.Context, date time.Time) (*UserStats, error) {
+func (c *Client) GetUserStats(ctx context.Context, date time.Time, opts ...RequestOption) (*UserStats, error) {
 	var stats UserStats
 	path := fmt.Sprintf("/stats-service/stats/daily/%s", date.Format("2006-01-02"))
-	if err := c.Get(ctx, path, &stats); err != nil {
+	if err := c.Get(ctx, path, &stats, opts...); err != nil {
 		return nil, fmt.Errorf("failed to get user stats: %w", err)
 	}
 	return &stats, nil
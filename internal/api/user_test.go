@@ -3,11 +3,11 @@ package api
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"net/http"
 	"testing"
 	"time"
 
+	"github.com/sstent/go-garminconnect/garmintest"
 	"github.com/sstent/go-garminconnect/internal/auth/garth"
 	"github.com/stretchr/testify/assert"
 )
@@ -74,7 +74,7 @@ func TestGetUserProfile(t *testing.T) {
 		},
 	}
 
-	mockServer := NewMockServer()
+	mockServer := garmintest.NewServer()
 	defer mockServer.Close()
 	// Create client with non-expired session
 	session := &garth.Session{
@@ -94,7 +94,7 @@ func TestGetUserProfile(t *testing.T) {
 			mockServer.Reset()
 
 			// Set custom handler directly
-			mockServer.SetUserHandler(func(w http.ResponseWriter, r *http.Request) {
+			mockServer.Handle(garmintest.Profile, func(w http.ResponseWriter, r *http.Request) {
 				w.Header().Set("Content-Type", "application/json")
 				w.WriteHeader(tt.mockStatus)
 				json.NewEncoder(w).Encode(tt.mockResponse)
@@ -116,7 +116,7 @@ func TestGetUserProfile(t *testing.T) {
 }
 
 func BenchmarkGetUserProfile(b *testing.B) {
-	mockServer := NewMockServer()
+	mockServer := garmintest.NewServer()
 	defer mockServer.Close()
 
 	mockResponse := map[string]interface{}{
@@ -127,7 +127,7 @@ func BenchmarkGetUserProfile(b *testing.B) {
 		"profileId":            "benchmark-123",
 		"profileImageUrlLarge": "https://example.com/benchmark.jpg",
 	}
-	mockServer.SetResponse("/userprofile-service/socialProfile", http.StatusOK, mockResponse)
+	mockServer.SetJSON(garmintest.Profile, http.StatusOK, mockResponse)
 
 	client := NewClientWithBaseURL(mockServer.URL())
 	b.ResetTimer()
@@ -162,12 +162,12 @@ func TestGetUserStats(t *testing.T) {
 			},
 			mockStatus: http.StatusOK,
 			expected: &UserStats{
-				TotalSteps:    10000,
-				TotalDistance: 8500.5,
-				TotalCalories: 2200,
-				ActiveMinutes: 45,
-				RestingHR:     55,
-				Date:          testDate,
+				TotalSteps:    Ptr(10000),
+				TotalDistance: Ptr(8500.5),
+				TotalCalories: Ptr(2200),
+				ActiveMinutes: Ptr(45),
+				RestingHR:     Ptr(55),
+				Date:          NewDate(now),
 			},
 		},
 		{
@@ -188,7 +188,7 @@ func TestGetUserStats(t *testing.T) {
 		},
 	}
 
-	mockServer := NewMockServer()
+	mockServer := garmintest.NewServer()
 	defer mockServer.Close()
 	// Create client with non-expired session
 	session := &garth.Session{
@@ -208,7 +208,7 @@ func TestGetUserStats(t *testing.T) {
 			mockServer.Reset()
 
 			// Set custom handler directly for stats
-			mockServer.SetStatsHandler(func(w http.ResponseWriter, r *http.Request) {
+			mockServer.Handle(garmintest.Stats, func(w http.ResponseWriter, r *http.Request) {
 				w.Header().Set("Content-Type", "application/json")
 				w.WriteHeader(tt.mockStatus)
 				json.NewEncoder(w).Encode(tt.mockResponse)
@@ -231,17 +231,16 @@ func TestGetUserStats(t *testing.T) {
 
 func BenchmarkGetUserStats(b *testing.B) {
 	now := time.Now()
-	mockServer := NewMockServer()
+	mockServer := garmintest.NewServer()
 	defer mockServer.Close()
 
-	path := fmt.Sprintf("/stats-service/stats/daily/%s", now.Format("2006-01-02"))
 	mockResponse := map[string]interface{}{
 		"totalSteps":    15000,
 		"totalDistance": 12000.0,
 		"totalCalories": 3000,
 		"activeMinutes": 60,
 	}
-	mockServer.SetResponse(path, http.StatusOK, mockResponse)
+	mockServer.SetJSON(garmintest.Stats, http.StatusOK, mockResponse)
 
 	client := NewClientWithBaseURL(mockServer.URL())
 	b.ResetTimer()
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// WithResponseValidation runs the Validate method of response models after
+// Get decodes them and fails the request with a *ValidationError when a field
+// breaks its validator tags, e.g. a negative sleep duration. Models without
+// validator tags are not affected.
+func WithResponseValidation() ClientOption {
+	return func(c *Client) {
+		c.validate = true
+	}
+}
+
+// WithValidationReporter validates responses like WithResponseValidation but
+// passes failures to report and returns the data anyway, e.g. to log them
+func WithValidationReporter(report func(*ValidationError)) ClientOption {
+	return func(c *Client) {
+		c.validate = true
+		c.onInvalid = report
+	}
+}
+
+// WithoutValidation skips response validation for a single request
+func WithoutValidation() RequestOption {
+	return func(o *requestOptions) {
+		o.noValidation = true
+	}
+}
+
+// FieldError is one validator tag a response field failed
+type FieldError struct {
+	Field string      // JSON path of the field, e.g. "sleepTimeSeconds" or "[2].goal"
+	Rule  string      // the failed tag, e.g. "min"
+	Param string      // the tag's parameter, e.g. "0"
+	Value interface{} // the decoded value
+}
+
+func (e FieldError) String() string {
+	rule := e.Rule
+	if e.Param != "" {
+		rule += "=" + e.Param
+	}
+	return fmt.Sprintf("%s %v fails %s", e.Field, e.Value, rule)
+}
+
+// ValidationError reports a response whose model failed its validator tags
+type ValidationError struct {
+	Path   string // the request path
+	Model  string // the model type, e.g. "SleepData"
+	Fields []FieldError
+	// Err is the validator's error
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Fields) == 0 {
+		return fmt.Sprintf("%s: %s response failed validation: %v", e.Path, e.Model, e.Err)
+	}
+	fields := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		fields[i] = f.String()
+	}
+	return fmt.Sprintf("%s: %s response failed validation: %s", e.Path, e.Model, strings.Join(fields, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// validateResponse checks v, a model or a slice of models, against its
+// validator tags. It returns nil when v is valid or has no Validate method.
+func validateResponse(path string, v interface{}) error {
+	if m, ok := v.(validatable); ok {
+		if err := m.Validate(); err != nil {
+			return newValidationError(path, v, "", err)
+		}
+		return nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return nil
+	}
+	var result *ValidationError
+	list := rv.Elem()
+	for i := 0; i < list.Len(); i++ {
+		elem := list.Index(i).Addr().Interface()
+		m, ok := elem.(validatable)
+		if !ok {
+			return nil
+		}
+		err := m.Validate()
+		if err == nil {
+			continue
+		}
+		verr := newValidationError(path, elem, fmt.Sprintf("[%d].", i), err)
+		if result == nil {
+			result = verr
+			continue
+		}
+		result.Fields = append(result.Fields, verr.Fields...)
+		result.Err = errors.Join(result.Err, err)
+	}
+	if result == nil {
+		return nil
+	}
+	return result
+}
+
+// newValidationError converts the error of model's Validate method, naming
+// fields by their JSON path under prefix
+func newValidationError(path string, model interface{}, prefix string, err error) *ValidationError {
+	t := reflect.TypeOf(model)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	verr := &ValidationError{Path: path, Model: t.Name(), Err: err}
+
+	var fieldErrs validator.ValidationErrors
+	if errors.As(err, &fieldErrs) {
+		for _, fe := range fieldErrs {
+			// The namespace starts with the model's type name
+			_, field, _ := strings.Cut(fe.Namespace(), ".")
+			verr.Fields = append(verr.Fields, FieldError{
+				Field: prefix + field,
+				Rule:  fe.Tag(),
+				Param: fe.Param(),
+				Value: fe.Value(),
+			})
+		}
+	}
+	return verr
+}
+
+// checkResponse validates v when the client and the request ask for it
+func (c *Client) checkResponse(path string, v interface{}, o requestOptions) error {
+	if !(c.validate || c.strict) || o.noValidation {
+		return nil
+	}
+	err := validateResponse(path, v)
+	var verr *ValidationError
+	if c.onInvalid != nil && !c.strict && errors.As(err, &verr) {
+		c.onInvalid(verr)
+		return nil
+	}
+	return err
+}
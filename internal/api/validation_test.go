@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseValidation(t *testing.T) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"calendarDate": "2024-03-01", "sleepTimeSeconds": -3600, "sleepScore": 140}`))
+	})
+	defer server.Close()
+	ctx := context.Background()
+	date := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	// Off by default
+	client := NewClientWithBaseURL(server.URL())
+	_, err := client.GetSleepData(ctx, date)
+	assert.NoError(t, err)
+
+	WithResponseValidation()(client)
+	_, err = client.GetSleepData(ctx, date)
+	var verr *ValidationError
+	if assert.True(t, errors.As(err, &verr), "got %v", err) {
+		assert.Equal(t, "/wellness-service/sleep/daily/2024-03-01", verr.Path)
+		assert.Equal(t, "SleepData", verr.Model)
+		assert.Equal(t, []FieldError{
+			{Field: "sleepTimeSeconds", Rule: "min", Param: "0", Value: -3600},
+			{Field: "sleepScore", Rule: "max", Param: "100", Value: 140},
+		}, verr.Fields)
+	}
+	assert.ErrorContains(t, err, "sleepTimeSeconds -3600 fails min=0; sleepScore 140 fails max=100")
+
+	sleep, err := client.GetSleepData(ctx, date, WithoutValidation())
+	assert.NoError(t, err)
+	assert.Equal(t, -3600, *sleep.SleepTimeSeconds)
+
+	t.Run("reporter", func(t *testing.T) {
+		var reported []*ValidationError
+		client := NewClientWithBaseURL(server.URL())
+		WithValidationReporter(func(e *ValidationError) { reported = append(reported, e) })(client)
+
+		sleep, err := client.GetSleepData(ctx, date)
+		assert.NoError(t, err)
+		assert.Equal(t, -3600, *sleep.SleepTimeSeconds)
+		if assert.Len(t, reported, 1) {
+			assert.Len(t, reported[0].Fields, 2)
+		}
+	})
+}
+
+func TestValidateResponseSlice(t *testing.T) {
+	steps := []DailySteps{
+		{CalendarDate: NewDate(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)), TotalSteps: 9000},
+		{TotalSteps: -5},
+	}
+	err := validateResponse("/steps", &steps)
+	var verr *ValidationError
+	if assert.True(t, errors.As(err, &verr)) {
+		assert.Equal(t, "DailySteps", verr.Model)
+		assert.Equal(t, []FieldError{
+			{Field: "[1].calendarDate", Rule: "required", Value: time.Time{}},
+			{Field: "[1].totalSteps", Rule: "min", Param: "0", Value: -5},
+		}, verr.Fields)
+	}
+
+	assert.NoError(t, validateResponse("/steps", &steps[0]))
+	assert.NoError(t, validateResponse("/activities", &[]Activity{{}}), "models without Validate are not checked")
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpO2AndBloodPressure(t *testing.T) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/wellness-service/wellness/daily/spo2/2024-03-01":
+			w.Write([]byte(`{"calendarDate": "2024-03-01", "averageSpO2": 96.5, "lowestSpO2": 89, "avgSleepSpO2": 95}`))
+		case "/bloodpressure-service/bloodpressure/range/2024-03-01/2024-03-07":
+			w.Write([]byte(`{"measurements": [
+				{"measurementTimestampGMT": "2024-03-02T07:30:00Z", "systolic": 118, "diastolic": 76, "pulse": 58},
+				{"measurementTimestampGMT": "2024-03-05T07:35:00Z", "systolic": 124, "diastolic": 80}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	spo2, err := client.GetSpO2Data(context.Background(), start)
+	assert.NoError(t, err)
+	assert.Equal(t, 96.5, *spo2.Average)
+	assert.Equal(t, 89, *spo2.Lowest)
+	assert.Nil(t, spo2.LatestValue)
+
+	readings, err := client.GetBloodPressure(context.Background(), start, start.AddDate(0, 0, 6))
+	assert.NoError(t, err)
+	assert.Len(t, readings, 2)
+	assert.Equal(t, 118, readings[0].Systolic)
+	assert.Equal(t, 58, *readings[0].Pulse)
+	assert.Nil(t, readings[1].Pulse)
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Weigh-in sources reported by the weight service
+const (
+	WeighInSourceIndexScale = "INDEX_SCALE"
+	WeighInSourceManual     = "MANUAL"
+)
+
+// WeighIn is a single weight measurement, from a smart scale or entered by hand.
+// Composition fields are nil when the source did not measure them.
+type WeighIn struct {
+	SamplePK       int64      `json:"samplePk"`
+	Date           Date       `json:"calendarDate"`
+	Timestamp      GarminTime `json:"timestampGMT"`
+	SourceType     string     `json:"sourceType"` // e.g. "INDEX_SCALE", "MANUAL"
+	Weight         float64    `json:"weight"`     // grams
+	BMI            *float64   `json:"bmi"`        // kg/m²
+	BodyFat        *float64   `json:"bodyFat"`    // percentage
+	BodyWater      *float64   `json:"bodyWater"`  // percentage
+	BoneMass       *float64   `json:"boneMass"`   // grams
+	MuscleMass     *float64   `json:"muscleMass"` // grams
+	MetabolicAge   *int       `json:"metabolicAge"`
+	VisceralFat    *int       `json:"visceralFat"`
+	PhysiqueRating *int       `json:"physiqueRating"`
+}
+
+// Manual reports whether the weigh-in was entered by hand
+func (w WeighIn) Manual() bool {
+	return w.SourceType == WeighInSourceManual
+}
+
+// BodyComposition converts the weigh-in to the summary returned by GetBodyComposition
+func (w WeighIn) BodyComposition() BodyComposition {
+	return BodyComposition{
+		Weight:       w.Weight,
+		BoneMass:     Value(w.BoneMass),
+		MuscleMass:   Value(w.MuscleMass),
+		BodyFat:      Value(w.BodyFat),
+		Hydration:    Value(w.BodyWater),
+		BMI:          w.BMI,
+		MetabolicAge: w.MetabolicAge,
+		VisceralFat:  w.VisceralFat,
+		Timestamp:    w.Timestamp,
+	}
+}
+
+// GetWeighIns retrieves every weigh-in between start and end inclusive, not
+// just the daily summary, ordered by time. Ranges longer than the service
+// accepts are split into several requests.
+func (c *Client) GetWeighIns(ctx context.Context, start, end time.Time, opts ...RequestOption) ([]WeighIn, error) {
+	weighIns, err := fetchSpans(ctx, c, "/weight-service", start, end, func(ctx context.Context, start, end time.Time) ([]WeighIn, error) {
+		var response struct {
+			DailyWeightSummaries []struct {
+				AllWeightMetrics []WeighIn `json:"allWeightMetrics"`
+			} `json:"dailyWeightSummaries"`
+		}
+		path := fmt.Sprintf("/weight-service/weight/range/%s/%s?includeAll=true", start.Format("2006-01-02"), end.Format("2006-01-02"))
+		if err := c.Get(ctx, path, &response, opts...); err != nil {
+			return nil, fmt.Errorf("failed to get weigh-ins: %w", err)
+		}
+
+		var weighIns []WeighIn
+		for _, day := range response.DailyWeightSummaries {
+			weighIns = append(weighIns, day.AllWeightMetrics...)
+		}
+		return weighIns, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(weighIns, func(i, j int) bool {
+		return weighIns[i].Timestamp.Before(weighIns[j].Timestamp.Time)
+	})
+	return weighIns, nil
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWeighIns(t *testing.T) {
+	var paths []string
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		assert.Equal(t, "true", r.URL.Query().Get("includeAll"))
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/weight-service/weight/range/2024-03-01/2024-03-31" {
+			w.Write([]byte(`{"dailyWeightSummaries": []}`))
+			return
+		}
+		// Summaries are newest first and list every weigh-in of the day
+		w.Write([]byte(`{"dailyWeightSummaries": [
+			{"summaryDate": "2024-03-05", "allWeightMetrics": [
+				{"samplePk": 3, "calendarDate": "2024-03-05", "timestampGMT": 1709654400000, "sourceType": "MANUAL", "weight": 72100}
+			]},
+			{"summaryDate": "2024-03-02", "allWeightMetrics": [
+				{"samplePk": 2, "calendarDate": "2024-03-02", "timestampGMT": 1709402400000, "sourceType": "INDEX_SCALE",
+					"weight": 72800, "bmi": 22.5, "bodyFat": 15.1, "bodyWater": 58.9, "boneMass": 2900, "muscleMass": 55400, "metabolicAge": 30},
+				{"samplePk": 1, "calendarDate": "2024-03-02", "timestampGMT": 1709362800000, "sourceType": "INDEX_SCALE", "weight": 72500}
+			]}
+		]}`))
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	weighIns, err := client.GetWeighIns(context.Background(), start, start.AddDate(0, 0, 40))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"/weight-service/weight/range/2024-03-01/2024-03-31",
+		"/weight-service/weight/range/2024-04-01/2024-04-10",
+	}, paths)
+
+	assert.Len(t, weighIns, 3)
+	assert.Equal(t, []int64{1, 2, 3}, []int64{weighIns[0].SamplePK, weighIns[1].SamplePK, weighIns[2].SamplePK})
+	assert.False(t, weighIns[1].Manual())
+	assert.True(t, weighIns[2].Manual())
+	assert.Nil(t, weighIns[2].BodyFat)
+
+	composition := weighIns[1].BodyComposition()
+	assert.Equal(t, 72800.0, composition.Weight)
+	assert.Equal(t, 15.1, composition.BodyFat)
+	assert.Equal(t, 30, *composition.MetabolicAge)
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Workout is a structured workout saved in the user's workout library
+type Workout struct {
+	RawJSON
+	ID          int64         `json:"workoutId,omitempty"`
+	Name        string        `json:"workoutName"`
+	Description string        `json:"description,omitempty"`
+	SportType   string        `json:"sportTypeKey"` // e.g. "running", "cycling"
+	Steps       []WorkoutStep `json:"workoutSteps"`
+}
+
+// FlatSteps returns the workout's steps with repeat groups expanded in order
+func (w Workout) FlatSteps() []WorkoutStep {
+	return flattenSteps(w.Steps)
+}
+
+// GetWorkout retrieves a workout from the library by ID
+func (c *Client) GetWorkout(ctx context.Context, id int64, opts ...RequestOption) (*Workout, error) {
+	var workout Workout
+	path := fmt.Sprintf("/workout-service/workout/%d", id)
+	if err := c.Get(ctx, path, &workout, opts...); err != nil {
+		return nil, fmt.Errorf("failed to get workout %d: %w", id, err)
+	}
+	return &workout, nil
+}
+
+// CreateWorkout saves a workout to the library and returns it with its new ID
+func (c *Client) CreateWorkout(ctx context.Context, workout Workout, opts ...RequestOption) (*Workout, error) {
+	if workout.Name == "" {
+		return nil, fmt.Errorf("workout name is required")
+	}
+	if len(workout.Steps) == 0 {
+		return nil, fmt.Errorf("workout %q has no steps", workout.Name)
+	}
+
+	var created Workout
+	if err := c.Post(ctx, "/workout-service/workout", workout, &created, opts...); err != nil {
+		return nil, fmt.Errorf("failed to create workout: %w", err)
+	}
+	return &created, nil
+}
+
+// ScheduledWorkout is a library workout placed on the calendar
+type ScheduledWorkout struct {
+	ID        int64 `json:"workoutScheduleId"`
+	WorkoutID int64 `json:"workoutId"`
+	Date      Date  `json:"calendarDate"`
+}
+
+// ScheduleWorkout places a library workout on the calendar for the given day,
+// from where it syncs to the watch
+func (c *Client) ScheduleWorkout(ctx context.Context, workoutID int64, date time.Time, opts ...RequestOption) (*ScheduledWorkout, error) {
+	body := map[string]string{"date": date.Format("2006-01-02")}
+	var scheduled ScheduledWorkout
+	path := fmt.Sprintf("/workout-service/schedule/%d", workoutID)
+	if err := c.Post(ctx, path, body, &scheduled, opts...); err != nil {
+		return nil, fmt.Errorf("failed to schedule workout %d: %w", workoutID, err)
+	}
+	return &scheduled, nil
+}
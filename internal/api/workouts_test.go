@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWorkout(t *testing.T) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/workout-service/workout/77", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"workoutId": 77, "workoutName": "Sweet spot", "sportTypeKey": "cycling", "workoutSteps": [
+			{"stepOrder": 1, "stepType": "warmup", "endCondition": "time", "endConditionValue": 600},
+			{"stepOrder": 2, "stepType": "repeat", "numberOfIterations": 2, "workoutSteps": [
+				{"stepOrder": 3, "stepType": "interval", "endCondition": "time", "endConditionValue": 480,
+					"targetType": "power.zone", "targetValueOne": 220, "targetValueTwo": 235},
+				{"stepOrder": 4, "stepType": "recovery", "endCondition": "time", "endConditionValue": 120}
+			]}
+		]}`))
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+
+	workout, err := client.GetWorkout(context.Background(), 77)
+	assert.NoError(t, err)
+	assert.Equal(t, "Sweet spot", workout.Name)
+	assert.Len(t, workout.Steps, 2)
+	steps := workout.FlatSteps()
+	assert.Len(t, steps, 5)
+	assert.Equal(t, 235.0, *steps[3].TargetHigh)
+}
+
+func TestCreateWorkout(t *testing.T) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/workout-service/workout", r.URL.Path)
+		var workout Workout
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&workout))
+		assert.Equal(t, "Tempo", workout.Name)
+		workout.ID = 91
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(workout)
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+	ctx := context.Background()
+
+	created, err := client.CreateWorkout(ctx, Workout{Name: "Tempo", SportType: "cycling", Steps: []WorkoutStep{{Order: 1, Type: StepInterval}}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(91), created.ID)
+
+	_, err = client.CreateWorkout(ctx, Workout{Name: "Empty"})
+	assert.Error(t, err)
+}
+
+func TestScheduleWorkout(t *testing.T) {
+	server := garmintest.NewServer()
+	server.HandleAll(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/workout-service/schedule/91", r.URL.Path)
+		var body map[string]string
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "2024-03-05", body["date"])
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"workoutScheduleId": 5001, "workoutId": 91, "calendarDate": "2024-03-05"}`))
+	})
+	defer server.Close()
+	client := NewClientWithBaseURL(server.URL())
+
+	scheduled, err := client.ScheduleWorkout(context.Background(), 91, time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5001), scheduled.ID)
+	assert.Equal(t, "2024-03-05", scheduled.Date.String())
+}
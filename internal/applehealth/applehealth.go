@@ -0,0 +1,184 @@
+package applehealth
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// dateLayout is the timestamp format of Apple Health export.xml files
+const dateLayout = "2006-01-02 15:04:05 -0700"
+
+// SourceName identifies the exported records in Apple Health
+const SourceName = "Garmin Connect"
+
+// Data holds the Garmin data to convert. Daily values are recorded over the
+// calendar day in Location (default UTC).
+type Data struct {
+	Steps    []api.DailySteps
+	Stats    []api.UserStats
+	Sleep    []api.SleepData
+	Workouts []api.Activity
+	Location *time.Location
+}
+
+type healthData struct {
+	XMLName    xml.Name  `xml:"HealthData"`
+	Locale     string    `xml:"locale,attr"`
+	ExportDate exportDay `xml:"ExportDate"`
+	Records    []record  `xml:"Record"`
+	Workouts   []workout `xml:"Workout"`
+}
+
+type exportDay struct {
+	Value string `xml:"value,attr"`
+}
+
+type record struct {
+	Type         string `xml:"type,attr"`
+	SourceName   string `xml:"sourceName,attr"`
+	Unit         string `xml:"unit,attr,omitempty"`
+	CreationDate string `xml:"creationDate,attr"`
+	StartDate    string `xml:"startDate,attr"`
+	EndDate      string `xml:"endDate,attr"`
+	Value        string `xml:"value,attr"`
+}
+
+type workout struct {
+	ActivityType      string `xml:"workoutActivityType,attr"`
+	Duration          string `xml:"duration,attr"`
+	DurationUnit      string `xml:"durationUnit,attr"`
+	TotalDistance     string `xml:"totalDistance,attr,omitempty"`
+	TotalDistanceUnit string `xml:"totalDistanceUnit,attr,omitempty"`
+	SourceName        string `xml:"sourceName,attr"`
+	CreationDate      string `xml:"creationDate,attr"`
+	StartDate         string `xml:"startDate,attr"`
+	EndDate           string `xml:"endDate,attr"`
+}
+
+// Encode writes d in the export.xml format of Apple Health, which Health
+// import tools accept. Days and nights without data are skipped.
+func Encode(w io.Writer, d Data) error {
+	loc := d.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	now := time.Now().In(loc)
+	doc := healthData{Locale: "en_US", ExportDate: exportDay{Value: format(now)}}
+	add := func(typ, unit string, start, end time.Time, value string) {
+		doc.Records = append(doc.Records, record{
+			Type:         typ,
+			SourceName:   SourceName,
+			Unit:         unit,
+			CreationDate: format(end),
+			StartDate:    format(start),
+			EndDate:      format(end),
+			Value:        value,
+		})
+	}
+
+	for _, s := range d.Steps {
+		if s.TotalSteps == 0 {
+			continue
+		}
+		start, end := dayBounds(s.CalendarDate, loc)
+		add("HKQuantityTypeIdentifierStepCount", "count", start, end, strconv.Itoa(s.TotalSteps))
+		if s.DistanceMeters > 0 {
+			add("HKQuantityTypeIdentifierDistanceWalkingRunning", "km", start, end, formatFloat(s.DistanceMeters/1000))
+		}
+	}
+
+	for _, s := range d.Stats {
+		if s.RestingHR == nil {
+			continue
+		}
+		start, end := dayBounds(s.Date, loc)
+		add("HKQuantityTypeIdentifierRestingHeartRate", "count/min", start, end, strconv.Itoa(*s.RestingHR))
+	}
+
+	for _, s := range d.Sleep {
+		// Garmin reports stage totals but not when each stage occurred, so a
+		// night is exported as one asleep interval
+		if s.SleepStart.IsZero() || !s.SleepEnd.After(s.SleepStart.Time) {
+			continue
+		}
+		start, end := s.SleepStart.In(loc), s.SleepEnd.In(loc)
+		add("HKCategoryTypeIdentifierSleepAnalysis", "", start, end, "HKCategoryValueSleepAnalysisInBed")
+		add("HKCategoryTypeIdentifierSleepAnalysis", "", start, end, "HKCategoryValueSleepAnalysisAsleepUnspecified")
+	}
+
+	for _, a := range d.Workouts {
+		duration := time.Duration(a.Duration * float64(time.Second))
+		start := a.StartTime.In(loc)
+		wo := workout{
+			ActivityType: WorkoutType(a.Type),
+			Duration:     formatFloat(duration.Minutes()),
+			DurationUnit: "min",
+			SourceName:   SourceName,
+			CreationDate: format(start.Add(duration)),
+			StartDate:    format(start),
+			EndDate:      format(start.Add(duration)),
+		}
+		if a.Distance > 0 {
+			wo.TotalDistance = formatFloat(a.Distance / 1000)
+			wo.TotalDistanceUnit = "km"
+		}
+		doc.Workouts = append(doc.Workouts, wo)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write Apple Health export: %w", err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", " ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to write Apple Health export: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// WorkoutType maps a Garmin activity type to a HealthKit workout activity type
+func WorkoutType(garminType string) string {
+	t := strings.ToLower(garminType)
+	switch {
+	case strings.Contains(t, "running"):
+		return "HKWorkoutActivityTypeRunning"
+	case strings.Contains(t, "cycling"), strings.Contains(t, "biking"):
+		return "HKWorkoutActivityTypeCycling"
+	case strings.Contains(t, "swimming"):
+		return "HKWorkoutActivityTypeSwimming"
+	case strings.Contains(t, "walking"):
+		return "HKWorkoutActivityTypeWalking"
+	case strings.Contains(t, "hiking"):
+		return "HKWorkoutActivityTypeHiking"
+	case strings.Contains(t, "strength"):
+		return "HKWorkoutActivityTypeTraditionalStrengthTraining"
+	case strings.Contains(t, "yoga"):
+		return "HKWorkoutActivityTypeYoga"
+	case strings.Contains(t, "rowing"):
+		return "HKWorkoutActivityTypeRowing"
+	case strings.Contains(t, "elliptical"):
+		return "HKWorkoutActivityTypeElliptical"
+	}
+	return "HKWorkoutActivityTypeOther"
+}
+
+// dayBounds returns the first and last second of the calendar day in loc
+func dayBounds(d api.Date, loc *time.Location) (time.Time, time.Time) {
+	start := d.In(loc)
+	return start, start.AddDate(0, 0, 1).Add(-time.Second)
+}
+
+func format(t time.Time) string {
+	return t.Format(dateLayout)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,61 @@
+package applehealth
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode(t *testing.T) {
+	day := api.NewDate(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	berlin := time.FixedZone("CET", 3600)
+	data := Data{
+		Steps: []api.DailySteps{
+			{CalendarDate: day, TotalSteps: 10000, DistanceMeters: 7500},
+			{CalendarDate: api.NewDate(day.AddDate(0, 0, 1))},
+		},
+		Stats: []api.UserStats{{Date: day, RestingHR: api.Ptr(52)}, {Date: day}},
+		Sleep: []api.SleepData{
+			{
+				CalendarDate: day,
+				SleepStart:   api.NewGarminTime(time.Date(2024, 2, 29, 22, 0, 0, 0, time.UTC)),
+				SleepEnd:     api.NewGarminTime(time.Date(2024, 3, 1, 6, 0, 0, 0, time.UTC)),
+			},
+			{CalendarDate: api.NewDate(day.AddDate(0, 0, 1))},
+		},
+		Workouts: []api.Activity{
+			{Type: "running", StartTime: api.NewGarminTime(time.Date(2024, 3, 1, 7, 0, 0, 0, time.UTC)), Duration: 1800, Distance: 5000},
+			{Type: "breathwork", StartTime: api.NewGarminTime(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)), Duration: 600},
+		},
+		Location: berlin,
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, Encode(&buf, data))
+	out := buf.String()
+
+	assert.Contains(t, out, `<Record type="HKQuantityTypeIdentifierStepCount" sourceName="Garmin Connect" unit="count" creationDate="2024-03-01 23:59:59 +0100" startDate="2024-03-01 00:00:00 +0100" endDate="2024-03-01 23:59:59 +0100" value="10000">`)
+	assert.Contains(t, out, `type="HKQuantityTypeIdentifierDistanceWalkingRunning"`)
+	assert.Contains(t, out, `value="7.5"`)
+	assert.Contains(t, out, `unit="count/min"`)
+	assert.Contains(t, out, `startDate="2024-02-29 23:00:00 +0100" endDate="2024-03-01 07:00:00 +0100" value="HKCategoryValueSleepAnalysisAsleepUnspecified"`)
+	assert.Contains(t, out, `<Workout workoutActivityType="HKWorkoutActivityTypeRunning" duration="30" durationUnit="min" totalDistance="5" totalDistanceUnit="km"`)
+	assert.Contains(t, out, `workoutActivityType="HKWorkoutActivityTypeOther"`)
+
+	// Empty days and nights are skipped
+	var doc healthData
+	assert.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Len(t, doc.Records, 5)
+	assert.Len(t, doc.Workouts, 2)
+}
+
+func TestWorkoutType(t *testing.T) {
+	assert.Equal(t, "HKWorkoutActivityTypeRunning", WorkoutType("TRAIL_RUNNING"))
+	assert.Equal(t, "HKWorkoutActivityTypeSwimming", WorkoutType("lap_swimming"))
+	assert.Equal(t, "HKWorkoutActivityTypeCycling", WorkoutType("road_biking"))
+	assert.Equal(t, "HKWorkoutActivityTypeOther", WorkoutType(""))
+}
@@ -0,0 +1,20 @@
+// Package archive defines the read-only data access the CLI's exports share,
+// so they can read Garmin Connect or a local archive of it, e.g. a SQLite
+// export opened with sqlexport.OpenArchive
+package archive
+
+import (
+	"context"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/csvlog"
+)
+
+// Reader is the activity and daily data the exports read. *api.Client and
+// *sqlexport.Archive implement it, the latter also server.Backend.
+type Reader interface {
+	csvlog.Source
+	Location(ctx context.Context) (*time.Location, error)
+	GetActivitiesByDate(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.Activity, error)
+}
@@ -0,0 +1,206 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// Source is what Fallback serves: Reader and the methods of server.Backend.
+// *api.Client and *sqlexport.Archive implement it.
+type Source interface {
+	Reader
+	GetActivities(ctx context.Context, req api.PageRequest, opts ...api.RequestOption) ([]api.Activity, *api.Pagination, error)
+	GetActivityDetails(ctx context.Context, activityID int64, opts ...api.RequestOption) (*api.ActivityDetail, error)
+	GetUserProfile(ctx context.Context, opts ...api.RequestOption) (*api.UserProfile, error)
+	GetSleepData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.SleepData, error)
+	GetStressData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.DailyStress, error)
+	GetStepsData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.DailySteps, error)
+	GetHRVData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.HRVData, error)
+	GetBodyBatteryData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.BodyBatteryData, error)
+}
+
+// Fallback serves from Live and answers from Archive when Live is unreachable,
+// rate limited or failing with a server error. Other errors, e.g. not found,
+// are returned as they are, and so is the live error when the archive doesn't
+// hold the data either. Calls answered from the archive are recorded in the
+// Freshness of their context.
+type Fallback struct {
+	Live    Source
+	Archive Source
+	// ArchivedAt is when the archive was last written, reported as the age of
+	// archived data
+	ArchivedAt time.Time
+	// OnFallback, if set, is called with the live error of every call
+	// answered from the archive
+	OnFallback func(err error)
+}
+
+// Freshness records whether the data of a request came from the archive
+type Freshness struct {
+	mu         sync.Mutex
+	stale      bool
+	archivedAt time.Time
+	cause      error
+}
+
+type freshnessKey struct{}
+
+// WithFreshness returns a context whose Fallback calls record where their
+// data came from in the returned Freshness
+func WithFreshness(ctx context.Context) (context.Context, *Freshness) {
+	f := &Freshness{}
+	return context.WithValue(ctx, freshnessKey{}, f), f
+}
+
+// Stale reports whether any data came from the archive, and if so when the
+// archive was written and the live error that caused the fallback
+func (f *Freshness) Stale() (stale bool, archivedAt time.Time, cause error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.stale, f.archivedAt, f.cause
+}
+
+func (f *Freshness) record(archivedAt time.Time, cause error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stale, f.archivedAt, f.cause = true, archivedAt, cause
+}
+
+// fallsBack reports whether err means the live data is unavailable rather
+// than missing or refused
+func fallsBack(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var apiErr *api.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+	return true
+}
+
+// serve calls live and, if the live data is unavailable, archived
+func serve[T any](ctx context.Context, f *Fallback, live, archived func() (T, error)) (T, error) {
+	v, err := live()
+	if err == nil || !fallsBack(ctx, err) {
+		return v, err
+	}
+	a, archiveErr := archived()
+	if archiveErr != nil {
+		return v, err
+	}
+	if fresh, ok := ctx.Value(freshnessKey{}).(*Freshness); ok {
+		fresh.record(f.ArchivedAt, err)
+	}
+	if f.OnFallback != nil {
+		f.OnFallback(err)
+	}
+	return a, nil
+}
+
+// Location implements Reader
+func (f *Fallback) Location(ctx context.Context) (*time.Location, error) {
+	return serve(ctx, f,
+		func() (*time.Location, error) { return f.Live.Location(ctx) },
+		func() (*time.Location, error) { return f.Archive.Location(ctx) })
+}
+
+// GetActivitiesByDate implements Reader
+func (f *Fallback) GetActivitiesByDate(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.Activity, error) {
+	return serve(ctx, f,
+		func() ([]api.Activity, error) { return f.Live.GetActivitiesByDate(ctx, start, end, opts...) },
+		func() ([]api.Activity, error) { return f.Archive.GetActivitiesByDate(ctx, start, end) })
+}
+
+// GetStepsDataRange implements Reader
+func (f *Fallback) GetStepsDataRange(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.DailySteps, error) {
+	return serve(ctx, f,
+		func() ([]api.DailySteps, error) { return f.Live.GetStepsDataRange(ctx, start, end, opts...) },
+		func() ([]api.DailySteps, error) { return f.Archive.GetStepsDataRange(ctx, start, end) })
+}
+
+// GetSleepDataRange implements Reader
+func (f *Fallback) GetSleepDataRange(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.SleepData, error) {
+	return serve(ctx, f,
+		func() ([]api.SleepData, error) { return f.Live.GetSleepDataRange(ctx, start, end, opts...) },
+		func() ([]api.SleepData, error) { return f.Archive.GetSleepDataRange(ctx, start, end) })
+}
+
+// GetUserStats implements Reader
+func (f *Fallback) GetUserStats(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.UserStats, error) {
+	return serve(ctx, f,
+		func() (*api.UserStats, error) { return f.Live.GetUserStats(ctx, date, opts...) },
+		func() (*api.UserStats, error) { return f.Archive.GetUserStats(ctx, date) })
+}
+
+// GetActivities implements Source
+func (f *Fallback) GetActivities(ctx context.Context, req api.PageRequest, opts ...api.RequestOption) ([]api.Activity, *api.Pagination, error) {
+	type page struct {
+		activities []api.Activity
+		pagination *api.Pagination
+	}
+	p, err := serve(ctx, f,
+		func() (page, error) {
+			activities, pagination, err := f.Live.GetActivities(ctx, req, opts...)
+			return page{activities, pagination}, err
+		},
+		func() (page, error) {
+			activities, pagination, err := f.Archive.GetActivities(ctx, req)
+			return page{activities, pagination}, err
+		})
+	return p.activities, p.pagination, err
+}
+
+// GetActivityDetails implements Source
+func (f *Fallback) GetActivityDetails(ctx context.Context, activityID int64, opts ...api.RequestOption) (*api.ActivityDetail, error) {
+	return serve(ctx, f,
+		func() (*api.ActivityDetail, error) { return f.Live.GetActivityDetails(ctx, activityID, opts...) },
+		func() (*api.ActivityDetail, error) { return f.Archive.GetActivityDetails(ctx, activityID) })
+}
+
+// GetUserProfile implements Source
+func (f *Fallback) GetUserProfile(ctx context.Context, opts ...api.RequestOption) (*api.UserProfile, error) {
+	return serve(ctx, f,
+		func() (*api.UserProfile, error) { return f.Live.GetUserProfile(ctx, opts...) },
+		func() (*api.UserProfile, error) { return f.Archive.GetUserProfile(ctx) })
+}
+
+// GetSleepData implements Source
+func (f *Fallback) GetSleepData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.SleepData, error) {
+	return serve(ctx, f,
+		func() (*api.SleepData, error) { return f.Live.GetSleepData(ctx, date, opts...) },
+		func() (*api.SleepData, error) { return f.Archive.GetSleepData(ctx, date) })
+}
+
+// GetStressData implements Source
+func (f *Fallback) GetStressData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.DailyStress, error) {
+	return serve(ctx, f,
+		func() (*api.DailyStress, error) { return f.Live.GetStressData(ctx, date, opts...) },
+		func() (*api.DailyStress, error) { return f.Archive.GetStressData(ctx, date) })
+}
+
+// GetStepsData implements Source
+func (f *Fallback) GetStepsData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.DailySteps, error) {
+	return serve(ctx, f,
+		func() (*api.DailySteps, error) { return f.Live.GetStepsData(ctx, date, opts...) },
+		func() (*api.DailySteps, error) { return f.Archive.GetStepsData(ctx, date) })
+}
+
+// GetHRVData implements Source
+func (f *Fallback) GetHRVData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.HRVData, error) {
+	return serve(ctx, f,
+		func() (*api.HRVData, error) { return f.Live.GetHRVData(ctx, date, opts...) },
+		func() (*api.HRVData, error) { return f.Archive.GetHRVData(ctx, date) })
+}
+
+// GetBodyBatteryData implements Source
+func (f *Fallback) GetBodyBatteryData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.BodyBatteryData, error) {
+	return serve(ctx, f,
+		func() (*api.BodyBatteryData, error) { return f.Live.GetBodyBatteryData(ctx, date, opts...) },
+		func() (*api.BodyBatteryData, error) { return f.Archive.GetBodyBatteryData(ctx, date) })
+}
@@ -0,0 +1,104 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ Source = (*api.Client)(nil)
+	_ Source = (*Fallback)(nil)
+)
+
+// stubSource answers GetUserStats and GetActivities; the other methods are
+// not called
+type stubSource struct {
+	Source
+	stats *api.UserStats
+	err   error
+}
+
+func (s stubSource) GetUserStats(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.UserStats, error) {
+	return s.stats, s.err
+}
+
+func (s stubSource) GetActivities(ctx context.Context, req api.PageRequest, opts ...api.RequestOption) ([]api.Activity, *api.Pagination, error) {
+	if s.err != nil {
+		return nil, nil, s.err
+	}
+	return []api.Activity{{ActivityID: int64(*s.stats.TotalSteps)}}, &api.Pagination{Page: 1}, nil
+}
+
+func TestFallback(t *testing.T) {
+	archivedAt := time.Date(2024, 3, 1, 6, 0, 0, 0, time.UTC)
+	archived := stubSource{stats: &api.UserStats{TotalSteps: api.Ptr(1)}}
+	unreachable := errors.New("dial tcp: connection refused")
+
+	tests := []struct {
+		name    string
+		live    error
+		archive error
+		want    int
+		stale   bool
+		wantErr error
+	}{
+		{name: "live", want: 2},
+		{name: "unreachable", live: unreachable, want: 1, stale: true},
+		{name: "rate limited", live: &api.APIError{StatusCode: http.StatusTooManyRequests}, want: 1, stale: true},
+		{name: "server error", live: &api.APIError{StatusCode: http.StatusBadGateway}, want: 1, stale: true},
+		{name: "not found", live: &api.APIError{StatusCode: http.StatusNotFound}, wantErr: api.ErrNotFound{}},
+		{name: "not archived", live: unreachable, archive: fs.ErrNotExist, wantErr: unreachable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reported []error
+			f := &Fallback{
+				Live:       stubSource{stats: &api.UserStats{TotalSteps: api.Ptr(2)}, err: tt.live},
+				Archive:    stubSource{stats: archived.stats, err: tt.archive},
+				ArchivedAt: archivedAt,
+				OnFallback: func(err error) { reported = append(reported, err) },
+			}
+			ctx, fresh := WithFreshness(context.Background())
+
+			stats, err := f.GetUserStats(ctx, archivedAt)
+			activities, _, pageErr := f.GetActivities(ctx, api.PageRequest{})
+			stale, at, cause := fresh.Stale()
+			assert.Equal(t, tt.stale, stale)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.ErrorIs(t, pageErr, tt.wantErr)
+				assert.Empty(t, reported)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, pageErr)
+			assert.Equal(t, tt.want, *stats.TotalSteps)
+			if assert.Len(t, activities, 1) {
+				assert.Equal(t, int64(tt.want), activities[0].ActivityID)
+			}
+			if tt.stale {
+				assert.Equal(t, archivedAt, at)
+				assert.Equal(t, tt.live, cause)
+				assert.Len(t, reported, 2)
+			}
+		})
+	}
+}
+
+func TestFallbackCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	f := &Fallback{
+		Live:    stubSource{err: context.Canceled},
+		Archive: stubSource{stats: &api.UserStats{}},
+	}
+	_, err := f.GetUserStats(ctx, time.Now())
+	assert.ErrorIs(t, err, context.Canceled, "a cancelled call is not answered from the archive")
+}
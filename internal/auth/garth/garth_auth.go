@@ -18,6 +18,7 @@ import (
 
 // Session represents the authentication session with OAuth1 and OAuth2 tokens
 type Session struct {
+	Version      int       `json:"version"`
 	OAuth1Token  string    `json:"oauth1_token"`
 	OAuth1Secret string    `json:"oauth1_secret"`
 	OAuth2Token  string    `json:"oauth2_token"`
@@ -50,22 +51,94 @@ func (g *GarthAuthenticator) setCloudflareHeaders() {
 	g.HTTPClient.SetHeader("User-Agent", "garmin-connect-client")
 }
 
-// Login authenticates with Garmin Connect using username and password
+// Login authenticates with Garmin Connect using username and password,
+// asking MFAPrompter for a code when the account requires MFA
 func (g *GarthAuthenticator) Login(username, password string) (*Session, error) {
+	session, challenge, err := g.StartLogin(username, password)
+	if err != nil || challenge == nil {
+		return session, err
+	}
+
+	mfaCode, err := g.MFAPrompter.GetMFACode(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("authentication failed: MFA prompt failed: %w", err)
+	}
+	return g.CompleteMFA(challenge, mfaCode)
+}
+
+// StartLogin begins a login. When the account requires MFA it returns the
+// pending challenge instead of prompting, so the login can be finished later
+// with CompleteMFA, possibly by another process.
+func (g *GarthAuthenticator) StartLogin(username, password string) (*Session, *MFAChallenge, error) {
 	g.setCloudflareHeaders()
 
 	// Step 1: Get request token
 	requestToken, requestSecret, err := g.getRequestToken()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get request token: %w", err)
+		return nil, nil, fmt.Errorf("failed to get request token: %w", err)
 	}
 
 	// Step 2: Authenticate with username/password to get verifier
-	verifier, err := g.authenticate(username, password, requestToken)
+	verifier, mfaContext, err := g.authenticate(username, password, requestToken)
+	if err != nil {
+		return nil, nil, fmt.Errorf("authentication failed: %w", err)
+	}
+	if mfaContext != "" {
+		challenge := &MFAChallenge{
+			Context:       mfaContext,
+			RequestToken:  requestToken,
+			RequestSecret: requestSecret,
+			ExpiresAt:     time.Now().Add(MFAChallengeTTL),
+		}
+		if jar := g.HTTPClient.GetClient().Jar; jar != nil {
+			if u, err := url.Parse(g.BaseURL); err == nil {
+				challenge.Cookies = jar.Cookies(u)
+			}
+		}
+		return nil, challenge, nil
+	}
+
+	session, err := g.finishLogin(requestToken, requestSecret, verifier)
+	return session, nil, err
+}
+
+// CompleteMFA finishes a login paused by StartLogin with the user's MFA code
+func (g *GarthAuthenticator) CompleteMFA(challenge *MFAChallenge, code string) (*Session, error) {
+	if challenge.Expired() {
+		return nil, ErrMFAChallengeExpired
+	}
+	g.setCloudflareHeaders()
+
+	// Restore the SSO cookies when resuming in another process
+	if len(challenge.Cookies) > 0 {
+		if u, err := url.Parse(g.BaseURL); err == nil {
+			if jar := g.HTTPClient.GetClient().Jar; jar != nil {
+				jar.SetCookies(u, challenge.Cookies)
+			}
+		}
+	}
+
+	mfaResp, err := g.HTTPClient.R().
+		SetFormData(map[string]string{
+			"mfaContext": challenge.Context,
+			"code":       code,
+			"verify":     "Verify",
+			"embed":      "false",
+		}).
+		Post(g.BaseURL + "/sso/verifyMFA")
+	if err != nil {
+		return nil, fmt.Errorf("authentication failed: MFA submission failed: %w", err)
+	}
+	verifier, err := extractVerifierFromResponse(mfaResp.String())
 	if err != nil {
 		return nil, fmt.Errorf("authentication failed: %w", err)
 	}
 
+	return g.finishLogin(challenge.RequestToken, challenge.RequestSecret, verifier)
+}
+
+// finishLogin exchanges the verified request token for the session tokens
+func (g *GarthAuthenticator) finishLogin(requestToken, requestSecret, verifier string) (*Session, error) {
 	// Step 3: Exchange request token for access token
 	oauth1Token, oauth1Secret, err := g.getAccessToken(requestToken, requestSecret, verifier)
 	if err != nil {
@@ -118,8 +191,9 @@ func (g *GarthAuthenticator) getRequestToken() (token, secret string, err error)
 	return token, secret, nil
 }
 
-// authenticate handles username/password authentication and MFA
-func (g *GarthAuthenticator) authenticate(username, password, requestToken string) (verifier string, err error) {
+// authenticate submits the username and password. It returns the verifier,
+// or the MFA context when the account requires a code first.
+func (g *GarthAuthenticator) authenticate(username, password, requestToken string) (verifier, mfaContext string, err error) {
 	// Step 1: Submit credentials
 	loginResp, err := g.HTTPClient.R().
 		SetFormData(map[string]string{
@@ -132,13 +206,12 @@ func (g *GarthAuthenticator) authenticate(username, password, requestToken strin
 		SetQueryParam("ticket", requestToken).
 		Post(g.BaseURL + "/sso/signin")
 	if err != nil {
-		return "", fmt.Errorf("login request failed: %w", err)
+		return "", "", fmt.Errorf("login request failed: %w", err)
 	}
 
 	// Step 2: Check for MFA requirement
 	if strings.Contains(loginResp.String(), "mfa-required") {
 		// Extract MFA context from HTML
-		mfaContext := ""
 		if re := regexp.MustCompile(`name="mfaContext" value="([^"]+)"`); re.Match(loginResp.Body()) {
 			matches := re.FindStringSubmatch(string(loginResp.Body()))
 			if len(matches) > 1 {
@@ -147,34 +220,14 @@ func (g *GarthAuthenticator) authenticate(username, password, requestToken strin
 		}
 
 		if mfaContext == "" {
-			return "", errors.New("MFA required but no context found")
-		}
-
-		// Step 3: Prompt for MFA code
-		mfaCode, err := g.MFAPrompter.GetMFACode(context.Background())
-		if err != nil {
-			return "", fmt.Errorf("MFA prompt failed: %w", err)
-		}
-
-		// Step 4: Submit MFA code
-		mfaResp, err := g.HTTPClient.R().
-			SetFormData(map[string]string{
-				"mfaContext": mfaContext,
-				"code":       mfaCode,
-				"verify":     "Verify",
-				"embed":      "false",
-			}).
-			Post(g.BaseURL + "/sso/verifyMFA")
-		if err != nil {
-			return "", fmt.Errorf("MFA submission failed: %w", err)
+			return "", "", errors.New("MFA required but no context found")
 		}
-
-		// Step 5: Extract verifier from response
-		return extractVerifierFromResponse(mfaResp.String())
+		return "", mfaContext, nil
 	}
 
 	// Step 3: Extract verifier from response
-	return extractVerifierFromResponse(loginResp.String())
+	verifier, err = extractVerifierFromResponse(loginResp.String())
+	return verifier, "", err
 }
 
 // extractVerifierFromResponse parses verifier from HTML response
@@ -254,8 +307,9 @@ func (g *GarthAuthenticator) RefreshToken(oauth1Token, oauth1Secret string) (str
 	return g.getOAuth2Token(oauth1Token, oauth1Secret)
 }
 
-// Save persists the session to the specified path
+// Save persists the session to the specified path in the current schema version
 func (s *Session) Save(path string) error {
+	s.Version = SessionVersion
 	data, err := json.MarshalIndent(s, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal session: %w", err)
@@ -279,17 +333,18 @@ func (s *Session) IsExpired() bool {
 	return time.Now().After(s.ExpiresAt)
 }
 
-// LoadSession reads a session from the specified path
+// LoadSession reads a session from the specified path, migrating files
+// written by older versions
 func LoadSession(path string) (*Session, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read session file: %w", err)
 	}
 
-	var session Session
-	if err := json.Unmarshal(data, &session); err != nil {
+	session, err := decodeSession(data)
+	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal session data: %w", err)
 	}
 
-	return &session, nil
+	return session, nil
 }
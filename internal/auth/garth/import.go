@@ -0,0 +1,113 @@
+package garth
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Token file names written by the Python garth library's Client.dump
+const (
+	garthOAuth1File = "oauth1_token.json"
+	garthOAuth2File = "oauth2_token.json"
+)
+
+// garthOAuth1 is the OAuth1 token format of the Python garth library
+type garthOAuth1 struct {
+	OAuthToken       string `json:"oauth_token"`
+	OAuthTokenSecret string `json:"oauth_token_secret"`
+}
+
+// garthOAuth2 is the OAuth2 token format of the Python garth library
+type garthOAuth2 struct {
+	AccessToken string `json:"access_token"`
+	ExpiresAt   int64  `json:"expires_at"` // Unix seconds
+}
+
+// LoadSessionFromGarth converts tokens saved by the Python garth library into a
+// Session. path may be the directory written by garth's Client.dump (usually
+// ~/.garth), one of the token files inside it, or a file holding the base64
+// string returned by Client.dumps.
+func LoadSessionFromGarth(path string) (*Session, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read garth tokens: %w", err)
+	}
+	if info.IsDir() {
+		return loadGarthDir(path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read garth tokens: %w", err)
+	}
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("{")) {
+		// A single token file; its sibling holds the other token
+		return loadGarthDir(filepath.Dir(path))
+	}
+	return parseGarthDump(data)
+}
+
+// loadGarthDir reads the OAuth1 and OAuth2 token files of a garth directory
+func loadGarthDir(dir string) (*Session, error) {
+	var oauth1 garthOAuth1
+	var oauth2 garthOAuth2
+	for name, v := range map[string]interface{}{garthOAuth1File: &oauth1, garthOAuth2File: &oauth2} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read garth tokens: %w", err)
+		}
+		if err := json.Unmarshal(data, v); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s: %w", name, err)
+		}
+	}
+	return garthSession(oauth1, oauth2)
+}
+
+// parseGarthDump decodes the output of garth's Client.dumps: a base64 encoded
+// JSON array holding the OAuth1 and OAuth2 tokens
+func parseGarthDump(data []byte) (*Session, error) {
+	if !bytes.HasPrefix(data, []byte("[")) {
+		decoded, err := base64.StdEncoding.DecodeString(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode garth token dump: %w", err)
+		}
+		data = decoded
+	}
+
+	var tokens []json.RawMessage
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal garth token dump: %w", err)
+	}
+	if len(tokens) != 2 {
+		return nil, fmt.Errorf("garth token dump holds %d tokens, expected 2", len(tokens))
+	}
+
+	var oauth1 garthOAuth1
+	var oauth2 garthOAuth2
+	if err := json.Unmarshal(tokens[0], &oauth1); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal garth OAuth1 token: %w", err)
+	}
+	if err := json.Unmarshal(tokens[1], &oauth2); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal garth OAuth2 token: %w", err)
+	}
+	return garthSession(oauth1, oauth2)
+}
+
+func garthSession(oauth1 garthOAuth1, oauth2 garthOAuth2) (*Session, error) {
+	if oauth1.OAuthToken == "" || oauth1.OAuthTokenSecret == "" {
+		return nil, errors.New("garth tokens are missing the OAuth1 token")
+	}
+	return &Session{
+		OAuth1Token:  oauth1.OAuthToken,
+		OAuth1Secret: oauth1.OAuthTokenSecret,
+		OAuth2Token:  oauth2.AccessToken,
+		ExpiresAt:    time.Unix(oauth2.ExpiresAt, 0),
+	}, nil
+}
@@ -0,0 +1,61 @@
+package garth
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	garthOAuth1JSON = `{"oauth_token": "tok1", "oauth_token_secret": "sec1", "mfa_token": null, "domain": "garmin.com"}`
+	garthOAuth2JSON = `{"token_type": "Bearer", "access_token": "access2", "refresh_token": "ref", "expires_in": 3600, "expires_at": 1709280000}`
+)
+
+func TestLoadSessionFromGarth(t *testing.T) {
+	want := &Session{
+		OAuth1Token:  "tok1",
+		OAuth1Secret: "sec1",
+		OAuth2Token:  "access2",
+		ExpiresAt:    time.Unix(1709280000, 0),
+	}
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, garthOAuth1File), []byte(garthOAuth1JSON), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, garthOAuth2File), []byte(garthOAuth2JSON), 0600))
+
+	t.Run("directory", func(t *testing.T) {
+		session, err := LoadSessionFromGarth(dir)
+		assert.NoError(t, err)
+		assert.Equal(t, want, session)
+	})
+
+	t.Run("token file", func(t *testing.T) {
+		session, err := LoadSessionFromGarth(filepath.Join(dir, garthOAuth2File))
+		assert.NoError(t, err)
+		assert.Equal(t, want, session)
+	})
+
+	t.Run("base64 dump", func(t *testing.T) {
+		dump := base64.StdEncoding.EncodeToString([]byte("[" + garthOAuth1JSON + "," + garthOAuth2JSON + "]"))
+		path := filepath.Join(t.TempDir(), "garth_token")
+		assert.NoError(t, os.WriteFile(path, []byte(dump+"\n"), 0600))
+
+		session, err := LoadSessionFromGarth(path)
+		assert.NoError(t, err)
+		assert.Equal(t, want, session)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "garth_token")
+		assert.NoError(t, os.WriteFile(path, []byte("not base64!"), 0600))
+		_, err := LoadSessionFromGarth(path)
+		assert.ErrorContains(t, err, "failed to decode garth token dump")
+
+		_, err = LoadSessionFromGarth(t.TempDir())
+		assert.ErrorContains(t, err, "failed to read garth tokens")
+	})
+}
@@ -0,0 +1,29 @@
+package garth
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// MFAChallengeTTL is how long a pending MFA challenge is kept before the
+// login has to start over
+const MFAChallengeTTL = 10 * time.Minute
+
+// ErrMFAChallengeExpired is returned when a paused login is resumed too late
+var ErrMFAChallengeExpired = errors.New("MFA challenge expired, please log in again")
+
+// MFAChallenge is a login paused at the MFA step. It holds everything needed
+// to finish the login with CompleteMFA.
+type MFAChallenge struct {
+	Context       string
+	RequestToken  string
+	RequestSecret string
+	Cookies       []*http.Cookie
+	ExpiresAt     time.Time
+}
+
+// Expired reports whether the challenge can no longer be completed
+func (c *MFAChallenge) Expired() bool {
+	return !c.ExpiresAt.IsZero() && time.Now().After(c.ExpiresAt)
+}
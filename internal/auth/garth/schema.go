@@ -0,0 +1,55 @@
+package garth
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SessionVersion is the schema version Session.Save writes. Bump it together
+// with a new entry in sessionMigrations whenever the persisted format changes.
+const SessionVersion = 1
+
+// sessionMigrations upgrade a decoded session document by one version;
+// the entry at index i turns version i into version i+1
+var sessionMigrations = []func(doc map[string]json.RawMessage) error{
+	// Files written before versioning already use the version 1 fields
+	func(doc map[string]json.RawMessage) error { return nil },
+}
+
+// decodeSession decodes a persisted session of any known version, migrating
+// it to the current schema
+func decodeSession(data []byte) (*Session, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	version := 0
+	if raw, ok := doc["version"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return nil, fmt.Errorf("invalid session version: %w", err)
+		}
+	}
+	if version < 0 {
+		return nil, fmt.Errorf("invalid session version %d", version)
+	}
+	if version > SessionVersion {
+		return nil, fmt.Errorf("session version %d is newer than the supported version %d", version, SessionVersion)
+	}
+	for v := version; v < SessionVersion; v++ {
+		if err := sessionMigrations[v](doc); err != nil {
+			return nil, fmt.Errorf("failed to migrate session from version %d: %w", v, err)
+		}
+	}
+	doc["version"] = json.RawMessage(fmt.Sprint(SessionVersion))
+
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var session Session
+	if err := json.Unmarshal(migrated, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
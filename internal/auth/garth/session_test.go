@@ -67,3 +67,37 @@ func TestMFAPrompterInterface(t *testing.T) {
 	_, err = errorMock.GetMFACode(context.Background())
 	assert.Error(t, err, "Mock prompter should return error when set")
 }
+
+func TestLoadSessionVersions(t *testing.T) {
+	dir := t.TempDir()
+
+	// Files written before versioning are migrated
+	legacy := filepath.Join(dir, "legacy.json")
+	assert.NoError(t, os.WriteFile(legacy, []byte(`{
+		"oauth1_token": "t1", "oauth1_secret": "s1", "oauth2_token": "t2",
+		"expires_at": "2030-01-01T00:00:00Z"
+	}`), 0600))
+	session, err := LoadSession(legacy)
+	assert.NoError(t, err)
+	assert.Equal(t, SessionVersion, session.Version)
+	assert.Equal(t, "t2", session.OAuth2Token)
+	assert.Equal(t, 2030, session.ExpiresAt.Year())
+
+	// Files from a newer release are rejected instead of losing fields
+	newer := filepath.Join(dir, "newer.json")
+	assert.NoError(t, os.WriteFile(newer, []byte(`{"version": 99, "oauth2_token": "t2"}`), 0600))
+	_, err = LoadSession(newer)
+	assert.ErrorContains(t, err, "session version 99 is newer")
+
+	negative := filepath.Join(dir, "negative.json")
+	assert.NoError(t, os.WriteFile(negative, []byte(`{"version": -1, "oauth2_token": "t2"}`), 0600))
+	_, err = LoadSession(negative)
+	assert.ErrorContains(t, err, "invalid session version -1")
+
+	// Saving stamps the current version
+	saved := filepath.Join(dir, "saved.json")
+	assert.NoError(t, (&Session{OAuth2Token: "t2"}).Save(saved))
+	data, err := os.ReadFile(saved)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"version": 1`)
+}
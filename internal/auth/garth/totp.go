@@ -0,0 +1,49 @@
+package garth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TOTPPrompter answers MFA challenges with codes generated from the
+// authenticator app secret, so logins can run without a person present
+type TOTPPrompter struct {
+	// Secret is the base32 key shown when the authenticator app was set up
+	Secret string
+	// Now returns the current time; it defaults to time.Now
+	Now func() time.Time
+}
+
+// GetMFACode returns the code for the current 30 second period
+func (p TOTPPrompter) GetMFACode(ctx context.Context) (string, error) {
+	now := time.Now
+	if p.Now != nil {
+		now = p.Now
+	}
+	return TOTPCode(p.Secret, now())
+}
+
+// TOTPCode computes the six digit RFC 6238 code for secret at t
+func TOTPCode(secret string, t time.Time) (string, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.TrimRight(secret, "="))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode TOTP secret: %w", err)
+	}
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(t.Unix()/30))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:]) & 0x7fffffff
+	return fmt.Sprintf("%06d", code%1000000), nil
+}
@@ -0,0 +1,41 @@
+package garth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTOTPCode(t *testing.T) {
+	// RFC 6238 SHA1 test vectors, truncated to six digits
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tt := range tests {
+		code, err := TOTPCode(secret, time.Unix(tt.unix, 0))
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, code, tt.unix)
+	}
+
+	_, err := TOTPCode("not base32!", time.Now())
+	assert.Error(t, err)
+}
+
+func TestTOTPPrompter(t *testing.T) {
+	p := TOTPPrompter{
+		Secret: "gezd gnbv gy3t qojq gezd gnbv gy3t qojq",
+		Now:    func() time.Time { return time.Unix(59, 0) },
+	}
+	code, err := p.GetMFACode(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "287082", code)
+}
@@ -2,18 +2,35 @@ package auth
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/sstent/go-garminconnect/internal/auth/garth"
 )
 
+// ErrMFAPending is returned by LoginWithMFAStorage when the login waits for an
+// MFA code; finish it with ResumeMFALogin
+var ErrMFAPending = errors.New("MFA code required to finish login")
+
+// ErrNoPendingMFA is returned by ResumeMFALogin when no login is waiting
+var ErrNoPendingMFA = errors.New("no login is waiting for an MFA code")
+
 // MFAState represents the state of an MFA verification session
 type MFAState struct {
 	VerificationURL string    `json:"verification_url"`
 	SessionToken    string    `json:"session_token"`
 	MFACode         string    `json:"mfa_code"`
 	ExpiresAt       time.Time `json:"expires_at"`
+
+	// The OAuth request token and SSO cookies of the paused login
+	RequestToken  string         `json:"request_token,omitempty"`
+	RequestSecret string         `json:"request_secret,omitempty"`
+	Cookies       []*http.Cookie `json:"cookies,omitempty"`
 }
 
 // MFAStorage handles persistence of MFA state
@@ -37,6 +54,11 @@ func NewFileMFAStorage() *FileMFAStorage {
 	}
 }
 
+// NewFileMFAStorageAt creates a file-based MFA storage at path
+func NewFileMFAStorageAt(path string) *FileMFAStorage {
+	return &FileMFAStorage{filePath: path}
+}
+
 // Store saves MFA state to file
 func (s *FileMFAStorage) Store(state MFAState) error {
 	s.mutex.Lock()
@@ -79,3 +101,55 @@ func (s *FileMFAStorage) Clear() error {
 	defer s.mutex.Unlock()
 	return os.Remove(s.filePath)
 }
+
+// LoginWithMFAStorage logs in with username and password. When the account
+// requires MFA, the pending challenge is saved to storage and ErrMFAPending
+// is returned, so the login can be finished by a later CLI invocation or a web
+// callback through ResumeMFALogin.
+func LoginWithMFAStorage(g *garth.GarthAuthenticator, storage MFAStorage, username, password string) (*garth.Session, error) {
+	session, challenge, err := g.StartLogin(username, password)
+	if err != nil || challenge == nil {
+		return session, err
+	}
+
+	state := MFAState{
+		VerificationURL: g.BaseURL + "/sso/verifyMFA",
+		SessionToken:    challenge.Context,
+		ExpiresAt:       challenge.ExpiresAt,
+		RequestToken:    challenge.RequestToken,
+		RequestSecret:   challenge.RequestSecret,
+		Cookies:         challenge.Cookies,
+	}
+	if err := storage.Store(state); err != nil {
+		return nil, fmt.Errorf("failed to store MFA state: %w", err)
+	}
+	return nil, ErrMFAPending
+}
+
+// ResumeMFALogin finishes the login saved in storage with the MFA code.
+// The stored challenge is cleared once it succeeded or expired.
+func ResumeMFALogin(g *garth.GarthAuthenticator, storage MFAStorage, code string) (*garth.Session, error) {
+	state, err := storage.Get()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read MFA state: %w", err)
+	}
+	if state.SessionToken == "" {
+		return nil, ErrNoPendingMFA
+	}
+
+	session, err := g.CompleteMFA(&garth.MFAChallenge{
+		Context:       state.SessionToken,
+		RequestToken:  state.RequestToken,
+		RequestSecret: state.RequestSecret,
+		Cookies:       state.Cookies,
+		ExpiresAt:     state.ExpiresAt,
+	}, code)
+	if err != nil && !errors.Is(err, garth.ErrMFAChallengeExpired) {
+		// A mistyped code can be tried again
+		return nil, err
+	}
+	if clearErr := storage.Clear(); clearErr != nil && !os.IsNotExist(clearErr) {
+		return session, fmt.Errorf("failed to clear MFA state: %w", clearErr)
+	}
+	return session, err
+}
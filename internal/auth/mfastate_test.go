@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/auth/garth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMFAServer simulates a Garmin SSO login that requires MFA code 123456
+func newMFAServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth-service/oauth/request_token":
+			w.Write([]byte("oauth_token=req_token&oauth_token_secret=req_secret"))
+		case "/sso/signin":
+			http.SetCookie(w, &http.Cookie{Name: "CASTGC", Value: "sso-cookie", Path: "/"})
+			w.Write([]byte(`<div class="mfa-required"><input type="hidden" name="mfaContext" value="context123" /></div>`))
+		case "/sso/verifyMFA":
+			cookie, err := r.Cookie("CASTGC")
+			if err != nil || cookie.Value != "sso-cookie" || r.FormValue("mfaContext") != "context123" || r.FormValue("code") != "123456" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Write([]byte(`<input type="hidden" name="oauth_verifier" value="mfa_verifier" />`))
+		case "/oauth-service/oauth/access_token":
+			assert.Equal(t, "req_token", r.URL.Query().Get("oauth_token"))
+			w.Write([]byte("oauth_token=access_token&oauth_token_secret=access_secret"))
+		case "/oauth-service/oauth/exchange/user/2.0":
+			w.Write([]byte("oauth2_token"))
+		default:
+			t.Errorf("Unexpected request to path: %s", r.URL.Path)
+		}
+	}))
+}
+
+func TestLoginWithMFAStorage(t *testing.T) {
+	server := newMFAServer(t)
+	defer server.Close()
+	dir := t.TempDir()
+	storage := NewFileMFAStorageAt(filepath.Join(dir, "mfa_state.json"))
+
+	// The first invocation stops at the MFA step
+	first := garth.NewAuthenticator(server.URL, "")
+	session, err := LoginWithMFAStorage(first, storage, "mfa_user", "mfa_pass")
+	assert.ErrorIs(t, err, ErrMFAPending)
+	assert.Nil(t, session)
+
+	state, err := storage.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "context123", state.SessionToken)
+	assert.Equal(t, "req_token", state.RequestToken)
+	assert.True(t, state.ExpiresAt.After(time.Now()))
+
+	// A second invocation, with a fresh authenticator, resumes it
+	sessionPath := filepath.Join(dir, "session.json")
+	second := garth.NewAuthenticator(server.URL, sessionPath)
+	_, err = ResumeMFALogin(second, storage, "000000")
+	assert.Error(t, err, "a wrong code keeps the challenge for another try")
+
+	session, err = ResumeMFALogin(second, storage, "123456")
+	require.NoError(t, err)
+	assert.Equal(t, "access_token", session.OAuth1Token)
+	assert.Equal(t, "oauth2_token", session.OAuth2Token)
+	assert.FileExists(t, sessionPath)
+
+	_, err = ResumeMFALogin(second, storage, "123456")
+	assert.ErrorIs(t, err, ErrNoPendingMFA)
+}
+
+func TestResumeMFALoginExpired(t *testing.T) {
+	storage := NewFileMFAStorageAt(filepath.Join(t.TempDir(), "mfa_state.json"))
+	require.NoError(t, storage.Store(MFAState{SessionToken: "context123", ExpiresAt: time.Now().Add(-time.Minute)}))
+
+	_, err := ResumeMFALogin(garth.NewAuthenticator("http://127.0.0.1:0", ""), storage, "123456")
+	assert.ErrorIs(t, err, garth.ErrMFAChallengeExpired)
+
+	_, err = ResumeMFALogin(garth.NewAuthenticator("http://127.0.0.1:0", ""), storage, "123456")
+	assert.ErrorIs(t, err, ErrNoPendingMFA)
+}
@@ -0,0 +1,209 @@
+// Package csvlog keeps long-lived CSV files of daily metrics, one file per
+// metric with one row per day, for use in spreadsheets
+package csvlog
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// Metric names, which are also the file names without ".csv"
+const (
+	MetricSteps        = "steps"
+	MetricRestingHR    = "resting_hr"
+	MetricSleepMinutes = "sleep_minutes"
+	MetricSleepScore   = "sleep_score"
+)
+
+// Value is a metric's value on one day
+type Value struct {
+	Date   api.Date
+	Metric string
+	Value  float64
+}
+
+// Values extracts the daily metrics of the given data. Days without data for
+// a metric, such as nights the watch was not worn, have no value.
+func Values(steps []api.DailySteps, stats []api.UserStats, sleep []api.SleepData) []Value {
+	var values []Value
+	for _, s := range steps {
+		values = append(values, Value{s.CalendarDate, MetricSteps, float64(s.TotalSteps)})
+	}
+	for _, s := range stats {
+		if s.RestingHR != nil {
+			values = append(values, Value{s.Date, MetricRestingHR, float64(*s.RestingHR)})
+		}
+	}
+	for _, s := range sleep {
+		if api.Value(s.SleepTimeSeconds) > 0 {
+			values = append(values, Value{s.CalendarDate, MetricSleepMinutes, float64(*s.SleepTimeSeconds / 60)})
+		}
+		if s.SleepScore != nil {
+			values = append(values, Value{s.CalendarDate, MetricSleepScore, float64(*s.SleepScore)})
+		}
+	}
+	return values
+}
+
+// Result counts the rows an Append changed
+type Result struct {
+	Appended int // days not in the file before
+	Updated  int // days whose value changed, e.g. a day synced before it ended
+}
+
+// Append adds the values to <dir>/<metric>.csv, whose rows are "date,value"
+// below a "date,<metric>" header. Days already in a file with the same value
+// are skipped, so running it again over the same days changes nothing. New
+// days after the last row are appended; a changed or back-filled day rewrites
+// the file in date order.
+func Append(dir string, values []Value) (Result, error) {
+	var result Result
+	byMetric := make(map[string][]Value)
+	for _, v := range values {
+		byMetric[v.Metric] = append(byMetric[v.Metric], v)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return result, fmt.Errorf("failed to create CSV directory: %w", err)
+	}
+
+	metrics := make([]string, 0, len(byMetric))
+	for m := range byMetric {
+		metrics = append(metrics, m)
+	}
+	sort.Strings(metrics)
+	for _, m := range metrics {
+		r, err := appendMetric(filepath.Join(dir, m+".csv"), m, byMetric[m])
+		result.Appended += r.Appended
+		result.Updated += r.Updated
+		if err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func appendMetric(path, metric string, values []Value) (Result, error) {
+	var result Result
+	rows, err := readRows(path)
+	if err != nil {
+		return result, err
+	}
+	last := ""
+	existing := make(map[string]int, len(rows))
+	for i, row := range rows {
+		existing[row[0]] = i
+		last = max(last, row[0])
+	}
+
+	sort.Slice(values, func(i, j int) bool { return values[i].Date.Before(values[j].Date.Time) })
+	rewrite := false
+	var added [][]string
+	for _, v := range values {
+		row := []string{v.Date.String(), strconv.FormatFloat(v.Value, 'f', -1, 64)}
+		if i, ok := existing[row[0]]; ok {
+			if rows[i][1] != row[1] {
+				rows[i] = row
+				rewrite = true
+				result.Updated++
+			}
+			continue
+		}
+		if row[0] < last {
+			rewrite = true
+		}
+		existing[row[0]] = len(rows)
+		rows = append(rows, row)
+		added = append(added, row)
+		result.Appended++
+	}
+
+	switch {
+	case rewrite:
+		sort.Slice(rows, func(i, j int) bool { return rows[i][0] < rows[j][0] })
+		return result, writeRows(path, metric, rows)
+	case len(added) > 0:
+		return result, appendRows(path, metric, added)
+	}
+	return result, nil
+}
+
+// readRows returns the data rows of the file at path without its header
+func readRows(path string) ([][]string, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = 2
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if len(rows) > 0 {
+		rows = rows[1:]
+	}
+	return rows, nil
+}
+
+// appendRows adds rows to the end of the file, writing the header first when
+// the file is new
+func appendRows(path, metric string, rows [][]string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	w := csv.NewWriter(f)
+	if info.Size() == 0 {
+		w.Write([]string{"date", metric})
+	}
+	w.WriteAll(rows)
+	if err := w.Error(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// writeRows replaces the file atomically
+func writeRows(path, metric string, rows [][]string) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", tmp, err)
+	}
+	w := csv.NewWriter(f)
+	w.Write([]string{"date", metric})
+	w.WriteAll(rows)
+	if err := w.Error(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package csvlog
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func date(s string) api.Date {
+	d, _ := api.ParseDate(s)
+	return d
+}
+
+func readFile(t *testing.T, path string) string {
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	return string(data)
+}
+
+func TestValues(t *testing.T) {
+	values := Values(
+		[]api.DailySteps{{CalendarDate: date("2024-03-01"), TotalSteps: 9500}},
+		[]api.UserStats{{Date: date("2024-03-01"), RestingHR: api.Ptr(52)}, {Date: date("2024-03-02")}},
+		[]api.SleepData{{CalendarDate: date("2024-03-01"), SleepTimeSeconds: api.Ptr(27000), SleepScore: api.Ptr(81)}},
+	)
+	assert.Equal(t, []Value{
+		{date("2024-03-01"), MetricSteps, 9500},
+		{date("2024-03-01"), MetricRestingHR, 52},
+		{date("2024-03-01"), MetricSleepMinutes, 450},
+		{date("2024-03-01"), MetricSleepScore, 81},
+	}, values)
+}
+
+func TestAppend(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "steps.csv")
+	steps := func(day string, n float64) Value { return Value{date(day), MetricSteps, n} }
+
+	result, err := Append(dir, []Value{steps("2024-03-02", 8000), steps("2024-03-01", 9500)})
+	assert.NoError(t, err)
+	assert.Equal(t, Result{Appended: 2}, result)
+	assert.Equal(t, "date,steps\n2024-03-01,9500\n2024-03-02,8000\n", readFile(t, path))
+
+	// Running again over the same days changes nothing
+	result, err = Append(dir, []Value{steps("2024-03-02", 8000)})
+	assert.NoError(t, err)
+	assert.Equal(t, Result{}, result)
+
+	result, err = Append(dir, []Value{steps("2024-03-03", 4000)})
+	assert.NoError(t, err)
+	assert.Equal(t, Result{Appended: 1}, result)
+	assert.Equal(t, "date,steps\n2024-03-01,9500\n2024-03-02,8000\n2024-03-03,4000\n", readFile(t, path))
+
+	// A day synced before it ended is corrected, and back-filled days are kept in order
+	result, err = Append(dir, []Value{steps("2024-03-03", 11000), steps("2024-02-29", 7000)})
+	assert.NoError(t, err)
+	assert.Equal(t, Result{Appended: 1, Updated: 1}, result)
+	assert.Equal(t, "date,steps\n2024-02-29,7000\n2024-03-01,9500\n2024-03-02,8000\n2024-03-03,11000\n", readFile(t, path))
+}
+
+type fakeSource struct{}
+
+func (fakeSource) GetStepsDataRange(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.DailySteps, error) {
+	return []api.DailySteps{{CalendarDate: api.NewDate(start), TotalSteps: 9500}}, &api.RangeError{}
+}
+
+func (fakeSource) GetSleepDataRange(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.SleepData, error) {
+	return nil, nil
+}
+
+func (fakeSource) GetUserStats(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.UserStats, error) {
+	return &api.UserStats{RestingHR: api.Ptr(52)}, nil
+}
+
+func TestFetch(t *testing.T) {
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	values, err := Fetch(context.Background(), fakeSource{}, day, day)
+	assert.True(t, api.IsPartial(err))
+	assert.Equal(t, []Value{
+		{date("2024-03-01"), MetricSteps, 9500},
+		{date("2024-03-01"), MetricRestingHR, 52},
+	}, values, "stats without a date are keyed by the day requested")
+}
@@ -0,0 +1,76 @@
+package csvlog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// Source defines the client methods Fetch reads from
+type Source interface {
+	GetStepsDataRange(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.DailySteps, error)
+	GetSleepDataRange(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.SleepData, error)
+	GetUserStats(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.UserStats, error)
+}
+
+// Days holds the daily data the metrics are extracted from
+type Days struct {
+	Steps []api.DailySteps
+	Sleep []api.SleepData
+	Stats []api.UserStats
+}
+
+// Fetch gets the daily metrics of every day from start to end inclusive.
+// Days that fail to load are left out and reported in an error for which
+// api.IsPartial is true, alongside the values that did load.
+func Fetch(ctx context.Context, src Source, start, end time.Time) ([]Value, error) {
+	days, err := FetchDays(ctx, src, start, end)
+	if err != nil && !api.IsPartial(err) {
+		return nil, err
+	}
+	return Values(days.Steps, days.Stats, days.Sleep), err
+}
+
+// FetchDays gets the daily data of every day from start to end inclusive,
+// with partial failures reported like Fetch
+func FetchDays(ctx context.Context, src Source, start, end time.Time) (Days, error) {
+	var partial []error
+	check := func(what string, err error) error {
+		if err == nil {
+			return nil
+		}
+		err = fmt.Errorf("failed to get %s: %w", what, err)
+		if !api.IsPartial(err) {
+			return err
+		}
+		partial = append(partial, err)
+		return nil
+	}
+
+	steps, err := src.GetStepsDataRange(ctx, start, end)
+	if err := check("steps", err); err != nil {
+		return Days{}, err
+	}
+	sleep, err := src.GetSleepDataRange(ctx, start, end)
+	if err := check("sleep", err); err != nil {
+		return Days{}, err
+	}
+	stats, err := api.FetchRange(ctx, start, end, api.DefaultRangeOptions(), func(ctx context.Context, day time.Time) (api.UserStats, error) {
+		stats, err := src.GetUserStats(ctx, day)
+		if err != nil {
+			return api.UserStats{}, err
+		}
+		// Values are keyed by day, which not every response includes
+		if stats.Date.IsZero() {
+			stats.Date = api.NewDate(day)
+		}
+		return *stats, nil
+	})
+	if err := check("daily stats", err); err != nil {
+		return Days{}, err
+	}
+	return Days{Steps: steps, Sleep: sleep, Stats: stats}, errors.Join(partial...)
+}
@@ -0,0 +1,96 @@
+// Package daterange iterates and splits inclusive ranges of calendar days.
+// Days are at midnight in the location of the range's start.
+package daterange
+
+import (
+	"iter"
+	"time"
+)
+
+// Range is an inclusive span of calendar days
+type Range struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Days returns the number of calendar days in the range
+func (r Range) Days() int {
+	return len(Days(r.Start, r.End))
+}
+
+// Iter yields every calendar day from start to end inclusive
+func Iter(start, end time.Time) iter.Seq[time.Time] {
+	return func(yield func(time.Time) bool) {
+		// Compare wall-clock dates, not instants, so end's time zone cannot shift the last day
+		day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+		last := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, start.Location())
+		for !day.After(last) {
+			if !yield(day) {
+				return
+			}
+			day = day.AddDate(0, 0, 1)
+		}
+	}
+}
+
+// Days returns every calendar day from start to end inclusive
+func Days(start, end time.Time) []time.Time {
+	var days []time.Time
+	for day := range Iter(start, end) {
+		days = append(days, day)
+	}
+	return days
+}
+
+// Chunks splits the range into consecutive ranges of at most maxDays days,
+// e.g. to respect an API's maximum span per request
+func Chunks(start, end time.Time, maxDays int) []Range {
+	if maxDays < 1 {
+		maxDays = 1
+	}
+	var chunks []Range
+	var current Range
+	n := 0
+	for day := range Iter(start, end) {
+		if n == 0 {
+			current.Start = day
+		}
+		current.End = day
+		n++
+		if n == maxDays {
+			chunks = append(chunks, current)
+			n = 0
+		}
+	}
+	if n > 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
+
+// Weeks splits the range at every weekStart, so all but the first and last
+// ranges cover a full week
+func Weeks(start, end time.Time, weekStart time.Weekday) []Range {
+	return split(start, end, func(day time.Time) bool {
+		return day.Weekday() == weekStart
+	})
+}
+
+// Months splits the range at the first day of every month
+func Months(start, end time.Time) []Range {
+	return split(start, end, func(day time.Time) bool {
+		return day.Day() == 1
+	})
+}
+
+// split starts a new range at every day for which boundary returns true
+func split(start, end time.Time, boundary func(time.Time) bool) []Range {
+	var ranges []Range
+	for day := range Iter(start, end) {
+		if len(ranges) == 0 || boundary(day) {
+			ranges = append(ranges, Range{Start: day})
+		}
+		ranges[len(ranges)-1].End = day
+	}
+	return ranges
+}
@@ -0,0 +1,61 @@
+package daterange
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestIter(t *testing.T) {
+	var days []time.Time
+	for d := range Iter(day(2024, 2, 28).Add(15*time.Hour), day(2024, 3, 1).Add(time.Hour)) {
+		days = append(days, d)
+	}
+	assert.Equal(t, []time.Time{day(2024, 2, 28), day(2024, 2, 29), day(2024, 3, 1)}, days)
+
+	// Stopping early ends the iteration
+	n := 0
+	for range Iter(day(2024, 1, 1), day(2024, 12, 31)) {
+		n++
+		if n == 3 {
+			break
+		}
+	}
+	assert.Equal(t, 3, n)
+
+	assert.Empty(t, Days(day(2024, 3, 2), day(2024, 3, 1)))
+	assert.Equal(t, 366, Range{day(2024, 1, 1), day(2024, 12, 31)}.Days())
+}
+
+func TestChunks(t *testing.T) {
+	chunks := Chunks(day(2024, 1, 1), day(2024, 1, 10), 4)
+	assert.Equal(t, []Range{
+		{day(2024, 1, 1), day(2024, 1, 4)},
+		{day(2024, 1, 5), day(2024, 1, 8)},
+		{day(2024, 1, 9), day(2024, 1, 10)},
+	}, chunks)
+	assert.Len(t, Chunks(day(2024, 1, 1), day(2024, 1, 3), 0), 3)
+	assert.Nil(t, Chunks(day(2024, 1, 3), day(2024, 1, 1), 7))
+}
+
+func TestWeeksAndMonths(t *testing.T) {
+	// 2024-03-06 is a Wednesday
+	weeks := Weeks(day(2024, 3, 6), day(2024, 3, 20), time.Monday)
+	assert.Equal(t, []Range{
+		{day(2024, 3, 6), day(2024, 3, 10)},
+		{day(2024, 3, 11), day(2024, 3, 17)},
+		{day(2024, 3, 18), day(2024, 3, 20)},
+	}, weeks)
+
+	months := Months(day(2024, 1, 15), day(2024, 3, 1))
+	assert.Equal(t, []Range{
+		{day(2024, 1, 15), day(2024, 1, 31)},
+		{day(2024, 2, 1), day(2024, 2, 29)},
+		{day(2024, 3, 1), day(2024, 3, 1)},
+	}, months)
+}
@@ -0,0 +1,195 @@
+// Package dedupe finds activities recorded twice, e.g. by a watch and a bike
+// computer, and resolves them when uploading
+package dedupe
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/fitconv"
+	"github.com/tormoder/fit"
+)
+
+// Policy decides what happens to an upload duplicating an activity
+type Policy string
+
+const (
+	// PolicySkip keeps the activity already in the account
+	PolicySkip Policy = "skip"
+	// PolicyKeepLongest keeps the longer recording, replacing the activity in
+	// the account when the upload is longer
+	PolicyKeepLongest Policy = "keep-longest"
+	// PolicyPrompt asks Uploader.Prompt
+	PolicyPrompt Policy = "prompt"
+)
+
+// ParsePolicy returns the policy named s
+func ParsePolicy(s string) (Policy, error) {
+	switch p := Policy(s); p {
+	case PolicySkip, PolicyKeepLongest, PolicyPrompt:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid duplicate policy %q, expected skip, keep-longest or prompt", s)
+}
+
+// Resolution is what was done with a duplicate upload
+type Resolution int
+
+const (
+	// KeepBoth uploads the file next to the existing activity
+	KeepBoth Resolution = iota
+	// Skip doesn't upload the file
+	Skip
+	// Replace uploads the file and deletes the existing activity
+	Replace
+)
+
+// String returns the name of the resolution
+func (r Resolution) String() string {
+	switch r {
+	case Skip:
+		return "skipped"
+	case Replace:
+		return "replaced"
+	default:
+		return "kept both"
+	}
+}
+
+const (
+	// startTolerance is how far apart the starts of duplicates may be
+	startTolerance = time.Minute
+	// distanceTolerance is the relative difference allowed between the
+	// distances of duplicates, but at least minDistanceTolerance meters
+	distanceTolerance    = 0.02
+	minDistanceTolerance = 100
+)
+
+// IsDuplicate reports whether a and b are likely the same activity: they
+// started within a minute of each other and cover the same distance, within
+// 2% or 100 m
+func IsDuplicate(a, b api.Activity) bool {
+	if a.StartTime.IsZero() || b.StartTime.IsZero() {
+		return false
+	}
+	if d := a.StartTime.Sub(b.StartTime.Time); d < -startTolerance || d > startTolerance {
+		return false
+	}
+	tolerance := math.Max(distanceTolerance*math.Max(a.Distance, b.Distance), minDistanceTolerance)
+	return math.Abs(a.Distance-b.Distance) <= tolerance
+}
+
+// Find returns the first of existing that candidate duplicates
+func Find(candidate api.Activity, existing []api.Activity) (api.Activity, bool) {
+	for _, a := range existing {
+		if IsDuplicate(candidate, a) {
+			return a, true
+		}
+	}
+	return api.Activity{}, false
+}
+
+// Client is the part of *api.Client the Uploader uses
+type Client interface {
+	GetActivitiesByDate(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.Activity, error)
+	UploadActivity(ctx context.Context, fitFile []byte, opts ...api.RequestOption) (int64, error)
+	DeleteActivity(ctx context.Context, activityID int64, opts ...api.RequestOption) error
+}
+
+// Uploader uploads FIT files, resolving uploads that duplicate an activity
+// already in the account with Policy
+type Uploader struct {
+	Client Client
+	Policy Policy
+	// Prompt resolves duplicates under PolicyPrompt
+	Prompt func(existing, upload api.Activity) (Resolution, error)
+}
+
+// Result is the outcome of an upload
+type Result struct {
+	// ActivityID is the uploaded activity, or the existing one when the
+	// upload was skipped
+	ActivityID int64
+	// Duplicate is the activity the file duplicated, if any
+	Duplicate  *api.Activity
+	Resolution Resolution
+}
+
+// Upload uploads fitFile unless Policy resolves it as a duplicate to skip.
+// Files whose summary can't be read are uploaded unchecked.
+func (u *Uploader) Upload(ctx context.Context, fitFile []byte) (Result, error) {
+	upload, ok := summary(fitFile)
+	if !ok {
+		return u.upload(ctx, fitFile, nil, KeepBoth)
+	}
+	start := upload.StartTime.Time
+	existing, err := u.Client.GetActivitiesByDate(ctx, start.AddDate(0, 0, -1), start.AddDate(0, 0, 1))
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to check for duplicates: %w", err)
+	}
+	duplicate, ok := Find(upload, existing)
+	if !ok {
+		return u.upload(ctx, fitFile, nil, KeepBoth)
+	}
+
+	resolution, err := u.resolve(duplicate, upload)
+	if err != nil {
+		return Result{}, err
+	}
+	if resolution == Skip {
+		return Result{ActivityID: duplicate.ActivityID, Duplicate: &duplicate, Resolution: Skip}, nil
+	}
+	return u.upload(ctx, fitFile, &duplicate, resolution)
+}
+
+// resolve applies the policy to a duplicate
+func (u *Uploader) resolve(existing, upload api.Activity) (Resolution, error) {
+	switch u.Policy {
+	case PolicySkip:
+		return Skip, nil
+	case PolicyKeepLongest:
+		if upload.Duration > existing.Duration {
+			return Replace, nil
+		}
+		return Skip, nil
+	case PolicyPrompt:
+		if u.Prompt == nil {
+			return Skip, fmt.Errorf("no prompt to resolve the duplicate of activity %d", existing.ActivityID)
+		}
+		return u.Prompt(existing, upload)
+	}
+	return Skip, fmt.Errorf("invalid duplicate policy %q", u.Policy)
+}
+
+// upload uploads fitFile and, for Replace, deletes the duplicate afterwards,
+// so a failed upload never loses the existing activity
+func (u *Uploader) upload(ctx context.Context, fitFile []byte, duplicate *api.Activity, resolution Resolution) (Result, error) {
+	id, err := u.Client.UploadActivity(ctx, fitFile)
+	if err != nil {
+		return Result{}, err
+	}
+	result := Result{ActivityID: id, Duplicate: duplicate, Resolution: resolution}
+	if resolution == Replace && duplicate.ActivityID != id {
+		if err := u.Client.DeleteActivity(ctx, duplicate.ActivityID); err != nil {
+			return result, fmt.Errorf("activity %d uploaded but its duplicate wasn't deleted: %w", id, err)
+		}
+	}
+	return result, nil
+}
+
+// summary returns the summary of an activity FIT file
+func summary(fitFile []byte) (api.Activity, bool) {
+	file, err := fit.Decode(bytes.NewReader(fitFile))
+	if err != nil {
+		return api.Activity{}, false
+	}
+	detail, _, err := fitconv.FromFIT(file)
+	if err != nil {
+		return api.Activity{}, false
+	}
+	return detail.Activity, true
+}
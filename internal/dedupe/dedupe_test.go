@@ -0,0 +1,128 @@
+package dedupe
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/fitconv"
+	"github.com/stretchr/testify/assert"
+	"github.com/tormoder/fit"
+)
+
+var _ Client = (*api.Client)(nil)
+
+var start = time.Date(2024, 3, 12, 7, 30, 0, 0, time.UTC)
+
+func TestIsDuplicate(t *testing.T) {
+	run := api.Activity{StartTime: api.NewGarminTime(start), Distance: 10000}
+	tests := []struct {
+		name  string
+		other api.Activity
+		want  bool
+	}{
+		{"same", run, true},
+		{"other device", api.Activity{StartTime: api.NewGarminTime(start.Add(40 * time.Second)), Distance: 10150}, true},
+		{"short distances", api.Activity{StartTime: api.NewGarminTime(start), Distance: 90}, false},
+		{"later start", api.Activity{StartTime: api.NewGarminTime(start.Add(2 * time.Minute)), Distance: 10000}, false},
+		{"other distance", api.Activity{StartTime: api.NewGarminTime(start), Distance: 12000}, false},
+		{"no start", api.Activity{Distance: 10000}, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, IsDuplicate(run, tt.other), tt.name)
+	}
+	assert.True(t, IsDuplicate(api.Activity{StartTime: api.NewGarminTime(start), Distance: 0},
+		api.Activity{StartTime: api.NewGarminTime(start), Distance: 60}), "strength sessions without distance match by start")
+}
+
+// fakeClient has one activity in the account
+type fakeClient struct {
+	existing []api.Activity
+	uploads  int
+	deleted  []int64
+}
+
+func (f *fakeClient) GetActivitiesByDate(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.Activity, error) {
+	return f.existing, nil
+}
+
+func (f *fakeClient) UploadActivity(ctx context.Context, fitFile []byte, opts ...api.RequestOption) (int64, error) {
+	f.uploads++
+	return 2, nil
+}
+
+func (f *fakeClient) DeleteActivity(ctx context.Context, activityID int64, opts ...api.RequestOption) error {
+	f.deleted = append(f.deleted, activityID)
+	return nil
+}
+
+// fitFile returns an activity FIT file lasting duration
+func fitFile(t *testing.T, duration float64) []byte {
+	file, err := fitconv.ToFIT(&api.ActivityDetail{Activity: api.Activity{
+		Type:      "running",
+		StartTime: api.NewGarminTime(start.Add(20 * time.Second)),
+		Duration:  duration,
+		Distance:  10050,
+	}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := fit.Encode(&buf, file, binary.LittleEndian); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestUploader(t *testing.T) {
+	existing := api.Activity{ActivityID: 1, StartTime: api.NewGarminTime(start), Duration: 3000, Distance: 10000}
+	tests := []struct {
+		name       string
+		policy     Policy
+		duration   float64
+		prompt     Resolution
+		want       Result
+		wantUpload bool
+		wantDelete []int64
+	}{
+		{name: "skip", policy: PolicySkip, duration: 3600, want: Result{ActivityID: 1, Resolution: Skip}},
+		{name: "longer upload", policy: PolicyKeepLongest, duration: 3600, want: Result{ActivityID: 2, Resolution: Replace}, wantUpload: true, wantDelete: []int64{1}},
+		{name: "shorter upload", policy: PolicyKeepLongest, duration: 2400, want: Result{ActivityID: 1, Resolution: Skip}},
+		{name: "prompt", policy: PolicyPrompt, duration: 2400, prompt: KeepBoth, want: Result{ActivityID: 2, Resolution: KeepBoth}, wantUpload: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeClient{existing: []api.Activity{existing}}
+			u := &Uploader{Client: client, Policy: tt.policy, Prompt: func(e, upload api.Activity) (Resolution, error) {
+				assert.Equal(t, int64(1), e.ActivityID)
+				assert.Equal(t, tt.duration, upload.Duration)
+				return tt.prompt, nil
+			}}
+			result, err := u.Upload(context.Background(), fitFile(t, tt.duration))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.ActivityID, result.ActivityID)
+			assert.Equal(t, tt.want.Resolution, result.Resolution)
+			if assert.NotNil(t, result.Duplicate) {
+				assert.Equal(t, int64(1), result.Duplicate.ActivityID)
+			}
+			assert.Equal(t, tt.wantUpload, client.uploads == 1)
+			assert.Equal(t, tt.wantDelete, client.deleted)
+		})
+	}
+
+	client := &fakeClient{}
+	result, err := (&Uploader{Client: client, Policy: PolicySkip}).Upload(context.Background(), fitFile(t, 3600))
+	assert.NoError(t, err)
+	assert.Equal(t, Result{ActivityID: 2}, result, "files without a duplicate are uploaded")
+}
+
+func TestParsePolicy(t *testing.T) {
+	p, err := ParsePolicy("keep-longest")
+	assert.NoError(t, err)
+	assert.Equal(t, PolicyKeepLongest, p)
+	_, err = ParsePolicy("newest")
+	assert.Error(t, err)
+}
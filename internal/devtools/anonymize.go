@@ -0,0 +1,70 @@
+package devtools
+
+import "strings"
+
+// personalFields are replaced with placeholder values, matched case-insensitively
+var personalFields = map[string]interface{}{
+	"displayname":                "Mock User",
+	"fullname":                   "Mock User Full",
+	"username":                   "mockuser",
+	"emailaddress":               "mock@example.com",
+	"profileid":                  "mock-123",
+	"userprofilepk":              1,
+	"userprofileid":              1,
+	"ownerid":                    1,
+	"ownerdisplayname":           "Mock User",
+	"ownerfullname":              "Mock User Full",
+	"userid":                     1,
+	"location":                   "Mock Location",
+	"locationname":               "Mock Location",
+	"birthdate":                  "1990-01-01",
+	"profileimageurllarge":       "https://example.com/mock.jpg",
+	"profileimageurlmedium":      "https://example.com/mock.jpg",
+	"profileimageurlsmall":       "https://example.com/mock.jpg",
+	"ownerprofileimageurllarge":  "https://example.com/mock.jpg",
+	"ownerprofileimageurlmedium": "https://example.com/mock.jpg",
+	"ownerprofileimageurlsmall":  "https://example.com/mock.jpg",
+	"serialnumber":               "0000000000",
+	"deviceid":                   1,
+}
+
+// coordinateFields hold GPS positions, which are zeroed so fixtures can't reveal where someone lives
+var coordinateFields = map[string]bool{
+	"lat":            true,
+	"lon":            true,
+	"latitude":       true,
+	"longitude":      true,
+	"startlatitude":  true,
+	"startlongitude": true,
+	"endlatitude":    true,
+	"endlongitude":   true,
+}
+
+// Anonymize replaces personal identifiers and coordinates in a decoded JSON value
+func Anonymize(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			key := strings.ToLower(k)
+			if replacement, ok := personalFields[key]; ok && child != nil {
+				val[k] = replacement
+				continue
+			}
+			if coordinateFields[key] {
+				if _, isNumber := child.(float64); isNumber {
+					val[k] = 0.0
+				}
+				continue
+			}
+			val[k] = Anonymize(child)
+		}
+		return val
+	case []interface{}:
+		for i, child := range val {
+			val[i] = Anonymize(child)
+		}
+		return val
+	default:
+		return v
+	}
+}
@@ -0,0 +1,130 @@
+package devtools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// Getter performs raw GET requests against the API
+type Getter interface {
+	Get(ctx context.Context, path string, v interface{}, opts ...api.RequestOption) error
+}
+
+// Result reports the outcome of capturing one endpoint
+type Result struct {
+	Endpoint garmintest.Endpoint
+	Path     string
+	File     string
+	Err      error
+}
+
+// capture describes how to request the fixture for one endpoint
+type capture struct {
+	endpoint garmintest.Endpoint
+	path     func(date string, activityID int64) string
+}
+
+// captures lists endpoints in request order; the activity list must come first
+// so later captures can reference a real activity ID
+var captures = []capture{
+	{garmintest.Activities, func(string, int64) string {
+		return "/activitylist-service/activities/search?page=1&pageSize=10"
+	}},
+	{garmintest.Activity, func(_ string, id int64) string {
+		return fmt.Sprintf("/activity-service/activity/%d", id)
+	}},
+	{garmintest.Profile, func(string, int64) string { return "/userprofile-service/socialProfile" }},
+	{garmintest.Settings, func(string, int64) string { return "/userprofile-service/userprofile/settings" }},
+	{garmintest.Stats, func(d string, _ int64) string { return "/stats-service/stats/daily/" + d }},
+	{garmintest.Sleep, func(d string, _ int64) string { return "/wellness-service/sleep/daily/" + d }},
+	{garmintest.Stress, func(d string, _ int64) string { return "/wellness-service/stress/daily/" + d }},
+	{garmintest.Steps, func(d string, _ int64) string { return "/wellness-service/steps/daily/" + d }},
+	{garmintest.HRV, func(d string, _ int64) string { return "/hrv-service/hrv/" + d }},
+	{garmintest.BodyBattery, func(d string, _ int64) string { return "/bodybattery-service/bodybattery/" + d }},
+	{garmintest.BodyComposition, func(d string, _ int64) string {
+		params := url.Values{}
+		params.Add("startDate", d)
+		params.Add("endDate", d)
+		return "/body-composition?" + params.Encode()
+	}},
+	{garmintest.Calendar, func(d string, _ int64) string {
+		t, _ := time.Parse("2006-01-02", d)
+		return fmt.Sprintf("/calendar-service/year/%d/month/%d", t.Year(), int(t.Month())-1)
+	}},
+}
+
+// Capture requests every supported endpoint for date and writes anonymized
+// <endpoint>.json fixtures into dir. Failures are reported per endpoint
+// rather than aborting the run.
+func Capture(ctx context.Context, api Getter, dir string, date time.Time) ([]Result, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+
+	day := date.Format("2006-01-02")
+	var activityID int64
+	results := make([]Result, 0, len(captures))
+	for _, c := range captures {
+		if c.endpoint == garmintest.Activity && activityID == 0 {
+			results = append(results, Result{Endpoint: c.endpoint, Err: fmt.Errorf("no activity available to capture")})
+			continue
+		}
+
+		res := Result{Endpoint: c.endpoint, Path: c.path(day, activityID)}
+		var raw json.RawMessage
+		if err := api.Get(ctx, res.Path, &raw); err != nil {
+			res.Err = err
+			results = append(results, res)
+			continue
+		}
+
+		if c.endpoint == garmintest.Activities {
+			activityID = firstActivityID(raw)
+		}
+
+		res.File = filepath.Join(dir, string(c.endpoint)+".json")
+		res.Err = writeFixture(res.File, raw)
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// firstActivityID extracts the first activity ID from an activity list response
+func firstActivityID(raw json.RawMessage) int64 {
+	var list struct {
+		Activities []struct {
+			ActivityID int64 `json:"activityId"`
+		} `json:"activities"`
+	}
+	if err := json.Unmarshal(raw, &list); err != nil || len(list.Activities) == 0 {
+		// Some API versions return a bare array
+		var arr []struct {
+			ActivityID int64 `json:"activityId"`
+		}
+		if err := json.Unmarshal(raw, &arr); err != nil || len(arr) == 0 {
+			return 0
+		}
+		return arr[0].ActivityID
+	}
+	return list.Activities[0].ActivityID
+}
+
+func writeFixture(path string, raw json.RawMessage) error {
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return fmt.Errorf("response is not valid JSON: %w", err)
+	}
+	data, err := json.MarshalIndent(Anonymize(v), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
@@ -0,0 +1,82 @@
+package devtools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/garmintest"
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/auth/garth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCapture(t *testing.T) {
+	srv := garmintest.NewServer()
+	defer srv.Close()
+	srv.SetJSON(garmintest.Profile, http.StatusOK, map[string]interface{}{
+		"displayName":  "real.person",
+		"emailAddress": "real@person.example",
+		"height":       180.0,
+	})
+	srv.SetJSON(garmintest.Activity, http.StatusOK, map[string]interface{}{
+		"activityId":     1,
+		"startLatitude":  60.1699,
+		"startLongitude": 24.9384,
+	})
+	srv.InjectError(garmintest.HRV, http.StatusNotFound, 1)
+
+	session := &garth.Session{OAuth2Token: "token", ExpiresAt: time.Now().Add(time.Hour)}
+	client, err := api.NewClient(api.NewMockAuthenticator(), session, "")
+	assert.NoError(t, err)
+	client.HTTPClient.SetBaseURL(srv.URL())
+
+	dir := t.TempDir()
+	results, err := Capture(context.Background(), client, dir, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Len(t, results, len(captures))
+
+	failed := map[garmintest.Endpoint]bool{}
+	for _, r := range results {
+		if r.Err != nil {
+			failed[r.Endpoint] = true
+		}
+	}
+	assert.Equal(t, map[garmintest.Endpoint]bool{garmintest.HRV: true}, failed)
+	srv.AssertQuery(t, garmintest.BodyComposition, "startDate", "2024-03-01")
+	_, ok := srv.LastRequest(garmintest.Activity)
+	assert.True(t, ok)
+
+	var profile map[string]interface{}
+	data, err := os.ReadFile(filepath.Join(dir, "profile.json"))
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(data, &profile))
+	assert.Equal(t, "Mock User", profile["displayName"])
+	assert.Equal(t, "mock@example.com", profile["emailAddress"])
+	assert.Equal(t, 180.0, profile["height"])
+
+	data, err = os.ReadFile(filepath.Join(dir, "activity.json"))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "60.1699")
+
+	// Captured fixtures can be loaded straight back into the fake server
+	assert.NoError(t, srv.LoadFixtures(os.DirFS(dir)))
+}
+
+func TestAnonymizeNested(t *testing.T) {
+	v := map[string]interface{}{
+		"OwnerFullName": "Jane Doe",
+		"gpsTracks": []interface{}{
+			map[string]interface{}{"lat": 1.5, "lon": 2.5, "ele": 10.0},
+		},
+		"location": nil,
+	}
+	Anonymize(v)
+	assert.Equal(t, "Mock User Full", v["OwnerFullName"])
+	assert.Equal(t, map[string]interface{}{"lat": 0.0, "lon": 0.0, "ele": 10.0}, v["gpsTracks"].([]interface{})[0])
+	assert.Nil(t, v["location"])
+}
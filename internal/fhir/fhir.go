@@ -0,0 +1,235 @@
+// Package fhir converts Garmin health data into FHIR R4 Observation resources
+package fhir
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// Code systems used by the exported observations
+const (
+	loincSystem    = "http://loinc.org"
+	ucumSystem     = "http://unitsofmeasure.org"
+	categorySystem = "http://terminology.hl7.org/CodeSystem/observation-category"
+)
+
+// LOINC codes of the exported observations
+const (
+	CodeRestingHeartRate = "40443-4"
+	CodeBodyWeight       = "29463-7"
+	CodeOxygenSaturation = "59408-5"
+	CodeBloodPressure    = "85354-9"
+	CodeSystolic         = "8480-6"
+	CodeDiastolic        = "8462-4"
+	CodeSleepDuration    = "93832-4"
+)
+
+// Data holds the Garmin data to convert. Timestamps are written in Location
+// (default UTC).
+type Data struct {
+	HeartRate     []api.HeartRateData
+	Weight        []api.BodyComposition
+	SpO2          []api.SpO2Data
+	BloodPressure []api.BloodPressure
+	Sleep         []api.SleepData
+	// Patient is the subject reference, e.g. "Patient/123"; omitted when empty
+	Patient  string
+	Location *time.Location
+}
+
+// Bundle is a FHIR collection bundle
+type Bundle struct {
+	ResourceType string  `json:"resourceType"`
+	Type         string  `json:"type"`
+	Timestamp    string  `json:"timestamp"`
+	Entry        []Entry `json:"entry"`
+}
+
+// Entry wraps a resource in a bundle
+type Entry struct {
+	FullURL  string      `json:"fullUrl"`
+	Resource Observation `json:"resource"`
+}
+
+// Observation is the subset of the FHIR R4 Observation resource used by the export
+type Observation struct {
+	ResourceType      string            `json:"resourceType"`
+	ID                string            `json:"id"`
+	Status            string            `json:"status"`
+	Category          []CodeableConcept `json:"category"`
+	Code              CodeableConcept   `json:"code"`
+	Subject           *Reference        `json:"subject,omitempty"`
+	EffectiveDateTime string            `json:"effectiveDateTime,omitempty"`
+	EffectivePeriod   *Period           `json:"effectivePeriod,omitempty"`
+	ValueQuantity     *Quantity         `json:"valueQuantity,omitempty"`
+	Component         []Component       `json:"component,omitempty"`
+}
+
+// CodeableConcept is a set of codings
+type CodeableConcept struct {
+	Coding []Coding `json:"coding"`
+	Text   string   `json:"text,omitempty"`
+}
+
+// Coding is a code from a code system
+type Coding struct {
+	System  string `json:"system"`
+	Code    string `json:"code"`
+	Display string `json:"display,omitempty"`
+}
+
+// Reference points to another resource
+type Reference struct {
+	Reference string `json:"reference"`
+}
+
+// Period is a time interval
+type Period struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Quantity is a measured value with a UCUM unit
+type Quantity struct {
+	Value  float64 `json:"value"`
+	Unit   string  `json:"unit"`
+	System string  `json:"system"`
+	Code   string  `json:"code"`
+}
+
+// Component is one part of a multi-value observation such as blood pressure
+type Component struct {
+	Code          CodeableConcept `json:"code"`
+	ValueQuantity Quantity        `json:"valueQuantity"`
+}
+
+// Observations converts d into Observation resources. IDs are derived from the
+// measurement type and time, so repeated exports update the same resources.
+// Days without data are skipped.
+func Observations(d Data) []Observation {
+	loc := d.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	var subject *Reference
+	if d.Patient != "" {
+		subject = &Reference{Reference: d.Patient}
+	}
+	var obs []Observation
+	add := func(o Observation) {
+		o.ResourceType = "Observation"
+		o.Status = "final"
+		o.Subject = subject
+		obs = append(obs, o)
+	}
+
+	for _, hr := range d.HeartRate {
+		if hr.RestingHR == nil {
+			continue
+		}
+		add(Observation{
+			ID:                "garmin-rhr-" + hr.Date.String(),
+			Category:          category("vital-signs"),
+			Code:              loinc(CodeRestingHeartRate, "Heart rate --resting"),
+			EffectiveDateTime: hr.Date.String(),
+			ValueQuantity:     quantity(float64(*hr.RestingHR), "beats/minute", "/min"),
+		})
+	}
+
+	for _, w := range d.Weight {
+		if w.Weight <= 0 {
+			continue
+		}
+		add(Observation{
+			ID:                fmt.Sprintf("garmin-weight-%d", w.Timestamp.Unix()),
+			Category:          category("vital-signs"),
+			Code:              loinc(CodeBodyWeight, "Body weight"),
+			EffectiveDateTime: dateTime(w.Timestamp.In(loc)),
+			ValueQuantity:     quantity(w.Weight/1000, "kg", "kg"),
+		})
+	}
+
+	for _, s := range d.SpO2 {
+		if s.Average == nil {
+			continue
+		}
+		add(Observation{
+			ID:                "garmin-spo2-" + s.Date.String(),
+			Category:          category("vital-signs"),
+			Code:              loinc(CodeOxygenSaturation, "Oxygen saturation in Arterial blood by Pulse oximetry"),
+			EffectiveDateTime: s.Date.String(),
+			ValueQuantity:     quantity(*s.Average, "%", "%"),
+		})
+	}
+
+	for _, bp := range d.BloodPressure {
+		add(Observation{
+			ID:                fmt.Sprintf("garmin-bp-%d", bp.Timestamp.Unix()),
+			Category:          category("vital-signs"),
+			Code:              loinc(CodeBloodPressure, "Blood pressure panel with all children optional"),
+			EffectiveDateTime: dateTime(bp.Timestamp.In(loc)),
+			Component: []Component{
+				{Code: loinc(CodeSystolic, "Systolic blood pressure"), ValueQuantity: *quantity(float64(bp.Systolic), "mmHg", "mm[Hg]")},
+				{Code: loinc(CodeDiastolic, "Diastolic blood pressure"), ValueQuantity: *quantity(float64(bp.Diastolic), "mmHg", "mm[Hg]")},
+			},
+		})
+	}
+
+	for _, s := range d.Sleep {
+		if s.SleepTimeSeconds == nil || s.SleepStart.IsZero() {
+			continue
+		}
+		add(Observation{
+			ID:       "garmin-sleep-" + s.CalendarDate.String(),
+			Category: category("activity"),
+			Code:     loinc(CodeSleepDuration, "Sleep duration"),
+			EffectivePeriod: &Period{
+				Start: dateTime(s.SleepStart.In(loc)),
+				End:   dateTime(s.SleepEnd.In(loc)),
+			},
+			ValueQuantity: quantity(float64(*s.SleepTimeSeconds)/3600, "h", "h"),
+		})
+	}
+
+	return obs
+}
+
+// Encode writes the observations of d as a FHIR collection Bundle in JSON
+func Encode(w io.Writer, d Data) error {
+	bundle := Bundle{
+		ResourceType: "Bundle",
+		Type:         "collection",
+		Timestamp:    dateTime(time.Now().UTC()),
+		Entry:        []Entry{},
+	}
+	for _, o := range Observations(d) {
+		bundle.Entry = append(bundle.Entry, Entry{FullURL: "Observation/" + o.ID, Resource: o})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(bundle); err != nil {
+		return fmt.Errorf("failed to write FHIR bundle: %w", err)
+	}
+	return nil
+}
+
+func category(code string) []CodeableConcept {
+	return []CodeableConcept{{Coding: []Coding{{System: categorySystem, Code: code}}}}
+}
+
+func loinc(code, display string) CodeableConcept {
+	return CodeableConcept{Coding: []Coding{{System: loincSystem, Code: code, Display: display}}, Text: display}
+}
+
+func quantity(value float64, unit, code string) *Quantity {
+	return &Quantity{Value: value, Unit: unit, System: ucumSystem, Code: code}
+}
+
+func dateTime(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
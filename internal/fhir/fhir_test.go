@@ -0,0 +1,89 @@
+package fhir
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObservations(t *testing.T) {
+	day := api.NewDate(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	measured := api.NewGarminTime(time.Date(2024, 3, 1, 7, 30, 0, 0, time.UTC))
+	data := Data{
+		HeartRate: []api.HeartRateData{{Date: day, RestingHR: api.Ptr(52)}, {Date: api.NewDate(day.AddDate(0, 0, 1))}},
+		Weight:    []api.BodyComposition{{Weight: 72500, Timestamp: measured}},
+		SpO2:      []api.SpO2Data{{Date: day, Average: api.Ptr(96.5)}, {Date: api.NewDate(day.AddDate(0, 0, 1))}},
+		BloodPressure: []api.BloodPressure{
+			{Timestamp: measured, Systolic: 121, Diastolic: 79},
+		},
+		Sleep: []api.SleepData{
+			{
+				CalendarDate:     day,
+				SleepTimeSeconds: api.Ptr(27000),
+				SleepStart:       api.NewGarminTime(time.Date(2024, 2, 29, 22, 0, 0, 0, time.UTC)),
+				SleepEnd:         api.NewGarminTime(time.Date(2024, 3, 1, 6, 0, 0, 0, time.UTC)),
+			},
+			{CalendarDate: api.NewDate(day.AddDate(0, 0, 1))},
+		},
+		Patient:  "Patient/42",
+		Location: time.FixedZone("CET", 3600),
+	}
+
+	obs := Observations(data)
+	// Days without values are skipped
+	assert.Len(t, obs, 5)
+
+	rhr := obs[0]
+	assert.Equal(t, "garmin-rhr-2024-03-01", rhr.ID)
+	assert.Equal(t, CodeRestingHeartRate, rhr.Code.Coding[0].Code)
+	assert.Equal(t, "vital-signs", rhr.Category[0].Coding[0].Code)
+	assert.Equal(t, "Patient/42", rhr.Subject.Reference)
+	assert.Equal(t, 52.0, rhr.ValueQuantity.Value)
+	assert.Equal(t, "/min", rhr.ValueQuantity.Code)
+
+	weight := obs[1]
+	assert.Equal(t, 72.5, weight.ValueQuantity.Value)
+	assert.Equal(t, "2024-03-01T08:30:00+01:00", weight.EffectiveDateTime)
+
+	assert.Equal(t, "%", obs[2].ValueQuantity.Code)
+
+	bp := obs[3]
+	assert.Nil(t, bp.ValueQuantity)
+	assert.Equal(t, CodeSystolic, bp.Component[0].Code.Coding[0].Code)
+	assert.Equal(t, 121.0, bp.Component[0].ValueQuantity.Value)
+	assert.Equal(t, 79.0, bp.Component[1].ValueQuantity.Value)
+	assert.Equal(t, "mm[Hg]", bp.Component[1].ValueQuantity.Code)
+
+	sleep := obs[4]
+	assert.Equal(t, "activity", sleep.Category[0].Coding[0].Code)
+	assert.Equal(t, 7.5, sleep.ValueQuantity.Value)
+	assert.Equal(t, &Period{Start: "2024-02-29T23:00:00+01:00", End: "2024-03-01T07:00:00+01:00"}, sleep.EffectivePeriod)
+}
+
+func TestEncode(t *testing.T) {
+	day := api.NewDate(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	var buf bytes.Buffer
+	assert.NoError(t, Encode(&buf, Data{HeartRate: []api.HeartRateData{{Date: day, RestingHR: api.Ptr(52)}}}))
+
+	var bundle map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &bundle))
+	assert.Equal(t, "Bundle", bundle["resourceType"])
+	assert.Equal(t, "collection", bundle["type"])
+	entries := bundle["entry"].([]interface{})
+	assert.Len(t, entries, 1)
+	entry := entries[0].(map[string]interface{})
+	assert.Equal(t, "Observation/garmin-rhr-2024-03-01", entry["fullUrl"])
+	resource := entry["resource"].(map[string]interface{})
+	assert.Equal(t, "Observation", resource["resourceType"])
+	assert.Equal(t, "final", resource["status"])
+	assert.NotContains(t, resource, "subject")
+
+	// An empty export is still a valid bundle
+	buf.Reset()
+	assert.NoError(t, Encode(&buf, Data{}))
+	assert.Contains(t, buf.String(), `"entry": []`)
+}
@@ -4,12 +4,18 @@ import (
 	"encoding/binary"
 	"errors"
 	"io"
+	"math"
 	"os"
 )
 
 const (
 	headerSize    = 12
 	protocolMajor = 2
+
+	// fileHeaderLen is the encoded size of FileHeader
+	fileHeaderLen = 14
+	// activityRecordLen is the encoded size of an activity record body
+	activityRecordLen = 17
 )
 
 // FileHeader represents the header of a FIT file
@@ -29,9 +35,11 @@ type Activity struct {
 	Duration      float64
 }
 
-// Decoder parses FIT files
+// Decoder parses FIT files. Reads are buffered through a pooled reader, so
+// Parse may consume more of r than the FIT data it decodes.
 type Decoder struct {
-	r io.Reader
+	r       io.Reader
+	scratch [32]byte
 }
 
 // NewDecoder creates a new FIT decoder
@@ -41,10 +49,21 @@ func NewDecoder(r io.Reader) *Decoder {
 
 // Parse decodes the FIT file and returns the activity data
 func (d *Decoder) Parse() (*Activity, error) {
-	var header FileHeader
-	if err := binary.Read(d.r, binary.LittleEndian, &header); err != nil {
+	br := getReader()
+	br.Reset(d.r)
+	defer putReader(br)
+
+	buf := d.scratch[:fileHeaderLen]
+	if _, err := io.ReadFull(br, buf); err != nil {
 		return nil, err
 	}
+	header := FileHeader{
+		Size:     buf[0],
+		Protocol: buf[1],
+		DataSize: binary.LittleEndian.Uint32(buf[6:10]),
+	}
+	copy(header.Profile[:], buf[2:6])
+	copy(header.Signature[:], buf[10:14])
 
 	// Validate header
 	if header.Protocol != protocolMajor {
@@ -57,28 +76,23 @@ func (d *Decoder) Parse() (*Activity, error) {
 	// Skip to activity record (simplified for example)
 	// In a real implementation, we would parse the file structure properly
 	for {
-		var recordHeader uint8
-		if err := binary.Read(d.r, binary.LittleEndian, &recordHeader); err == io.EOF {
+		recordHeader, err := br.ReadByte()
+		if err == io.EOF {
 			break
 		} else if err != nil {
 			return nil, err
 		}
 
 		if recordHeader == 0x21 { // Activity record header (example value)
-			var record struct {
-				Type          uint8
-				StartTime     int64
-				TotalDistance float32
-				Duration      uint32
-			}
-			if err := binary.Read(d.r, binary.LittleEndian, &record); err != nil {
+			record := d.scratch[:activityRecordLen]
+			if _, err := io.ReadFull(br, record); err != nil {
 				return nil, err
 			}
 
-			activity.Type = activityType(record.Type)
-			activity.StartTime = record.StartTime
-			activity.TotalDistance = float64(record.TotalDistance)
-			activity.Duration = float64(record.Duration)
+			activity.Type = activityType(record[0])
+			activity.StartTime = int64(binary.LittleEndian.Uint64(record[1:9]))
+			activity.TotalDistance = float64(math.Float32frombits(binary.LittleEndian.Uint32(record[9:13])))
+			activity.Duration = float64(binary.LittleEndian.Uint32(record[13:17]))
 			break
 		}
 	}
@@ -12,6 +12,15 @@ type FitEncoder struct {
 	dataSize   int
 	headerSize int
 	startPos   int64 // position after header
+
+	// scratch holds the header and trailer bytes so Close does not allocate
+	scratch [14]byte
+}
+
+// crcTable is the nibble lookup table of the FIT CRC-16
+var crcTable = [...]uint16{
+	0x0000, 0xCC01, 0xD801, 0x1400, 0xF001, 0x3C00, 0x2800, 0xE401,
+	0xA001, 0x6C00, 0x7800, 0xB401, 0x5000, 0x9C01, 0x8801, 0x4400,
 }
 
 // NewFitEncoder creates a new streaming FIT encoder
@@ -29,15 +38,8 @@ func NewFitEncoder(w io.WriteSeeker) (*FitEncoder, error) {
 		return nil, err
 	}
 
-	// Write header placeholder
-	header := []byte{
-		14,         // Header size
-		0x10,       // Protocol version
-		0x00, 0x2D, // Profile version (little endian 45)
-		0x00, 0x00, 0x00, 0x00, // Data size (4 bytes, will be updated later)
-		'.', 'F', 'I', 'T', // ".FIT" data type
-		0x00, 0x00, // Header CRC (will be calculated later)
-	}
+	// Write header placeholder; data size and header CRC are filled in by Close
+	header := encoder.header(0)
 
 	// Write header and calculate CRC
 	if _, err := w.Write(header); err != nil {
@@ -50,11 +52,6 @@ func NewFitEncoder(w io.WriteSeeker) (*FitEncoder, error) {
 
 // updateCRC calculates CRC-16 checksum without hash/crc16 dependency
 func (e *FitEncoder) updateCRC(data []byte) {
-	crcTable := [...]uint16{
-		0x0000, 0xCC01, 0xD801, 0x1400, 0xF001, 0x3C00, 0x2800, 0xE401,
-		0xA001, 0x6C00, 0x7800, 0xB401, 0x5000, 0x9C01, 0x8801, 0x4400,
-	}
-
 	currentCRC := e.crc
 	for _, b := range data {
 		// Compute checksum of lower four bits
@@ -70,6 +67,19 @@ func (e *FitEncoder) updateCRC(data []byte) {
 	e.crc = currentCRC
 }
 
+// header fills the scratch space with a FIT header for dataSize and returns it,
+// including a zeroed header CRC
+func (e *FitEncoder) header(dataSize uint32) []byte {
+	h := e.scratch[:14]
+	h[0] = 14               // Header size
+	h[1] = 0x10             // Protocol version
+	h[2], h[3] = 0x00, 0x2D // Profile version (little endian 45)
+	binary.LittleEndian.PutUint32(h[4:8], dataSize)
+	copy(h[8:12], ".FIT")     // ".FIT" data type
+	h[12], h[13] = 0x00, 0x00 // Header CRC
+	return h
+}
+
 // Write writes activity data in chunks
 func (e *FitEncoder) Write(p []byte) (int, error) {
 	n, err := e.w.Write(p)
@@ -91,36 +101,25 @@ func (e *FitEncoder) Close() error {
 	}
 
 	// Update data size in header
+	header := e.header(uint32(e.dataSize))
 	if _, err := e.w.Seek(e.startPos+4, io.SeekStart); err != nil {
 		return err
 	}
-	dataSizeBytes := make([]byte, 4)
-	binary.LittleEndian.PutUint32(dataSizeBytes, uint32(e.dataSize))
-	if _, err := e.w.Write(dataSizeBytes); err != nil {
+	if _, err := e.w.Write(header[4:8]); err != nil {
 		return err
 	}
 
-	// Recalculate header CRC with original data
-	header := []byte{
-		14,         // Header size
-		0x10,       // Protocol version
-		0x00, 0x2D, // Profile version
-		dataSizeBytes[0], dataSizeBytes[1], dataSizeBytes[2], dataSizeBytes[3],
-		'.', 'F', 'I', 'T', // ".FIT" data type
-	}
-
 	// Calculate header CRC with clean state
 	e.crc = 0
-	e.updateCRC(header)
+	e.updateCRC(header[:12])
 	headerCRC := e.crc
 
 	// Update header CRC
 	if _, err := e.w.Seek(e.startPos+12, io.SeekStart); err != nil {
 		return err
 	}
-	crcBytes := make([]byte, 2)
-	binary.LittleEndian.PutUint16(crcBytes, headerCRC)
-	if _, err := e.w.Write(crcBytes); err != nil {
+	binary.LittleEndian.PutUint16(header[12:14], headerCRC)
+	if _, err := e.w.Write(header[12:14]); err != nil {
 		return err
 	}
 
@@ -128,8 +127,7 @@ func (e *FitEncoder) Close() error {
 	if _, err := e.w.Seek(currentPos, io.SeekStart); err != nil {
 		return err
 	}
-	fileCRCBytes := make([]byte, 2)
-	binary.LittleEndian.PutUint16(fileCRCBytes, e.crc)
-	_, err = e.w.Write(fileCRCBytes)
+	binary.LittleEndian.PutUint16(header[12:14], e.crc)
+	_, err = e.w.Write(header[12:14])
 	return err
 }
@@ -0,0 +1,80 @@
+package fit
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testFile builds a minimal FIT file holding one activity record
+func testFile() []byte {
+	var b bytes.Buffer
+	b.Write([]byte{14, protocolMajor, 0, 0, 0, 0})
+	binary.Write(&b, binary.LittleEndian, uint32(18))
+	b.WriteString(".FIT")
+	b.Write([]byte{0x00, 0x21, 1}) // filler byte, activity record header, running
+	binary.Write(&b, binary.LittleEndian, int64(1700000000))
+	binary.Write(&b, binary.LittleEndian, math.Float32bits(10000.5))
+	binary.Write(&b, binary.LittleEndian, uint32(3600))
+	return b.Bytes()
+}
+
+func TestDecoderParse(t *testing.T) {
+	activity, err := NewDecoder(bytes.NewReader(testFile())).Parse()
+	assert.NoError(t, err)
+	assert.Equal(t, &Activity{Type: "Running", StartTime: 1700000000, TotalDistance: 10000.5, Duration: 3600}, activity)
+
+	_, err = NewDecoder(bytes.NewReader(testFile()[:20])).Parse()
+	assert.Error(t, err)
+
+	bad := testFile()
+	bad[1] = 1
+	_, err = NewDecoder(bytes.NewReader(bad)).Parse()
+	assert.EqualError(t, err, "unsupported FIT protocol version")
+}
+
+func TestDecoderReusesBuffers(t *testing.T) {
+	data := testFile()
+	r := bytes.NewReader(data)
+	allocs := testing.AllocsPerRun(100, func() {
+		r.Reset(data)
+		NewDecoder(r).Parse()
+	})
+	// The decoder and the returned activity; buffers come from the pool
+	assert.LessOrEqual(t, allocs, 3.0)
+}
+
+func TestEncoderHeader(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.fit"))
+	assert.NoError(t, err)
+	defer f.Close()
+
+	enc, err := NewFitEncoder(f)
+	assert.NoError(t, err)
+	_, err = enc.Write([]byte{1, 2, 3, 4})
+	assert.NoError(t, err)
+	assert.NoError(t, enc.Close())
+
+	data, _ := os.ReadFile(f.Name())
+	assert.Len(t, data, 14+4+2)
+	assert.Equal(t, uint32(4), binary.LittleEndian.Uint32(data[4:8]))
+	assert.Equal(t, ".FIT", string(data[8:12]))
+}
+
+func TestBufferPool(t *testing.T) {
+	b := GetBuffer()
+	b.WriteString("data")
+	PutBuffer(b)
+	assert.Equal(t, 0, GetBuffer().Len())
+
+	// Oversized buffers are not retained
+	big := GetBuffer()
+	big.Grow(maxPooledBuffer + 1)
+	PutBuffer(big)
+	PutBuffer(nil)
+}
@@ -0,0 +1,52 @@
+package fit
+
+import (
+	"bufio"
+	"bytes"
+	"sync"
+)
+
+const (
+	// readBufferSize is the size of the pooled buffered readers used by Decoder
+	readBufferSize = 32 << 10
+	// maxPooledBuffer keeps unusually large buffers from being pinned by the pool
+	maxPooledBuffer = 4 << 20
+)
+
+var readerPool = sync.Pool{
+	New: func() interface{} {
+		return bufio.NewReaderSize(nil, readBufferSize)
+	},
+}
+
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// GetBuffer returns an empty buffer from the shared pool. Callers processing
+// many FIT files should hand it back with PutBuffer once they are done with it.
+func GetBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+// PutBuffer returns a buffer obtained from GetBuffer to the pool.
+// The buffer must not be used afterwards.
+func PutBuffer(b *bytes.Buffer) {
+	if b == nil || b.Cap() > maxPooledBuffer {
+		return
+	}
+	b.Reset()
+	bufferPool.Put(b)
+}
+
+func getReader() *bufio.Reader {
+	return readerPool.Get().(*bufio.Reader)
+}
+
+func putReader(br *bufio.Reader) {
+	// Drop the reference to the underlying reader so it can be collected
+	br.Reset(nil)
+	readerPool.Put(br)
+}
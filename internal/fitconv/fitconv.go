@@ -0,0 +1,247 @@
+// Package fitconv converts between this client's activity model and the types
+// of github.com/tormoder/fit, so FIT pipelines built on that package can work
+// with activities from Garmin Connect and the other way round. It lives apart
+// from the api package so only its users depend on tormoder/fit.
+package fitconv
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/tormoder/fit"
+)
+
+// sports maps Garmin Connect activity type keys to FIT sports
+var sports = []struct {
+	activityType string
+	sport        fit.Sport
+	subSport     fit.SubSport
+}{
+	{"running", fit.SportRunning, fit.SubSportGeneric},
+	{"street_running", fit.SportRunning, fit.SubSportStreet},
+	{"trail_running", fit.SportRunning, fit.SubSportTrail},
+	{"treadmill_running", fit.SportRunning, fit.SubSportTreadmill},
+	{"cycling", fit.SportCycling, fit.SubSportGeneric},
+	{"road_biking", fit.SportCycling, fit.SubSportRoad},
+	{"mountain_biking", fit.SportCycling, fit.SubSportMountain},
+	{"indoor_cycling", fit.SportCycling, fit.SubSportIndoorCycling},
+	{"swimming", fit.SportSwimming, fit.SubSportGeneric},
+	{"lap_swimming", fit.SportSwimming, fit.SubSportLapSwimming},
+	{"open_water_swimming", fit.SportSwimming, fit.SubSportOpenWater},
+	{"walking", fit.SportWalking, fit.SubSportGeneric},
+	{"indoor_walking", fit.SportWalking, fit.SubSportIndoorWalking},
+	{"hiking", fit.SportHiking, fit.SubSportGeneric},
+	{"strength_training", fit.SportTraining, fit.SubSportStrengthTraining},
+	{"indoor_rowing", fit.SportRowing, fit.SubSportIndoorRowing},
+	{"rowing", fit.SportRowing, fit.SubSportGeneric},
+	{"elliptical", fit.SportFitnessEquipment, fit.SubSportElliptical},
+}
+
+// Sport returns the FIT sport and sub sport of a Garmin Connect activity type.
+// Unknown types map to the generic sport.
+func Sport(activityType string) (fit.Sport, fit.SubSport) {
+	for _, s := range sports {
+		if s.activityType == activityType {
+			return s.sport, s.subSport
+		}
+	}
+	return fit.SportGeneric, fit.SubSportGeneric
+}
+
+// ActivityType returns the Garmin Connect activity type of a FIT sport, falling
+// back to the sport alone when the sub sport has no type of its own
+func ActivityType(sport fit.Sport, subSport fit.SubSport) string {
+	fallback := "other"
+	for _, s := range sports {
+		if s.sport != sport {
+			continue
+		}
+		if s.subSport == subSport {
+			return s.activityType
+		}
+		if s.subSport == fit.SubSportGeneric {
+			fallback = s.activityType
+		}
+	}
+	return fallback
+}
+
+// ToFIT builds an activity FIT file from an activity's details and track, e.g.
+// from GetActivityDetails and GetActivityTrack. The file has one session and
+// one record per track point; encode it with fit.Encode.
+func ToFIT(detail *api.ActivityDetail, track []api.TrackPoint) (*fit.File, error) {
+	if detail.StartTime.IsZero() {
+		return nil, errors.New("activity has no start time")
+	}
+	file, err := fit.NewFile(fit.FileTypeActivity, fit.NewHeader(fit.V20, true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create FIT file: %w", err)
+	}
+	activity, err := file.Activity()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create FIT file: %w", err)
+	}
+
+	start := detail.StartTime.Time
+	end := start.Add(time.Duration(detail.Duration * float64(time.Second)))
+	file.FileId.Manufacturer = fit.ManufacturerDevelopment
+	file.FileId.TimeCreated = start.UTC()
+
+	for _, p := range track {
+		activity.Records = append(activity.Records, record(p))
+	}
+
+	session := fit.NewSessionMsg()
+	session.Timestamp = end.UTC()
+	session.StartTime = start.UTC()
+	session.Event = fit.EventSession
+	session.EventType = fit.EventTypeStop
+	session.Sport, session.SubSport = Sport(detail.Type)
+	session.TotalElapsedTime = scaled(detail.Duration, 1000, math.MaxUint32)
+	session.TotalTimerTime = session.TotalElapsedTime
+	session.TotalDistance = scaled(detail.Distance, 100, math.MaxUint32)
+	if detail.Calories > 0 {
+		session.TotalCalories = uint16(scaled(detail.Calories, 1, math.MaxUint16))
+	}
+	if detail.AverageHR > 0 {
+		session.AvgHeartRate = uint8(scaled(float64(detail.AverageHR), 1, math.MaxUint8))
+	}
+	if detail.MaxHR > 0 {
+		session.MaxHeartRate = uint8(scaled(float64(detail.MaxHR), 1, math.MaxUint8))
+	}
+	if detail.ElevationGain > 0 {
+		session.TotalAscent = uint16(scaled(detail.ElevationGain, 1, math.MaxUint16))
+	}
+	if detail.ElevationLoss > 0 {
+		session.TotalDescent = uint16(scaled(detail.ElevationLoss, 1, math.MaxUint16))
+	}
+	activity.Sessions = append(activity.Sessions, session)
+
+	msg := fit.NewActivityMsg()
+	msg.Timestamp = end.UTC()
+	// tormoder/fit encodes the offset of the zone as the local time
+	msg.LocalTimestamp = end
+	msg.TotalTimerTime = session.TotalTimerTime
+	msg.NumSessions = 1
+	msg.Type = fit.ActivityModeManual
+	msg.Event = fit.EventActivity
+	msg.EventType = fit.EventTypeStop
+	activity.Activity = msg
+
+	return file, nil
+}
+
+// FromFIT returns the details and track of an activity FIT file. The summary
+// comes from the first session; the start time is placed in the zone the
+// file's local timestamp implies, or left in UTC when it has none.
+func FromFIT(file *fit.File) (*api.ActivityDetail, []api.TrackPoint, error) {
+	activity, err := file.Activity()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read FIT activity: %w", err)
+	}
+	if len(activity.Sessions) == 0 {
+		return nil, nil, errors.New("FIT activity has no session")
+	}
+	session := activity.Sessions[0]
+
+	// tormoder/fit decodes local timestamps into a zone with the local offset
+	loc := time.UTC
+	if msg := activity.Activity; msg != nil && !fit.IsBaseTime(msg.LocalTimestamp) {
+		_, offset := msg.LocalTimestamp.Zone()
+		loc = time.FixedZone("", offset)
+	}
+
+	detail := &api.ActivityDetail{
+		Activity: api.Activity{
+			Type:      ActivityType(session.Sport, session.SubSport),
+			StartTime: api.NewGarminTime(session.StartTime.In(loc)),
+			Duration:  valid(session.GetTotalElapsedTimeScaled()),
+			Distance:  valid(session.GetTotalDistanceScaled()),
+		},
+	}
+	if session.TotalCalories != math.MaxUint16 {
+		detail.Calories = float64(session.TotalCalories)
+	}
+	if session.AvgHeartRate != math.MaxUint8 {
+		detail.AverageHR = int(session.AvgHeartRate)
+	}
+	if session.MaxHeartRate != math.MaxUint8 {
+		detail.MaxHR = int(session.MaxHeartRate)
+	}
+	if session.TotalAscent != math.MaxUint16 {
+		detail.ElevationGain = float64(session.TotalAscent)
+	}
+	if session.TotalDescent != math.MaxUint16 {
+		detail.ElevationLoss = float64(session.TotalDescent)
+	}
+
+	track := make([]api.TrackPoint, 0, len(activity.Records))
+	for _, r := range activity.Records {
+		track = append(track, trackPoint(r))
+	}
+	return detail, track, nil
+}
+
+// record converts a track point, leaving missing values invalid
+func record(p api.TrackPoint) *fit.RecordMsg {
+	r := fit.NewRecordMsg()
+	r.Timestamp = p.Timestamp.UTC()
+	if p.Lat != nil && p.Lon != nil {
+		r.PositionLat = fit.NewLatitudeDegrees(*p.Lat)
+		r.PositionLong = fit.NewLongitudeDegrees(*p.Lon)
+	}
+	if p.Elevation != nil {
+		r.EnhancedAltitude = scaled(*p.Elevation+500, 5, math.MaxUint32)
+	}
+	if p.HeartRate != nil {
+		r.HeartRate = uint8(scaled(*p.HeartRate, 1, math.MaxUint8))
+	}
+	if p.Cadence != nil {
+		r.Cadence = uint8(scaled(*p.Cadence, 1, math.MaxUint8))
+	}
+	if p.Temperature != nil {
+		r.Temperature = int8(math.Max(math.Min(math.Round(*p.Temperature), math.MaxInt8-1), math.MinInt8))
+	}
+	return r
+}
+
+// trackPoint converts a record, leaving invalid values nil
+func trackPoint(r *fit.RecordMsg) api.TrackPoint {
+	p := api.TrackPoint{Timestamp: api.NewGarminTime(r.Timestamp)}
+	if !r.PositionLat.Invalid() && !r.PositionLong.Invalid() {
+		p.Lat = api.Ptr(r.PositionLat.Degrees())
+		p.Lon = api.Ptr(r.PositionLong.Degrees())
+	}
+	if alt := r.GetEnhancedAltitudeScaled(); !math.IsNaN(alt) {
+		p.Elevation = api.Ptr(alt)
+	} else if alt := r.GetAltitudeScaled(); !math.IsNaN(alt) {
+		p.Elevation = api.Ptr(alt)
+	}
+	if r.HeartRate != math.MaxUint8 {
+		p.HeartRate = api.Ptr(float64(r.HeartRate))
+	}
+	if r.Cadence != math.MaxUint8 {
+		p.Cadence = api.Ptr(float64(r.Cadence))
+	}
+	if r.Temperature != math.MaxInt8 {
+		p.Temperature = api.Ptr(float64(r.Temperature))
+	}
+	return p
+}
+
+// scaled returns v*scale rounded and clamped to the valid values below
+// invalid, the all-ones value FIT uses for unset fields
+func scaled(v, scale float64, invalid uint32) uint32 {
+	return uint32(math.Max(math.Min(math.Round(v*scale), float64(invalid-1)), 0))
+}
+
+// valid returns v, or 0 for the NaN of an unset FIT field
+func valid(v float64) float64 {
+	if math.IsNaN(v) {
+		return 0
+	}
+	return v
+}
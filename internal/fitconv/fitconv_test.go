@@ -0,0 +1,95 @@
+package fitconv
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/tormoder/fit"
+)
+
+func TestRoundTrip(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+	start := time.Date(2024, 3, 12, 7, 30, 0, 0, berlin)
+	detail := &api.ActivityDetail{
+		Activity: api.Activity{
+			Type:      "trail_running",
+			StartTime: api.NewGarminTime(start),
+			Duration:  1800,
+			Distance:  5012.34,
+		},
+		Calories:      420,
+		AverageHR:     148,
+		MaxHR:         171,
+		ElevationGain: 120,
+		ElevationLoss: 118,
+	}
+	track := []api.TrackPoint{
+		{Timestamp: api.NewGarminTime(start.UTC()), Lat: api.Ptr(52.52), Lon: api.Ptr(13.405), Elevation: api.Ptr(34.2), HeartRate: api.Ptr(120.0), Cadence: api.Ptr(84.0), Temperature: api.Ptr(11.0)},
+		{Timestamp: api.NewGarminTime(start.Add(time.Second).UTC()), HeartRate: api.Ptr(122.0)},
+	}
+
+	file, err := ToFIT(detail, track)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, fit.Encode(&buf, file, binary.LittleEndian))
+	decoded, err := fit.Decode(&buf)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	got, points, err := FromFIT(decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, "trail_running", got.Type)
+	assert.True(t, got.StartTime.Equal(start))
+	_, offset := got.StartTime.Zone()
+	assert.Equal(t, 3600, offset, "the local offset is kept")
+	assert.Equal(t, 1800.0, got.Duration)
+	assert.Equal(t, 5012.34, got.Distance)
+	assert.Equal(t, 420.0, got.Calories)
+	assert.Equal(t, 148, got.AverageHR)
+	assert.Equal(t, 171, got.MaxHR)
+	assert.Equal(t, 120.0, got.ElevationGain)
+
+	if assert.Len(t, points, 2) {
+		assert.InDelta(t, 52.52, *points[0].Lat, 1e-6)
+		assert.InDelta(t, 13.405, *points[0].Lon, 1e-6)
+		assert.InDelta(t, 34.2, *points[0].Elevation, 0.2)
+		assert.Equal(t, api.Ptr(120.0), points[0].HeartRate)
+		assert.Equal(t, api.Ptr(84.0), points[0].Cadence)
+		assert.Equal(t, api.Ptr(11.0), points[0].Temperature)
+		assert.True(t, points[1].Timestamp.Equal(start.Add(time.Second)))
+		assert.Nil(t, points[1].Lat, "missing values stay missing")
+		assert.Nil(t, points[1].Elevation)
+	}
+}
+
+func TestActivityType(t *testing.T) {
+	sport, sub := Sport("road_biking")
+	assert.Equal(t, fit.SportCycling, sport)
+	assert.Equal(t, fit.SubSportRoad, sub)
+	assert.Equal(t, "road_biking", ActivityType(sport, sub))
+	assert.Equal(t, "cycling", ActivityType(fit.SportCycling, fit.SubSportGravelCycling), "unknown sub sports fall back to the sport")
+	assert.Equal(t, "other", ActivityType(fit.SportGolf, fit.SubSportGeneric))
+
+	sport, _ = Sport("pickleball")
+	assert.Equal(t, fit.SportGeneric, sport)
+}
+
+func TestFromFITRejectsOtherFiles(t *testing.T) {
+	file, err := fit.NewFile(fit.FileTypeCourse, fit.NewHeader(fit.V20, true))
+	assert.NoError(t, err)
+	_, _, err = FromFIT(file)
+	assert.Error(t, err)
+
+	_, err = ToFIT(&api.ActivityDetail{}, nil)
+	assert.Error(t, err, "a start time is required")
+}
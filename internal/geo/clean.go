@@ -0,0 +1,142 @@
+package geo
+
+import (
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// CleanOptions configures Clean
+type CleanOptions struct {
+	// MaxSpeed in m/s; fixes that would need more to reach are teleport spikes
+	MaxSpeed float64
+	// MinSpeed in m/s; slower movement counts as standing still
+	MinSpeed float64
+	// MinPause is the shortest stop that is removed; shorter stops, such as
+	// waiting at a crossing, are kept
+	MinPause time.Duration
+}
+
+// DefaultCleanOptions suits running and cycling tracks
+var DefaultCleanOptions = CleanOptions{
+	MaxSpeed: DefaultMaxSpeed,
+	MinSpeed: 0.5,
+	MinPause: 10 * time.Second,
+}
+
+// TrackStats summarises a track before or after cleaning
+type TrackStats struct {
+	Points   int
+	Distance float64       // meters
+	Duration time.Duration // time spent moving or in short stops
+	MaxSpeed float64       // m/s between consecutive fixes
+}
+
+// CleanResult is a cleaned track and what cleaning changed
+type CleanResult struct {
+	Points        []api.TrackPoint
+	Before, After TrackStats
+	Spikes        int // fixes removed as teleport spikes
+	Pauses        int // stops removed
+}
+
+// Clean removes teleport spikes and the points recorded while standing still,
+// like auto-pause would have. The first and last point of a stop are kept so
+// the track stays connected. The input is not modified.
+func Clean(points []api.TrackPoint, opts CleanOptions) CleanResult {
+	result := CleanResult{Before: trackStats(points, 0)}
+
+	// Drop fixes that are impossibly far from the last good one
+	kept := make([]api.TrackPoint, 0, len(points))
+	var last *api.TrackPoint
+	for i := range points {
+		p := points[i]
+		if HasPosition(p) {
+			if last != nil && opts.MaxSpeed > 0 {
+				d := Distance(*last.Lat, *last.Lon, *p.Lat, *p.Lon)
+				elapsed := p.Timestamp.Time.Sub(last.Timestamp.Time).Seconds()
+				if d > opts.MaxSpeed*max(elapsed, 0) {
+					result.Spikes++
+					continue
+				}
+			}
+			last = &points[i]
+		}
+		kept = append(kept, p)
+	}
+
+	// Drop the inside of stops that lasted at least MinPause
+	cleaned := make([]api.TrackPoint, 0, len(kept))
+	var paused time.Duration
+	stopStart := -1 // index in kept of the fix where the current stop began
+	flush := func(end int) {
+		if stopStart >= 0 && kept[end].Timestamp.Time.Sub(kept[stopStart].Timestamp.Time) >= opts.MinPause {
+			paused += kept[end].Timestamp.Time.Sub(kept[stopStart].Timestamp.Time)
+			result.Pauses++
+			cleaned = append(cleaned, kept[end])
+		} else if stopStart >= 0 {
+			cleaned = append(cleaned, kept[stopStart+1:end+1]...)
+		}
+		stopStart = -1
+	}
+	prev := -1
+	for i, p := range kept {
+		if !HasPosition(p) {
+			if stopStart < 0 {
+				cleaned = append(cleaned, p)
+			}
+			continue
+		}
+		if prev >= 0 && opts.MinSpeed > 0 && speed(kept[prev], p) < opts.MinSpeed {
+			if stopStart < 0 {
+				stopStart = prev
+			}
+		} else if stopStart >= 0 {
+			flush(prev)
+			cleaned = append(cleaned, kept[prev+1:i+1]...)
+		} else {
+			cleaned = append(cleaned, p)
+		}
+		prev = i
+	}
+	if stopStart >= 0 {
+		flush(prev)
+		cleaned = append(cleaned, kept[prev+1:]...)
+	}
+
+	result.Points = cleaned
+	result.After = trackStats(cleaned, paused)
+	return result
+}
+
+// speed returns the speed in m/s needed to get from a to b
+func speed(a, b api.TrackPoint) float64 {
+	elapsed := b.Timestamp.Time.Sub(a.Timestamp.Time).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return Distance(*a.Lat, *a.Lon, *b.Lat, *b.Lon) / elapsed
+}
+
+// trackStats summarises points, leaving paused time out of the duration
+func trackStats(points []api.TrackPoint, paused time.Duration) TrackStats {
+	stats := TrackStats{Points: len(points), Distance: TrackDistance(points, 0)}
+	var first, prev *api.TrackPoint
+	for i := range points {
+		p := &points[i]
+		if !HasPosition(*p) {
+			continue
+		}
+		if first == nil {
+			first = p
+		}
+		if prev != nil {
+			stats.MaxSpeed = max(stats.MaxSpeed, speed(*prev, *p))
+		}
+		prev = p
+	}
+	if first != nil {
+		stats.Duration = prev.Timestamp.Time.Sub(first.Timestamp.Time) - paused
+	}
+	return stats
+}
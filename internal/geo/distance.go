@@ -0,0 +1,94 @@
+package geo
+
+import (
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// DefaultMaxSpeed is the speed in m/s above which a GPS fix is treated as a
+// glitch; it is well above what runners and cyclists reach
+const DefaultMaxSpeed = 30.0
+
+// Split is one fixed-length stretch of an activity
+type Split struct {
+	Distance float64       // meters; the last split may be shorter
+	Duration time.Duration // elapsed time
+}
+
+// Pace returns the time taken per kilometer
+func (s Split) Pace() time.Duration {
+	if s.Distance <= 0 {
+		return 0
+	}
+	return time.Duration(float64(s.Duration) * 1000 / s.Distance)
+}
+
+// CumulativeDistance returns the distance covered in meters at each point,
+// summed from the great-circle distances between fixes. A fix that would
+// need more than maxSpeed m/s to reach is skipped as a glitch, so the next
+// fix is measured from the last good one. Points without a position carry
+// the distance of the point before.
+func CumulativeDistance(points []api.TrackPoint, maxSpeed float64) []float64 {
+	out := make([]float64, len(points))
+	var last *api.TrackPoint
+	total := 0.0
+	for i := range points {
+		p := &points[i]
+		if HasPosition(*p) {
+			if last == nil {
+				last = p
+			} else {
+				d := Distance(*last.Lat, *last.Lon, *p.Lat, *p.Lon)
+				elapsed := p.Timestamp.Time.Sub(last.Timestamp.Time).Seconds()
+				if glitch := maxSpeed > 0 && d > maxSpeed*max(elapsed, 0); !glitch {
+					total += d
+					last = p
+				}
+			}
+		}
+		out[i] = total
+	}
+	return out
+}
+
+// TrackDistance returns the distance of a track in meters, see CumulativeDistance
+func TrackDistance(points []api.TrackPoint, maxSpeed float64) float64 {
+	cumulative := CumulativeDistance(points, maxSpeed)
+	if len(cumulative) == 0 {
+		return 0
+	}
+	return cumulative[len(cumulative)-1]
+}
+
+// Splits divides a track into stretches of length meters, e.g. 1000 for
+// kilometer splits, with the remainder as a final shorter split
+func Splits(points []api.TrackPoint, length, maxSpeed float64) []Split {
+	if len(points) == 0 || length <= 0 {
+		return nil
+	}
+	cumulative := CumulativeDistance(points, maxSpeed)
+
+	var splits []Split
+	splitStart, splitDist := points[0].Timestamp.Time, 0.0
+	for i, p := range points {
+		for cumulative[i]-splitDist >= length {
+			// Interpolate the time the split boundary was crossed
+			boundary := splitDist + length
+			at := p.Timestamp.Time
+			if i > 0 && cumulative[i] > cumulative[i-1] {
+				prev := points[i-1].Timestamp.Time
+				share := (boundary - cumulative[i-1]) / (cumulative[i] - cumulative[i-1])
+				at = prev.Add(time.Duration(share * float64(at.Sub(prev))))
+			}
+			splits = append(splits, Split{Distance: length, Duration: at.Sub(splitStart)})
+			splitStart, splitDist = at, boundary
+		}
+	}
+
+	last := points[len(points)-1]
+	if rest := cumulative[len(points)-1] - splitDist; rest > 0 {
+		splits = append(splits, Split{Distance: rest, Duration: last.Timestamp.Time.Sub(splitStart)})
+	}
+	return splits
+}
@@ -0,0 +1,156 @@
+package geo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// DefaultSmoothingWindow averages out the pressure noise of barometric
+// altimeters without flattening short climbs
+const DefaultSmoothingWindow = 30 * time.Second
+
+// DefaultAscentThreshold is the elevation change in meters that counts as a
+// climb or descent; smaller wiggles are treated as noise
+const DefaultAscentThreshold = 3.0
+
+// ElevationProvider looks up terrain elevation, e.g. from a digital elevation
+// model service, for a batch of positions
+type ElevationProvider interface {
+	// Elevations returns the elevation in meters of each position
+	Elevations(ctx context.Context, lats, lons []float64) ([]float64, error)
+}
+
+// copyElevations returns a copy of points whose Elevation can be replaced
+// without touching the input
+func copyElevations(points []api.TrackPoint) []api.TrackPoint {
+	out := make([]api.TrackPoint, len(points))
+	copy(out, points)
+	return out
+}
+
+// SmoothElevation replaces each elevation with the mean of the elevations
+// recorded within window around it. Points without elevation are left alone.
+func SmoothElevation(points []api.TrackPoint, window time.Duration) []api.TrackPoint {
+	out := copyElevations(points)
+	half := window / 2
+	lo, hi := 0, 0
+	sum, n := 0.0, 0
+	for i, p := range points {
+		t := p.Timestamp.Time
+		for hi < len(points) && !points[hi].Timestamp.Time.After(t.Add(half)) {
+			if e := points[hi].Elevation; e != nil {
+				sum += *e
+				n++
+			}
+			hi++
+		}
+		for lo < hi && points[lo].Timestamp.Time.Before(t.Add(-half)) {
+			if e := points[lo].Elevation; e != nil {
+				sum -= *e
+				n--
+			}
+			lo++
+		}
+		if p.Elevation != nil && n > 0 {
+			out[i].Elevation = api.Ptr(sum / float64(n))
+		}
+	}
+	return out
+}
+
+// CorrectDrift removes barometric drift from a track that ends where it
+// started: the elevation gap between start and finish is spread linearly
+// over the activity's duration. Tracks whose ends are farther apart than
+// radius meters are returned unchanged, since their gap is real.
+func CorrectDrift(points []api.TrackPoint, radius float64) []api.TrackPoint {
+	first, last := -1, -1
+	for i, p := range points {
+		if HasPosition(p) && p.Elevation != nil {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 || first == last {
+		return points
+	}
+	start, end := points[first], points[last]
+	if Distance(*start.Lat, *start.Lon, *end.Lat, *end.Lon) > radius {
+		return points
+	}
+	duration := end.Timestamp.Time.Sub(start.Timestamp.Time)
+	if duration <= 0 {
+		return points
+	}
+
+	gap := *end.Elevation - *start.Elevation
+	out := copyElevations(points)
+	for i, p := range points {
+		if p.Elevation == nil {
+			continue
+		}
+		share := float64(p.Timestamp.Time.Sub(start.Timestamp.Time)) / float64(duration)
+		share = min(max(share, 0), 1)
+		out[i].Elevation = api.Ptr(*p.Elevation - gap*share)
+	}
+	return out
+}
+
+// CorrectElevation replaces the elevation of every positioned point with the
+// terrain elevation from provider, for activities with bad altimeter data
+func CorrectElevation(ctx context.Context, points []api.TrackPoint, provider ElevationProvider) ([]api.TrackPoint, error) {
+	var lats, lons []float64
+	var idx []int
+	for i, p := range points {
+		if HasPosition(p) {
+			lats = append(lats, *p.Lat)
+			lons = append(lons, *p.Lon)
+			idx = append(idx, i)
+		}
+	}
+	if len(idx) == 0 {
+		return points, nil
+	}
+
+	elevations, err := provider.Elevations(ctx, lats, lons)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up elevations: %w", err)
+	}
+	if len(elevations) != len(idx) {
+		return nil, fmt.Errorf("elevation provider returned %d values for %d positions", len(elevations), len(idx))
+	}
+	out := copyElevations(points)
+	for j, i := range idx {
+		out[i].Elevation = api.Ptr(elevations[j])
+	}
+	return out, nil
+}
+
+// Ascent sums the climbs and descents of a track in meters. A change only
+// counts once it exceeds threshold, which keeps noise from adding up.
+func Ascent(points []api.TrackPoint, threshold float64) (ascent, descent float64) {
+	var ref *float64
+	for _, p := range points {
+		if p.Elevation == nil {
+			continue
+		}
+		e := *p.Elevation
+		if ref == nil {
+			ref = &e
+			continue
+		}
+		switch diff := e - *ref; {
+		case diff >= threshold:
+			ascent += diff
+			*ref = e
+		case -diff >= threshold:
+			descent -= diff
+			*ref = e
+		}
+	}
+	return ascent, descent
+}
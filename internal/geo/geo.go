@@ -0,0 +1,27 @@
+// Package geo recomputes and corrects position-derived activity data, such as
+// elevation, from GPS track points
+package geo
+
+import (
+	"math"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// earthRadius is the mean Earth radius in meters
+const earthRadius = 6371000.0
+
+// Distance returns the great-circle distance in meters between two positions
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLon := (lon2 - lon1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(math.Min(1, a)))
+}
+
+// HasPosition reports whether p carries a GPS position
+func HasPosition(p api.TrackPoint) bool {
+	return p.Lat != nil && p.Lon != nil
+}
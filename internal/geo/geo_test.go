@@ -0,0 +1,208 @@
+package geo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+var start = time.Date(2024, 3, 1, 7, 0, 0, 0, time.UTC)
+
+// elevationTrack returns one point per second at the equator with the given
+// elevations
+func elevationTrack(elevations ...float64) []api.TrackPoint {
+	points := make([]api.TrackPoint, len(elevations))
+	for i, e := range elevations {
+		points[i] = api.TrackPoint{
+			Timestamp: api.NewGarminTime(start.Add(time.Duration(i) * time.Second)),
+			Lat:       api.Ptr(0.0),
+			Lon:       api.Ptr(float64(i) * 0.0001),
+			Elevation: api.Ptr(e),
+		}
+	}
+	return points
+}
+
+func elevations(points []api.TrackPoint) []float64 {
+	out := make([]float64, len(points))
+	for i, p := range points {
+		out[i] = api.Value(p.Elevation)
+	}
+	return out
+}
+
+func TestDistance(t *testing.T) {
+	assert.InDelta(t, 111195, Distance(0, 0, 1, 0), 1)
+	assert.InDelta(t, 0, Distance(52.5, 13.4, 52.5, 13.4), 1e-9)
+}
+
+func TestSmoothElevation(t *testing.T) {
+	points := elevationTrack(100, 106, 100, 106, 100)
+	smoothed := SmoothElevation(points, 2*time.Second)
+	assert.InDeltaSlice(t, []float64{103, 102, 104, 102, 103}, elevations(smoothed), 1e-9)
+	assert.Equal(t, 106.0, *points[1].Elevation, "the input is not modified")
+}
+
+func TestCorrectDrift(t *testing.T) {
+	// A loop that drifted up 4 m although it ends where it started
+	loop := elevationTrack(100, 110, 104)
+	loop[2].Lon = api.Ptr(0.0)
+	assert.InDeltaSlice(t, []float64{100, 108, 100}, elevations(CorrectDrift(loop, 50)), 1e-9)
+
+	// A point-to-point track keeps its real elevation gain
+	line := elevationTrack(100, 110, 104)
+	assert.Equal(t, line, CorrectDrift(line, 10))
+}
+
+type fixedProvider []float64
+
+func (f fixedProvider) Elevations(ctx context.Context, lats, lons []float64) ([]float64, error) {
+	return f[:min(len(f), len(lats))], nil
+}
+
+func TestCorrectElevation(t *testing.T) {
+	points := elevationTrack(0, 0, 0)
+	points[1].Lat = nil
+	corrected, err := CorrectElevation(context.Background(), points, fixedProvider{50, 52, 54})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{50, 0, 52}, elevations(corrected))
+
+	_, err = CorrectElevation(context.Background(), elevationTrack(0, 0), fixedProvider{50})
+	assert.Error(t, err)
+}
+
+func TestAscent(t *testing.T) {
+	// 1 m wiggles are noise; the 10 m climb and 6 m descent count
+	ascent, descent := Ascent(elevationTrack(100, 101, 100, 101, 105, 110, 107, 104), DefaultAscentThreshold)
+	assert.Equal(t, 10.0, ascent)
+	assert.Equal(t, 6.0, descent)
+}
+
+// runTrack returns a run east along the equator at the given meters per
+// second, one fix per second
+func runTrack(speeds ...float64) []api.TrackPoint {
+	const degPerMeter = 1 / 111194.93
+	points := []api.TrackPoint{{Timestamp: api.NewGarminTime(start), Lat: api.Ptr(0.0), Lon: api.Ptr(0.0)}}
+	lon := 0.0
+	for i, v := range speeds {
+		lon += v * degPerMeter
+		points = append(points, api.TrackPoint{
+			Timestamp: api.NewGarminTime(start.Add(time.Duration(i+1) * time.Second)),
+			Lat:       api.Ptr(0.0),
+			Lon:       api.Ptr(lon),
+		})
+	}
+	return points
+}
+
+func TestTrackDistance(t *testing.T) {
+	points := runTrack(4, 4, 4, 4)
+	assert.InDelta(t, 16, TrackDistance(points, DefaultMaxSpeed), 0.01)
+
+	// A fix 2 km off the track is skipped
+	glitch := api.TrackPoint{Timestamp: points[2].Timestamp, Lat: api.Ptr(0.02), Lon: points[2].Lon}
+	withGlitch := append(append(append([]api.TrackPoint{}, points[:2]...), glitch), points[3:]...)
+	assert.InDelta(t, 16, TrackDistance(withGlitch, DefaultMaxSpeed), 0.01)
+	assert.Greater(t, TrackDistance(withGlitch, 0), 4000.0)
+
+	assert.Equal(t, 0.0, TrackDistance(nil, DefaultMaxSpeed))
+}
+
+func TestSplits(t *testing.T) {
+	// 5 m/s for 250 s, then 4 m/s for 125 s: 1250 + 500 meters
+	speeds := make([]float64, 0, 375)
+	for range 250 {
+		speeds = append(speeds, 5)
+	}
+	for range 125 {
+		speeds = append(speeds, 4)
+	}
+	splits := Splits(runTrack(speeds...), 1000, DefaultMaxSpeed)
+	assert.Len(t, splits, 2)
+	assert.InDelta(t, float64(200*time.Second), float64(splits[0].Duration), float64(time.Second))
+	assert.InDelta(t, float64(200*time.Second), float64(splits[0].Pace()), float64(time.Second))
+	assert.InDelta(t, 750, splits[1].Distance, 0.5)
+	assert.InDelta(t, float64(175*time.Second), float64(splits[1].Duration), float64(time.Second))
+	assert.InDelta(t, float64(233*time.Second), float64(splits[1].Pace()), float64(time.Second))
+}
+
+func TestClean(t *testing.T) {
+	// Run 20 s, stand 30 s, run 5 s, stand 3 s at a crossing, run 5 s
+	speeds := make([]float64, 0, 63)
+	for range 20 {
+		speeds = append(speeds, 4)
+	}
+	for range 30 {
+		speeds = append(speeds, 0)
+	}
+	for range 5 {
+		speeds = append(speeds, 4)
+	}
+	for range 3 {
+		speeds = append(speeds, 0)
+	}
+	for range 5 {
+		speeds = append(speeds, 4)
+	}
+	points := runTrack(speeds...)
+	// A teleport spike in the first stretch
+	points[10].Lat = api.Ptr(0.01)
+
+	result := Clean(points, DefaultCleanOptions)
+	assert.Equal(t, 1, result.Spikes)
+	assert.Equal(t, 1, result.Pauses)
+	assert.Equal(t, len(points)-1-29, len(result.Points))
+
+	assert.Equal(t, len(points), result.Before.Points)
+	assert.Greater(t, result.Before.Distance, 2000.0)
+	assert.Greater(t, result.Before.MaxSpeed, 500.0)
+	assert.InDelta(t, 120, result.After.Distance, 0.1)
+	assert.InDelta(t, 4, result.After.MaxSpeed, 0.01)
+	assert.Equal(t, 63*time.Second, result.Before.Duration)
+	assert.Equal(t, 33*time.Second, result.After.Duration)
+	assert.Equal(t, 0.01, *points[10].Lat, "the input is not modified")
+}
+
+// coursePath returns a course heading east along the equator from lon
+func coursePath(id int64, lon float64, n int) api.Course {
+	c := api.Course{ID: id}
+	for i := range n {
+		c.Points = append(c.Points, api.CoursePoint{Lat: 0, Lon: lon + float64(i)*0.001})
+	}
+	return c
+}
+
+func TestFrechetRespectsDirection(t *testing.T) {
+	forward := CoursePositions(coursePath(1, 0, 10).Points)
+	backward := make([]LatLon, len(forward))
+	for i, p := range forward {
+		backward[len(forward)-1-i] = p
+	}
+	assert.InDelta(t, 0, Hausdorff(forward, backward), 1e-9)
+	assert.InDelta(t, 1000, Frechet(forward, backward), 1, "opposite ends are 9 * 111 m apart")
+	assert.InDelta(t, 0, Frechet(forward, Resample(forward, 10)), 1e-6, "the points are already evenly spaced")
+}
+
+func TestMatchCourse(t *testing.T) {
+	// The activity runs along the course 30 m to its north
+	track := elevationTrack(make([]float64, 91)...)
+	for i := range track {
+		track[i].Lat = api.Ptr(0.00027)
+	}
+	courses := []api.Course{
+		{ID: 1}, // listed without points
+		coursePath(2, 0.002, 10),
+		coursePath(3, 0, 10),
+	}
+
+	match, ok := MatchCourse(track, courses, DefaultMatchThreshold)
+	assert.True(t, ok)
+	assert.Equal(t, int64(3), match.Course.ID)
+	assert.InDelta(t, 30, match.Distance, 1)
+
+	_, ok = MatchCourse(track, courses, 10)
+	assert.False(t, ok)
+}
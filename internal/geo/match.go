@@ -0,0 +1,157 @@
+package geo
+
+import (
+	"math"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// DefaultMatchThreshold is how far in meters an activity may stray from a
+// course, allowing for GPS error and the odd detour, and still follow it
+const DefaultMatchThreshold = 100.0
+
+// matchSamples is the number of evenly spaced positions paths are reduced to
+// before they are compared, which bounds the cost of Frechet
+const matchSamples = 200
+
+// LatLon is a position in degrees
+type LatLon struct {
+	Lat, Lon float64
+}
+
+// TrackPositions returns the positions of the points that have one
+func TrackPositions(points []api.TrackPoint) []LatLon {
+	var out []LatLon
+	for _, p := range points {
+		if HasPosition(p) {
+			out = append(out, LatLon{*p.Lat, *p.Lon})
+		}
+	}
+	return out
+}
+
+// CoursePositions returns the positions along a course
+func CoursePositions(points []api.CoursePoint) []LatLon {
+	out := make([]LatLon, len(points))
+	for i, p := range points {
+		out[i] = LatLon{p.Lat, p.Lon}
+	}
+	return out
+}
+
+func dist(a, b LatLon) float64 {
+	return Distance(a.Lat, a.Lon, b.Lat, b.Lon)
+}
+
+// Resample returns n positions spaced evenly by distance along path
+func Resample(path []LatLon, n int) []LatLon {
+	if len(path) < 2 || n < 2 {
+		return path
+	}
+	cumulative := make([]float64, len(path))
+	for i := 1; i < len(path); i++ {
+		cumulative[i] = cumulative[i-1] + dist(path[i-1], path[i])
+	}
+	total := cumulative[len(path)-1]
+	if total == 0 {
+		return path[:1]
+	}
+
+	out := make([]LatLon, 0, n)
+	j := 1
+	for k := 0; k < n; k++ {
+		target := total * float64(k) / float64(n-1)
+		for j < len(path)-1 && cumulative[j] < target {
+			j++
+		}
+		share := 0.0
+		if seg := cumulative[j] - cumulative[j-1]; seg > 0 {
+			share = math.Min(1, (target-cumulative[j-1])/seg)
+		}
+		a, b := path[j-1], path[j]
+		out = append(out, LatLon{a.Lat + share*(b.Lat-a.Lat), a.Lon + share*(b.Lon-a.Lon)})
+	}
+	return out
+}
+
+// Hausdorff returns the largest distance in meters from a position on either
+// path to the nearest position on the other. It ignores the direction the
+// paths were travelled in.
+func Hausdorff(a, b []LatLon) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return math.Inf(1)
+	}
+	return math.Max(directedHausdorff(a, b), directedHausdorff(b, a))
+}
+
+func directedHausdorff(a, b []LatLon) float64 {
+	worst := 0.0
+	for _, p := range a {
+		nearest := math.Inf(1)
+		for _, q := range b {
+			nearest = math.Min(nearest, dist(p, q))
+		}
+		worst = math.Max(worst, nearest)
+	}
+	return worst
+}
+
+// Frechet returns the discrete Fréchet distance between two paths in meters:
+// the shortest leash that lets both be walked from start to end without going
+// back. Unlike Hausdorff it tells a route from the same route reversed.
+func Frechet(a, b []LatLon) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return math.Inf(1)
+	}
+	prev := make([]float64, len(b))
+	cur := make([]float64, len(b))
+	for i := range a {
+		for j := range b {
+			d := dist(a[i], b[j])
+			switch {
+			case i == 0 && j == 0:
+				cur[j] = d
+			case i == 0:
+				cur[j] = math.Max(cur[j-1], d)
+			case j == 0:
+				cur[j] = math.Max(prev[j], d)
+			default:
+				cur[j] = math.Max(math.Min(prev[j], math.Min(prev[j-1], cur[j-1])), d)
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)-1]
+}
+
+// CourseMatch is the course an activity followed
+type CourseMatch struct {
+	Course   api.Course
+	Distance float64 // Fréchet distance in meters between track and course
+}
+
+// MatchCourse returns the course the track followed most closely, comparing
+// with the Fréchet distance. ok is false when no course is within threshold
+// meters. Courses without points, as listed by GetCourses, are skipped.
+func MatchCourse(track []api.TrackPoint, courses []api.Course, threshold float64) (match CourseMatch, ok bool) {
+	path := Resample(TrackPositions(track), matchSamples)
+	if len(path) == 0 {
+		return CourseMatch{}, false
+	}
+	best := threshold
+	for _, course := range courses {
+		route := CoursePositions(course.Points)
+		if len(route) == 0 {
+			continue
+		}
+		// Courses starting or ending elsewhere can't be within the threshold
+		if dist(path[0], route[0]) > best || dist(path[len(path)-1], route[len(route)-1]) > best {
+			continue
+		}
+		if d := Frechet(path, Resample(route, matchSamples)); d <= best {
+			best = d
+			match, ok = CourseMatch{Course: course, Distance: d}, true
+		}
+	}
+	return match, ok
+}
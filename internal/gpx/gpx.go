@@ -0,0 +1,149 @@
+package gpx
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Profile selects the flavour of GPX written by Encode
+type Profile string
+
+const (
+	// ProfileStandard writes plain GPX 1.1 with position, elevation and time
+	ProfileStandard Profile = "standard"
+	// ProfileStrava adds the Garmin TrackPointExtension (hr, cad, atemp) that
+	// Strava reads sensor data from
+	ProfileStrava Profile = "strava"
+)
+
+const (
+	timeLayout      = "2006-01-02T15:04:05Z"
+	gpxNamespace    = "http://www.topografix.com/GPX/1/1"
+	schemaLocation  = "http://www.topografix.com/GPX/1/1 http://www.topografix.com/GPX/1/1/gpx.xsd"
+	xsiNamespace    = "http://www.w3.org/2001/XMLSchema-instance"
+	gpxtpxNamespace = "http://www.garmin.com/xmlschemas/TrackPointExtension/v1"
+)
+
+// Point is one track point. Points without a position are skipped.
+type Point struct {
+	Time        time.Time
+	Lat, Lon    float64
+	HasPosition bool
+	Elevation   *float64
+	HeartRate   *float64
+	Cadence     *float64
+	Temperature *float64
+}
+
+// Track is a single recorded activity
+type Track struct {
+	Name   string
+	Type   string // e.g. "running", "cycling"
+	Start  time.Time
+	Points []Point
+}
+
+type gpxDoc struct {
+	XMLName        xml.Name `xml:"gpx"`
+	Version        string   `xml:"version,attr"`
+	Creator        string   `xml:"creator,attr"`
+	Xmlns          string   `xml:"xmlns,attr"`
+	Xsi            string   `xml:"xmlns:xsi,attr"`
+	SchemaLocation string   `xml:"xsi:schemaLocation,attr"`
+	Gpxtpx         string   `xml:"xmlns:gpxtpx,attr,omitempty"`
+	Time           string   `xml:"metadata>time,omitempty"`
+	Track          gpxTrack `xml:"trk"`
+}
+
+type gpxTrack struct {
+	Name   string     `xml:"name,omitempty"`
+	Type   string     `xml:"type,omitempty"`
+	Points []gpxPoint `xml:"trkseg>trkpt"`
+}
+
+type gpxPoint struct {
+	Lat        string        `xml:"lat,attr"`
+	Lon        string        `xml:"lon,attr"`
+	Elevation  string        `xml:"ele,omitempty"`
+	Time       string        `xml:"time,omitempty"`
+	Extensions *gpxExtension `xml:"extensions>gpxtpx:TrackPointExtension,omitempty"`
+}
+
+type gpxExtension struct {
+	Temperature string `xml:"gpxtpx:atemp,omitempty"`
+	HeartRate   string `xml:"gpxtpx:hr,omitempty"`
+	Cadence     string `xml:"gpxtpx:cad,omitempty"`
+}
+
+// Encode writes t as a GPX 1.1 document using profile. Times are always
+// written in UTC, which Strava and most importers require.
+func Encode(w io.Writer, t Track, profile Profile) error {
+	doc := gpxDoc{
+		Version:        "1.1",
+		Creator:        "go-garminconnect",
+		Xmlns:          gpxNamespace,
+		Xsi:            xsiNamespace,
+		SchemaLocation: schemaLocation,
+		Track:          gpxTrack{Name: t.Name, Type: t.Type},
+	}
+	if !t.Start.IsZero() {
+		doc.Time = formatTime(t.Start)
+	}
+	strava := profile == ProfileStrava
+	if strava {
+		doc.Gpxtpx = gpxtpxNamespace
+	}
+
+	for _, p := range t.Points {
+		if !p.HasPosition {
+			continue
+		}
+		point := gpxPoint{
+			Lat:       formatFloat(p.Lat, 7),
+			Lon:       formatFloat(p.Lon, 7),
+			Elevation: formatOptional(p.Elevation, 1),
+		}
+		if !p.Time.IsZero() {
+			point.Time = formatTime(p.Time)
+		}
+		if strava {
+			ext := gpxExtension{
+				Temperature: formatOptional(p.Temperature, 0),
+				HeartRate:   formatOptional(p.HeartRate, 0),
+				Cadence:     formatOptional(p.Cadence, 0),
+			}
+			if ext != (gpxExtension{}) {
+				point.Extensions = &ext
+			}
+		}
+		doc.Track.Points = append(doc.Track.Points, point)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write GPX: %w", err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to write GPX: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+func formatFloat(v float64, prec int) string {
+	return fmt.Sprintf("%.*f", prec, v)
+}
+
+func formatOptional(v *float64, prec int) string {
+	if v == nil {
+		return ""
+	}
+	return formatFloat(*v, prec)
+}
@@ -0,0 +1,55 @@
+package gpx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ptr(v float64) *float64 { return &v }
+
+func testTrack() Track {
+	berlin := time.FixedZone("CET", 3600)
+	start := time.Date(2024, 3, 1, 8, 0, 0, 0, berlin)
+	return Track{
+		Name:  "Morning Run",
+		Type:  "running",
+		Start: start,
+		Points: []Point{
+			{Time: start, Lat: 52.52, Lon: 13.405, HasPosition: true, Elevation: ptr(34.2), HeartRate: ptr(142), Cadence: ptr(86), Temperature: ptr(11)},
+			{Time: start.Add(time.Second), HeartRate: ptr(143)},
+			{Time: start.Add(2 * time.Second), Lat: 52.5201, Lon: 13.4051, HasPosition: true},
+		},
+	}
+}
+
+func TestEncodeStrava(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, Encode(&buf, testTrack(), ProfileStrava))
+	out := buf.String()
+
+	assert.Contains(t, out, `xmlns:gpxtpx="http://www.garmin.com/xmlschemas/TrackPointExtension/v1"`)
+	assert.Contains(t, out, `<trkpt lat="52.5200000" lon="13.4050000">`)
+	assert.Contains(t, out, `<time>2024-03-01T07:00:00Z</time>`, "times are converted to UTC")
+	assert.Contains(t, out, `<gpxtpx:TrackPointExtension>`)
+	assert.Contains(t, out, `<gpxtpx:atemp>11</gpxtpx:atemp>`)
+	assert.Contains(t, out, `<gpxtpx:hr>142</gpxtpx:hr>`)
+	assert.Contains(t, out, `<gpxtpx:cad>86</gpxtpx:cad>`)
+	assert.Equal(t, 2, bytes.Count(buf.Bytes(), []byte("<trkpt ")), "points without a position are skipped")
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("<extensions>")), "points without readings carry no extension")
+
+	assert.NoError(t, xml.Unmarshal(buf.Bytes(), new(struct{})), "output is well-formed XML")
+}
+
+func TestEncodeStandard(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, Encode(&buf, testTrack(), ProfileStandard))
+	out := buf.String()
+
+	assert.Contains(t, out, `<ele>34.2</ele>`)
+	assert.Contains(t, out, `<name>Morning Run</name>`)
+	assert.NotContains(t, out, "gpxtpx")
+}
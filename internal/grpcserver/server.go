@@ -0,0 +1,338 @@
+// Package grpcserver exposes the client over gRPC, so services written in
+// other languages can run garmin-cli as a sidecar. The service definitions
+// are in proto/garmin/v1/garmin.proto; garminpb is generated from them with
+//
+//	protoc -I proto --go_out=internal/grpcserver/garminpb --go_opt=paths=source_relative \
+//		--go-grpc_out=internal/grpcserver/garminpb --go-grpc_opt=paths=source_relative \
+//		garmin/v1/garmin.proto
+//
+// and the generated files moved out of garmin/v1.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/grpcserver/garminpb"
+	"github.com/sstent/go-garminconnect/internal/watch"
+)
+
+// Defaults for requests that leave the period or interval unset
+const (
+	DefaultDays     = 2
+	DefaultInterval = 5 * time.Minute
+)
+
+// Backend defines the client methods served over gRPC
+type Backend interface {
+	watch.Source
+	GetActivityDetails(ctx context.Context, activityID int64, opts ...api.RequestOption) (*api.ActivityDetail, error)
+	DownloadActivity(ctx context.Context, activityID int64, opts ...api.RequestOption) ([]byte, error)
+	GetDailySnapshot(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.DailySnapshot, error)
+	Today(ctx context.Context) (time.Time, error)
+}
+
+// Server implements the activity, health and sync services
+type Server struct {
+	// Account is the label set on every event, e.g. when several servers
+	// publish to the same consumer
+	Account string
+
+	backend Backend
+
+	// mu guards watcher, which remembers what earlier Poll calls saw; it is
+	// created by the first Poll
+	mu      sync.Mutex
+	watcher *watch.Watcher
+}
+
+// NewServer creates the services backed by the given client
+func NewServer(backend Backend) *Server {
+	return &Server{backend: backend}
+}
+
+// newWatcher creates a watcher labelling its events with s.Account
+func (s *Server) newWatcher() *watch.Watcher {
+	w := watch.NewWatcher(s.backend)
+	w.Account = s.Account
+	return w
+}
+
+// Register adds the services to g
+func (s *Server) Register(g grpc.ServiceRegistrar) {
+	garminpb.RegisterActivityServiceServer(g, activityService{s: s})
+	garminpb.RegisterHealthServiceServer(g, healthService{s: s})
+	garminpb.RegisterSyncServiceServer(g, syncService{s: s})
+}
+
+type activityService struct {
+	garminpb.UnimplementedActivityServiceServer
+	s *Server
+}
+
+// ListActivities returns the activities started between two dates
+func (a activityService) ListActivities(ctx context.Context, req *garminpb.ListActivitiesRequest) (*garminpb.ListActivitiesResponse, error) {
+	start, err := parseDate("start_date", req.GetStartDate())
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseDate("end_date", req.GetEndDate())
+	if err != nil {
+		return nil, err
+	}
+	if end.Before(start) {
+		return nil, status.Error(codes.InvalidArgument, "end_date is before start_date")
+	}
+
+	activities, err := a.s.backend.GetActivitiesByDate(ctx, start, end)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	resp := &garminpb.ListActivitiesResponse{Activities: make([]*garminpb.Activity, len(activities))}
+	for i := range activities {
+		resp.Activities[i] = activityMessage(&activities[i])
+	}
+	return resp, nil
+}
+
+// GetActivity returns the summary of one activity
+func (a activityService) GetActivity(ctx context.Context, req *garminpb.GetActivityRequest) (*garminpb.Activity, error) {
+	if req.GetActivityId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "activity_id is required")
+	}
+	detail, err := a.s.backend.GetActivityDetails(ctx, req.GetActivityId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	msg := activityMessage(&detail.Activity)
+	msg.Calories = api.Ptr(detail.Calories)
+	msg.AverageHr = api.Ptr(int32(detail.AverageHR))
+	msg.MaxHr = api.Ptr(int32(detail.MaxHR))
+	msg.ElevationGainMeters = api.Ptr(detail.ElevationGain)
+	return msg, nil
+}
+
+// DownloadActivity returns the original FIT file of an activity
+func (a activityService) DownloadActivity(ctx context.Context, req *garminpb.GetActivityRequest) (*garminpb.ActivityFile, error) {
+	if req.GetActivityId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "activity_id is required")
+	}
+	data, err := a.s.backend.DownloadActivity(ctx, req.GetActivityId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &garminpb.ActivityFile{ActivityId: req.GetActivityId(), Fit: data}, nil
+}
+
+type healthService struct {
+	garminpb.UnimplementedHealthServiceServer
+	s *Server
+}
+
+// GetDailySummary returns the wellness metrics of one day
+func (h healthService) GetDailySummary(ctx context.Context, req *garminpb.GetDailySummaryRequest) (*garminpb.DailySummary, error) {
+	date, err := parseDate("date", req.GetDate())
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := h.s.backend.GetDailySnapshot(ctx, date)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return summaryMessage(snapshot), nil
+}
+
+type syncService struct {
+	garminpb.UnimplementedSyncServiceServer
+	s *Server
+}
+
+// Poll reports what changed since the previous Poll
+func (y syncService) Poll(ctx context.Context, req *garminpb.PollRequest) (*garminpb.PollResponse, error) {
+	y.s.mu.Lock()
+	defer y.s.mu.Unlock()
+
+	if y.s.watcher == nil {
+		y.s.watcher = y.s.newWatcher()
+	}
+	events, err := y.s.poll(ctx, y.s.watcher, req.GetDays())
+	if err != nil && !api.IsPartial(err) {
+		return nil, statusError(err)
+	}
+	if err != nil {
+		log.Printf("poll incomplete: %v", err)
+	}
+	resp := &garminpb.PollResponse{Events: make([]*garminpb.Event, len(events))}
+	for i := range events {
+		resp.Events[i] = eventMessage(&events[i])
+	}
+	return resp, nil
+}
+
+// WatchEvents streams the changes seen by polls of its own watcher
+func (y syncService) WatchEvents(req *garminpb.WatchEventsRequest, stream grpc.ServerStreamingServer[garminpb.Event]) error {
+	ctx := stream.Context()
+	interval := DefaultInterval
+	if req.GetIntervalSeconds() > 0 {
+		interval = time.Duration(req.GetIntervalSeconds()) * time.Second
+	}
+
+	watcher := y.s.newWatcher()
+	// priming polls only record what is already there; they continue until
+	// one succeeds, so a failed first poll can't make the next one report
+	// everything
+	priming := true
+	for {
+		events, err := y.s.poll(ctx, watcher, req.GetDays())
+		switch {
+		case ctx.Err() != nil:
+			return status.FromContextError(ctx.Err()).Err()
+		case err != nil && !api.IsPartial(err):
+			log.Printf("poll failed: %v", err)
+		case err != nil:
+			log.Printf("poll incomplete: %v", err)
+		}
+		if priming {
+			priming = err != nil
+		} else {
+			for i := range events {
+				if err := stream.Send(eventMessage(&events[i])); err != nil {
+					return err
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// poll checks the given number of days up to today with w
+func (s *Server) poll(ctx context.Context, w *watch.Watcher, days int32) ([]watch.Event, error) {
+	if days <= 0 {
+		days = DefaultDays
+	}
+	today, err := s.backend.Today(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return w.Poll(ctx, today.AddDate(0, 0, 1-int(days)), today)
+}
+
+// parseDate parses a YYYY-MM-DD request field
+func parseDate(field, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, status.Errorf(codes.InvalidArgument, "%s is required", field)
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, status.Errorf(codes.InvalidArgument, "invalid %s %q, expected YYYY-MM-DD", field, value)
+	}
+	return t, nil
+}
+
+// statusError maps a client error to the closest gRPC status
+func statusError(err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	log.Printf("backend request failed: %v", err)
+
+	code := codes.Unavailable
+	var apiErr *api.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusNotFound:
+			code = codes.NotFound
+		case http.StatusUnauthorized:
+			code = codes.Unauthenticated
+		case http.StatusForbidden:
+			code = codes.PermissionDenied
+		case http.StatusTooManyRequests:
+			code = codes.ResourceExhausted
+		}
+	}
+	return status.Error(code, err.Error())
+}
+
+func activityMessage(a *api.Activity) *garminpb.Activity {
+	msg := &garminpb.Activity{
+		ActivityId:      a.ActivityID,
+		Name:            a.Name,
+		Type:            a.Type,
+		DurationSeconds: a.Duration,
+		DistanceMeters:  a.Distance,
+	}
+	if !a.StartTime.Time.IsZero() {
+		msg.StartTime = timestamppb.New(a.StartTime.Time)
+	}
+	return msg
+}
+
+func summaryMessage(s *api.DailySnapshot) *garminpb.DailySummary {
+	msg := &garminpb.DailySummary{Date: s.Date.Format("2006-01-02")}
+	if s.Steps != nil {
+		msg.Steps = api.Ptr(int32(s.Steps.TotalSteps))
+		msg.StepGoal = api.Ptr(int32(s.Steps.Goal))
+	}
+	if s.Sleep != nil {
+		msg.SleepSeconds = int32Ptr(s.Sleep.SleepTimeSeconds)
+		msg.SleepScore = int32Ptr(s.Sleep.SleepScore)
+	}
+	if s.Stress != nil {
+		msg.StressLevel = api.Ptr(int32(s.Stress.OverallStressLevel))
+	}
+	if s.HRV != nil {
+		msg.HrvLastNightAvg = s.HRV.LastNightAvg
+	}
+	if s.BodyBattery != nil {
+		msg.BodyBatteryHighest = int32Ptr(s.BodyBattery.Highest)
+		msg.BodyBatteryLowest = int32Ptr(s.BodyBattery.Lowest)
+	}
+	if s.HeartRate != nil {
+		msg.RestingHr = int32Ptr(s.HeartRate.RestingHR)
+	}
+	for _, name := range []string{api.SnapshotSteps, api.SnapshotSleep, api.SnapshotStress, api.SnapshotHRV, api.SnapshotBodyBattery, api.SnapshotHeartRate} {
+		if _, failed := s.Errors[name]; failed {
+			msg.Missing = append(msg.Missing, name)
+		}
+	}
+	return msg
+}
+
+func eventMessage(e *watch.Event) *garminpb.Event {
+	msg := &garminpb.Event{
+		Type:     e.Type,
+		Account:  e.Account,
+		Detected: timestamppb.New(e.Detected),
+		Date:     e.Date,
+		Metric:   e.Metric,
+		Value:    e.Value,
+		Previous: e.Previous,
+	}
+	if e.Activity != nil {
+		msg.Activity = activityMessage(e.Activity)
+	}
+	return msg
+}
+
+// int32Ptr converts an optional int field
+func int32Ptr(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	return api.Ptr(int32(*v))
+}
@@ -0,0 +1,233 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/grpcserver/garminpb"
+)
+
+// fakeBackend serves fixed data; polled receives a value on every activity
+// listing so tests can tell when a poll has started, and the first failures
+// listings fail
+type fakeBackend struct {
+	mu         sync.Mutex
+	activities []api.Activity
+	failures   int
+	polled     chan struct{}
+}
+
+func (f *fakeBackend) GetActivitiesByDate(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.Activity, error) {
+	f.mu.Lock()
+	activities := append([]api.Activity(nil), f.activities...)
+	fail := f.failures > 0
+	if fail {
+		f.failures--
+	}
+	f.mu.Unlock()
+	if f.polled != nil {
+		select {
+		case f.polled <- struct{}{}:
+		default:
+		}
+	}
+	if fail {
+		return nil, &api.APIError{StatusCode: http.StatusServiceUnavailable, Message: "unavailable"}
+	}
+	return activities, nil
+}
+
+func (f *fakeBackend) add(a api.Activity) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.activities = append(f.activities, a)
+}
+
+func (f *fakeBackend) GetStepsDataRange(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.DailySteps, error) {
+	return nil, nil
+}
+
+func (f *fakeBackend) GetSleepDataRange(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.SleepData, error) {
+	return nil, nil
+}
+
+func (f *fakeBackend) GetUserStats(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.UserStats, error) {
+	return &api.UserStats{}, nil
+}
+
+func (f *fakeBackend) GetActivityDetails(ctx context.Context, activityID int64, opts ...api.RequestOption) (*api.ActivityDetail, error) {
+	if activityID != 1 {
+		return nil, &api.APIError{StatusCode: http.StatusNotFound, Message: "not found"}
+	}
+	return &api.ActivityDetail{
+		Activity:  api.Activity{ActivityID: 1, Name: "Morning Run", Type: "running", StartTime: api.NewGarminTime(time.Date(2024, 3, 1, 7, 0, 0, 0, time.UTC))},
+		Calories:  420,
+		AverageHR: 148,
+	}, nil
+}
+
+func (f *fakeBackend) DownloadActivity(ctx context.Context, activityID int64, opts ...api.RequestOption) ([]byte, error) {
+	return []byte("FIT"), nil
+}
+
+func (f *fakeBackend) GetDailySnapshot(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.DailySnapshot, error) {
+	return &api.DailySnapshot{
+		Date:      date,
+		Steps:     &api.DailySteps{TotalSteps: 9500, Goal: 8000},
+		HeartRate: &api.HeartRateData{RestingHR: api.Ptr(52)},
+		Errors:    map[string]error{api.SnapshotSleep: errors.New("timeout")},
+	}, nil
+}
+
+func (f *fakeBackend) Today(ctx context.Context) (time.Time, error) {
+	return time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), nil
+}
+
+// dial serves srv over an in-memory connection
+func dial(t *testing.T, srv *Server, opts ...grpc.ServerOption) *grpc.ClientConn {
+	lis := bufconn.Listen(1 << 20)
+	g := grpc.NewServer(opts...)
+	srv.Register(g)
+	go g.Serve(lis)
+	t.Cleanup(g.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestActivityService(t *testing.T) {
+	backend := &fakeBackend{activities: []api.Activity{{ActivityID: 1, Name: "Morning Run", Distance: 5000}}}
+	client := garminpb.NewActivityServiceClient(dial(t, NewServer(backend)))
+	ctx := context.Background()
+
+	list, err := client.ListActivities(ctx, &garminpb.ListActivitiesRequest{StartDate: "2024-03-01", EndDate: "2024-03-07"})
+	assert.NoError(t, err)
+	if assert.Len(t, list.Activities, 1) {
+		assert.Equal(t, 5000.0, list.Activities[0].DistanceMeters)
+		assert.Nil(t, list.Activities[0].Calories, "only set by GetActivity")
+	}
+
+	_, err = client.ListActivities(ctx, &garminpb.ListActivitiesRequest{StartDate: "March", EndDate: "2024-03-07"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	activity, err := client.GetActivity(ctx, &garminpb.GetActivityRequest{ActivityId: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "running", activity.Type)
+	assert.Equal(t, 420.0, activity.GetCalories())
+	assert.Equal(t, int32(148), activity.GetAverageHr())
+	assert.Equal(t, time.Date(2024, 3, 1, 7, 0, 0, 0, time.UTC), activity.StartTime.AsTime())
+
+	_, err = client.GetActivity(ctx, &garminpb.GetActivityRequest{ActivityId: 2})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	file, err := client.DownloadActivity(ctx, &garminpb.GetActivityRequest{ActivityId: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("FIT"), file.Fit)
+}
+
+func TestHealthService(t *testing.T) {
+	client := garminpb.NewHealthServiceClient(dial(t, NewServer(&fakeBackend{})))
+
+	summary, err := client.GetDailySummary(context.Background(), &garminpb.GetDailySummaryRequest{Date: "2024-03-01"})
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-03-01", summary.Date)
+	assert.Equal(t, int32(9500), summary.GetSteps())
+	assert.Equal(t, int32(52), summary.GetRestingHr())
+	assert.Nil(t, summary.SleepSeconds)
+	assert.Equal(t, []string{api.SnapshotSleep}, summary.Missing)
+}
+
+func TestSyncService(t *testing.T) {
+	backend := &fakeBackend{activities: []api.Activity{{ActivityID: 1, Name: "Morning Run"}}}
+	client := garminpb.NewSyncServiceClient(dial(t, NewServer(backend)))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := client.Poll(ctx, &garminpb.PollRequest{})
+	assert.NoError(t, err)
+	if assert.Len(t, resp.Events, 1) {
+		assert.Equal(t, int64(1), resp.Events[0].Activity.ActivityId)
+	}
+	resp, err = client.Poll(ctx, &garminpb.PollRequest{})
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Events)
+
+	backend.polled = make(chan struct{})
+	stream, err := client.WatchEvents(ctx, &garminpb.WatchEventsRequest{IntervalSeconds: 1})
+	assert.NoError(t, err)
+	<-backend.polled
+	backend.add(api.Activity{ActivityID: 2, Name: "Evening Ride"})
+
+	event, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, "activity", event.Type)
+	assert.Equal(t, int64(2), event.Activity.ActivityId, "activities present when the stream started are not sent")
+}
+
+func TestWatchEventsPrimesAfterFailure(t *testing.T) {
+	backend := &fakeBackend{
+		activities: []api.Activity{{ActivityID: 1, Name: "Morning Run"}},
+		failures:   1,
+		polled:     make(chan struct{}),
+	}
+	srv := NewServer(backend)
+	srv.Account = "sam"
+	client := garminpb.NewSyncServiceClient(dial(t, srv))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := client.WatchEvents(ctx, &garminpb.WatchEventsRequest{IntervalSeconds: 1})
+	assert.NoError(t, err)
+	<-backend.polled // fails
+	<-backend.polled // primes
+	backend.add(api.Activity{ActivityID: 2, Name: "Evening Ride"})
+
+	event, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), event.Activity.ActivityId, "a failed first poll does not end priming")
+	assert.Equal(t, "sam", event.Account)
+}
+
+func TestTokenAuth(t *testing.T) {
+	conn := dial(t, NewServer(&fakeBackend{}), TokenAuth("s3cret")...)
+	health := garminpb.NewHealthServiceClient(conn)
+	syncClient := garminpb.NewSyncServiceClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req := &garminpb.GetDailySummaryRequest{Date: "2024-03-01"}
+
+	_, err := health.GetDailySummary(ctx, req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = health.GetDailySummary(metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer wrong"), req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	stream, err := syncClient.WatchEvents(ctx, &garminpb.WatchEventsRequest{})
+	if assert.NoError(t, err) {
+		_, err = stream.Recv()
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+
+	authed := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer s3cret")
+	_, err = health.GetDailySummary(authed, req)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,246 @@
+package healthapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dghubble/oauth1"
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// DefaultBackfillURL is the Health API endpoint for backfill requests
+const DefaultBackfillURL = "https://apis.garmin.com/wellness-api/rest/backfill"
+
+// maxBackfillWindow is the longest range Garmin accepts in one backfill request
+const maxBackfillWindow = 90 * 24 * time.Hour
+
+// ErrBackfillDuplicate is returned when Garmin already accepted a backfill for
+// the same user, summary type and range
+var ErrBackfillDuplicate = errors.New("backfill already requested")
+
+// RequestBackfill asks Garmin to resend the user's summaryType records between
+// start and end. The data arrives asynchronously through the usual push or ping
+// notifications; ranges longer than 90 days are split into several requests.
+func (p *Puller) RequestBackfill(ctx context.Context, userAccessToken, summaryType string, start, end time.Time) error {
+	if !end.After(start) {
+		return errors.New("backfill end must be after start")
+	}
+
+	secret, err := p.Tokens.TokenSecret(userAccessToken)
+	if err != nil {
+		return fmt.Errorf("failed to resolve token secret: %w", err)
+	}
+	if p.HTTPClient != nil {
+		ctx = context.WithValue(ctx, oauth1.HTTPClient, p.HTTPClient)
+	}
+	client := p.Config.Client(ctx, oauth1.NewToken(userAccessToken, secret))
+
+	base := p.BackfillURL
+	if base == "" {
+		base = DefaultBackfillURL
+	}
+	for from := start; from.Before(end); from = from.Add(maxBackfillWindow) {
+		to := from.Add(maxBackfillWindow)
+		if to.After(end) {
+			to = end
+		}
+		if err := requestBackfillWindow(ctx, client, base, summaryType, from, to); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requestBackfillWindow submits a single backfill request of at most 90 days
+func requestBackfillWindow(ctx context.Context, client *http.Client, base, summaryType string, from, to time.Time) error {
+	query := url.Values{
+		"summaryStartTimeInSeconds": {strconv.FormatInt(from.Unix(), 10)},
+		"summaryEndTimeInSeconds":   {strconv.FormatInt(to.Unix(), 10)},
+	}
+	endpoint := base + "/" + url.PathEscape(summaryType) + "?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create backfill request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("backfill request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusAccepted, http.StatusOK:
+		return nil
+	case http.StatusConflict:
+		return fmt.Errorf("%s %s to %s: %w", summaryType, from.Format(time.DateOnly), to.Format(time.DateOnly), ErrBackfillDuplicate)
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("backfill request failed with status %d: %s", resp.StatusCode, body)
+	}
+}
+
+// DefaultBackfillQuietPeriod is how long a backfill may go without deliveries
+// before the tracker considers it complete
+const DefaultBackfillQuietPeriod = 15 * time.Minute
+
+// BackfillStatus reports the progress of a tracked backfill
+type BackfillStatus struct {
+	UserID      string
+	SummaryType string
+	Requested   time.Time
+	// LastDelivery is the time the most recent matching event arrived
+	LastDelivery time.Time
+	Days         int // days in the requested range
+	Delivered    int // days for which records have arrived
+	// Complete is set once every day arrived or deliveries stopped for the
+	// quiet period; days without data are never delivered
+	Complete bool
+}
+
+// backfill is the tracker's state for one user and summary type
+type backfill struct {
+	requested    time.Time
+	lastDelivery time.Time
+	days         map[string]bool // calendar date to delivered
+}
+
+// BackfillTracker follows delivery of requested backfills by observing the
+// events passed to a Receiver's handler
+type BackfillTracker struct {
+	// QuietPeriod overrides DefaultBackfillQuietPeriod when set
+	QuietPeriod time.Duration
+
+	mu        sync.Mutex
+	backfills map[[2]string]*backfill
+	now       func() time.Time
+}
+
+// NewBackfillTracker creates an empty tracker
+func NewBackfillTracker() *BackfillTracker {
+	return &BackfillTracker{
+		backfills: make(map[[2]string]*backfill),
+		now:       time.Now,
+	}
+}
+
+// Track starts following a backfill of summaryType for userID covering the
+// calendar days from start to end inclusive
+func (t *BackfillTracker) Track(userID, summaryType string, start, end time.Time) {
+	days := make(map[string]bool)
+	for _, day := range api.Days(start, end) {
+		days[day.Format(time.DateOnly)] = false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.backfills[[2]string{userID, summaryType}] = &backfill{requested: t.now(), days: days}
+}
+
+// Wrap returns a handler that records deliveries before passing events on
+func (t *BackfillTracker) Wrap(next HandlerFunc) HandlerFunc {
+	return func(ctx context.Context, event Event) error {
+		t.Observe(event)
+		return next(ctx, event)
+	}
+}
+
+// Observe marks the days covered by event as delivered
+func (t *BackfillTracker) Observe(event Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b, ok := t.backfills[[2]string{event.UserID, event.SummaryType}]
+	if !ok {
+		return
+	}
+	b.lastDelivery = t.now()
+	for _, date := range eventDates(event) {
+		if _, ok := b.days[date]; ok {
+			b.days[date] = true
+		}
+	}
+}
+
+// Status returns the progress of the backfill for userID and summaryType
+func (t *BackfillTracker) Status(userID, summaryType string) (BackfillStatus, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	b, ok := t.backfills[[2]string{userID, summaryType}]
+	if !ok {
+		return BackfillStatus{}, false
+	}
+	status := BackfillStatus{
+		UserID:       userID,
+		SummaryType:  summaryType,
+		Requested:    b.requested,
+		LastDelivery: b.lastDelivery,
+		Days:         len(b.days),
+	}
+	for _, delivered := range b.days {
+		if delivered {
+			status.Delivered++
+		}
+	}
+
+	quiet := t.QuietPeriod
+	if quiet <= 0 {
+		quiet = DefaultBackfillQuietPeriod
+	}
+	last := b.requested
+	if b.lastDelivery.After(last) {
+		last = b.lastDelivery
+	}
+	status.Complete = status.Delivered == status.Days || t.now().Sub(last) >= quiet
+	return status, true
+}
+
+// Wait blocks until the backfill is complete, checking every interval
+func (t *BackfillTracker) Wait(ctx context.Context, userID, summaryType string, interval time.Duration) (BackfillStatus, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		status, ok := t.Status(userID, summaryType)
+		if !ok {
+			return status, fmt.Errorf("no backfill of %s tracked for user %s", summaryType, userID)
+		}
+		if status.Complete {
+			return status, nil
+		}
+		select {
+		case <-ctx.Done():
+			return status, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// eventDates lists the calendar dates of the records carried by an event
+func eventDates(event Event) []string {
+	var dates []string
+	for _, s := range event.Steps {
+		dates = append(dates, s.CalendarDate.Format(time.DateOnly))
+	}
+	for _, s := range event.Stress {
+		dates = append(dates, s.CalendarDate.Format(time.DateOnly))
+	}
+	for _, s := range event.Sleep {
+		dates = append(dates, s.CalendarDate.Format(time.DateOnly))
+	}
+	for _, h := range event.HRV {
+		dates = append(dates, h.Date.Format(time.DateOnly))
+	}
+	for _, a := range event.Activities {
+		dates = append(dates, a.StartTime.Format(time.DateOnly))
+	}
+	return dates
+}
@@ -0,0 +1,96 @@
+package healthapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestBackfill(t *testing.T) {
+	var mu sync.Mutex
+	var windows [][2]int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/sleeps", r.URL.Path)
+		assert.Contains(t, r.Header.Get("Authorization"), `oauth_token="tok"`)
+		from, _ := strconv.ParseInt(r.URL.Query().Get("summaryStartTimeInSeconds"), 10, 64)
+		to, _ := strconv.ParseInt(r.URL.Query().Get("summaryEndTimeInSeconds"), 10, 64)
+
+		mu.Lock()
+		defer mu.Unlock()
+		windows = append(windows, [2]int64{from, to})
+		if len(windows) > 2 {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	p := NewPuller("consumer", "consumer-secret", staticTokens{"tok": "tok-secret"})
+	p.BackfillURL = server.URL
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 120)
+
+	err := p.RequestBackfill(context.Background(), "tok", SummarySleeps, start, end)
+	assert.NoError(t, err)
+	assert.Equal(t, [][2]int64{
+		{start.Unix(), start.AddDate(0, 0, 90).Unix()},
+		{start.AddDate(0, 0, 90).Unix(), end.Unix()},
+	}, windows)
+
+	err = p.RequestBackfill(context.Background(), "tok", SummarySleeps, start, end)
+	assert.ErrorIs(t, err, ErrBackfillDuplicate)
+
+	err = p.RequestBackfill(context.Background(), "unknown", SummarySleeps, start, end)
+	assert.Error(t, err)
+	err = p.RequestBackfill(context.Background(), "tok", SummarySleeps, end, start)
+	assert.Error(t, err)
+}
+
+func TestBackfillTracker(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	tracker := NewBackfillTracker()
+	tracker.QuietPeriod = time.Hour
+	tracker.now = func() time.Time { return now }
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	tracker.Track("u1", SummaryDailies, start, start.AddDate(0, 0, 2))
+
+	var delivered int
+	handle := tracker.Wrap(func(ctx context.Context, e Event) error {
+		delivered++
+		return nil
+	})
+	day := func(d int) api.Date { return api.Date{Time: start.AddDate(0, 0, d)} }
+
+	assert.NoError(t, handle(context.Background(), Event{UserID: "u1", SummaryType: SummaryDailies,
+		Steps: []api.DailySteps{{CalendarDate: day(0)}, {CalendarDate: day(1)}}}))
+	// Other users and summary types do not count towards the backfill
+	assert.NoError(t, handle(context.Background(), Event{UserID: "u2", SummaryType: SummaryDailies,
+		Steps: []api.DailySteps{{CalendarDate: day(2)}}}))
+	assert.Equal(t, 2, delivered)
+
+	status, ok := tracker.Status("u1", SummaryDailies)
+	assert.True(t, ok)
+	assert.Equal(t, 3, status.Days)
+	assert.Equal(t, 2, status.Delivered)
+	assert.False(t, status.Complete)
+
+	// The last day had no data, so completion follows from the quiet period
+	now = now.Add(time.Hour)
+	status, err := tracker.Wait(context.Background(), "u1", SummaryDailies, time.Millisecond)
+	assert.NoError(t, err)
+	assert.True(t, status.Complete)
+
+	_, ok = tracker.Status("u1", SummarySleeps)
+	assert.False(t, ok)
+	_, err = tracker.Wait(context.Background(), "u1", SummarySleeps, time.Millisecond)
+	assert.Error(t, err)
+}
@@ -0,0 +1,140 @@
+package healthapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// Event carries normalized summaries of one type received for one user
+type Event struct {
+	UserID      string
+	SummaryType string
+	Steps       []api.DailySteps
+	Stress      []api.DailyStress
+	Sleep       []api.SleepData
+	Activities  []api.Activity
+	HRV         []api.HRVData
+	// Unsupported holds the raw records of summary types without a package model
+	Unsupported []json.RawMessage
+}
+
+// normalize decodes raw Health API records into this package's data models
+func normalize(summaryType, userID string, records []json.RawMessage) (Event, error) {
+	event := Event{UserID: userID, SummaryType: summaryType}
+
+	for _, raw := range records {
+		var err error
+		switch summaryType {
+		case SummaryDailies:
+			var d DailySummary
+			if err = json.Unmarshal(raw, &d); err == nil {
+				var date api.Date
+				if date, err = api.ParseDate(d.CalendarDate); err == nil {
+					event.Steps = append(event.Steps, dailySteps(d, date))
+					event.Stress = append(event.Stress, dailyStress(d, date))
+				}
+			}
+		case SummaryActivities:
+			var a ActivitySummary
+			if err = json.Unmarshal(raw, &a); err == nil {
+				event.Activities = append(event.Activities, activity(a))
+			}
+		case SummarySleeps:
+			var s SleepSummary
+			if err = json.Unmarshal(raw, &s); err == nil {
+				var date api.Date
+				if date, err = api.ParseDate(s.CalendarDate); err == nil {
+					event.Sleep = append(event.Sleep, sleepData(s, date))
+				}
+			}
+		case SummaryStress:
+			var s StressSummary
+			if err = json.Unmarshal(raw, &s); err == nil {
+				var date api.Date
+				if date, err = api.ParseDate(s.CalendarDate); err == nil {
+					event.Stress = append(event.Stress, api.DailyStress{
+						CalendarDate:       date,
+						OverallStressLevel: s.AverageStressLevel,
+					})
+				}
+			}
+		case SummaryHRV:
+			var h HRVSummary
+			if err = json.Unmarshal(raw, &h); err == nil {
+				var date api.Date
+				if date, err = api.ParseDate(h.CalendarDate); err == nil {
+					event.HRV = append(event.HRV, api.HRVData{
+						Date:         date,
+						LastNightAvg: api.Ptr(h.LastNightAvg),
+						WeeklyAvg:    api.Ptr(h.WeeklyAvg),
+						HrvStatus:    h.Status,
+					})
+				}
+			}
+		default:
+			event.Unsupported = append(event.Unsupported, raw)
+		}
+		if err != nil {
+			return Event{}, fmt.Errorf("failed to decode %s record: %w", summaryType, err)
+		}
+	}
+
+	return event, nil
+}
+
+func dailySteps(d DailySummary, date api.Date) api.DailySteps {
+	return api.DailySteps{
+		CalendarDate:     date,
+		TotalSteps:       d.Steps,
+		Goal:             d.StepsGoal,
+		ActiveMinutes:    (d.ModerateIntensityDurationInSeconds + d.VigorousIntensityDurationInSeconds) / 60,
+		DistanceMeters:   d.DistanceInMeters,
+		CaloriesBurned:   d.ActiveKilocalories,
+		StepsToGoal:      max(d.StepsGoal-d.Steps, 0),
+		StepGoalAchieved: d.StepsGoal > 0 && d.Steps >= d.StepsGoal,
+	}
+}
+
+func dailyStress(d DailySummary, date api.Date) api.DailyStress {
+	return api.DailyStress{
+		CalendarDate:         date,
+		OverallStressLevel:   d.AverageStressLevel,
+		RestStressDuration:   d.RestStressDurationInSeconds,
+		LowStressDuration:    d.LowStressDurationInSeconds,
+		MediumStressDuration: d.MediumStressDurationInSeconds,
+		HighStressDuration:   d.HighStressDurationInSeconds,
+		StressQualifier:      d.StressQualifier,
+	}
+}
+
+func activity(a ActivitySummary) api.Activity {
+	// Convert to the local wall-clock time the rest of the client reports
+	local := time.Unix(a.StartTimeInSeconds+int64(a.StartTimeOffsetInSeconds), 0).UTC()
+	return api.Activity{
+		ActivityID: a.ActivityID,
+		Name:       a.ActivityName,
+		Type:       a.ActivityType,
+		StartTime:  api.NewGarminTime(local),
+		Duration:   a.DurationInSeconds,
+		Distance:   a.DistanceInMeters,
+	}
+}
+
+func sleepData(s SleepSummary, date api.Date) api.SleepData {
+	data := api.SleepData{
+		CalendarDate:      date,
+		SleepTimeSeconds:  api.Ptr(s.DurationInSeconds),
+		DeepSleepSeconds:  api.Ptr(s.DeepSleepDurationInSeconds),
+		LightSleepSeconds: api.Ptr(s.LightSleepDurationInSeconds),
+		RemSleepSeconds:   api.Ptr(s.RemSleepInSeconds),
+		AwakeSeconds:      api.Ptr(s.AwakeDurationInSeconds),
+	}
+	if s.OverallSleepScore != nil {
+		data.SleepScore = api.Ptr(s.OverallSleepScore.Value)
+		data.SleepScores = &api.SleepScores{Overall: s.OverallSleepScore.Value}
+	}
+	return data
+}
@@ -0,0 +1,74 @@
+package healthapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/dghubble/oauth1"
+)
+
+// TokenStore resolves the OAuth1 token secret of a user who authorized the consumer
+type TokenStore interface {
+	TokenSecret(userAccessToken string) (string, error)
+}
+
+// Puller fetches summaries announced by ping notifications using the
+// consumer's OAuth1 credentials and the user's access token
+type Puller struct {
+	Config *oauth1.Config
+	Tokens TokenStore
+	// HTTPClient is the transport used for pull requests (default http.DefaultClient)
+	HTTPClient *http.Client
+	// BackfillURL overrides DefaultBackfillURL, mainly for tests
+	BackfillURL string
+}
+
+// NewPuller creates a puller for the given consumer credentials
+func NewPuller(consumerKey, consumerSecret string, tokens TokenStore) *Puller {
+	return &Puller{
+		Config: oauth1.NewConfig(consumerKey, consumerSecret),
+		Tokens: tokens,
+	}
+}
+
+// Pull retrieves the records available at the ping's callback URL
+func (p *Puller) Pull(ctx context.Context, ping Ping) ([]json.RawMessage, error) {
+	if ping.CallbackURL == "" {
+		return nil, errors.New("ping has no callback URL")
+	}
+
+	secret, err := p.Tokens.TokenSecret(ping.UserAccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve token secret for user %s: %w", ping.UserID, err)
+	}
+
+	if p.HTTPClient != nil {
+		ctx = context.WithValue(ctx, oauth1.HTTPClient, p.HTTPClient)
+	}
+	client := p.Config.Client(ctx, oauth1.NewToken(ping.UserAccessToken, secret))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ping.CallbackURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pull request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("pull request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("pull request failed with status %d: %s", resp.StatusCode, body)
+	}
+
+	var records []json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&records); err != nil {
+		return nil, fmt.Errorf("failed to decode pulled summaries: %w", err)
+	}
+	return records, nil
+}
@@ -0,0 +1,203 @@
+package healthapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// SignatureHeader carries the hex encoded HMAC-SHA256 of the request body
+const SignatureHeader = "X-Garmin-Signature"
+
+// defaultMaxBodyBytes bounds notification bodies; pushed activity summaries can be large
+const defaultMaxBodyBytes = 16 << 20
+
+// HandlerFunc receives normalized events
+type HandlerFunc func(ctx context.Context, event Event) error
+
+// Receiver implements the Garmin Health API notification endpoint for both the
+// push model (summaries in the body) and the ping/pull model (callback URLs)
+type Receiver struct {
+	// Secret enables signature verification when set
+	Secret []byte
+	// Puller fetches data for ping notifications; pings are rejected when nil
+	Puller *Puller
+	// Handle is called with every normalized event
+	Handle HandlerFunc
+	// MaxBodyBytes limits the accepted request size
+	MaxBodyBytes int64
+
+	pending sync.WaitGroup
+}
+
+// NewReceiver creates a receiver delivering events to handle
+func NewReceiver(handle HandlerFunc) *Receiver {
+	return &Receiver{
+		Handle:       handle,
+		MaxBodyBytes: defaultMaxBodyBytes,
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (rc *Receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := rc.MaxBodyBytes
+	if limit <= 0 {
+		limit = defaultMaxBodyBytes
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, limit))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if err := rc.verify(body, r.Header.Get(SignatureHeader)); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var n notification
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&n); err != nil {
+		http.Error(w, "invalid notification body", http.StatusBadRequest)
+		return
+	}
+
+	pushes, pings, err := split(n)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(pings) > 0 && rc.Puller == nil {
+		http.Error(w, "ping notifications are not supported", http.StatusNotImplemented)
+		return
+	}
+
+	for _, p := range pushes {
+		event, err := normalize(p.summaryType, p.userID, p.records)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := rc.Handle(r.Context(), event); err != nil {
+			log.Printf("health API push handler failed: %v", err)
+			http.Error(w, "failed to process notification", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Garmin expects a prompt response, so pulls run after replying
+	for _, p := range pings {
+		rc.pending.Add(1)
+		go func(summaryType string, ping Ping) {
+			defer rc.pending.Done()
+			if err := rc.pull(context.Background(), summaryType, ping); err != nil {
+				log.Printf("health API pull for user %s failed: %v", ping.UserID, err)
+			}
+		}(p.summaryType, p.ping)
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Wait blocks until all background pulls have finished
+func (rc *Receiver) Wait() {
+	rc.pending.Wait()
+}
+
+// verify checks the body signature when a secret is configured
+func (rc *Receiver) verify(body []byte, signature string) error {
+	if len(rc.Secret) == 0 {
+		return nil
+	}
+	if signature == "" {
+		return errors.New("missing signature")
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return errors.New("malformed signature")
+	}
+	mac := hmac.New(sha256.New, rc.Secret)
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+func (rc *Receiver) pull(ctx context.Context, summaryType string, ping Ping) error {
+	records, err := rc.Puller.Pull(ctx, ping)
+	if err != nil {
+		return err
+	}
+	event, err := normalize(summaryType, ping.UserID, records)
+	if err != nil {
+		return err
+	}
+	return rc.Handle(ctx, event)
+}
+
+// pushGroup holds pushed records of one summary type for one user
+type pushGroup struct {
+	summaryType string
+	userID      string
+	records     []json.RawMessage
+}
+
+// pullRequest is a ping entry awaiting a pull
+type pullRequest struct {
+	summaryType string
+	ping        Ping
+}
+
+// split separates ping entries from pushed records, grouping pushes per user
+func split(n notification) ([]pushGroup, []pullRequest, error) {
+	types := make([]string, 0, len(n))
+	for t := range n {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	var pushes []pushGroup
+	var pings []pullRequest
+	for _, summaryType := range types {
+		groups := make(map[string]int)
+		for _, raw := range n[summaryType] {
+			var rec record
+			if err := json.Unmarshal(raw, &rec); err != nil {
+				return nil, nil, fmt.Errorf("invalid %s entry: %w", summaryType, err)
+			}
+
+			if rec.CallbackURL != "" {
+				var ping Ping
+				if err := json.Unmarshal(raw, &ping); err != nil {
+					return nil, nil, fmt.Errorf("invalid %s ping: %w", summaryType, err)
+				}
+				pings = append(pings, pullRequest{summaryType: summaryType, ping: ping})
+				continue
+			}
+
+			i, ok := groups[rec.UserID]
+			if !ok {
+				i = len(pushes)
+				groups[rec.UserID] = i
+				pushes = append(pushes, pushGroup{summaryType: summaryType, userID: rec.UserID})
+			}
+			pushes[i].records = append(pushes[i].records, raw)
+		}
+	}
+	return pushes, pings, nil
+}
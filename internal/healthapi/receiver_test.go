@@ -0,0 +1,160 @@
+package healthapi
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+type staticTokens map[string]string
+
+func (s staticTokens) TokenSecret(token string) (string, error) {
+	if secret, ok := s[token]; ok {
+		return secret, nil
+	}
+	return "", errors.New("unknown token")
+}
+
+// collector records delivered events
+type collector struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (c *collector) handle(ctx context.Context, e Event) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.events = append(c.events, e)
+	return nil
+}
+
+func post(rc *Receiver, body string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/garmin/push", strings.NewReader(body))
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	rc.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestReceiverPush(t *testing.T) {
+	c := &collector{}
+	rc := NewReceiver(c.handle)
+
+	body := `{
+		"dailies": [
+			{"userId": "u1", "summaryId": "d1", "calendarDate": "2024-03-01", "steps": 12000, "stepsGoal": 10000,
+			 "distanceInMeters": 9100.5, "activeKilocalories": 640, "moderateIntensityDurationInSeconds": 1200,
+			 "vigorousIntensityDurationInSeconds": 600, "averageStressLevel": 31, "stressQualifier": "balanced"},
+			{"userId": "u2", "summaryId": "d2", "calendarDate": "2024-03-01", "steps": 4000, "stepsGoal": 8000}
+		],
+		"sleeps": [
+			{"userId": "u1", "summaryId": "s1", "calendarDate": "2024-03-01", "durationInSeconds": 27000,
+			 "deepSleepDurationInSeconds": 5400, "remSleepInSeconds": 6000, "overallSleepScore": {"value": 81}}
+		],
+		"activities": [
+			{"userId": "u1", "summaryId": "a1", "activityId": 99, "activityName": "Tempo", "activityType": "RUNNING",
+			 "startTimeInSeconds": 1709280000, "startTimeOffsetInSeconds": 3600, "durationInSeconds": 2400, "distanceInMeters": 8000}
+		]
+	}`
+
+	rec := post(rc, body, nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, c.events, 4)
+
+	// Summary types are processed in sorted order, users in arrival order
+	assert.Equal(t, "activities", c.events[0].SummaryType)
+	activity := c.events[0].Activities[0]
+	assert.Equal(t, int64(99), activity.ActivityID)
+	assert.Equal(t, time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC), activity.StartTime.Time)
+
+	daily := c.events[1]
+	assert.Equal(t, "u1", daily.UserID)
+	assert.Equal(t, 12000, daily.Steps[0].TotalSteps)
+	assert.Equal(t, 30, daily.Steps[0].ActiveMinutes)
+	assert.True(t, daily.Steps[0].StepGoalAchieved)
+	assert.Equal(t, 31, daily.Stress[0].OverallStressLevel)
+	assert.Equal(t, 4000, c.events[2].Steps[0].StepsToGoal)
+
+	sleep := c.events[3].Sleep[0]
+	assert.Equal(t, api.Ptr(27000), sleep.SleepTimeSeconds)
+	assert.Equal(t, api.Ptr(81), sleep.SleepScore)
+}
+
+func TestReceiverSignature(t *testing.T) {
+	c := &collector{}
+	rc := NewReceiver(c.handle)
+	rc.Secret = []byte("s3cret")
+
+	body := `{"dailies": [{"userId": "u1", "calendarDate": "2024-03-01", "steps": 1}]}`
+	mac := hmac.New(sha256.New, rc.Secret)
+	mac.Write([]byte(body))
+	valid := hex.EncodeToString(mac.Sum(nil))
+
+	assert.Equal(t, http.StatusUnauthorized, post(rc, body, nil).Code)
+	assert.Equal(t, http.StatusUnauthorized, post(rc, body, http.Header{SignatureHeader: {"zz"}}).Code)
+	assert.Equal(t, http.StatusUnauthorized, post(rc, body, http.Header{SignatureHeader: {hex.EncodeToString([]byte("wrong"))}}).Code)
+	assert.Equal(t, http.StatusOK, post(rc, body, http.Header{SignatureHeader: {valid}}).Code)
+	assert.Len(t, c.events, 1)
+}
+
+func TestReceiverPingPull(t *testing.T) {
+	var authHeader string
+	garmin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"userId": "u1", "calendarDate": "2024-03-02", "lastNightAvg": 52, "weeklyAvg": 49, "status": "BALANCED"}]`))
+	}))
+	defer garmin.Close()
+
+	c := &collector{}
+	rc := NewReceiver(c.handle)
+
+	body := `{"hrv": [{"userId": "u1", "userAccessToken": "tok", "callbackURL": "` + garmin.URL + `/hrv?token=abc"}]}`
+
+	t.Run("rejected without puller", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotImplemented, post(rc, body, nil).Code)
+	})
+
+	rc.Puller = NewPuller("consumer", "consumer-secret", staticTokens{"tok": "tok-secret"})
+	assert.Equal(t, http.StatusOK, post(rc, body, nil).Code)
+	rc.Wait()
+
+	assert.Contains(t, authHeader, `oauth_consumer_key="consumer"`)
+	assert.Contains(t, authHeader, `oauth_token="tok"`)
+	assert.Len(t, c.events, 1)
+	assert.Equal(t, "BALANCED", c.events[0].HRV[0].HrvStatus)
+	assert.Equal(t, api.Ptr(52.0), c.events[0].HRV[0].LastNightAvg)
+}
+
+func TestReceiverErrors(t *testing.T) {
+	rc := NewReceiver(func(ctx context.Context, e Event) error { return errors.New("storage down") })
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	rc.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	assert.Equal(t, http.StatusBadRequest, post(rc, `not json`, nil).Code)
+	assert.Equal(t, http.StatusBadRequest, post(rc, `{"sleeps": [{"userId": "u1", "calendarDate": "yesterday"}]}`, nil).Code)
+	assert.Equal(t, http.StatusInternalServerError, post(rc, `{"dailies": [{"userId": "u1", "calendarDate": "2024-03-01"}]}`, nil).Code)
+
+	t.Run("unsupported summary types are passed through raw", func(t *testing.T) {
+		c := &collector{}
+		rc := NewReceiver(c.handle)
+		assert.Equal(t, http.StatusOK, post(rc, `{"pulseox": [{"userId": "u1", "spo2": 97}]}`, nil).Code)
+		assert.Len(t, c.events[0].Unsupported, 1)
+	})
+}
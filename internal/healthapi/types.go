@@ -0,0 +1,88 @@
+package healthapi
+
+import "encoding/json"
+
+// Summary types delivered by the Garmin Health API, keyed as in the notification body
+const (
+	SummaryDailies    = "dailies"
+	SummaryActivities = "activities"
+	SummarySleeps     = "sleeps"
+	SummaryStress     = "stressDetails"
+	SummaryHRV        = "hrv"
+)
+
+// Ping is a ping/pull notification entry announcing data available at CallbackURL
+type Ping struct {
+	UserID                   string `json:"userId"`
+	UserAccessToken          string `json:"userAccessToken"`
+	UploadStartTimeInSeconds int64  `json:"uploadStartTimeInSeconds"`
+	UploadEndTimeInSeconds   int64  `json:"uploadEndTimeInSeconds"`
+	CallbackURL              string `json:"callbackURL"`
+}
+
+// record holds the fields common to every pushed summary
+type record struct {
+	UserID          string `json:"userId"`
+	UserAccessToken string `json:"userAccessToken"`
+	SummaryID       string `json:"summaryId"`
+	CallbackURL     string `json:"callbackURL"`
+}
+
+// DailySummary is a pushed "dailies" record
+type DailySummary struct {
+	CalendarDate                       string  `json:"calendarDate"`
+	Steps                              int     `json:"steps"`
+	StepsGoal                          int     `json:"stepsGoal"`
+	DistanceInMeters                   float64 `json:"distanceInMeters"`
+	ActiveKilocalories                 int     `json:"activeKilocalories"`
+	ModerateIntensityDurationInSeconds int     `json:"moderateIntensityDurationInSeconds"`
+	VigorousIntensityDurationInSeconds int     `json:"vigorousIntensityDurationInSeconds"`
+	RestingHeartRateInBeatsPerMinute   int     `json:"restingHeartRateInBeatsPerMinute"`
+	AverageStressLevel                 int     `json:"averageStressLevel"`
+	RestStressDurationInSeconds        int     `json:"restStressDurationInSeconds"`
+	LowStressDurationInSeconds         int     `json:"lowStressDurationInSeconds"`
+	MediumStressDurationInSeconds      int     `json:"mediumStressDurationInSeconds"`
+	HighStressDurationInSeconds        int     `json:"highStressDurationInSeconds"`
+	StressQualifier                    string  `json:"stressQualifier"`
+}
+
+// ActivitySummary is a pushed "activities" record
+type ActivitySummary struct {
+	ActivityID               int64   `json:"activityId"`
+	ActivityName             string  `json:"activityName"`
+	ActivityType             string  `json:"activityType"`
+	StartTimeInSeconds       int64   `json:"startTimeInSeconds"`
+	StartTimeOffsetInSeconds int     `json:"startTimeOffsetInSeconds"`
+	DurationInSeconds        float64 `json:"durationInSeconds"`
+	DistanceInMeters         float64 `json:"distanceInMeters"`
+}
+
+// SleepSummary is a pushed "sleeps" record
+type SleepSummary struct {
+	CalendarDate                string `json:"calendarDate"`
+	DurationInSeconds           int    `json:"durationInSeconds"`
+	DeepSleepDurationInSeconds  int    `json:"deepSleepDurationInSeconds"`
+	LightSleepDurationInSeconds int    `json:"lightSleepDurationInSeconds"`
+	RemSleepInSeconds           int    `json:"remSleepInSeconds"`
+	AwakeDurationInSeconds      int    `json:"awakeDurationInSeconds"`
+	OverallSleepScore           *struct {
+		Value int `json:"value"`
+	} `json:"overallSleepScore"`
+}
+
+// StressSummary is a pushed "stressDetails" record
+type StressSummary struct {
+	CalendarDate       string `json:"calendarDate"`
+	AverageStressLevel int    `json:"averageStressLevel"`
+}
+
+// HRVSummary is a pushed "hrv" record
+type HRVSummary struct {
+	CalendarDate string  `json:"calendarDate"`
+	LastNightAvg float64 `json:"lastNightAvg"`
+	WeeklyAvg    float64 `json:"weeklyAvg"`
+	Status       string  `json:"status"`
+}
+
+// notification is the body of a push or ping request: summary type to records
+type notification map[string][]json.RawMessage
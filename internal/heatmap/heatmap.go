@@ -0,0 +1,197 @@
+// Package heatmap aggregates GPS tracks of many activities into a density grid
+// on the Web Mercator tile scheme used by slippy maps
+package heatmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// DefaultZoom gives cells of about 19 m at the equator, fine enough to keep
+// neighbouring streets apart
+const DefaultZoom = 21
+
+// maxLat is the latitude limit of the Web Mercator projection
+const maxLat = 85.05112878
+
+// Cell is one grid square, addressed like a map tile at the grid's zoom level
+type Cell struct {
+	X, Y int
+}
+
+// CellCount is a cell and the number of tracks passing through it
+type CellCount struct {
+	Cell
+	Count int
+}
+
+// Grid counts how many tracks pass through each cell
+type Grid struct {
+	Zoom  int
+	cells map[Cell]int
+}
+
+// NewGrid creates an empty grid with cells the size of map tiles at zoom
+func NewGrid(zoom int) *Grid {
+	return &Grid{Zoom: zoom, cells: make(map[Cell]int)}
+}
+
+// CellAt returns the cell containing a position at zoom
+func CellAt(lat, lon float64, zoom int) Cell {
+	lat = math.Max(-maxLat, math.Min(maxLat, lat))
+	n := math.Exp2(float64(zoom))
+	rad := lat * math.Pi / 180
+	x := int((lon + 180) / 360 * n)
+	y := int((1 - math.Log(math.Tan(rad)+1/math.Cos(rad))/math.Pi) / 2 * n)
+	limit := int(n) - 1
+	return Cell{X: min(max(x, 0), limit), Y: min(max(y, 0), limit)}
+}
+
+// Bounds returns the south-west and north-east corners of the cell at zoom
+func (c Cell) Bounds(zoom int) (south, west, north, east float64) {
+	n := math.Exp2(float64(zoom))
+	lon := func(x int) float64 { return float64(x)/n*360 - 180 }
+	lat := func(y int) float64 { return math.Atan(math.Sinh(math.Pi*(1-2*float64(y)/n))) * 180 / math.Pi }
+	return lat(c.Y + 1), lon(c.X), lat(c.Y), lon(c.X + 1)
+}
+
+// Add counts the cells a track passes through. Each cell is counted once per
+// track, so pauses and laps on the same loop do not inflate it. Gaps between
+// consecutive points are filled so sparse recordings still draw a line.
+func (g *Grid) Add(track []api.TrackPoint) {
+	seen := make(map[Cell]bool)
+	var prev *Cell
+	for _, p := range track {
+		if p.Lat == nil || p.Lon == nil {
+			prev = nil
+			continue
+		}
+		cell := CellAt(*p.Lat, *p.Lon, g.Zoom)
+		if prev == nil {
+			seen[cell] = true
+		} else {
+			for _, c := range line(*prev, cell) {
+				seen[c] = true
+			}
+		}
+		prev = &cell
+	}
+	for c := range seen {
+		g.cells[c]++
+	}
+}
+
+// line returns the cells on the straight line from a to b, including both ends
+func line(a, b Cell) []Cell {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	steps := max(abs(dx), abs(dy))
+	if steps == 0 {
+		return []Cell{a}
+	}
+	cells := make([]Cell, 0, steps+1)
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		cells = append(cells, Cell{
+			X: a.X + int(math.Round(float64(dx)*t)),
+			Y: a.Y + int(math.Round(float64(dy)*t)),
+		})
+	}
+	return cells
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// Cells returns all visited cells, ordered by row then column
+func (g *Grid) Cells() []CellCount {
+	cells := make([]CellCount, 0, len(g.cells))
+	for c, n := range g.cells {
+		cells = append(cells, CellCount{Cell: c, Count: n})
+	}
+	sortCells(cells)
+	return cells
+}
+
+// Tile returns the cells inside map tile x/y at zoom z, which must not be
+// greater than the grid's zoom
+func (g *Grid) Tile(z, x, y int) ([]CellCount, error) {
+	if z < 0 || z > g.Zoom {
+		return nil, fmt.Errorf("invalid tile zoom %d for grid zoom %d", z, g.Zoom)
+	}
+	shift := g.Zoom - z
+	var cells []CellCount
+	for c, n := range g.cells {
+		if c.X>>shift == x && c.Y>>shift == y {
+			cells = append(cells, CellCount{Cell: c, Count: n})
+		}
+	}
+	sortCells(cells)
+	return cells, nil
+}
+
+func sortCells(cells []CellCount) {
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].Y == cells[j].Y {
+			return cells[i].X < cells[j].X
+		}
+		return cells[i].Y < cells[j].Y
+	})
+}
+
+type featureCollection struct {
+	Type     string    `json:"type"`
+	Features []feature `json:"features"`
+}
+
+type feature struct {
+	Type       string                 `json:"type"`
+	Geometry   geometry               `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type geometry struct {
+	Type        string         `json:"type"`
+	Coordinates [][][2]float64 `json:"coordinates"`
+}
+
+// Encode writes cells as a GeoJSON FeatureCollection of square polygons. Each
+// feature carries the track count and its intensity relative to the busiest
+// cell, between 0 and 1.
+func Encode(w io.Writer, zoom int, cells []CellCount) error {
+	busiest := 0
+	for _, c := range cells {
+		busiest = max(busiest, c.Count)
+	}
+
+	fc := featureCollection{Type: "FeatureCollection", Features: make([]feature, 0, len(cells))}
+	for _, c := range cells {
+		south, west, north, east := c.Bounds(zoom)
+		fc.Features = append(fc.Features, feature{
+			Type: "Feature",
+			Geometry: geometry{
+				Type: "Polygon",
+				Coordinates: [][][2]float64{{
+					{west, south}, {east, south}, {east, north}, {west, north}, {west, south},
+				}},
+			},
+			Properties: map[string]interface{}{
+				"count":     c.Count,
+				"intensity": float64(c.Count) / float64(busiest),
+			},
+		})
+	}
+
+	if err := json.NewEncoder(w).Encode(fc); err != nil {
+		return fmt.Errorf("failed to write GeoJSON: %w", err)
+	}
+	return nil
+}
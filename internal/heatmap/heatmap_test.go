@@ -0,0 +1,81 @@
+package heatmap
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func point(lat, lon float64) api.TrackPoint {
+	return api.TrackPoint{Lat: api.Ptr(lat), Lon: api.Ptr(lon)}
+}
+
+func TestCellAt(t *testing.T) {
+	assert.Equal(t, Cell{X: 0, Y: 0}, CellAt(0, 0, 0))
+	assert.Equal(t, Cell{X: 1, Y: 1}, CellAt(-10, 10, 1))
+	assert.Equal(t, Cell{X: 0, Y: 0}, CellAt(89, -180, 1))
+	// The antimeridian and poles stay inside the grid
+	assert.Equal(t, Cell{X: 3, Y: 3}, CellAt(-90, 180, 2))
+
+	south, west, north, east := Cell{X: 1, Y: 0}.Bounds(1)
+	assert.Equal(t, 0.0, south)
+	assert.Equal(t, 0.0, west)
+	assert.InDelta(t, maxLat, north, 1e-6)
+	assert.Equal(t, 180.0, east)
+}
+
+func TestGrid(t *testing.T) {
+	g := NewGrid(8)
+	a := CellAt(47.0, 8.0, 8)
+
+	// A loop run twice and a paused point still count once per track
+	g.Add([]api.TrackPoint{point(47.0, 8.0), point(47.0, 8.0), {}, point(47.0, 8.0)})
+	g.Add([]api.TrackPoint{point(47.0, 8.0)})
+	// Points five cells apart are joined by a line
+	g.Add([]api.TrackPoint{point(47.0, 8.0), point(47.0, 8.0+5*360.0/256)})
+
+	cells := g.Cells()
+	assert.Len(t, cells, 6)
+	assert.Equal(t, CellCount{Cell: a, Count: 3}, cells[0])
+	for _, c := range cells[1:] {
+		assert.Equal(t, 1, c.Count)
+		assert.Equal(t, a.Y, c.Y)
+	}
+
+	tile, err := g.Tile(4, a.X>>4, a.Y>>4)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tile)
+	assert.Equal(t, a, tile[0].Cell)
+	empty, err := g.Tile(4, 0, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, empty)
+	_, err = g.Tile(9, 0, 0)
+	assert.Error(t, err)
+}
+
+func TestEncode(t *testing.T) {
+	cells := []CellCount{{Cell: Cell{X: 0, Y: 0}, Count: 4}, {Cell: Cell{X: 1, Y: 1}, Count: 1}}
+	var buf bytes.Buffer
+	assert.NoError(t, Encode(&buf, 1, cells))
+
+	var fc struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Geometry struct {
+				Type        string         `json:"type"`
+				Coordinates [][][2]float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]float64 `json:"properties"`
+		} `json:"features"`
+	}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &fc))
+	assert.Equal(t, "FeatureCollection", fc.Type)
+	assert.Len(t, fc.Features, 2)
+	assert.Equal(t, "Polygon", fc.Features[0].Geometry.Type)
+	assert.Equal(t, [2]float64{-180, 0}, fc.Features[0].Geometry.Coordinates[0][0])
+	assert.Equal(t, 1.0, fc.Features[0].Properties["intensity"])
+	assert.Equal(t, 0.25, fc.Features[1].Properties["intensity"])
+}
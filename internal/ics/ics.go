@@ -0,0 +1,103 @@
+package ics
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405"
+	// maxLineOctets is the RFC 5545 content line limit, excluding the line break
+	maxLineOctets = 75
+)
+
+// Event is a single VEVENT entry
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	// AllDay writes Start/End as dates; End is exclusive as RFC 5545 requires
+	AllDay bool
+	// Floating writes times without a zone, to be shown in the viewer's local time
+	Floating bool
+}
+
+// Calendar is an iCalendar (RFC 5545) document
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Encode writes the calendar in iCalendar format
+func (c *Calendar) Encode(w io.Writer) error {
+	lw := &lineWriter{w: w}
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:-//go-garminconnect//EN")
+	lw.line("CALSCALE:GREGORIAN")
+	if c.Name != "" {
+		lw.line("X-WR-CALNAME:" + escape(c.Name))
+	}
+
+	stamp := time.Now().UTC().Format(dateTimeLayout) + "Z"
+	for _, e := range c.Events {
+		lw.line("BEGIN:VEVENT")
+		lw.line("UID:" + escape(e.UID))
+		lw.line("DTSTAMP:" + stamp)
+		switch {
+		case e.AllDay:
+			lw.line("DTSTART;VALUE=DATE:" + e.Start.Format(dateLayout))
+			lw.line("DTEND;VALUE=DATE:" + e.End.Format(dateLayout))
+		case e.Floating:
+			lw.line("DTSTART:" + e.Start.Format(dateTimeLayout))
+			lw.line("DTEND:" + e.End.Format(dateTimeLayout))
+		default:
+			lw.line("DTSTART:" + e.Start.UTC().Format(dateTimeLayout) + "Z")
+			lw.line("DTEND:" + e.End.UTC().Format(dateTimeLayout) + "Z")
+		}
+		lw.line("SUMMARY:" + escape(e.Summary))
+		if e.Description != "" {
+			lw.line("DESCRIPTION:" + escape(e.Description))
+		}
+		lw.line("END:VEVENT")
+	}
+	lw.line("END:VCALENDAR")
+	return lw.err
+}
+
+// escape applies RFC 5545 TEXT escaping
+func escape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+// lineWriter writes CRLF terminated content lines, folding long lines
+type lineWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (lw *lineWriter) line(s string) {
+	if lw.err != nil {
+		return
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range s {
+		size := len(string(r))
+		// Continuation lines start with a space, which counts towards the limit
+		if n+size > maxLineOctets {
+			b.WriteString("\r\n ")
+			n = 1
+		}
+		b.WriteRune(r)
+		n += size
+	}
+	b.WriteString("\r\n")
+	_, lw.err = fmt.Fprint(lw.w, b.String())
+}
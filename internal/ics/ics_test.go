@@ -0,0 +1,63 @@
+package ics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendarEncode(t *testing.T) {
+	cal := &Calendar{
+		Name: "Garmin",
+		Events: []Event{
+			{
+				UID:         "activity-1@garminconnect",
+				Summary:     "Run, easy; recovery",
+				Description: "10.0 km\nAvg HR 140",
+				Start:       time.Date(2024, 3, 1, 7, 0, 0, 0, time.UTC),
+				End:         time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC),
+				Floating:    true,
+			},
+			{
+				UID:     "workout-2@garminconnect",
+				Summary: "Intervals",
+				Start:   time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC),
+				End:     time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC),
+				AllDay:  true,
+			},
+			{
+				UID:     "event-3@garminconnect",
+				Summary: "Race",
+				Start:   time.Date(2024, 3, 9, 9, 0, 0, 0, time.FixedZone("CET", 3600)),
+				End:     time.Date(2024, 3, 9, 11, 0, 0, 0, time.FixedZone("CET", 3600)),
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, cal.Encode(&buf))
+	out := buf.String()
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	assert.Contains(t, out, "SUMMARY:Run\\, easy\\; recovery\r\n")
+	assert.Contains(t, out, "DESCRIPTION:10.0 km\\nAvg HR 140\r\n")
+	assert.Contains(t, out, "DTSTART:20240301T070000\r\n")
+	assert.Contains(t, out, "DTSTART;VALUE=DATE:20240304\r\nDTEND;VALUE=DATE:20240305\r\n")
+	assert.Contains(t, out, "DTSTART:20240309T080000Z\r\n")
+	assert.Equal(t, 3, strings.Count(out, "BEGIN:VEVENT"))
+}
+
+func TestLineFolding(t *testing.T) {
+	var buf bytes.Buffer
+	cal := &Calendar{Events: []Event{{UID: "x", Summary: strings.Repeat("ä", 60)}}}
+	assert.NoError(t, cal.Encode(&buf))
+
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), maxLineOctets)
+	}
+	assert.Contains(t, buf.String(), "\r\n ä")
+}
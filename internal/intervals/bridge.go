@@ -0,0 +1,177 @@
+package intervals
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/analysis"
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// Source defines the Garmin client methods the bridge reads from
+type Source interface {
+	GetActivitiesByDate(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.Activity, error)
+	DownloadActivity(ctx context.Context, activityID int64, opts ...api.RequestOption) ([]byte, error)
+	GetHRVData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.HRVData, error)
+	GetSleepData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.SleepData, error)
+	GetUserStats(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.UserStats, error)
+	GetBodyComposition(ctx context.Context, req api.BodyCompositionRequest, opts ...api.RequestOption) ([]api.BodyComposition, error)
+	GetActivityTrack(ctx context.Context, activityID int64, opts ...api.RequestOption) ([]api.TrackPoint, error)
+	GetActivityPowerSeries(ctx context.Context, activityID int64, opts ...api.RequestOption) ([]api.PowerSample, error)
+}
+
+// Bridge forwards Garmin activities and wellness data to intervals.icu
+type Bridge struct {
+	Source Source
+	Target *Client
+
+	// Records, if set, is updated with the best efforts of every uploaded
+	// activity, and OnRecord is called for each personal record that falls
+	Records  *analysis.RecordBook
+	OnRecord func(analysis.PersonalRecord)
+}
+
+// SyncResult reports what a sync forwarded
+type SyncResult struct {
+	Uploaded   int
+	Duplicates int
+	Days       int
+	Records    []analysis.PersonalRecord
+}
+
+// Sync forwards the activities and wellness data of every day from start to
+// end inclusive. Activities already on intervals.icu are counted as duplicates.
+func (b *Bridge) Sync(ctx context.Context, start, end time.Time) (SyncResult, error) {
+	var result SyncResult
+
+	activities, err := b.Source.GetActivitiesByDate(ctx, start, end)
+	if err != nil {
+		return result, fmt.Errorf("failed to list activities: %w", err)
+	}
+	for _, a := range activities {
+		err := b.ForwardActivity(ctx, a)
+		switch {
+		case errors.Is(err, ErrDuplicate):
+			result.Duplicates++
+		case err != nil:
+			return result, err
+		default:
+			result.Uploaded++
+			records, err := b.CheckRecords(ctx, a)
+			if err != nil {
+				return result, err
+			}
+			result.Records = append(result.Records, records...)
+		}
+	}
+
+	for _, day := range api.Days(start, end) {
+		if err := b.ForwardWellness(ctx, day); err != nil {
+			return result, err
+		}
+		result.Days++
+	}
+	return result, nil
+}
+
+// ForwardActivity uploads the FIT file of one activity
+func (b *Bridge) ForwardActivity(ctx context.Context, a api.Activity) error {
+	fit, err := b.Source.DownloadActivity(ctx, a.ActivityID)
+	if err != nil {
+		return fmt.Errorf("failed to download activity %d: %w", a.ActivityID, err)
+	}
+	_, err = b.Target.UploadActivity(ctx, "garmin-"+strconv.FormatInt(a.ActivityID, 10), a.Name, fit)
+	return err
+}
+
+// CheckRecords updates Records with the best efforts of one activity and
+// returns the personal records it set. Activities already checked set none.
+func (b *Bridge) CheckRecords(ctx context.Context, a api.Activity) ([]analysis.PersonalRecord, error) {
+	if b.Records == nil {
+		return nil, nil
+	}
+	track, err := b.Source.GetActivityTrack(ctx, a.ActivityID)
+	if err := ignoreMissing(err); err != nil {
+		return nil, fmt.Errorf("failed to get track of activity %d: %w", a.ActivityID, err)
+	}
+	power, err := b.Source.GetActivityPowerSeries(ctx, a.ActivityID)
+	if err := ignoreMissing(err); err != nil {
+		return nil, fmt.Errorf("failed to get power of activity %d: %w", a.ActivityID, err)
+	}
+
+	efforts := analysis.BestEfforts(a.ActivityID, a.Type, analysis.ActivityData{Track: track, Power: power})
+	records, err := b.Records.Update(efforts)
+	if err != nil {
+		return nil, err
+	}
+	if b.OnRecord != nil {
+		for _, r := range records {
+			b.OnRecord(r)
+		}
+	}
+	return records, nil
+}
+
+// ForwardWellness sends the HRV, sleep, resting heart rate and weight of one
+// day. Metrics Garmin has no data for are left out.
+func (b *Bridge) ForwardWellness(ctx context.Context, day time.Time) error {
+	w := Wellness{ID: day.Format("2006-01-02")}
+
+	hrv, err := b.Source.GetHRVData(ctx, day)
+	if err := ignoreMissing(err); err != nil {
+		return fmt.Errorf("failed to get HRV data: %w", err)
+	}
+	if hrv != nil {
+		w.HRV = hrv.LastNightAvg
+	}
+
+	sleep, err := b.Source.GetSleepData(ctx, day)
+	if err := ignoreMissing(err); err != nil {
+		return fmt.Errorf("failed to get sleep data: %w", err)
+	}
+	if sleep != nil {
+		w.SleepSecs = sleep.SleepTimeSeconds
+		if sleep.SleepScore != nil {
+			w.SleepScore = api.Ptr(float64(*sleep.SleepScore))
+		}
+	}
+
+	stats, err := b.Source.GetUserStats(ctx, day)
+	if err := ignoreMissing(err); err != nil {
+		return fmt.Errorf("failed to get daily stats: %w", err)
+	}
+	if stats != nil {
+		w.RestingHR = stats.RestingHR
+	}
+
+	day = api.NormalizeDate(day, day.Location())
+	weighIns, err := b.Source.GetBodyComposition(ctx, api.BodyCompositionRequest{
+		StartDate: api.NewGarminTime(day),
+		EndDate:   api.NewGarminTime(day),
+	})
+	if err := ignoreMissing(err); err != nil {
+		return fmt.Errorf("failed to get body composition: %w", err)
+	}
+	// The last weigh-in of the day wins
+	for _, m := range weighIns {
+		if m.Weight > 0 {
+			w.Weight = api.Ptr(m.Weight / 1000)
+		}
+	}
+
+	if w == (Wellness{ID: w.ID}) {
+		return nil
+	}
+	return b.Target.UpdateWellness(ctx, w)
+}
+
+// ignoreMissing drops errors for days Garmin has no data for
+func ignoreMissing(err error) error {
+	if errors.Is(err, api.ErrNotFound{}) {
+		return nil
+	}
+	return err
+}
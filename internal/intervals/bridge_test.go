@@ -0,0 +1,145 @@
+package intervals
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/analysis"
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSource struct{}
+
+func (fakeSource) GetActivitiesByDate(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.Activity, error) {
+	return []api.Activity{{ActivityID: 1, Name: "Morning Run"}, {ActivityID: 2, Name: "Old Ride"}}, nil
+}
+
+func (fakeSource) DownloadActivity(ctx context.Context, activityID int64, opts ...api.RequestOption) ([]byte, error) {
+	return []byte("FIT"), nil
+}
+
+func (fakeSource) GetHRVData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.HRVData, error) {
+	return &api.HRVData{LastNightAvg: api.Ptr(48.0)}, nil
+}
+
+func (fakeSource) GetSleepData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.SleepData, error) {
+	if date.Day() == 2 {
+		return nil, &api.APIError{StatusCode: http.StatusNotFound}
+	}
+	return &api.SleepData{SleepTimeSeconds: api.Ptr(28800), SleepScore: api.Ptr(85)}, nil
+}
+
+func (fakeSource) GetUserStats(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.UserStats, error) {
+	return &api.UserStats{RestingHR: api.Ptr(52)}, nil
+}
+
+func (fakeSource) GetBodyComposition(ctx context.Context, req api.BodyCompositionRequest, opts ...api.RequestOption) ([]api.BodyComposition, error) {
+	return []api.BodyComposition{{Weight: 71200}, {Weight: 70800}}, nil
+}
+
+// GetActivityTrack returns a 1.2 km run at 4 m/s
+func (fakeSource) GetActivityTrack(ctx context.Context, activityID int64, opts ...api.RequestOption) ([]api.TrackPoint, error) {
+	start := time.Date(2024, 3, 1, 7, 0, 0, 0, time.UTC)
+	points := make([]api.TrackPoint, 301)
+	for i := range points {
+		points[i] = api.TrackPoint{
+			Timestamp: api.NewGarminTime(start.Add(time.Duration(i) * time.Second)),
+			Lat:       api.Ptr(0.0),
+			Lon:       api.Ptr(float64(i) * 4 / 111194.93),
+		}
+	}
+	return points, nil
+}
+
+func (fakeSource) GetActivityPowerSeries(ctx context.Context, activityID int64, opts ...api.RequestOption) ([]api.PowerSample, error) {
+	return nil, &api.APIError{StatusCode: http.StatusNotFound}
+}
+
+func TestBridgeSync(t *testing.T) {
+	var mu sync.Mutex
+	wellness := map[string]Wellness{}
+	var uploads []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ := r.BasicAuth()
+		assert.Equal(t, "API_KEY", user)
+		assert.Equal(t, "secret", pass)
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/athlete/0/activities":
+			externalID := r.URL.Query().Get("external_id")
+			if externalID == "garmin-2" {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			file, _, err := r.FormFile("file")
+			assert.NoError(t, err)
+			data, _ := io.ReadAll(file)
+			assert.Equal(t, "FIT", string(data))
+			uploads = append(uploads, externalID)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id": "i100"}`))
+		case r.Method == http.MethodPut:
+			var body Wellness
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "/api/v1/athlete/0/wellness/"+body.ID, r.URL.Path)
+			wellness[body.ID] = body
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	target := NewClient("secret", "")
+	target.HTTPClient.SetBaseURL(server.URL)
+	bridge := &Bridge{Source: fakeSource{}, Target: target}
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	result, err := bridge.Sync(context.Background(), start, start.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, SyncResult{Uploaded: 1, Duplicates: 1, Days: 2}, result)
+	assert.Equal(t, []string{"garmin-1"}, uploads)
+
+	day := wellness["2024-03-01"]
+	assert.Equal(t, api.Ptr(48.0), day.HRV)
+	assert.Equal(t, api.Ptr(28800), day.SleepSecs)
+	assert.Equal(t, api.Ptr(85.0), day.SleepScore)
+	assert.Equal(t, api.Ptr(52), day.RestingHR)
+	assert.Equal(t, api.Ptr(70.8), day.Weight)
+
+	// Days without sleep still forward the other metrics
+	assert.Nil(t, wellness["2024-03-02"].SleepSecs)
+	assert.Equal(t, api.Ptr(52), wellness["2024-03-02"].RestingHR)
+}
+
+func TestBridgeCheckRecords(t *testing.T) {
+	book, err := analysis.OpenRecordBook("")
+	assert.NoError(t, err)
+	var notified []string
+	bridge := &Bridge{
+		Source:   fakeSource{},
+		Records:  book,
+		OnRecord: func(r analysis.PersonalRecord) { notified = append(notified, r.Name) },
+	}
+
+	records, err := bridge.CheckRecords(context.Background(), api.Activity{ActivityID: 1, Type: "running"})
+	assert.NoError(t, err)
+	if assert.Len(t, records, 1, "a run without power sets the 1K only") {
+		assert.Equal(t, "1K", records[0].Name)
+		assert.InDelta(t, 250, records[0].Time.Seconds(), 0.5)
+	}
+	assert.Equal(t, []string{"1K"}, notified)
+
+	records, err = bridge.CheckRecords(context.Background(), api.Activity{ActivityID: 1, Type: "running"})
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+}
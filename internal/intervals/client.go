@@ -0,0 +1,94 @@
+package intervals
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// DefaultBaseURL is the intervals.icu API host
+const DefaultBaseURL = "https://intervals.icu"
+
+// ErrDuplicate is returned when intervals.icu already has an uploaded activity
+var ErrDuplicate = errors.New("activity already exists on intervals.icu")
+
+// Client uploads data to the intervals.icu API of one athlete
+type Client struct {
+	HTTPClient *resty.Client
+	athleteID  string
+}
+
+// NewClient creates a client authenticating with an API key from the
+// intervals.icu settings page. athleteID "0" selects the key's own athlete.
+func NewClient(apiKey, athleteID string) *Client {
+	if athleteID == "" {
+		athleteID = "0"
+	}
+	client := resty.New()
+	client.SetBaseURL(DefaultBaseURL)
+	client.SetTimeout(60 * time.Second)
+	// intervals.icu expects the literal user name API_KEY with the key as password
+	client.SetBasicAuth("API_KEY", apiKey)
+	client.SetHeader("Accept", "application/json")
+	return &Client{HTTPClient: client, athleteID: athleteID}
+}
+
+// Wellness is one day of wellness data. Nil fields are left unchanged on
+// intervals.icu.
+type Wellness struct {
+	ID         string   `json:"id"` // date, YYYY-MM-DD
+	RestingHR  *int     `json:"restingHR,omitempty"`
+	HRV        *float64 `json:"hrv,omitempty"` // rMSSD, ms
+	SleepSecs  *int     `json:"sleepSecs,omitempty"`
+	SleepScore *float64 `json:"sleepScore,omitempty"`
+	Weight     *float64 `json:"weight,omitempty"` // kg
+}
+
+// UploadActivity uploads a FIT file and returns the intervals.icu activity ID.
+// externalID identifies the source activity so repeated uploads are detected;
+// they return ErrDuplicate.
+func (c *Client) UploadActivity(ctx context.Context, externalID, name string, fit []byte) (string, error) {
+	var result struct {
+		ID string `json:"id"`
+	}
+	resp, err := c.HTTPClient.R().
+		SetContext(ctx).
+		SetPathParam("athlete", c.athleteID).
+		SetQueryParam("name", name).
+		SetQueryParam("external_id", externalID).
+		SetFileReader("file", externalID+".fit", bytes.NewReader(fit)).
+		SetResult(&result).
+		Post("/api/v1/athlete/{athlete}/activities")
+	if err != nil {
+		return "", fmt.Errorf("failed to upload activity: %w", err)
+	}
+	if resp.StatusCode() == http.StatusConflict {
+		return "", ErrDuplicate
+	}
+	if resp.IsError() {
+		return "", fmt.Errorf("failed to upload activity: %s: %s", resp.Status(), resp.String())
+	}
+	return result.ID, nil
+}
+
+// UpdateWellness sets the wellness values of one day
+func (c *Client) UpdateWellness(ctx context.Context, w Wellness) error {
+	resp, err := c.HTTPClient.R().
+		SetContext(ctx).
+		SetPathParams(map[string]string{"athlete": c.athleteID, "date": w.ID}).
+		SetHeader("Content-Type", "application/json").
+		SetBody(w).
+		Put("/api/v1/athlete/{athlete}/wellness/{date}")
+	if err != nil {
+		return fmt.Errorf("failed to update wellness: %w", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("failed to update wellness: %s: %s", resp.Status(), resp.String())
+	}
+	return nil
+}
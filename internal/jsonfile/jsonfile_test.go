@@ -0,0 +1,32 @@
+package jsonfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "log.json")
+
+	entries := map[string]int64{"missing": 1}
+	assert.NoError(t, Load(path, &entries), "a missing file is empty")
+	assert.Equal(t, map[string]int64{"missing": 1}, entries)
+
+	assert.NoError(t, Save(path, map[string]int64{"abc": 42}))
+	var loaded map[string]int64
+	assert.NoError(t, Load(path, &loaded))
+	assert.Equal(t, map[string]int64{"abc": 42}, loaded)
+
+	info, err := os.Stat(path)
+	if assert.NoError(t, err) {
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+	files, _ := os.ReadDir(filepath.Dir(path))
+	assert.Len(t, files, 1, "no temporary files are left behind")
+
+	assert.NoError(t, os.WriteFile(path, []byte("{"), 0600))
+	assert.ErrorContains(t, Load(path, &loaded), "failed to parse")
+}
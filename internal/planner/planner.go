@@ -0,0 +1,300 @@
+// Package planner generates periodized running plans towards a goal race as
+// Garmin structured workouts
+package planner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// MinWeeks is the shortest plan Generate builds
+const MinWeeks = 4
+
+// Goal is the race a plan builds towards
+type Goal struct {
+	Name       string        // defaults to "Race"
+	Date       time.Time     // race day
+	Distance   float64       // meters
+	TargetTime time.Duration // finish time aimed for
+}
+
+// Speed returns the goal race pace in meters per second
+func (g Goal) Speed() float64 {
+	return g.Distance / g.TargetTime.Seconds()
+}
+
+// Phase is a training block of the plan
+type Phase string
+
+const (
+	// PhaseBase builds aerobic volume with tempo work
+	PhaseBase Phase = "base"
+	// PhaseBuild adds intervals faster than race pace
+	PhaseBuild Phase = "build"
+	// PhasePeak rehearses race pace at the highest volume
+	PhasePeak Phase = "peak"
+	// PhaseTaper cuts volume so the race is run fresh
+	PhaseTaper Phase = "taper"
+)
+
+// PlannedWorkout is one workout of the plan on the day it is to be run
+type PlannedWorkout struct {
+	Date    time.Time
+	Week    int // from 1
+	Phase   Phase
+	Workout api.Workout
+}
+
+// Generate builds a plan of three runs a week from the week containing start up
+// to race day: a quality session, an easy run and a long run. Every fourth
+// week of the base and build phases is a lighter recovery week. Weeks end on
+// the race's weekday, so a leading partial week is left out.
+func Generate(goal Goal, start time.Time) ([]PlannedWorkout, error) {
+	if goal.Distance <= 0 || goal.TargetTime <= 0 {
+		return nil, errors.New("goal distance and target time are required")
+	}
+	if goal.Name == "" {
+		goal.Name = "Race"
+	}
+	race := api.NormalizeDate(goal.Date, goal.Date.Location())
+	start = api.NormalizeDate(start, race.Location())
+	weeks := (int(race.Sub(start).Hours()/24+0.5) + 1) / 7
+	if weeks < MinWeeks {
+		return nil, fmt.Errorf("goal race is less than %d weeks away", MinWeeks)
+	}
+
+	p := paces(goal.Speed())
+	phases := periodize(weeks)
+	longPeak := math.Min(math.Max(goal.Distance*0.75, 10000), 32000)
+	progression := 0
+	for _, ph := range phases {
+		if ph == PhaseBase || ph == PhaseBuild {
+			progression++
+		}
+	}
+
+	var plan []PlannedWorkout
+	done := 0
+	for i, phase := range phases {
+		week := i + 1
+		weekStart := race.AddDate(0, 0, 7*(week-weeks)-6)
+		recovery := (phase == PhaseBase || phase == PhaseBuild) && week%4 == 0
+		add := func(day int, w api.Workout) {
+			w.Name = fmt.Sprintf("%s W%d: %s", goal.Name, week, w.Name)
+			w.SportType = "running"
+			plan = append(plan, PlannedWorkout{Date: weekStart.AddDate(0, 0, day), Week: week, Phase: phase, Workout: w})
+		}
+
+		long := longPeak
+		switch {
+		case phase == PhaseTaper:
+			long *= 0.6
+		case phase == PhasePeak:
+		default:
+			long *= 0.6 + 0.4*float64(done)/float64(progression)
+			done++
+		}
+		if recovery {
+			long *= 0.8
+		}
+
+		switch {
+		case recovery:
+			add(1, easyRun(p, 30*time.Minute))
+		case phase == PhaseBase:
+			add(1, tempoRun(p, 15*time.Minute+time.Duration(done)*time.Minute))
+		case phase == PhaseBuild:
+			add(1, intervals(p, min(4+done/3, 8)))
+		case phase == PhasePeak:
+			add(1, racePaceRun(p, math.Min(math.Max(goal.Distance/5, 1000), 5000)))
+		default:
+			add(1, sharpener(p))
+		}
+		if week == weeks {
+			add(3, easyRun(p, 25*time.Minute))
+			raceRun := workout("Race", step(api.StepInterval, "distance", goal.Distance, p.race))
+			raceRun.Description = fmt.Sprintf("Goal: %s in %s", formatDistance(goal.Distance), goal.TargetTime)
+			add(6, raceRun)
+			continue
+		}
+		easy := 45 * time.Minute
+		if phase == PhaseTaper {
+			easy = 30 * time.Minute
+		}
+		add(3, easyRun(p, easy))
+		add(5, longRun(p, math.Round(long/500)*500))
+	}
+	return plan, nil
+}
+
+// periodize assigns a phase to each week: the last one or two weeks taper and
+// the weeks before split into base, build and peak
+func periodize(weeks int) []Phase {
+	taper := 1
+	if weeks >= 10 {
+		taper = 2
+	}
+	rest := weeks - taper
+	peak := rest / 4
+	build := rest * 35 / 100
+	base := rest - peak - build
+
+	phases := make([]Phase, 0, weeks)
+	for i := range weeks {
+		switch {
+		case i < base:
+			phases = append(phases, PhaseBase)
+		case i < base+build:
+			phases = append(phases, PhaseBuild)
+		case i < rest:
+			phases = append(phases, PhasePeak)
+		default:
+			phases = append(phases, PhaseTaper)
+		}
+	}
+	return phases
+}
+
+// paceRange is a pace target in meters per second
+type paceRange struct {
+	low, high float64
+}
+
+// trainingPaces are the pace targets derived from the goal race pace
+type trainingPaces struct {
+	easy, tempo, interval, race paceRange
+}
+
+func paces(speed float64) trainingPaces {
+	at := func(low, high float64) paceRange { return paceRange{speed * low, speed * high} }
+	return trainingPaces{
+		easy:     at(0.75, 0.85),
+		tempo:    at(0.95, 1.0),
+		interval: at(1.05, 1.1),
+		race:     at(0.98, 1.02),
+	}
+}
+
+func workout(name string, steps ...api.WorkoutStep) api.Workout {
+	order := 0
+	var number func([]api.WorkoutStep)
+	number = func(steps []api.WorkoutStep) {
+		for i := range steps {
+			order++
+			steps[i].Order = order
+			number(steps[i].Steps)
+		}
+	}
+	number(steps)
+	return api.Workout{Name: name, Steps: steps}
+}
+
+// step builds a step ending after value seconds ("time") or meters
+// ("distance"), with a pace target unless target is the zero range
+func step(kind, endCondition string, value float64, target paceRange) api.WorkoutStep {
+	s := api.WorkoutStep{
+		Type:              kind,
+		EndCondition:      endCondition,
+		EndConditionValue: api.Ptr(value),
+		TargetType:        "no.target",
+	}
+	if target != (paceRange{}) {
+		s.TargetType = "pace.zone"
+		s.TargetLow = api.Ptr(target.low)
+		s.TargetHigh = api.Ptr(target.high)
+	}
+	return s
+}
+
+func timed(kind string, d time.Duration, target paceRange) api.WorkoutStep {
+	return step(kind, "time", d.Seconds(), target)
+}
+
+func repeat(times int, steps ...api.WorkoutStep) api.WorkoutStep {
+	return api.WorkoutStep{Type: api.StepRepeat, Iterations: times, Steps: steps}
+}
+
+func easyRun(p trainingPaces, d time.Duration) api.Workout {
+	return workout("Easy", timed(api.StepInterval, d, p.easy))
+}
+
+func longRun(p trainingPaces, meters float64) api.Workout {
+	return workout("Long run "+formatDistance(meters), step(api.StepInterval, "distance", meters, p.easy))
+}
+
+func tempoRun(p trainingPaces, d time.Duration) api.Workout {
+	return workout("Tempo",
+		timed(api.StepWarmup, 10*time.Minute, p.easy),
+		timed(api.StepInterval, d, p.tempo),
+		timed(api.StepCooldown, 10*time.Minute, p.easy),
+	)
+}
+
+func intervals(p trainingPaces, reps int) api.Workout {
+	return workout(fmt.Sprintf("%d x 1 km", reps),
+		timed(api.StepWarmup, 15*time.Minute, p.easy),
+		repeat(reps,
+			step(api.StepInterval, "distance", 1000, p.interval),
+			timed(api.StepRecovery, 2*time.Minute, paceRange{}),
+		),
+		timed(api.StepCooldown, 10*time.Minute, p.easy),
+	)
+}
+
+func racePaceRun(p trainingPaces, meters float64) api.Workout {
+	return workout("Race pace 3 x "+formatDistance(meters),
+		timed(api.StepWarmup, 15*time.Minute, p.easy),
+		repeat(3,
+			step(api.StepInterval, "distance", meters, p.race),
+			timed(api.StepRecovery, 3*time.Minute, paceRange{}),
+		),
+		timed(api.StepCooldown, 10*time.Minute, p.easy),
+	)
+}
+
+func sharpener(p trainingPaces) api.Workout {
+	return workout("Sharpener",
+		timed(api.StepWarmup, 10*time.Minute, p.easy),
+		repeat(4,
+			timed(api.StepInterval, 2*time.Minute, p.race),
+			timed(api.StepRecovery, 2*time.Minute, paceRange{}),
+		),
+		timed(api.StepCooldown, 10*time.Minute, p.easy),
+	)
+}
+
+func formatDistance(meters float64) string {
+	if meters >= 1000 {
+		return fmt.Sprintf("%g km", math.Round(meters/100)/10)
+	}
+	return fmt.Sprintf("%g m", math.Round(meters))
+}
+
+// Scheduler defines the client methods Schedule uses
+type Scheduler interface {
+	CreateWorkout(ctx context.Context, workout api.Workout, opts ...api.RequestOption) (*api.Workout, error)
+	ScheduleWorkout(ctx context.Context, workoutID int64, date time.Time, opts ...api.RequestOption) (*api.ScheduledWorkout, error)
+}
+
+// Schedule saves each workout of the plan to the library and places it on the
+// calendar. It stops at the first failure, returning what was scheduled.
+func Schedule(ctx context.Context, s Scheduler, plan []PlannedWorkout) ([]api.ScheduledWorkout, error) {
+	var scheduled []api.ScheduledWorkout
+	for _, p := range plan {
+		created, err := s.CreateWorkout(ctx, p.Workout)
+		if err != nil {
+			return scheduled, err
+		}
+		sw, err := s.ScheduleWorkout(ctx, created.ID, p.Date)
+		if err != nil {
+			return scheduled, err
+		}
+		scheduled = append(scheduled, *sw)
+	}
+	return scheduled, nil
+}
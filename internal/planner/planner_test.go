@@ -0,0 +1,87 @@
+package planner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+var marathon = Goal{
+	Name:       "Berlin",
+	Date:       time.Date(2024, 9, 29, 0, 0, 0, 0, time.UTC), // a Sunday
+	Distance:   42195,
+	TargetTime: 3*time.Hour + 30*time.Minute,
+}
+
+func TestGenerate(t *testing.T) {
+	// A Wednesday, so the partial week up to Sunday is left out
+	plan, err := Generate(marathon, time.Date(2024, 6, 5, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Len(t, plan, 16*3)
+
+	first := plan[0]
+	assert.Equal(t, 1, first.Week)
+	assert.Equal(t, PhaseBase, first.Phase)
+	assert.Equal(t, time.Tuesday, first.Date.Weekday())
+	assert.Equal(t, "Berlin W1: Tempo", first.Workout.Name)
+	assert.Equal(t, "running", first.Workout.SportType)
+
+	race := plan[len(plan)-1]
+	assert.Equal(t, marathon.Date, race.Date)
+	assert.Equal(t, PhaseTaper, race.Phase)
+	assert.Equal(t, 42195.0, *race.Workout.Steps[0].EndConditionValue)
+	assert.InDelta(t, marathon.Speed(), (*race.Workout.Steps[0].TargetLow+*race.Workout.Steps[0].TargetHigh)/2, 1e-9)
+
+	phases := map[Phase]int{}
+	var longRuns []float64
+	for _, p := range plan {
+		phases[p.Phase]++
+		if p.Date.Weekday() == time.Saturday {
+			longRuns = append(longRuns, *p.Workout.Steps[0].EndConditionValue)
+		}
+	}
+	assert.Equal(t, map[Phase]int{PhaseBase: 21, PhaseBuild: 12, PhasePeak: 9, PhaseTaper: 6}, phases)
+	assert.Equal(t, 19000.0, longRuns[0])
+	assert.Less(t, longRuns[3], longRuns[2], "week 4 is a recovery week")
+	assert.Equal(t, 31500.0, longRuns[12], "the peak long run")
+	assert.Equal(t, "Easy", plan[9].Workout.Name[len("Berlin W4: "):])
+
+	// Steps are numbered through repeat groups
+	build := plan[8*3].Workout
+	assert.Equal(t, PhaseBuild, plan[8*3].Phase)
+	assert.Equal(t, api.StepRepeat, build.Steps[1].Type)
+	assert.Equal(t, 3, build.Steps[1].Steps[0].Order)
+	assert.Equal(t, 5, build.Steps[2].Order)
+
+	_, err = Generate(marathon, marathon.Date.AddDate(0, 0, -20))
+	assert.Error(t, err)
+}
+
+type fakeScheduler struct {
+	scheduled map[int64]time.Time
+}
+
+func (f *fakeScheduler) CreateWorkout(ctx context.Context, workout api.Workout, opts ...api.RequestOption) (*api.Workout, error) {
+	workout.ID = int64(len(f.scheduled) + 1)
+	return &workout, nil
+}
+
+func (f *fakeScheduler) ScheduleWorkout(ctx context.Context, workoutID int64, date time.Time, opts ...api.RequestOption) (*api.ScheduledWorkout, error) {
+	f.scheduled[workoutID] = date
+	return &api.ScheduledWorkout{WorkoutID: workoutID, Date: api.NewDate(date)}, nil
+}
+
+func TestSchedule(t *testing.T) {
+	plan, err := Generate(Goal{Date: marathon.Date, Distance: 5000, TargetTime: 20 * time.Minute}, marathon.Date.AddDate(0, 0, -27))
+	assert.NoError(t, err)
+	assert.Len(t, plan, 12)
+
+	s := &fakeScheduler{scheduled: map[int64]time.Time{}}
+	scheduled, err := Schedule(context.Background(), s, plan)
+	assert.NoError(t, err)
+	assert.Len(t, scheduled, 12)
+	assert.Equal(t, marathon.Date, s.scheduled[12])
+}
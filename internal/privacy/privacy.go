@@ -0,0 +1,58 @@
+// Package privacy removes location details that identify an athlete from
+// activity tracks before they are exported or shared
+package privacy
+
+import (
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/geo"
+)
+
+// DefaultRadius is the distance in meters hidden around the start and end of
+// a track, enough to cover a house and its street
+const DefaultRadius = 200.0
+
+// TrimEnds removes the points at the start of the track until it first leaves
+// radius meters around its starting position, and likewise at the end, so the
+// export doesn't reveal where the activity began and finished. Points without
+// a position inside the trimmed stretches are removed too.
+func TrimEnds(points []api.TrackPoint, radius float64) []api.TrackPoint {
+	first := -1
+	for i, p := range points {
+		if geo.HasPosition(p) {
+			first = i
+			break
+		}
+	}
+	if first < 0 || radius <= 0 {
+		return points
+	}
+	last := first
+	for i := len(points) - 1; i > first; i-- {
+		if geo.HasPosition(points[i]) {
+			last = i
+			break
+		}
+	}
+
+	start, end := len(points), 0
+	origin := points[first]
+	for i := first; i < len(points); i++ {
+		p := points[i]
+		if geo.HasPosition(p) && geo.Distance(*origin.Lat, *origin.Lon, *p.Lat, *p.Lon) > radius {
+			start = i
+			break
+		}
+	}
+	finish := points[last]
+	for i := last; i >= 0; i-- {
+		p := points[i]
+		if geo.HasPosition(p) && geo.Distance(*finish.Lat, *finish.Lon, *p.Lat, *p.Lon) > radius {
+			end = i + 1
+			break
+		}
+	}
+	if start >= end {
+		return nil
+	}
+	return points[start:end]
+}
@@ -0,0 +1,55 @@
+package privacy
+
+import (
+	"testing"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+// track walks north from the equator in steps of about 111 m
+func track(steps int) []api.TrackPoint {
+	points := make([]api.TrackPoint, steps)
+	for i := range points {
+		points[i] = api.TrackPoint{Lat: api.Ptr(float64(i) * 0.001), Lon: api.Ptr(0.0)}
+	}
+	return points
+}
+
+func TestTrimEnds(t *testing.T) {
+	points := track(11) // 0 to ~1112 m
+	// A point without position before the first fix is trimmed with the start
+	points = append([]api.TrackPoint{{HeartRate: api.Ptr(90.0)}}, points...)
+
+	trimmed := TrimEnds(points, 250)
+	assert.Len(t, trimmed, 5)
+	assert.InDelta(t, 0.003, *trimmed[0].Lat, 1e-9)
+	assert.InDelta(t, 0.007, *trimmed[len(trimmed)-1].Lat, 1e-9)
+
+	assert.Equal(t, points, TrimEnds(points, 0))
+	assert.Empty(t, TrimEnds(track(3), 250), "a track that never leaves the radius is hidden entirely")
+	assert.Empty(t, TrimEnds(nil, 250))
+}
+
+func TestApplyZones(t *testing.T) {
+	home := Zone{Name: "home", Lat: 0, Lon: 0, Radius: 250}
+	points := append(track(6), api.TrackPoint{HeartRate: api.Ptr(120.0)})
+
+	trimmed := ApplyZones(points, []Zone{home}, ZoneTrim)
+	assert.Len(t, trimmed, 4, "points within 250 m of home are removed")
+	assert.InDelta(t, 0.003, *trimmed[0].Lat, 1e-9)
+	assert.Nil(t, trimmed[3].Lat, "points without position are kept")
+
+	fuzzed := ApplyZones(points, []Zone{home}, ZoneFuzz)
+	assert.Len(t, fuzzed, len(points))
+	for _, p := range fuzzed[:3] {
+		// All points near home collapse into the same coarse cell
+		assert.Equal(t, *fuzzed[0].Lat, *p.Lat)
+		assert.Equal(t, *fuzzed[0].Lon, *p.Lon)
+	}
+	assert.NotEqual(t, 0.0, *fuzzed[0].Lat)
+	assert.Equal(t, 0.0, *points[0].Lat, "the input is not modified")
+	assert.Equal(t, *points[4].Lat, *fuzzed[4].Lat)
+
+	assert.Equal(t, points, ApplyZones(points, nil, ZoneTrim))
+}
@@ -0,0 +1,104 @@
+package privacy
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/geo"
+)
+
+// Zone is an area, such as home or work, whose points are hidden from exports
+type Zone struct {
+	Name   string  `json:"name"`
+	Lat    float64 `json:"lat"`
+	Lon    float64 `json:"lon"`
+	Radius float64 `json:"radius"` // meters; DefaultRadius when 0
+}
+
+// ZoneMode selects how points inside a zone are hidden
+type ZoneMode string
+
+const (
+	// ZoneTrim removes the points inside a zone
+	ZoneTrim ZoneMode = "trim"
+	// ZoneFuzz keeps the points but snaps their positions to a grid as coarse
+	// as the zone, so the track stays continuous without the exact location
+	ZoneFuzz ZoneMode = "fuzz"
+)
+
+// metersPerDegree is the length of one degree of latitude
+const metersPerDegree = 6371000.0 * math.Pi / 180
+
+// radius returns the zone radius in meters
+func (z Zone) radius() float64 {
+	if z.Radius <= 0 {
+		return DefaultRadius
+	}
+	return z.Radius
+}
+
+// Contains reports whether a position lies inside the zone
+func (z Zone) Contains(lat, lon float64) bool {
+	return geo.Distance(z.Lat, z.Lon, lat, lon) <= z.radius()
+}
+
+// LoadZones reads privacy zones from a JSON array
+func LoadZones(path string) ([]Zone, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read privacy zones: %w", err)
+	}
+	var zones []Zone
+	if err := json.Unmarshal(data, &zones); err != nil {
+		return nil, fmt.Errorf("failed to parse privacy zones: %w", err)
+	}
+	return zones, nil
+}
+
+// ApplyZones hides the points of a track that lie inside any of zones using
+// mode. Points without a position are kept. The input is not modified.
+func ApplyZones(points []api.TrackPoint, zones []Zone, mode ZoneMode) []api.TrackPoint {
+	if len(zones) == 0 {
+		return points
+	}
+	out := make([]api.TrackPoint, 0, len(points))
+	for _, p := range points {
+		zone, ok := zoneOf(p, zones)
+		if !ok {
+			out = append(out, p)
+			continue
+		}
+		if mode == ZoneTrim {
+			continue
+		}
+		p.Lat, p.Lon = zone.snap(*p.Lat, *p.Lon)
+		out = append(out, p)
+	}
+	return out
+}
+
+// zoneOf returns the first zone containing p
+func zoneOf(p api.TrackPoint, zones []Zone) (Zone, bool) {
+	if !geo.HasPosition(p) {
+		return Zone{}, false
+	}
+	for _, z := range zones {
+		if z.Contains(*p.Lat, *p.Lon) {
+			return z, true
+		}
+	}
+	return Zone{}, false
+}
+
+// snap moves a position to the centre of its cell on a grid of cells as wide
+// as the zone radius. The grid is fixed per zone, so nearby points collapse
+// into the same cell.
+func (z Zone) snap(lat, lon float64) (*float64, *float64) {
+	latStep := z.radius() / metersPerDegree
+	lonStep := latStep / math.Max(math.Cos(z.Lat*math.Pi/180), 0.01)
+	return api.Ptr((math.Floor(lat/latStep) + 0.5) * latStep),
+		api.Ptr((math.Floor(lon/lonStep) + 0.5) * lonStep)
+}
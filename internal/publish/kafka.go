@@ -0,0 +1,78 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/sstent/go-garminconnect/internal/watch"
+)
+
+// KafkaRESTPublisher produces events to a Kafka topic through a Kafka REST
+// Proxy (Confluent v2 API); it does not speak the Kafka protocol to brokers.
+// TLS to the proxy is used for https:// URLs. Events are keyed by account so
+// each account's events stay in order on one partition.
+type KafkaRESTPublisher struct {
+	HTTPClient *resty.Client
+	Topic      string
+}
+
+// NewKafkaRESTPublisher creates a publisher for the proxy at baseURL, e.g.
+// "http://localhost:8082"
+func NewKafkaRESTPublisher(baseURL, topic string) *KafkaRESTPublisher {
+	client := resty.New()
+	client.SetBaseURL(baseURL)
+	client.SetTimeout(30 * time.Second)
+	client.SetHeader("Accept", "application/vnd.kafka.v2+json")
+	return &KafkaRESTPublisher{HTTPClient: client, Topic: topic}
+}
+
+type kafkaRecord struct {
+	Key   *string     `json:"key"`
+	Value watch.Event `json:"value"`
+}
+
+// Publish implements Publisher
+func (p *KafkaRESTPublisher) Publish(ctx context.Context, events []watch.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	records := make([]kafkaRecord, len(events))
+	for i, e := range events {
+		records[i] = kafkaRecord{Value: e}
+		if e.Account != "" {
+			records[i].Key = &e.Account
+		}
+	}
+
+	var result struct {
+		Offsets []struct {
+			Error *string `json:"error"`
+		} `json:"offsets"`
+	}
+	resp, err := p.HTTPClient.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/vnd.kafka.json.v2+json").
+		SetPathParam("topic", p.Topic).
+		SetBody(map[string]interface{}{"records": records}).
+		SetResult(&result).
+		Post("/topics/{topic}")
+	if err != nil {
+		return fmt.Errorf("failed to publish to Kafka: %w", err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("failed to publish to Kafka: %s: %s", resp.Status(), resp.String())
+	}
+	for i, o := range result.Offsets {
+		if o.Error != nil {
+			return fmt.Errorf("failed to publish event %d to Kafka: %s", i, *o.Error)
+		}
+	}
+	return nil
+}
+
+// Close implements Publisher
+func (p *KafkaRESTPublisher) Close() error {
+	return nil
+}
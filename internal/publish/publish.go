@@ -0,0 +1,94 @@
+// Package publish forwards watch events to message buses, so platforms
+// ingesting many accounts can consume them without running the CLI's output
+package publish
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sstent/go-garminconnect/internal/watch"
+)
+
+// Publisher delivers events to a message bus
+type Publisher interface {
+	// Publish returns once the bus has accepted every event
+	Publish(ctx context.Context, events []watch.Event) error
+	Close() error
+}
+
+// Multi publishes to every publisher in turn, returning their joined errors
+type Multi []Publisher
+
+// Publish implements Publisher
+func (m Multi) Publish(ctx context.Context, events []watch.Event) error {
+	var errs []error
+	for _, p := range m {
+		errs = append(errs, p.Publish(ctx, events))
+	}
+	return errors.Join(errs...)
+}
+
+// Close implements Publisher
+func (m Multi) Close() error {
+	var errs []error
+	for _, p := range m {
+		errs = append(errs, p.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// DefaultRetryLimit is how many undelivered events a Retrying publisher keeps
+const DefaultRetryLimit = 10000
+
+// Retrying keeps the events a publish failed to deliver and sends them again,
+// ahead of the new ones, on the next Publish, so a bus outage between polls
+// loses nothing. Events may be delivered twice, e.g. to the publishers of a
+// Multi that succeeded the first time. It is not safe for concurrent use.
+type Retrying struct {
+	Publisher Publisher
+	// Limit caps the undelivered events kept; the oldest are dropped beyond
+	// it. Zero means DefaultRetryLimit.
+	Limit int
+
+	pending []watch.Event
+	dropped int
+}
+
+// Publish implements Publisher. The error reports how many events are kept
+// for the next attempt.
+func (r *Retrying) Publish(ctx context.Context, events []watch.Event) error {
+	r.pending = append(r.pending, events...)
+	limit := r.Limit
+	if limit <= 0 {
+		limit = DefaultRetryLimit
+	}
+	if over := len(r.pending) - limit; over > 0 {
+		r.pending = append([]watch.Event(nil), r.pending[over:]...)
+		r.dropped += over
+	}
+	if len(r.pending) == 0 {
+		return nil
+	}
+
+	if err := r.Publisher.Publish(ctx, r.pending); err != nil {
+		return fmt.Errorf("%d events kept for retry: %w", len(r.pending), err)
+	}
+	r.pending = nil
+	return nil
+}
+
+// Pending returns the number of events waiting to be delivered
+func (r *Retrying) Pending() int {
+	return len(r.pending)
+}
+
+// Dropped returns the number of events discarded because of Limit
+func (r *Retrying) Dropped() int {
+	return r.dropped
+}
+
+// Close implements Publisher
+func (r *Retrying) Close() error {
+	return r.Publisher.Close()
+}
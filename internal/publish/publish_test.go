@@ -0,0 +1,181 @@
+package publish
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/watch"
+	"github.com/stretchr/testify/assert"
+)
+
+var events = []watch.Event{
+	{Type: watch.EventActivity, Account: "sam", Detected: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), Activity: &api.Activity{ActivityID: 7}},
+	{Type: watch.EventDailyMetric, Account: "sam", Date: "2024-03-01", Metric: "steps", Value: api.Ptr(9000.0)},
+}
+
+// fakeNATS accepts connections and records their CONNECT options and the
+// subjects and payloads published. Sending on drop closes every open
+// connection.
+func fakeNATS(t *testing.T) (addr string, received chan string, drop chan struct{}) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	received = make(chan string, 10)
+	drop = make(chan struct{})
+	conns := make(chan net.Conn, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conns <- conn
+			go serveNATS(conn, received)
+		}
+	}()
+	go func() {
+		for range drop {
+			for len(conns) > 0 {
+				(<-conns).Close()
+			}
+		}
+	}()
+	return ln.Addr().String(), received, drop
+}
+
+func serveNATS(conn net.Conn, received chan<- string) {
+	defer conn.Close()
+	fmt.Fprint(conn, "INFO {\"server_id\":\"test\",\"max_payload\":1048576}\r\n")
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case line == "PING":
+			fmt.Fprint(conn, "PONG\r\n")
+		case strings.HasPrefix(line, "CONNECT "):
+			received <- line
+		case strings.HasPrefix(line, "PUB "):
+			var subject string
+			var size int
+			fmt.Sscanf(line, "PUB %s %d", &subject, &size)
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(r, payload); err != nil {
+				return
+			}
+			received <- subject + " " + string(payload[:size])
+		}
+	}
+}
+
+func TestNATSPublisher(t *testing.T) {
+	addr, received, drop := fakeNATS(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	p, err := DialNATS(ctx, "nats://garmin:secret@"+addr, "garmin.events")
+	assert.NoError(t, err)
+	defer p.Close()
+	var connect map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(<-received, "CONNECT ")), &connect))
+	assert.Equal(t, "garmin", connect["user"])
+	assert.Equal(t, "secret", connect["pass"])
+
+	assert.NoError(t, p.Publish(ctx, events))
+	subject, payload, _ := strings.Cut(<-received, " ")
+	assert.Equal(t, "garmin.events.activity", subject)
+	var event watch.Event
+	assert.NoError(t, json.Unmarshal([]byte(payload), &event))
+	assert.Equal(t, int64(7), event.Activity.ActivityID)
+	subject, _, _ = strings.Cut(<-received, " ")
+	assert.Equal(t, "garmin.events.daily_metric", subject)
+
+	// A dropped connection is re-established
+	drop <- struct{}{}
+	assert.True(t, strings.HasPrefix(<-received, "CONNECT "))
+	assert.NoError(t, p.Publish(ctx, events[:1]))
+	subject, _, _ = strings.Cut(<-received, " ")
+	assert.Equal(t, "garmin.events.activity", subject)
+}
+
+func TestKafkaRESTPublisher(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, []string{"/topics/garmin-events", "/topics/full"}, r.URL.Path)
+		assert.Equal(t, "application/vnd.kafka.json.v2+json", r.Header.Get("Content-Type"))
+		var body struct {
+			Records []struct {
+				Key   *string     `json:"key"`
+				Value watch.Event `json:"value"`
+			} `json:"records"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Len(t, body.Records, 2)
+		assert.Equal(t, "sam", *body.Records[0].Key)
+		assert.Equal(t, "steps", body.Records[1].Value.Metric)
+
+		w.Header().Set("Content-Type", "application/vnd.kafka.v2+json")
+		if r.URL.Path == "/topics/full" {
+			w.Write([]byte(`{"offsets": [{"partition": 0, "offset": -1, "error_code": 50002, "error": "topic full"}]}`))
+			return
+		}
+		w.Write([]byte(`{"offsets": [{"partition": 0, "offset": 10}, {"partition": 0, "offset": 11}]}`))
+	}))
+	defer server.Close()
+
+	p := NewKafkaRESTPublisher(server.URL, "garmin-events")
+	assert.NoError(t, p.Publish(context.Background(), events))
+
+	full := NewKafkaRESTPublisher(server.URL, "full")
+	err := Multi{p, full}.Publish(context.Background(), events)
+	assert.ErrorContains(t, err, "topic full")
+}
+
+// flakyPublisher fails while down is set and records what it delivered
+type flakyPublisher struct {
+	down      bool
+	delivered []watch.Event
+}
+
+func (p *flakyPublisher) Publish(ctx context.Context, events []watch.Event) error {
+	if p.down {
+		return fmt.Errorf("bus unavailable")
+	}
+	p.delivered = append(p.delivered, events...)
+	return nil
+}
+
+func (p *flakyPublisher) Close() error { return nil }
+
+func TestRetrying(t *testing.T) {
+	ctx := context.Background()
+	bus := &flakyPublisher{down: true}
+	r := &Retrying{Publisher: bus, Limit: 3}
+
+	err := r.Publish(ctx, events)
+	assert.ErrorContains(t, err, "2 events kept for retry: bus unavailable")
+	assert.Equal(t, 2, r.Pending())
+
+	more := []watch.Event{{Type: watch.EventDailyMetric, Metric: "sleep"}, {Type: watch.EventDailyMetric, Metric: "hrv"}}
+	assert.Error(t, r.Publish(ctx, more))
+	assert.Equal(t, 3, r.Pending())
+	assert.Equal(t, 1, r.Dropped())
+
+	bus.down = false
+	assert.NoError(t, r.Publish(ctx, nil))
+	assert.Equal(t, 0, r.Pending())
+	assert.Equal(t, []watch.Event{events[1], more[0], more[1]}, bus.delivered)
+}
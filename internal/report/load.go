@@ -0,0 +1,134 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"math"
+	"text/template"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/analysis"
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// LoadSource defines the client methods needed to build a training load report
+type LoadSource interface {
+	GetActivitiesByDate(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.Activity, error)
+}
+
+// Load reports the acute and chronic training load and their ratio per day
+type Load struct {
+	Start   time.Time            `json:"start"`
+	End     time.Time            `json:"end"`
+	Options analysis.LoadOptions `json:"options"`
+	Days    []analysis.LoadPoint `json:"days"`
+	Latest  analysis.LoadPoint   `json:"latest"`
+}
+
+// BuildLoad computes the training load for the days from end-days+1 to end.
+// Activities from three chronic windows before that warm up the averages.
+// The load of each activity comes from the activity list, so only the list is
+// fetched.
+func BuildLoad(ctx context.Context, src LoadSource, end time.Time, days int, opts analysis.LoadOptions) (*Load, error) {
+	opts, start, warmup := loadPeriod(end, days, opts)
+	activities, err := src.GetActivitiesByDate(ctx, warmup, end)
+	if err != nil {
+		return nil, err
+	}
+	return computeLoad(activities, start, end, opts), nil
+}
+
+// loadPeriod returns opts with the defaults filled in, the first day of a load
+// report of days ending on end and the first day of its warmup
+func loadPeriod(end time.Time, days int, opts analysis.LoadOptions) (analysis.LoadOptions, time.Time, time.Time) {
+	if opts.AcuteDays <= 0 || opts.ChronicDays <= 0 {
+		opts = analysis.DefaultLoadOptions()
+	}
+	if days < 1 {
+		days = 1
+	}
+	start := end.AddDate(0, 0, 1-days)
+	return opts, start, start.AddDate(0, 0, -int(math.Ceil(3*opts.ChronicDays)))
+}
+
+// computeLoad computes the load from start to end of activities covering the
+// warmup of loadPeriod
+func computeLoad(activities []api.Activity, start, end time.Time, opts analysis.LoadOptions) *Load {
+	points := analysis.WorkloadRatio(analysis.LoadsByDay(activities), start, end, opts)
+	load := &Load{Start: start, End: end, Options: opts, Days: points}
+	if len(points) > 0 {
+		load.Latest = points[len(points)-1]
+	}
+	return load
+}
+
+// LoadZone describes the injury-risk band of an acute-to-chronic ratio
+func LoadZone(ratio float64) string {
+	switch {
+	case ratio == 0:
+		return "no data"
+	case ratio < 0.8:
+		return "undertraining"
+	case ratio <= 1.3:
+		return "optimal"
+	case ratio <= 1.5:
+		return "caution"
+	default:
+		return "high risk"
+	}
+}
+
+var loadFuncs = map[string]interface{}{
+	"date":  func(t time.Time) string { return t.Format("Mon 2006-01-02") },
+	"load":  func(v float64) string { return fmt.Sprintf("%.0f", v) },
+	"ratio": func(v float64) string { return fmt.Sprintf("%.2f", v) },
+	"zone":  LoadZone,
+}
+
+const loadMarkdownTemplate = `# Training load report
+{{date .Start}} – {{date .End}}
+
+Acute ({{.Options.AcuteDays}} days) to chronic ({{.Options.ChronicDays}} days) workload ratio: **{{ratio .Latest.Ratio}}** ({{zone .Latest.Ratio}})
+
+| Day | Load | Acute | Chronic | Ratio |
+|-----|-----:|------:|--------:|------:|
+{{- range .Days}}
+| {{date .Date}} | {{load .Load}} | {{load .Acute}} | {{load .Chronic}} | {{ratio .Ratio}} |
+{{- end}}
+`
+
+const loadHTMLTemplate = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Training load report</title></head>
+<body>
+<h1>Training load report</h1>
+<p>{{date .Start}} – {{date .End}}</p>
+<p>Acute ({{.Options.AcuteDays}} days) to chronic ({{.Options.ChronicDays}} days) workload ratio: <strong>{{ratio .Latest.Ratio}}</strong> ({{zone .Latest.Ratio}})</p>
+<table>
+<tr><th>Day</th><th>Load</th><th>Acute</th><th>Chronic</th><th>Ratio</th></tr>
+{{- range .Days}}
+<tr><td>{{date .Date}}</td><td>{{load .Load}}</td><td>{{load .Acute}}</td><td>{{load .Chronic}}</td><td>{{ratio .Ratio}}</td></tr>
+{{- end}}
+</table>
+</body>
+</html>
+`
+
+var (
+	loadMarkdownTmpl = template.Must(template.New("load-markdown").Funcs(loadFuncs).Parse(loadMarkdownTemplate))
+	loadHTMLTmpl     = htmltemplate.Must(htmltemplate.New("load-html").Funcs(loadFuncs).Parse(loadHTMLTemplate))
+)
+
+// Render writes the report in the requested format
+func (l *Load) Render(out io.Writer, format Format) error {
+	switch format {
+	case FormatMarkdown, "md", "":
+		return loadMarkdownTmpl.Execute(out, l)
+	case FormatHTML:
+		return loadHTMLTmpl.Execute(out, l)
+	default:
+		return fmt.Errorf("unsupported report format: %s", format)
+	}
+}
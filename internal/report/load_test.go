@@ -0,0 +1,58 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/analysis"
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeLoadSource struct {
+	start, end time.Time
+	activities []api.Activity
+}
+
+func (f *fakeLoadSource) GetActivitiesByDate(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.Activity, error) {
+	f.start, f.end = start, end
+	return f.activities, nil
+}
+
+func TestBuildLoad(t *testing.T) {
+	activity := func(day int, load float64) api.Activity {
+		return api.Activity{StartTime: api.NewGarminTime(time.Date(2024, 3, day, 7, 0, 0, 0, time.UTC)), TrainingLoad: api.Ptr(load)}
+	}
+	src := &fakeLoadSource{activities: []api.Activity{activity(1, 120), activity(10, 90), activity(13, 300)}}
+	end := time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC)
+
+	load, err := BuildLoad(context.Background(), src, end, 7, analysis.LoadOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC), load.Start)
+	assert.Equal(t, time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -126), src.start)
+	assert.Equal(t, analysis.DefaultLoadOptions(), load.Options)
+	assert.Len(t, load.Days, 7)
+	assert.Equal(t, load.Days[6], load.Latest)
+	// A big session after a quiet spell spikes the ratio
+	assert.Greater(t, load.Latest.Ratio, 1.5)
+	assert.Equal(t, "high risk", LoadZone(load.Latest.Ratio))
+
+	var buf bytes.Buffer
+	assert.NoError(t, load.Render(&buf, FormatMarkdown))
+	assert.Contains(t, buf.String(), "Acute (7 days) to chronic (42 days) workload ratio")
+	assert.Contains(t, buf.String(), "| Wed 2024-03-13 | 300 |")
+
+	buf.Reset()
+	assert.NoError(t, load.Render(&buf, FormatHTML))
+	assert.Contains(t, buf.String(), "<td>Wed 2024-03-13</td><td>300</td>")
+	assert.Error(t, load.Render(&buf, "pdf"))
+}
+
+func TestLoadZone(t *testing.T) {
+	assert.Equal(t, "no data", LoadZone(0))
+	assert.Equal(t, "undertraining", LoadZone(0.6))
+	assert.Equal(t, "optimal", LoadZone(1.1))
+	assert.Equal(t, "caution", LoadZone(1.4))
+}
@@ -0,0 +1,147 @@
+package report
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"text/template"
+	"time"
+)
+
+// Format identifies a report output format
+type Format string
+
+const (
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+)
+
+var funcs = map[string]interface{}{
+	"date": func(t time.Time) string { return t.Format("Mon 2006-01-02") },
+	"dur":  formatDuration,
+	"km":   func(m float64) string { return fmt.Sprintf("%.1f km", m/1000) },
+	"score": func(s float64) string {
+		return fmt.Sprintf("%.0f", s)
+	},
+	"load":  loadFuncs["load"],
+	"ratio": loadFuncs["ratio"],
+	"zone":  LoadZone,
+}
+
+const markdownTemplate = `# Weekly training report
+{{date .Start}} – {{date .End}}
+
+## Volume by sport
+
+| Sport | Activities | Duration | Distance |
+|-------|-----------:|---------:|---------:|
+{{- range .Volume}}
+| {{.Sport}} | {{.Count}} | {{dur .Duration}} | {{km .Distance}} |
+{{- end}}
+| **{{.Total.Sport}}** | **{{.Total.Count}}** | **{{dur .Total.Duration}}** | **{{km .Total.Distance}}** |
+
+## Time in heart rate zones
+{{if .Zones}}
+| Zone | Time |
+|------|-----:|
+{{- range .Zones}}
+| Z{{.Zone}} | {{dur .Duration}} |
+{{- end}}
+{{else}}
+No heart rate zone data recorded.
+{{end}}
+## Training load
+{{if or .Load.Total .Load.Chronic}}
+- Week total: {{load .Load.Total}}
+- Acute / chronic load at week end: {{load .Load.Acute}} / {{load .Load.Chronic}}
+- Acute-to-chronic ratio: {{ratio .Load.Ratio}} ({{zone .Load.Ratio}})
+{{else}}
+No training load recorded.
+{{end}}
+## Sleep
+{{if .Sleep.Nights}}
+- Nights recorded: {{.Sleep.Nights}}
+- Average duration: {{dur .Sleep.AvgDuration}}
+- Average deep / light / REM: {{dur .Sleep.AvgDeepSleep}} / {{dur .Sleep.AvgLightSleep}} / {{dur .Sleep.AvgRemSleep}}
+- Average score: {{score .Sleep.AvgScore}}
+{{else}}
+No sleep data recorded.
+{{end}}`
+
+const htmlTemplate = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Weekly training report</title></head>
+<body>
+<h1>Weekly training report</h1>
+<p>{{date .Start}} – {{date .End}}</p>
+<h2>Volume by sport</h2>
+<table>
+<tr><th>Sport</th><th>Activities</th><th>Duration</th><th>Distance</th></tr>
+{{- range .Volume}}
+<tr><td>{{.Sport}}</td><td>{{.Count}}</td><td>{{dur .Duration}}</td><td>{{km .Distance}}</td></tr>
+{{- end}}
+<tr><th>{{.Total.Sport}}</th><th>{{.Total.Count}}</th><th>{{dur .Total.Duration}}</th><th>{{km .Total.Distance}}</th></tr>
+</table>
+<h2>Time in heart rate zones</h2>
+{{- if .Zones}}
+<table>
+<tr><th>Zone</th><th>Time</th></tr>
+{{- range .Zones}}
+<tr><td>Z{{.Zone}}</td><td>{{dur .Duration}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>No heart rate zone data recorded.</p>
+{{- end}}
+<h2>Training load</h2>
+{{- if or .Load.Total .Load.Chronic}}
+<ul>
+<li>Week total: {{load .Load.Total}}</li>
+<li>Acute / chronic load at week end: {{load .Load.Acute}} / {{load .Load.Chronic}}</li>
+<li>Acute-to-chronic ratio: {{ratio .Load.Ratio}} ({{zone .Load.Ratio}})</li>
+</ul>
+{{- else}}
+<p>No training load recorded.</p>
+{{- end}}
+<h2>Sleep</h2>
+{{- if .Sleep.Nights}}
+<ul>
+<li>Nights recorded: {{.Sleep.Nights}}</li>
+<li>Average duration: {{dur .Sleep.AvgDuration}}</li>
+<li>Average deep / light / REM: {{dur .Sleep.AvgDeepSleep}} / {{dur .Sleep.AvgLightSleep}} / {{dur .Sleep.AvgRemSleep}}</li>
+<li>Average score: {{score .Sleep.AvgScore}}</li>
+</ul>
+{{- else}}
+<p>No sleep data recorded.</p>
+{{- end}}
+</body>
+</html>
+`
+
+var (
+	markdownTmpl = template.Must(template.New("markdown").Funcs(funcs).Parse(markdownTemplate))
+	htmlTmpl     = htmltemplate.Must(htmltemplate.New("html").Funcs(funcs).Parse(htmlTemplate))
+)
+
+// Render writes the report in the requested format
+func (w *Weekly) Render(out io.Writer, format Format) error {
+	switch format {
+	case FormatMarkdown, "md", "":
+		return markdownTmpl.Execute(out, w)
+	case FormatHTML:
+		return htmlTmpl.Execute(out, w)
+	default:
+		return fmt.Errorf("unsupported report format: %s", format)
+	}
+}
+
+// formatDuration renders a duration as hours and minutes, e.g. "1h05m"
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h := d / time.Hour
+	m := (d % time.Hour) / time.Minute
+	if h == 0 {
+		return fmt.Sprintf("%dm", m)
+	}
+	return fmt.Sprintf("%dh%02dm", h, m)
+}
@@ -0,0 +1,122 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"sort"
+	"sync"
+	"text/template"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Athlete is one athlete of a squad, read through a client logged in to the
+// account they consented to share
+type Athlete struct {
+	Name   string
+	Source Source
+}
+
+// AthleteWeek is one athlete's week in a squad report. Err is set instead of
+// Weekly when the athlete's data could not be fetched.
+type AthleteWeek struct {
+	Name   string  `json:"name"`
+	Weekly *Weekly `json:"weekly,omitempty"`
+	Err    error   `json:"-"`
+	Error  string  `json:"error,omitempty"`
+}
+
+// Squad combines the weekly reports of several athletes for a coach
+type Squad struct {
+	Start    time.Time     `json:"start"`
+	End      time.Time     `json:"end"`
+	Athletes []AthleteWeek `json:"athletes"`
+}
+
+// BuildSquad builds the week containing day for every athlete, fetching up to
+// concurrency athletes at once. A failing athlete is reported in its row
+// rather than failing the squad; an error is only returned when ctx is
+// cancelled. Athletes are sorted by name.
+func BuildSquad(ctx context.Context, athletes []Athlete, day time.Time, concurrency int) (*Squad, error) {
+	start := WeekStart(day)
+	squad := &Squad{Start: start, End: start.AddDate(0, 0, 6), Athletes: make([]AthleteWeek, len(athletes))}
+
+	var g errgroup.Group
+	g.SetLimit(max(concurrency, 1))
+	var mu sync.Mutex
+	for i, a := range athletes {
+		g.Go(func() error {
+			row := AthleteWeek{Name: a.Name}
+			row.Weekly, row.Err = BuildWeekly(ctx, a.Source, day)
+			if row.Err != nil {
+				row.Error = row.Err.Error()
+			}
+			mu.Lock()
+			squad.Athletes[i] = row
+			mu.Unlock()
+			return nil
+		})
+	}
+	g.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(squad.Athletes, func(i, j int) bool { return squad.Athletes[i].Name < squad.Athletes[j].Name })
+	return squad, nil
+}
+
+const squadMarkdownTemplate = `# Squad report
+{{date .Start}} – {{date .End}}
+
+| Athlete | Activities | Duration | Distance | Avg sleep | Sleep score |
+|---------|-----------:|---------:|---------:|----------:|------------:|
+{{- range .Athletes}}
+{{- if .Weekly}}
+| {{.Name}} | {{.Weekly.Total.Count}} | {{dur .Weekly.Total.Duration}} | {{km .Weekly.Total.Distance}} | {{if .Weekly.Sleep.Nights}}{{dur .Weekly.Sleep.AvgDuration}} | {{score .Weekly.Sleep.AvgScore}}{{else}}– | –{{end}} |
+{{- else}}
+| {{.Name}} | unavailable: {{.Error}} | | | | |
+{{- end}}
+{{- end}}
+`
+
+const squadHTMLTemplate = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Squad report</title></head>
+<body>
+<h1>Squad report</h1>
+<p>{{date .Start}} – {{date .End}}</p>
+<table>
+<tr><th>Athlete</th><th>Activities</th><th>Duration</th><th>Distance</th><th>Avg sleep</th><th>Sleep score</th></tr>
+{{- range .Athletes}}
+{{- if .Weekly}}
+<tr><td>{{.Name}}</td><td>{{.Weekly.Total.Count}}</td><td>{{dur .Weekly.Total.Duration}}</td><td>{{km .Weekly.Total.Distance}}</td>
+{{- if .Weekly.Sleep.Nights}}<td>{{dur .Weekly.Sleep.AvgDuration}}</td><td>{{score .Weekly.Sleep.AvgScore}}</td>{{else}}<td>–</td><td>–</td>{{end}}</tr>
+{{- else}}
+<tr><td>{{.Name}}</td><td colspan="5">unavailable: {{.Error}}</td></tr>
+{{- end}}
+{{- end}}
+</table>
+</body>
+</html>
+`
+
+var (
+	squadMarkdownTmpl = template.Must(template.New("squad-markdown").Funcs(funcs).Parse(squadMarkdownTemplate))
+	squadHTMLTmpl     = htmltemplate.Must(htmltemplate.New("squad-html").Funcs(funcs).Parse(squadHTMLTemplate))
+)
+
+// Render writes the squad report in the requested format
+func (s *Squad) Render(out io.Writer, format Format) error {
+	switch format {
+	case FormatMarkdown, "md", "":
+		return squadMarkdownTmpl.Execute(out, s)
+	case FormatHTML:
+		return squadHTMLTmpl.Execute(out, s)
+	default:
+		return fmt.Errorf("unsupported report format: %s", format)
+	}
+}
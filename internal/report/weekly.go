@@ -0,0 +1,98 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/analysis"
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// Source defines the client methods needed to build a report
+type Source interface {
+	GetActivitiesByDate(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.Activity, error)
+	GetActivityHRZones(ctx context.Context, activityID int64, opts ...api.RequestOption) ([]api.HRZone, error)
+	GetSleepData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.SleepData, error)
+}
+
+// Weekly summarizes one Monday-to-Sunday training week
+type Weekly struct {
+	Start  time.Time              `json:"start"`
+	End    time.Time              `json:"end"`
+	Volume []analysis.SportVolume `json:"volume"`
+	Total  analysis.SportVolume   `json:"total"`
+	Zones  []analysis.ZoneTime    `json:"zones"`
+	Sleep  analysis.SleepSummary  `json:"sleep"`
+	Load   WeekLoad               `json:"load"`
+}
+
+// WeekLoad is the training load of a week and the acute and chronic load
+// (default 7 and 42 day windows) at its end
+type WeekLoad struct {
+	Total   float64 `json:"total"`
+	Acute   float64 `json:"acute"`   // ATL
+	Chronic float64 `json:"chronic"` // CTL
+	Ratio   float64 `json:"ratio"`   // ACWR
+}
+
+// WeekStart returns midnight on the Monday of the week containing t
+func WeekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7 // days since Monday
+	y, m, d := t.AddDate(0, 0, -offset).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// BuildWeekly gathers activities, heart rate zones, training load and sleep
+// for the week containing day
+func BuildWeekly(ctx context.Context, src Source, day time.Time) (*Weekly, error) {
+	start := WeekStart(day)
+	end := start.AddDate(0, 0, 6)
+
+	// One list covers the week and the warmup of its training load
+	loadOpts, _, warmup := loadPeriod(end, 7, analysis.DefaultLoadOptions())
+	history, err := src.GetActivitiesByDate(ctx, warmup, end)
+	if err != nil {
+		return nil, err
+	}
+	var activities []api.Activity
+	for _, a := range history {
+		if !a.StartTime.Before(start) && a.StartTime.Before(end.AddDate(0, 0, 1)) {
+			activities = append(activities, a)
+		}
+	}
+
+	zones := make([][]api.HRZone, 0, len(activities))
+	for _, a := range activities {
+		z, err := src.GetActivityHRZones(ctx, a.ActivityID)
+		if err != nil {
+			return nil, fmt.Errorf("activity %d: %w", a.ActivityID, err)
+		}
+		zones = append(zones, z)
+	}
+
+	// Missing sleep data for a night is common (watch not worn), so errors are skipped
+	var nights []api.SleepData
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if sleep, err := src.GetSleepData(ctx, d); err == nil && sleep != nil {
+			nights = append(nights, *sleep)
+		}
+	}
+
+	load := computeLoad(history, start, end, loadOpts)
+	weekLoad := WeekLoad{Acute: load.Latest.Acute, Chronic: load.Latest.Chronic, Ratio: load.Latest.Ratio}
+	for _, d := range load.Days {
+		weekLoad.Total += d.Load
+	}
+
+	volume := analysis.VolumeBySport(activities)
+	return &Weekly{
+		Start:  start,
+		End:    end,
+		Volume: volume,
+		Total:  analysis.TotalVolume(volume),
+		Zones:  analysis.SumHRZones(zones...),
+		Sleep:  analysis.AverageSleep(nights),
+		Load:   weekLoad,
+	}, nil
+}
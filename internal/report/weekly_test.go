@@ -0,0 +1,128 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSource struct {
+	activities []api.Activity
+	zones      map[int64][]api.HRZone
+	sleep      map[string]*api.SleepData
+	err        error
+	// listed are the periods of the activity lists requested
+	listed [][2]time.Time
+}
+
+func (f *fakeSource) GetActivitiesByDate(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.Activity, error) {
+	f.listed = append(f.listed, [2]time.Time{start, end})
+	return f.activities, f.err
+}
+
+func (f *fakeSource) GetActivityHRZones(ctx context.Context, activityID int64, opts ...api.RequestOption) ([]api.HRZone, error) {
+	return f.zones[activityID], nil
+}
+
+func (f *fakeSource) GetSleepData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.SleepData, error) {
+	if s, ok := f.sleep[date.Format("2006-01-02")]; ok {
+		return s, nil
+	}
+	return nil, errors.New("no sleep data")
+}
+
+func TestWeekStart(t *testing.T) {
+	sunday := time.Date(2024, 3, 17, 21, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), WeekStart(sunday))
+
+	monday := time.Date(2024, 3, 11, 6, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), WeekStart(monday))
+}
+
+func TestBuildWeekly(t *testing.T) {
+	src := &fakeSource{
+		activities: []api.Activity{
+			{ActivityID: 1, Type: "running", Duration: 3600, Distance: 10000, StartTime: api.NewGarminTime(time.Date(2024, 3, 13, 7, 0, 0, 0, time.UTC)), TrainingLoad: api.Ptr(150.0)},
+			{ActivityID: 2, Type: "cycling", Duration: 5400, Distance: 40000, StartTime: api.NewGarminTime(time.Date(2024, 3, 16, 9, 0, 0, 0, time.UTC))},
+			// Before the week: only warms up the training load
+			{ActivityID: 3, Type: "running", Duration: 1800, Distance: 5000, StartTime: api.NewGarminTime(time.Date(2024, 1, 10, 7, 0, 0, 0, time.UTC)), TrainingLoad: api.Ptr(10.0)},
+		},
+		zones: map[int64][]api.HRZone{
+			1: {{ZoneNumber: 2, SecsInZone: 3000}, {ZoneNumber: 3, SecsInZone: 600}},
+			2: {{ZoneNumber: 2, SecsInZone: 5400}},
+		},
+		sleep: map[string]*api.SleepData{
+			"2024-03-12": {SleepTimeSeconds: api.Ptr(28800), SleepScore: api.Ptr(82)},
+		},
+	}
+
+	weekly, err := BuildWeekly(context.Background(), src, time.Date(2024, 3, 13, 12, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), weekly.Start)
+	assert.Equal(t, time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC), weekly.End)
+	assert.Equal(t, 2, weekly.Total.Count)
+	assert.Equal(t, "cycling", weekly.Volume[0].Sport)
+	assert.Len(t, weekly.Zones, 2)
+	assert.Equal(t, 140*time.Minute, weekly.Zones[0].Duration)
+	assert.Equal(t, 1, weekly.Sleep.Nights)
+	assert.Equal(t, 150.0, weekly.Load.Total)
+	assert.Greater(t, weekly.Load.Acute, weekly.Load.Chronic)
+	assert.Greater(t, weekly.Load.Ratio, 1.5, "one hard session after little training")
+	// The list of the week doubles as the warmup of the training load
+	assert.Equal(t, [][2]time.Time{{time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -126), weekly.End}}, src.listed)
+
+	t.Run("markdown", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, weekly.Render(&buf, FormatMarkdown))
+		assert.Contains(t, buf.String(), "| cycling | 1 | 1h30m | 40.0 km |")
+		assert.Contains(t, buf.String(), "| Z2 | 2h20m |")
+		assert.Contains(t, buf.String(), "Average score: 82")
+		assert.Contains(t, buf.String(), "- Week total: 150")
+		assert.Contains(t, buf.String(), "(high risk)")
+	})
+
+	t.Run("html", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, weekly.Render(&buf, FormatHTML))
+		assert.Contains(t, buf.String(), "<td>running</td><td>1</td><td>1h00m</td><td>10.0 km</td>")
+		assert.Contains(t, buf.String(), "<li>Week total: 150</li>")
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		assert.Error(t, weekly.Render(&bytes.Buffer{}, "pdf"))
+	})
+}
+
+func TestBuildWeeklyActivitiesError(t *testing.T) {
+	_, err := BuildWeekly(context.Background(), &fakeSource{err: errors.New("boom")}, time.Now())
+	assert.EqualError(t, err, "boom")
+}
+
+func TestBuildSquad(t *testing.T) {
+	athletes := []Athlete{
+		{Name: "Sam", Source: &fakeSource{activities: []api.Activity{
+			{ActivityID: 1, Type: "running", Duration: 3600, Distance: 12000, StartTime: api.NewGarminTime(time.Date(2024, 3, 12, 7, 0, 0, 0, time.UTC))},
+		}}},
+		{Name: "Alex", Source: &fakeSource{err: errors.New("session expired")}},
+	}
+
+	squad, err := BuildSquad(context.Background(), athletes, time.Date(2024, 3, 13, 12, 0, 0, 0, time.UTC), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), squad.Start)
+	if assert.Len(t, squad.Athletes, 2) {
+		assert.Equal(t, "Alex", squad.Athletes[0].Name)
+		assert.Error(t, squad.Athletes[0].Err)
+		assert.Nil(t, squad.Athletes[0].Weekly)
+		assert.Equal(t, 1, squad.Athletes[1].Weekly.Total.Count)
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, squad.Render(&buf, FormatMarkdown))
+	assert.Contains(t, buf.String(), "| Sam | 1 | 1h00m | 12.0 km | – | – |")
+	assert.Contains(t, buf.String(), "| Alex | unavailable: session expired |")
+}
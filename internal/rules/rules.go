@@ -0,0 +1,270 @@
+// Package rules runs user-defined actions for the events of watch mode, so
+// exports, webhooks and scripts can be automated without writing Go. Rules are
+// read from a YAML file:
+//
+//	rules:
+//	  - name: archive runs
+//	    on: new_activity
+//	    type: running
+//	    actions:
+//	      - export: gpx
+//	        dir: ~/runs
+//	      - webhook: https://example.com/hooks/garmin
+//	        headers:
+//	          Authorization: Bearer secret
+//	      - run: [./upload.sh, --quiet]
+package rules
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/watch"
+	"gopkg.in/yaml.v3"
+)
+
+// Triggers a rule can react to
+const (
+	OnNewActivity = "new_activity"
+	OnDailyMetric = "daily_metric"
+)
+
+// Export formats
+const (
+	FormatGPX = "gpx"
+	FormatFIT = "fit"
+)
+
+// Rule runs its actions, in order, for every event it matches. An action that
+// fails skips the remaining actions of the rule for that event.
+type Rule struct {
+	Name string `yaml:"name"`
+	On   string `yaml:"on"` // OnNewActivity or OnDailyMetric
+	// Type limits new_activity rules to an activity type, e.g. "running"
+	Type string `yaml:"type"`
+	// Metric limits daily_metric rules to one metric, e.g. "resting_hr"
+	Metric  string   `yaml:"metric"`
+	Actions []Action `yaml:"actions"`
+}
+
+// Action is one step of a rule; exactly one of Export, Webhook and Run is set
+type Action struct {
+	// Export writes the activity as FormatGPX or FormatFIT to
+	// <Dir>/<activity ID>.<format>; Dir defaults to the working directory
+	Export string `yaml:"export"`
+	Dir    string `yaml:"dir"`
+
+	// Webhook POSTs the event as JSON to this URL with Headers
+	Webhook string            `yaml:"webhook"`
+	Headers map[string]string `yaml:"headers"`
+
+	// Run executes a command with the event as JSON on stdin. The environment
+	// has GARMIN_EVENT_TYPE, GARMIN_ACTIVITY_ID, GARMIN_ACTIVITY_TYPE,
+	// GARMIN_METRIC and GARMIN_FILE, the file of the rule's last export.
+	Run []string `yaml:"run"`
+}
+
+// Load reads and checks the rules in the YAML file at path
+func Load(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules: %w", err)
+	}
+	var config struct {
+		Rules []Rule `yaml:"rules"`
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse rules: %w", err)
+	}
+	for i, r := range config.Rules {
+		if err := r.check(); err != nil {
+			return nil, fmt.Errorf("rule %d (%s): %w", i+1, r.Name, err)
+		}
+	}
+	return config.Rules, nil
+}
+
+func (r Rule) check() error {
+	switch r.On {
+	case OnNewActivity:
+	case OnDailyMetric:
+		if r.Type != "" {
+			return errors.New("type only applies to new_activity rules")
+		}
+	default:
+		return fmt.Errorf("unknown trigger %q, expected new_activity or daily_metric", r.On)
+	}
+	if r.Metric != "" && r.On != OnDailyMetric {
+		return errors.New("metric only applies to daily_metric rules")
+	}
+	if len(r.Actions) == 0 {
+		return errors.New("no actions")
+	}
+	for i, a := range r.Actions {
+		set := 0
+		for _, s := range []bool{a.Export != "", a.Webhook != "", len(a.Run) > 0} {
+			if s {
+				set++
+			}
+		}
+		if set != 1 {
+			return fmt.Errorf("action %d must set exactly one of export, webhook and run", i+1)
+		}
+		if a.Export != "" {
+			if r.On != OnNewActivity {
+				return fmt.Errorf("action %d: only activities can be exported", i+1)
+			}
+			if a.Export != FormatGPX && a.Export != FormatFIT {
+				return fmt.Errorf("action %d: unknown export format %q, expected gpx or fit", i+1, a.Export)
+			}
+		}
+	}
+	return nil
+}
+
+// matches reports whether r reacts to e
+func (r Rule) matches(e *watch.Event) bool {
+	switch r.On {
+	case OnNewActivity:
+		return e.Type == watch.EventActivity && e.Activity != nil && (r.Type == "" || e.Activity.Type == r.Type)
+	case OnDailyMetric:
+		return e.Type == watch.EventDailyMetric && (r.Metric == "" || e.Metric == r.Metric)
+	}
+	return false
+}
+
+// Engine runs rules for watch events
+type Engine struct {
+	Rules []Rule
+	// Export writes activity in format to path
+	Export func(ctx context.Context, activity *api.Activity, format, path string) error
+	// HTTPClient sends webhooks; nil uses a client with a 30 second timeout
+	HTTPClient *http.Client
+	// Output receives the output of commands; nil discards it
+	Output io.Writer
+}
+
+// Handle runs the matching rules for every event and returns the errors of
+// the actions that failed
+func (e *Engine) Handle(ctx context.Context, events []watch.Event) error {
+	var errs []error
+	for i := range events {
+		for _, r := range e.Rules {
+			if !r.matches(&events[i]) {
+				continue
+			}
+			if err := e.run(ctx, r, &events[i]); err != nil {
+				errs = append(errs, fmt.Errorf("rule %q: %w", r.Name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// run executes the actions of r for event until one fails
+func (e *Engine) run(ctx context.Context, r Rule, event *watch.Event) error {
+	var file string
+	for _, a := range r.Actions {
+		var err error
+		switch {
+		case a.Export != "":
+			file, err = e.export(ctx, a, event)
+		case a.Webhook != "":
+			err = e.webhook(ctx, a, event)
+		default:
+			err = e.command(ctx, a, event, file)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Engine) export(ctx context.Context, a Action, event *watch.Event) (string, error) {
+	if e.Export == nil {
+		return "", errors.New("exporting is not supported")
+	}
+	dir := a.Dir
+	if rest, ok := strings.CutPrefix(dir, "~/"); ok {
+		dir = filepath.Join(os.Getenv("HOME"), rest)
+	}
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create export directory: %w", err)
+		}
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%d.%s", event.Activity.ActivityID, a.Export))
+	if err := e.Export(ctx, event.Activity, a.Export, path); err != nil {
+		return "", fmt.Errorf("failed to export activity %d: %w", event.Activity.ActivityID, err)
+	}
+	return path, nil
+}
+
+func (e *Engine) webhook(ctx context.Context, a Action, event *watch.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.Webhook, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range a.Headers {
+		req.Header.Set(k, v)
+	}
+
+	client := e.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+func (e *Engine) command(ctx context.Context, a Action, event *watch.Event, file string) error {
+	input, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	cmd := exec.CommandContext(ctx, a.Run[0], a.Run[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = e.Output
+	cmd.Stderr = e.Output
+	cmd.Env = append(os.Environ(),
+		"GARMIN_EVENT_TYPE="+event.Type,
+		"GARMIN_METRIC="+event.Metric,
+		"GARMIN_FILE="+file,
+	)
+	if event.Activity != nil {
+		cmd.Env = append(cmd.Env,
+			"GARMIN_ACTIVITY_ID="+strconv.FormatInt(event.Activity.ActivityID, 10),
+			"GARMIN_ACTIVITY_TYPE="+event.Activity.Type,
+		)
+	}
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("command %s failed: %w", a.Run[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package rules
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/watch"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeRules(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	rules, err := Load(writeRules(t, `
+rules:
+  - name: archive runs
+    on: new_activity
+    type: running
+    actions:
+      - export: gpx
+        dir: /tmp/runs
+      - run: [./notify.sh, --quiet]
+  - name: resting hr
+    on: daily_metric
+    metric: resting_hr
+    actions:
+      - webhook: https://example.com/hook
+        headers:
+          Authorization: Bearer secret
+`))
+	assert.NoError(t, err)
+	if assert.Len(t, rules, 2) {
+		assert.Equal(t, []string{"./notify.sh", "--quiet"}, rules[0].Actions[1].Run)
+		assert.Equal(t, "Bearer secret", rules[1].Actions[0].Headers["Authorization"])
+	}
+
+	tests := map[string]string{
+		"on: new_run":                                         `unknown trigger "new_run"`,
+		"on: new_activity\n    metric: steps":                 "metric only applies",
+		"on: daily_metric\n    type: running":                 "type only applies",
+		"on: new_activity":                                    "no actions",
+		"on: new_activity\n    actions:\n      - dir: x":      "exactly one of",
+		"on: daily_metric\n    actions:\n      - export: gpx": "only activities can be exported",
+		"on: new_activity\n    actions:\n      - export: tcx": `unknown export format "tcx"`,
+	}
+	for rule, want := range tests {
+		_, err := Load(writeRules(t, "rules:\n  - name: bad\n    "+rule+"\n"))
+		assert.ErrorContains(t, err, want, rule)
+	}
+}
+
+func TestEngine(t *testing.T) {
+	var hooks []watch.Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		var e watch.Event
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&e))
+		hooks = append(hooks, e)
+		if e.Metric == "steps" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	var exported []string
+	var output bytes.Buffer
+	engine := &Engine{
+		Rules: []Rule{
+			{Name: "runs", On: OnNewActivity, Type: "running", Actions: []Action{
+				{Export: FormatGPX, Dir: dir},
+				{Run: []string{"sh", "-c", `echo "$GARMIN_ACTIVITY_ID $GARMIN_FILE"`}},
+			}},
+			{Name: "metrics", On: OnDailyMetric, Actions: []Action{
+				{Webhook: server.URL, Headers: map[string]string{"Authorization": "Bearer secret"}},
+				{Run: []string{"sh", "-c", "echo metric $GARMIN_METRIC"}},
+			}},
+		},
+		Export: func(ctx context.Context, a *api.Activity, format, path string) error {
+			exported = append(exported, path)
+			return nil
+		},
+		Output: &output,
+	}
+
+	events := []watch.Event{
+		{Type: watch.EventActivity, Detected: time.Now(), Activity: &api.Activity{ActivityID: 7, Type: "running"}},
+		{Type: watch.EventActivity, Detected: time.Now(), Activity: &api.Activity{ActivityID: 8, Type: "cycling"}},
+		{Type: watch.EventDailyMetric, Date: "2024-03-01", Metric: "resting_hr", Value: api.Ptr(52.0)},
+		{Type: watch.EventDailyMetric, Date: "2024-03-01", Metric: "steps", Value: api.Ptr(9000.0)},
+	}
+	err := engine.Handle(context.Background(), events)
+	assert.ErrorContains(t, err, `rule "metrics": webhook returned 500`)
+
+	file := filepath.Join(dir, "7.gpx")
+	assert.Equal(t, []string{file}, exported, "rides don't match the running rule")
+	assert.Len(t, hooks, 2)
+	assert.Equal(t, "7 "+file+"\nmetric resting_hr\n", output.String(), "a failed webhook skips the rule's later actions")
+}
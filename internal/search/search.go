@@ -0,0 +1,121 @@
+// Package search keeps a full-text index of activities in SQLite, so their
+// names, descriptions and locations can be searched offline
+package search
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	_ "modernc.org/sqlite"
+)
+
+// Result is one activity matching a search
+type Result struct {
+	ActivityID int64
+	Name       string
+	Type       string
+	Location   string
+	StartTime  time.Time
+}
+
+// Index is a full-text index of activities stored in a SQLite file
+type Index struct {
+	db *sql.DB
+}
+
+// Open opens the index at path, creating it if needed
+func Open(path string) (*Index, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create search index directory: %w", err)
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open search index: %w", err)
+	}
+	// The rowid is the activity ID, so indexing an activity again replaces it
+	_, err = db.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS activities USING fts5(
+		name, description, location, type, start_time UNINDEXED,
+		tokenize = 'unicode61 remove_diacritics 2'
+	)`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create search index: %w", err)
+	}
+	return &Index{db: db}, nil
+}
+
+// Close closes the index
+func (x *Index) Close() error {
+	return x.db.Close()
+}
+
+// Add indexes the activities, replacing earlier versions of them
+func (x *Index) Add(ctx context.Context, activities []api.Activity) error {
+	tx, err := x.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to update search index: %w", err)
+	}
+	defer tx.Rollback()
+	for _, a := range activities {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM activities WHERE rowid = ?`, a.ActivityID); err != nil {
+			return fmt.Errorf("failed to update search index: %w", err)
+		}
+		_, err := tx.ExecContext(ctx, `INSERT INTO activities (rowid, name, description, location, type, start_time) VALUES (?, ?, ?, ?, ?, ?)`,
+			a.ActivityID, a.Name, a.Description, a.Location, strings.ReplaceAll(a.Type, "_", " "), a.StartTime.Format(time.RFC3339))
+		if err != nil {
+			return fmt.Errorf("failed to update search index: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to update search index: %w", err)
+	}
+	return nil
+}
+
+// Search returns up to limit activities matching every word of query, as a
+// word or a word prefix, best matches first. Words are matched without regard
+// to case or accents; no other query syntax is interpreted.
+func (x *Index) Search(ctx context.Context, query string, limit int) ([]Result, error) {
+	match := matchExpression(query)
+	if match == "" {
+		return nil, nil
+	}
+	rows, err := x.db.QueryContext(ctx, `SELECT rowid, name, location, type, start_time FROM activities
+		WHERE activities MATCH ? ORDER BY rank LIMIT ?`, match, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search activities: %w", err)
+	}
+	defer rows.Close()
+
+	var results []Result
+	for rows.Next() {
+		var r Result
+		var start string
+		if err := rows.Scan(&r.ActivityID, &r.Name, &r.Location, &r.Type, &start); err != nil {
+			return nil, fmt.Errorf("failed to search activities: %w", err)
+		}
+		r.Type = strings.ReplaceAll(r.Type, " ", "_")
+		r.StartTime, _ = time.Parse(time.RFC3339, start)
+		results = append(results, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search activities: %w", err)
+	}
+	return results, nil
+}
+
+// matchExpression turns the words of query into an FTS5 expression requiring
+// each of them as a prefix, quoting them so punctuation is not parsed as syntax
+func matchExpression(query string) string {
+	var terms []string
+	for _, word := range strings.Fields(query) {
+		terms = append(terms, `"`+strings.ReplaceAll(word, `"`, `""`)+`"*`)
+	}
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,63 @@
+package search
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "activities.db")
+	index, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	start := time.Date(2024, 3, 1, 21, 0, 0, 0, time.UTC)
+
+	err = index.Add(ctx, []api.Activity{
+		{ActivityID: 1, Name: "Tempo run", Description: "Nighttime loop by the lake", Location: "Zürich", Type: "running", StartTime: api.NewGarminTime(start)},
+		{ActivityID: 2, Name: "Easy ride", Location: "Zurich", Type: "road_biking", StartTime: api.NewGarminTime(start.AddDate(0, 0, 1))},
+		{ActivityID: 3, Name: "Tempo intervals", Type: "treadmill_running"},
+	})
+	assert.NoError(t, err)
+
+	results, err := index.Search(ctx, "tempo nighttime", 10)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, Result{ActivityID: 1, Name: "Tempo run", Type: "running", Location: "Zürich", StartTime: start}, results[0])
+	}
+
+	results, err = index.Search(ctx, "zurich", 10)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2, "accents are ignored")
+
+	results, err = index.Search(ctx, "bik", 10)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1, "words match as prefixes, activity types by their parts") {
+		assert.Equal(t, "road_biking", results[0].Type)
+	}
+
+	results, err = index.Search(ctx, `tempo"  AND -`, 10)
+	assert.NoError(t, err, "query syntax is not interpreted")
+	assert.Empty(t, results)
+
+	// Indexing an activity again replaces it
+	assert.NoError(t, index.Add(ctx, []api.Activity{{ActivityID: 3, Name: "Threshold intervals"}}))
+	results, err = index.Search(ctx, "tempo", 10)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.NoError(t, index.Close())
+
+	reopened, err := Open(path)
+	if assert.NoError(t, err) {
+		defer reopened.Close()
+		results, err = reopened.Search(ctx, "threshold", 10)
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+	}
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// tokenCookie remembers the token of a browser that opened a page with the
+// access_token parameter
+const tokenCookie = "garmin_token"
+
+// RequireToken passes requests carrying token on to next and answers the
+// others with 401. The token is accepted as an "Authorization: Bearer"
+// header, as an access_token query parameter for clients that can't set
+// headers, such as calendar apps, or as the cookie set when a page was opened
+// with the parameter, so the dashboard's own requests are authorized too.
+// GET /health stays open for liveness probes.
+func RequireToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if auth, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && tokenEqual(auth, token) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if c, err := r.Cookie(tokenCookie); err == nil && tokenEqual(c.Value, token) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if q := r.URL.Query().Get("access_token"); q != "" && tokenEqual(q, token) {
+			http.SetCookie(w, &http.Cookie{
+				Name:     tokenCookie,
+				Value:    token,
+				Path:     "/",
+				HttpOnly: true,
+				SameSite: http.SameSiteStrictMode,
+			})
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("WWW-Authenticate", `Bearer realm="garmin-cli"`)
+		writeJSON(w, http.StatusUnauthorized, []byte(`{"error":"missing or invalid token"}`))
+	})
+}
+
+// tokenEqual compares tokens in constant time
+func tokenEqual(got, want string) bool {
+	return subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireToken(t *testing.T) {
+	handler := RequireToken("s3cret", NewDashboard(NewServer(newFakeBackend(), time.Minute)))
+
+	serve := func(r *http.Request) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		return rec
+	}
+
+	rec := serve(httptest.NewRequest(http.MethodGet, "/api/user/profile", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, `Bearer realm="garmin-cli"`, rec.Header().Get("WWW-Authenticate"))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/user/profile", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	assert.Equal(t, http.StatusUnauthorized, serve(req).Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/user/profile", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	assert.Equal(t, http.StatusOK, serve(req).Code)
+
+	assert.Equal(t, http.StatusOK, serve(httptest.NewRequest(http.MethodGet, "/health", nil)).Code, "health stays open")
+
+	// Opening the dashboard with the parameter authorizes its later requests
+	rec = serve(httptest.NewRequest(http.MethodGet, "/?access_token=s3cret", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	cookies := rec.Result().Cookies()
+	if assert.Len(t, cookies, 1) {
+		assert.True(t, cookies[0].HttpOnly)
+		req = httptest.NewRequest(http.MethodGet, "/api/user/profile", nil)
+		req.AddCookie(cookies[0])
+		assert.Equal(t, http.StatusOK, serve(req).Code)
+	}
+	assert.Equal(t, http.StatusUnauthorized, serve(httptest.NewRequest(http.MethodGet, "/?access_token=wrong", nil)).Code)
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"sync"
+	"time"
+)
+
+// cacheEntry holds an encoded response and its expiry
+type cacheEntry struct {
+	body      []byte
+	expiresAt time.Time
+}
+
+// responseCache is a small TTL cache of encoded JSON responses keyed by request URL
+type responseCache struct {
+	ttl     time.Duration
+	mu      sync.RWMutex
+	entries map[string]cacheEntry
+}
+
+// newResponseCache creates a cache; a zero TTL disables caching
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{
+		ttl:     ttl,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// get returns the cached body for key if present and not expired
+func (c *responseCache) get(key string) ([]byte, bool) {
+	if c.ttl <= 0 {
+		return nil, false
+	}
+
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.body, true
+}
+
+// set stores body under key, pruning expired entries as it goes
+func (c *responseCache) set(key string, body []byte) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for k, e := range c.entries {
+		if now.After(e.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cacheEntry{body: body, expiresAt: now.Add(c.ttl)}
+}
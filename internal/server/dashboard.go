@@ -0,0 +1,25 @@
+package server
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed web
+var webFiles embed.FS
+
+// NewDashboard serves the embedded web UI at / and the JSON API under /api/
+func NewDashboard(apiServer *Server) http.Handler {
+	static, err := fs.Sub(webFiles, "web")
+	if err != nil {
+		// The embedded directory is part of the binary, so this can't fail at runtime
+		panic(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/", http.StripPrefix("/api", apiServer))
+	mux.Handle("GET /health", apiServer)
+	mux.Handle("/", http.FileServer(http.FS(static)))
+	return mux
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/ics"
+)
+
+// scheduleMonths is how many calendar months (starting with the current one)
+// are scanned for scheduled workouts
+const scheduleMonths = 2
+
+// CalendarSource defines the client methods used by the calendar feed
+type CalendarSource interface {
+	GetActivitiesByDate(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.Activity, error)
+	GetCalendarMonth(ctx context.Context, year int, month time.Month, opts ...api.RequestOption) ([]api.CalendarItem, error)
+}
+
+// ICSFeed serves completed activities and upcoming scheduled workouts as an iCalendar feed
+type ICSFeed struct {
+	source      CalendarSource
+	historyDays int
+	cache       *responseCache
+}
+
+// NewICSFeed creates a feed covering the last historyDays of activities.
+// The generated feed is cached for cacheTTL; a zero TTL disables caching.
+func NewICSFeed(source CalendarSource, historyDays int, cacheTTL time.Duration) *ICSFeed {
+	return &ICSFeed{
+		source:      source,
+		historyDays: historyDays,
+		cache:       newResponseCache(cacheTTL),
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (f *ICSFeed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, ok := f.cache.get("feed")
+	if !ok {
+		cal, err := f.Build(r.Context(), time.Now())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		var buf bytes.Buffer
+		if err := cal.Encode(&buf); err != nil {
+			writeError(w, fmt.Errorf("failed to encode calendar: %w", err))
+			return
+		}
+		body = buf.Bytes()
+		f.cache.set("feed", body)
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// Build assembles the calendar as of now
+func (f *ICSFeed) Build(ctx context.Context, now time.Time) (*ics.Calendar, error) {
+	cal := &ics.Calendar{Name: "Garmin Connect"}
+
+	activities, err := f.source.GetActivitiesByDate(ctx, now.AddDate(0, 0, -f.historyDays), now)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range activities {
+		cal.Events = append(cal.Events, activityEvent(a))
+	}
+
+	today := now.Format("2006-01-02")
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < scheduleMonths; i++ {
+		m := month.AddDate(0, i, 0)
+		items, err := f.source.GetCalendarMonth(ctx, m.Year(), m.Month())
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			// Dates are YYYY-MM-DD, so string comparison orders them correctly
+			if item.ItemType != "workout" || item.Date < today {
+				continue
+			}
+			event, err := workoutEvent(item)
+			if err != nil {
+				return nil, err
+			}
+			cal.Events = append(cal.Events, event)
+		}
+	}
+
+	return cal, nil
+}
+
+func activityEvent(a api.Activity) ics.Event {
+	description := fmt.Sprintf("%s\nDistance: %.2f km\nDuration: %s",
+		a.Type, a.Distance/1000, (time.Duration(a.Duration) * time.Second).String())
+	return ics.Event{
+		UID:         fmt.Sprintf("activity-%d@go-garminconnect", a.ActivityID),
+		Summary:     a.Name,
+		Description: description,
+		Start:       a.StartTime.Time,
+		End:         a.StartTime.Add(time.Duration(a.Duration * float64(time.Second))),
+		// Garmin reports local wall-clock start times without a zone
+		Floating: true,
+	}
+}
+
+func workoutEvent(item api.CalendarItem) (ics.Event, error) {
+	date, err := time.Parse("2006-01-02", item.Date)
+	if err != nil {
+		return ics.Event{}, fmt.Errorf("invalid calendar item date %q: %w", item.Date, err)
+	}
+	return ics.Event{
+		UID:         fmt.Sprintf("workout-%d@go-garminconnect", item.ID),
+		Summary:     "Planned: " + item.Title,
+		Description: item.ActivityType,
+		Start:       date,
+		End:         date.AddDate(0, 0, 1),
+		AllDay:      true,
+	}, nil
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCalendar struct {
+	months []time.Month
+	err    error
+}
+
+func (f *fakeCalendar) GetActivitiesByDate(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.Activity, error) {
+	return []api.Activity{{
+		ActivityID: 7,
+		Name:       "Lunch Run",
+		Type:       "running",
+		StartTime:  api.NewGarminTime(time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)),
+		Duration:   1800,
+		Distance:   5000,
+	}}, f.err
+}
+
+func (f *fakeCalendar) GetCalendarMonth(ctx context.Context, year int, month time.Month, opts ...api.RequestOption) ([]api.CalendarItem, error) {
+	f.months = append(f.months, month)
+	if month != time.March {
+		return nil, nil
+	}
+	return []api.CalendarItem{
+		{ID: 1, ItemType: "workout", Title: "Old intervals", Date: "2024-03-05"},
+		{ID: 2, ItemType: "workout", Title: "Threshold", Date: "2024-03-20", ActivityType: "running"},
+		{ID: 3, ItemType: "activity", Title: "Lunch Run", Date: "2024-03-10"},
+	}, nil
+}
+
+func TestICSFeedBuild(t *testing.T) {
+	src := &fakeCalendar{}
+	feed := NewICSFeed(src, 30, 0)
+
+	cal, err := feed.Build(context.Background(), time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Month{time.March, time.April}, src.months)
+	assert.Len(t, cal.Events, 2)
+
+	assert.Equal(t, "Lunch Run", cal.Events[0].Summary)
+	assert.Equal(t, time.Date(2024, 3, 10, 12, 30, 0, 0, time.UTC), cal.Events[0].End)
+	assert.True(t, cal.Events[0].Floating)
+
+	assert.Equal(t, "Planned: Threshold", cal.Events[1].Summary)
+	assert.True(t, cal.Events[1].AllDay)
+	assert.Equal(t, time.Date(2024, 3, 21, 0, 0, 0, 0, time.UTC), cal.Events[1].End)
+}
+
+func TestICSFeedServeHTTP(t *testing.T) {
+	feed := NewICSFeed(&fakeCalendar{}, 30, time.Minute)
+
+	rec := httptest.NewRecorder()
+	feed.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/calendar.ics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "UID:activity-7@go-garminconnect")
+
+	t.Run("backend error", func(t *testing.T) {
+		feed := NewICSFeed(&fakeCalendar{err: errors.New("boom")}, 30, time.Minute)
+		rec := httptest.NewRecorder()
+		feed.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/calendar.ics", nil))
+		assert.Equal(t, http.StatusBadGateway, rec.Code)
+	})
+}
@@ -0,0 +1,153 @@
+// Code generated by openapigen from openapi.json. DO NOT EDIT.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// operations implements the operations of openapi.json
+type operations interface {
+	// ListActivities: List one page of activities, most recent first (GET /activities)
+	ListActivities(ctx context.Context, params listActivitiesParams) (*ActivityPage, error)
+	// GetActivity: Get the details of one activity (GET /activities/{id})
+	GetActivity(ctx context.Context, params getActivityParams) (*api.ActivityDetail, error)
+	// GetHealth: Liveness check (GET /health)
+	GetHealth(ctx context.Context) (string, error)
+	// GetBodyBattery: Get the Body Battery of one day (GET /health/bodybattery)
+	GetBodyBattery(ctx context.Context, params getBodyBatteryParams) (*api.BodyBatteryData, error)
+	// GetHRV: Get the heart rate variability of one night (GET /health/hrv)
+	GetHRV(ctx context.Context, params getHRVParams) (*api.HRVData, error)
+	// GetSleep: Get the sleep of one night (GET /health/sleep)
+	GetSleep(ctx context.Context, params getSleepParams) (*api.SleepData, error)
+	// GetSteps: Get the steps of one day (GET /health/steps)
+	GetSteps(ctx context.Context, params getStepsParams) (*api.DailySteps, error)
+	// GetStress: Get the stress summary of one day (GET /health/stress)
+	GetStress(ctx context.Context, params getStressParams) (*api.DailyStress, error)
+	// GetUserProfile: Get the user's profile (GET /user/profile)
+	GetUserProfile(ctx context.Context) (*api.UserProfile, error)
+	// GetUserStats: Get the user's totals of one day (GET /user/stats)
+	GetUserStats(ctx context.Context, params getUserStatsParams) (*api.UserStats, error)
+}
+
+// listActivitiesParams holds the parameters of listActivities
+type listActivitiesParams struct {
+	Page     int
+	PageSize int
+}
+
+// getActivityParams holds the parameters of getActivity
+type getActivityParams struct {
+	ID int64
+}
+
+// getBodyBatteryParams holds the parameters of getBodyBattery
+type getBodyBatteryParams struct {
+	Date *time.Time
+}
+
+// getHRVParams holds the parameters of getHRV
+type getHRVParams struct {
+	Date *time.Time
+}
+
+// getSleepParams holds the parameters of getSleep
+type getSleepParams struct {
+	Date *time.Time
+}
+
+// getStepsParams holds the parameters of getSteps
+type getStepsParams struct {
+	Date *time.Time
+}
+
+// getStressParams holds the parameters of getStress
+type getStressParams struct {
+	Date *time.Time
+}
+
+// getUserStatsParams holds the parameters of getUserStats
+type getUserStatsParams struct {
+	Date *time.Time
+}
+
+// registerOperations routes the operations of openapi.json to ops
+func (s *Server) registerOperations(ops operations) {
+	s.mux.HandleFunc("GET /activities", s.handle(func(r *http.Request) (interface{}, error) {
+		var params listActivitiesParams
+		var err error
+		if params.Page, err = queryInt(r, "page", "page", 1, 0); err != nil {
+			return nil, err
+		}
+		if params.PageSize, err = queryInt(r, "pageSize", "pageSize", 20, 0); err != nil {
+			return nil, err
+		}
+		return ops.ListActivities(r.Context(), params)
+	}))
+	s.mux.HandleFunc("GET /activities/{id}", s.handle(func(r *http.Request) (interface{}, error) {
+		var params getActivityParams
+		var err error
+		if params.ID, err = pathInt64(r, "id", "activity ID"); err != nil {
+			return nil, err
+		}
+		return ops.GetActivity(r.Context(), params)
+	}))
+	s.mux.HandleFunc("GET /health", s.handleText(func(r *http.Request) (string, error) {
+		return ops.GetHealth(r.Context())
+	}))
+	s.mux.HandleFunc("GET /health/bodybattery", s.handle(func(r *http.Request) (interface{}, error) {
+		var params getBodyBatteryParams
+		var err error
+		if params.Date, err = queryDate(r, "date"); err != nil {
+			return nil, err
+		}
+		return ops.GetBodyBattery(r.Context(), params)
+	}))
+	s.mux.HandleFunc("GET /health/hrv", s.handle(func(r *http.Request) (interface{}, error) {
+		var params getHRVParams
+		var err error
+		if params.Date, err = queryDate(r, "date"); err != nil {
+			return nil, err
+		}
+		return ops.GetHRV(r.Context(), params)
+	}))
+	s.mux.HandleFunc("GET /health/sleep", s.handle(func(r *http.Request) (interface{}, error) {
+		var params getSleepParams
+		var err error
+		if params.Date, err = queryDate(r, "date"); err != nil {
+			return nil, err
+		}
+		return ops.GetSleep(r.Context(), params)
+	}))
+	s.mux.HandleFunc("GET /health/steps", s.handle(func(r *http.Request) (interface{}, error) {
+		var params getStepsParams
+		var err error
+		if params.Date, err = queryDate(r, "date"); err != nil {
+			return nil, err
+		}
+		return ops.GetSteps(r.Context(), params)
+	}))
+	s.mux.HandleFunc("GET /health/stress", s.handle(func(r *http.Request) (interface{}, error) {
+		var params getStressParams
+		var err error
+		if params.Date, err = queryDate(r, "date"); err != nil {
+			return nil, err
+		}
+		return ops.GetStress(r.Context(), params)
+	}))
+	s.mux.HandleFunc("GET /user/profile", s.handle(func(r *http.Request) (interface{}, error) {
+		return ops.GetUserProfile(r.Context())
+	}))
+	s.mux.HandleFunc("GET /user/stats", s.handle(func(r *http.Request) (interface{}, error) {
+		var params getUserStatsParams
+		var err error
+		if params.Date, err = queryDate(r, "date"); err != nil {
+			return nil, err
+		}
+		return ops.GetUserStats(r.Context(), params)
+	}))
+}
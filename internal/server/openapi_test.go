@@ -0,0 +1,138 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+// openAPIDoc is the part of openapi.json checked against the code
+type openAPIDoc struct {
+	Paths map[string]map[string]struct {
+		Parameters []struct {
+			Name   string `json:"name"`
+			Schema struct {
+				Default interface{} `json:"default"`
+			} `json:"schema"`
+		} `json:"parameters"`
+	} `json:"paths"`
+	Components struct {
+		Schemas map[string]openAPISchema `json:"schemas"`
+	} `json:"components"`
+}
+
+type openAPISchema struct {
+	Ref        string                   `json:"$ref"`
+	GoType     string                   `json:"x-go-type"`
+	Properties map[string]openAPISchema `json:"properties"`
+	AllOf      []openAPISchema          `json:"allOf"`
+}
+
+func loadOpenAPIDoc(t *testing.T) openAPIDoc {
+	var doc openAPIDoc
+	if err := json.Unmarshal(openAPISpec, &doc); err != nil {
+		t.Fatalf("openapi.json is invalid: %v", err)
+	}
+	return doc
+}
+
+func TestOpenAPIDocumentServed(t *testing.T) {
+	srv := NewServer(newFakeBackend(), 0)
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, string(openAPISpec), rec.Body.String())
+
+	// Every documented path is routed
+	for path := range loadOpenAPIDoc(t).Paths {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, strings.ReplaceAll(path, "{id}", "1"), nil))
+		assert.Equal(t, http.StatusOK, rec.Code, path)
+	}
+}
+
+func TestOpenAPIMatchesModels(t *testing.T) {
+	doc := loadOpenAPIDoc(t)
+	models := map[string]reflect.Type{
+		"*ActivityPage":        reflect.TypeOf(ActivityPage{}),
+		"*api.Pagination":      reflect.TypeOf(api.Pagination{}),
+		"*api.Activity":        reflect.TypeOf(api.Activity{}),
+		"*api.ActivityDetail":  reflect.TypeOf(api.ActivityDetail{}),
+		"api.Weather":          reflect.TypeOf(api.Weather{}),
+		"api.Gear":             reflect.TypeOf(api.Gear{}),
+		"api.GPSTrackPoint":    reflect.TypeOf(api.GPSTrackPoint{}),
+		"*api.UserProfile":     reflect.TypeOf(api.UserProfile{}),
+		"*api.UserStats":       reflect.TypeOf(api.UserStats{}),
+		"*api.SleepData":       reflect.TypeOf(api.SleepData{}),
+		"*api.SleepScores":     reflect.TypeOf(api.SleepScores{}),
+		"*api.DailyStress":     reflect.TypeOf(api.DailyStress{}),
+		"*api.DailySteps":      reflect.TypeOf(api.DailySteps{}),
+		"*api.HRVData":         reflect.TypeOf(api.HRVData{}),
+		"*api.BodyBatteryData": reflect.TypeOf(api.BodyBatteryData{}),
+	}
+
+	for name, schema := range doc.Components.Schemas {
+		if schema.GoType == "" {
+			continue
+		}
+		model, ok := models[schema.GoType]
+		if !assert.True(t, ok, "no model registered for %s", schema.GoType) {
+			continue
+		}
+		assert.Equal(t, modelFields(model), schemaFields(doc, schema), name)
+	}
+
+	params := doc.Paths["/activities"]["get"].Parameters
+	for _, p := range params {
+		if p.Name == "pageSize" {
+			assert.EqualValues(t, api.DefaultPageSize, p.Schema.Default)
+		}
+	}
+}
+
+// schemaFields lists the properties of s, following $ref and allOf
+func schemaFields(doc openAPIDoc, s openAPISchema) []string {
+	if s.Ref != "" {
+		s = doc.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+	var fields []string
+	for name := range s.Properties {
+		fields = append(fields, name)
+	}
+	for _, part := range s.AllOf {
+		fields = append(fields, schemaFields(doc, part)...)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// modelFields lists the JSON names of t's exported fields, including those of
+// embedded structs
+func modelFields(t reflect.Type) []string {
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if f.Anonymous && name == "" {
+			fields = append(fields, modelFields(f.Type)...)
+			continue
+		}
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return fields
+}
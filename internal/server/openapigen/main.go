@@ -0,0 +1,268 @@
+// Command openapigen generates the routing of the REST proxy from its OpenAPI
+// document. Run it with go generate in internal/server: it reads openapi.json
+// and writes openapi_gen.go, which declares the operations interface, one
+// parameter struct per operation and registerOperations, which parses the
+// parameters and routes each request to its operation.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+type document struct {
+	Paths      map[string]map[string]operation `json:"paths"`
+	Components struct {
+		Parameters map[string]parameter `json:"parameters"`
+		Schemas    map[string]schema    `json:"schemas"`
+	} `json:"components"`
+}
+
+type operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary"`
+	Parameters  []parameter         `json:"parameters"`
+	Responses   map[string]response `json:"responses"`
+}
+
+type parameter struct {
+	Ref      string `json:"$ref"`
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Required bool   `json:"required"`
+	Schema   schema `json:"schema"`
+}
+
+type schema struct {
+	Ref     string      `json:"$ref"`
+	Type    string      `json:"type"`
+	Format  string      `json:"format"`
+	Title   string      `json:"title"`
+	Default interface{} `json:"default"`
+	Minimum *float64    `json:"minimum"`
+	GoType  string      `json:"x-go-type"`
+}
+
+type response struct {
+	Content map[string]struct {
+		Schema schema `json:"schema"`
+	} `json:"content"`
+}
+
+// route is one operation ready to be written out
+type route struct {
+	method, path string
+	op           operation
+	params       []parameter
+	result       string // Go type of the 200 response
+	text         bool   // the response is text/plain
+}
+
+func main() {
+	in, out := "openapi.json", "openapi_gen.go"
+	data, err := os.ReadFile(in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		log.Fatalf("failed to parse %s: %v", in, err)
+	}
+
+	routes, err := collect(&doc)
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := format.Source(generate(routes))
+	if err != nil {
+		log.Fatalf("failed to format generated code: %v", err)
+	}
+	if err := os.WriteFile(out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// collect resolves the operations of doc, sorted by path and method
+func collect(doc *document) ([]route, error) {
+	var routes []route
+	for path, methods := range doc.Paths {
+		for method, op := range methods {
+			if op.OperationID == "" {
+				return nil, fmt.Errorf("%s %s has no operationId", method, path)
+			}
+			r := route{method: strings.ToUpper(method), path: path, op: op}
+			for _, p := range op.Parameters {
+				if p.Ref != "" {
+					name := strings.TrimPrefix(p.Ref, "#/components/parameters/")
+					resolved, ok := doc.Components.Parameters[name]
+					if !ok {
+						return nil, fmt.Errorf("%s: unknown parameter %s", op.OperationID, p.Ref)
+					}
+					p = resolved
+				}
+				if _, err := paramGoType(p); err != nil {
+					return nil, fmt.Errorf("%s: %w", op.OperationID, err)
+				}
+				r.params = append(r.params, p)
+			}
+
+			ok, found := op.Responses["200"]
+			if !found {
+				return nil, fmt.Errorf("%s has no 200 response", op.OperationID)
+			}
+			if c, isJSON := ok.Content["application/json"]; isJSON {
+				name := strings.TrimPrefix(c.Schema.Ref, "#/components/schemas/")
+				r.result = doc.Components.Schemas[name].GoType
+				if r.result == "" {
+					return nil, fmt.Errorf("%s: response schema %q has no x-go-type", op.OperationID, c.Schema.Ref)
+				}
+			} else if _, isText := ok.Content["text/plain"]; isText {
+				r.result, r.text = "string", true
+			} else {
+				return nil, fmt.Errorf("%s: unsupported response content", op.OperationID)
+			}
+			routes = append(routes, r)
+		}
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].path != routes[j].path {
+			return routes[i].path < routes[j].path
+		}
+		return routes[i].method < routes[j].method
+	})
+	return routes, nil
+}
+
+// paramGoType returns the Go type a parameter is parsed into
+func paramGoType(p parameter) (string, error) {
+	switch {
+	case p.In == "path" && p.Schema.Type == "integer" && p.Schema.Format == "int64":
+		return "int64", nil
+	case p.In == "query" && p.Schema.Type == "integer" && p.Schema.Default != nil:
+		return "int", nil
+	case p.In == "query" && p.Schema.Type == "string" && p.Schema.Format == "date" && !p.Required:
+		return "*time.Time", nil
+	}
+	return "", fmt.Errorf("unsupported %s parameter %q", p.In, p.Name)
+}
+
+// parseCall returns the expression parsing p from the request r
+func parseCall(p parameter) string {
+	label := p.Name
+	if p.Schema.Title != "" {
+		label = p.Schema.Title
+	}
+	switch p.In {
+	case "path":
+		return fmt.Sprintf("pathInt64(r, %q, %q)", p.Name, label)
+	}
+	if p.Schema.Type == "integer" {
+		minimum := "math.MinInt"
+		if p.Schema.Minimum != nil {
+			minimum = fmt.Sprint(*p.Schema.Minimum)
+		}
+		return fmt.Sprintf("queryInt(r, %q, %q, %v, %s)", p.Name, label, p.Schema.Default, minimum)
+	}
+	return fmt.Sprintf("queryDate(r, %q)", p.Name)
+}
+
+func generate(routes []route) []byte {
+	var body bytes.Buffer
+	w := func(format string, args ...interface{}) { fmt.Fprintf(&body, format, args...) }
+
+	w("// operations implements the operations of openapi.json\n")
+	w("type operations interface {\n")
+	for _, r := range routes {
+		w("// %s: %s (%s %s)\n", exported(r.op.OperationID), r.op.Summary, r.method, r.path)
+		if len(r.params) == 0 {
+			w("%s(ctx context.Context) (%s, error)\n", exported(r.op.OperationID), r.result)
+		} else {
+			w("%s(ctx context.Context, params %sParams) (%s, error)\n", exported(r.op.OperationID), r.op.OperationID, r.result)
+		}
+	}
+	w("}\n\n")
+
+	for _, r := range routes {
+		if len(r.params) == 0 {
+			continue
+		}
+		w("// %sParams holds the parameters of %s\n", r.op.OperationID, r.op.OperationID)
+		w("type %sParams struct {\n", r.op.OperationID)
+		for _, p := range r.params {
+			typ, _ := paramGoType(p)
+			w("%s %s\n", fieldName(p.Name), typ)
+		}
+		w("}\n\n")
+	}
+
+	w("// registerOperations routes the operations of openapi.json to ops\n")
+	w("func (s *Server) registerOperations(ops operations) {\n")
+	for _, r := range routes {
+		pattern := r.method + " " + r.path
+		name := exported(r.op.OperationID)
+		if r.text {
+			w("s.mux.HandleFunc(%q, s.handleText(func(r *http.Request) (string, error) {\n", pattern)
+		} else {
+			w("s.mux.HandleFunc(%q, s.handle(func(r *http.Request) (interface{}, error) {\n", pattern)
+		}
+		if len(r.params) == 0 {
+			w("return ops.%s(r.Context())\n", name)
+			w("}))\n")
+			continue
+		}
+		w("var params %sParams\n", r.op.OperationID)
+		w("var err error\n")
+		for _, p := range r.params {
+			w("if params.%s, err = %s; err != nil {\n", fieldName(p.Name), parseCall(p))
+			if r.text {
+				w("return \"\", err\n")
+			} else {
+				w("return nil, err\n")
+			}
+			w("}\n")
+		}
+		w("return ops.%s(r.Context(), params)\n", name)
+		w("}))\n")
+	}
+	w("}\n")
+
+	imports := []string{`"context"`, `"net/http"`}
+	for pkg, path := range map[string]string{"math.": `"math"`, "time.": `"time"`} {
+		if bytes.Contains(body.Bytes(), []byte(pkg)) {
+			imports = append(imports, path)
+		}
+	}
+	sort.Strings(imports)
+	if bytes.Contains(body.Bytes(), []byte("api.")) {
+		imports = append(imports, "", `"github.com/sstent/go-garminconnect/internal/api"`)
+	}
+
+	var src bytes.Buffer
+	src.WriteString("// Code generated by openapigen from openapi.json. DO NOT EDIT.\n\n")
+	src.WriteString("package server\n\n")
+	src.WriteString("import (\n" + strings.Join(imports, "\n") + "\n)\n\n")
+	src.Write(body.Bytes())
+	return src.Bytes()
+}
+
+// exported capitalizes an operationId into a method name
+func exported(id string) string {
+	r := []rune(id)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// fieldName turns a parameter name into a Go field name
+func fieldName(name string) string {
+	if name == "id" {
+		return "ID"
+	}
+	return exported(name)
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	_ "embed"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+//go:generate go run ./openapigen
+
+// openAPISpec describes the REST proxy; openapi_gen.go is generated from it
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// ActivityPage is the response of GET /activities
+type ActivityPage struct {
+	Activities []api.Activity  `json:"activities"`
+	Pagination *api.Pagination `json:"pagination"`
+}
+
+// backendOperations implements the operations of openapi.json with a Backend
+type backendOperations struct {
+	backend Backend
+}
+
+// todayer is implemented by backends that know the user's current calendar day
+type todayer interface {
+	Today(ctx context.Context) (time.Time, error)
+}
+
+// day returns date, or today (in the user's time zone when the backend knows
+// it) when the request left it out
+func (o backendOperations) day(ctx context.Context, date *time.Time) (time.Time, error) {
+	if date != nil {
+		return *date, nil
+	}
+	if t, ok := o.backend.(todayer); ok {
+		return t.Today(ctx)
+	}
+	return time.Now(), nil
+}
+
+func (o backendOperations) GetHealth(ctx context.Context) (string, error) {
+	return "OK", nil
+}
+
+func (o backendOperations) ListActivities(ctx context.Context, params listActivitiesParams) (*ActivityPage, error) {
+	activities, pagination, err := o.backend.GetActivities(ctx, api.PageRequest{Page: params.Page, PageSize: params.PageSize})
+	if err != nil {
+		return nil, err
+	}
+	return &ActivityPage{Activities: activities, Pagination: pagination}, nil
+}
+
+func (o backendOperations) GetActivity(ctx context.Context, params getActivityParams) (*api.ActivityDetail, error) {
+	return o.backend.GetActivityDetails(ctx, params.ID)
+}
+
+func (o backendOperations) GetUserProfile(ctx context.Context) (*api.UserProfile, error) {
+	return o.backend.GetUserProfile(ctx)
+}
+
+func (o backendOperations) GetUserStats(ctx context.Context, params getUserStatsParams) (*api.UserStats, error) {
+	day, err := o.day(ctx, params.Date)
+	if err != nil {
+		return nil, err
+	}
+	return o.backend.GetUserStats(ctx, day)
+}
+
+func (o backendOperations) GetSleep(ctx context.Context, params getSleepParams) (*api.SleepData, error) {
+	day, err := o.day(ctx, params.Date)
+	if err != nil {
+		return nil, err
+	}
+	return o.backend.GetSleepData(ctx, day)
+}
+
+func (o backendOperations) GetStress(ctx context.Context, params getStressParams) (*api.DailyStress, error) {
+	day, err := o.day(ctx, params.Date)
+	if err != nil {
+		return nil, err
+	}
+	return o.backend.GetStressData(ctx, day)
+}
+
+func (o backendOperations) GetSteps(ctx context.Context, params getStepsParams) (*api.DailySteps, error) {
+	day, err := o.day(ctx, params.Date)
+	if err != nil {
+		return nil, err
+	}
+	return o.backend.GetStepsData(ctx, day)
+}
+
+func (o backendOperations) GetHRV(ctx context.Context, params getHRVParams) (*api.HRVData, error) {
+	day, err := o.day(ctx, params.Date)
+	if err != nil {
+		return nil, err
+	}
+	return o.backend.GetHRVData(ctx, day)
+}
+
+func (o backendOperations) GetBodyBattery(ctx context.Context, params getBodyBatteryParams) (*api.BodyBatteryData, error) {
+	day, err := o.day(ctx, params.Date)
+	if err != nil {
+		return nil, err
+	}
+	return o.backend.GetBodyBatteryData(ctx, day)
+}
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/archive"
+)
+
+// Backend defines the client methods exposed by the REST proxy
+type Backend interface {
+	GetActivities(ctx context.Context, req api.PageRequest, opts ...api.RequestOption) ([]api.Activity, *api.Pagination, error)
+	GetActivityDetails(ctx context.Context, activityID int64, opts ...api.RequestOption) (*api.ActivityDetail, error)
+	GetUserProfile(ctx context.Context, opts ...api.RequestOption) (*api.UserProfile, error)
+	GetUserStats(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.UserStats, error)
+	GetSleepData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.SleepData, error)
+	GetStressData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.DailyStress, error)
+	GetStepsData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.DailySteps, error)
+	GetHRVData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.HRVData, error)
+	GetBodyBatteryData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.BodyBatteryData, error)
+}
+
+// Server exposes Garmin Connect data as local JSON endpoints
+type Server struct {
+	backend Backend
+	cache   *responseCache
+	mux     *http.ServeMux
+}
+
+// NewServer creates a REST proxy backed by the given client.
+// Responses are cached for cacheTTL; a zero TTL disables caching.
+func NewServer(backend Backend, cacheTTL time.Duration) *Server {
+	s := &Server{
+		backend: backend,
+		cache:   newResponseCache(cacheTTL),
+		mux:     http.NewServeMux(),
+	}
+	s.routes()
+	return s
+}
+
+// routes registers the endpoints of openapi.json and the document itself
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, openAPISpec)
+	})
+	s.registerOperations(backendOperations{backend: s.backend})
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// handle wraps a fetch function with caching and JSON encoding. Responses
+// answered from the archive of an archive.Fallback backend are marked with the
+// X-Data-Source and X-Archived-At headers.
+func (s *Server) handle(fetch func(r *http.Request) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.String()
+		if body, ok := s.cache.get(key); ok {
+			writeJSON(w, http.StatusOK, body)
+			return
+		}
+
+		ctx, fresh := archive.WithFreshness(r.Context())
+		result, err := fetch(r.WithContext(ctx))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		body, err := json.Marshal(result)
+		if err != nil {
+			writeError(w, fmt.Errorf("failed to encode response: %w", err))
+			return
+		}
+		// archived data is not cached so the next request tries Garmin again
+		if stale, archivedAt, _ := fresh.Stale(); stale {
+			w.Header().Set("X-Data-Source", "archive")
+			if !archivedAt.IsZero() {
+				w.Header().Set("X-Archived-At", archivedAt.UTC().Format(time.RFC3339))
+			}
+		} else {
+			s.cache.set(key, body)
+		}
+		writeJSON(w, http.StatusOK, body)
+	}
+}
+
+// handleText wraps an operation answering in plain text. These responses
+// are not cached.
+func (s *Server) handleText(fetch func(r *http.Request) (string, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		text, err := fetch(r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(text))
+	}
+}
+
+// requestError is returned for invalid client input
+type requestError struct {
+	msg string
+}
+
+func (e *requestError) Error() string { return e.msg }
+
+func badRequest(format string, args ...interface{}) error {
+	return &requestError{msg: fmt.Sprintf(format, args...)}
+}
+
+// queryInt parses an optional integer query parameter of at least minimum
+func queryInt(r *http.Request, name, label string, def, minimum int) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < minimum {
+		return 0, badRequest("invalid %s %q", label, v)
+	}
+	return n, nil
+}
+
+// pathInt64 parses an integer path parameter
+func pathInt64(r *http.Request, name, label string) (int64, error) {
+	n, err := strconv.ParseInt(r.PathValue(name), 10, 64)
+	if err != nil {
+		return 0, badRequest("invalid %s %q", label, r.PathValue(name))
+	}
+	return n, nil
+}
+
+// queryDate parses an optional YYYY-MM-DD query parameter
+func queryDate(r *http.Request, name string) (*time.Time, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse("2006-01-02", v)
+	if err != nil {
+		return nil, badRequest("invalid %s %q, expected YYYY-MM-DD", name, v)
+	}
+	return &t, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// writeError maps errors to HTTP status codes and a JSON error body
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusBadGateway
+	if _, ok := err.(*requestError); ok {
+		status = http.StatusBadRequest
+	} else if errors.Is(err, fs.ErrNotExist) {
+		// e.g. data an offline archive doesn't hold
+		status = http.StatusNotFound
+	} else {
+		log.Printf("backend request failed: %v", err)
+	}
+
+	body, _ := json.Marshal(map[string]string{"error": err.Error()})
+	writeJSON(w, status, body)
+}
@@ -0,0 +1,222 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/archive"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeBackend records calls and returns canned data
+type fakeBackend struct {
+	calls     map[string]int
+	lastDate  time.Time
+	detailErr error
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{calls: make(map[string]int)}
+}
+
+func (f *fakeBackend) GetActivities(ctx context.Context, req api.PageRequest, opts ...api.RequestOption) ([]api.Activity, *api.Pagination, error) {
+	f.calls["activities"]++
+	return []api.Activity{{ActivityID: 1, Name: "Morning Run"}}, &api.Pagination{Page: req.Page, PageSize: req.PageSize, TotalCount: 1}, nil
+}
+
+func (f *fakeBackend) GetActivityDetails(ctx context.Context, activityID int64, opts ...api.RequestOption) (*api.ActivityDetail, error) {
+	f.calls["activity"]++
+	if f.detailErr != nil {
+		return nil, f.detailErr
+	}
+	return &api.ActivityDetail{Activity: api.Activity{ActivityID: activityID}, Calories: 500}, nil
+}
+
+func (f *fakeBackend) GetUserProfile(ctx context.Context, opts ...api.RequestOption) (*api.UserProfile, error) {
+	f.calls["profile"]++
+	return &api.UserProfile{DisplayName: "mock"}, nil
+}
+
+func (f *fakeBackend) GetUserStats(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.UserStats, error) {
+	f.calls["stats"]++
+	return &api.UserStats{TotalSteps: api.Ptr(1000)}, nil
+}
+
+func (f *fakeBackend) GetSleepData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.SleepData, error) {
+	f.calls["sleep"]++
+	f.lastDate = date
+	return &api.SleepData{SleepTimeSeconds: api.Ptr(28800)}, nil
+}
+
+func (f *fakeBackend) GetStressData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.DailyStress, error) {
+	f.calls["stress"]++
+	return &api.DailyStress{}, nil
+}
+
+func (f *fakeBackend) GetStepsData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.DailySteps, error) {
+	f.calls["steps"]++
+	return &api.DailySteps{}, nil
+}
+
+func (f *fakeBackend) GetHRVData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.HRVData, error) {
+	f.calls["hrv"]++
+	return &api.HRVData{}, nil
+}
+
+func (f *fakeBackend) GetBodyBatteryData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.BodyBatteryData, error) {
+	f.calls["bodybattery"]++
+	return &api.BodyBatteryData{}, nil
+}
+
+func TestServerEndpoints(t *testing.T) {
+	backend := newFakeBackend()
+	srv := NewServer(backend, time.Minute)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"health check", "/health", http.StatusOK, "OK"},
+		{"activities", "/activities?page=2&pageSize=5", http.StatusOK, `"pageSize":5`},
+		{"activity detail", "/activities/42", http.StatusOK, `"activityId":42`},
+		{"invalid activity id", "/activities/abc", http.StatusBadRequest, "invalid activity ID"},
+		{"profile", "/user/profile", http.StatusOK, `"displayName":"mock"`},
+		{"stats", "/user/stats", http.StatusOK, `"totalSteps":1000`},
+		{"sleep", "/health/sleep?date=2024-03-01", http.StatusOK, `"sleepTimeSeconds":28800`},
+		{"invalid date", "/health/sleep?date=yesterday", http.StatusBadRequest, "invalid date"},
+		{"invalid page", "/activities?page=-1", http.StatusBadRequest, "invalid page"},
+		{"unknown route", "/nope", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+		})
+	}
+
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), backend.lastDate)
+}
+
+// zonedBackend knows the user's current calendar day
+type zonedBackend struct {
+	*fakeBackend
+	today time.Time
+}
+
+func (z *zonedBackend) Today(ctx context.Context) (time.Time, error) {
+	return z.today, nil
+}
+
+func TestServerDefaultsToUserToday(t *testing.T) {
+	today := time.Date(2024, 3, 2, 0, 0, 0, 0, time.FixedZone("JST", 9*3600))
+	backend := &zonedBackend{fakeBackend: newFakeBackend(), today: today}
+	srv := NewServer(backend, 0)
+
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/sleep", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, today, backend.lastDate)
+}
+
+func TestServerCaching(t *testing.T) {
+	backend := newFakeBackend()
+	srv := NewServer(backend, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/sleep?date=2024-03-01", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	assert.Equal(t, 1, backend.calls["sleep"])
+
+	// A different query is a different cache key
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/sleep?date=2024-03-02", nil))
+	assert.Equal(t, 2, backend.calls["sleep"])
+
+	t.Run("disabled", func(t *testing.T) {
+		backend := newFakeBackend()
+		srv := NewServer(backend, 0)
+		for i := 0; i < 2; i++ {
+			srv.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/user/profile", nil))
+		}
+		assert.Equal(t, 2, backend.calls["profile"])
+	})
+}
+
+func TestServerBackendError(t *testing.T) {
+	backend := newFakeBackend()
+	backend.detailErr = errors.New("API error 500: boom")
+	srv := NewServer(backend, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/activities/1", nil))
+		assert.Equal(t, http.StatusBadGateway, rec.Code)
+
+		var body map[string]string
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, "API error 500: boom", body["error"])
+	}
+	// Errors are never cached
+	assert.Equal(t, 2, backend.calls["activity"])
+
+	// Data that doesn't exist, e.g. in an offline archive, is not found
+	backend.detailErr = fmt.Errorf("activity 1: %w", fs.ErrNotExist)
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/activities/1", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+// detailSource answers GetActivityDetails; the other methods are not called
+type detailSource struct {
+	archive.Source
+	calls  int
+	detail *api.ActivityDetail
+	err    error
+}
+
+func (s *detailSource) GetActivityDetails(ctx context.Context, activityID int64, opts ...api.RequestOption) (*api.ActivityDetail, error) {
+	s.calls++
+	return s.detail, s.err
+}
+
+func TestServerArchivedResponses(t *testing.T) {
+	archivedAt := time.Date(2024, 3, 1, 6, 0, 0, 0, time.UTC)
+	live := &detailSource{err: errors.New("dial tcp: connection refused")}
+	srv := NewServer(&archive.Fallback{
+		Live:       live,
+		Archive:    &detailSource{detail: &api.ActivityDetail{Activity: api.Activity{ActivityID: 1, Name: "Morning Run"}}},
+		ArchivedAt: archivedAt,
+	}, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/activities/1", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Morning Run")
+		assert.Equal(t, "archive", rec.Header().Get("X-Data-Source"))
+		assert.Equal(t, "2024-03-01T06:00:00Z", rec.Header().Get("X-Archived-At"))
+	}
+	// Archived responses are not cached
+	assert.Equal(t, 2, live.calls)
+
+	live.err, live.detail = nil, &api.ActivityDetail{Activity: api.Activity{ActivityID: 1, Name: "Evening Run"}}
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/activities/1", nil))
+	assert.Contains(t, rec.Body.String(), "Evening Run")
+	assert.Empty(t, rec.Header().Get("X-Data-Source"))
+}
@@ -0,0 +1,140 @@
+// Dashboard rendering for the local Garmin Connect proxy.
+// Data is fetched from the JSON API mounted under /api.
+
+const DAYS = 14;
+
+// isoDate formats the local calendar date of d as YYYY-MM-DD. toISOString
+// would use the UTC date, which is a day off in the evening west of UTC and
+// in the morning east of it.
+function isoDate(d) {
+  const pad = (n) => String(n).padStart(2, "0");
+  return `${d.getFullYear()}-${pad(d.getMonth() + 1)}-${pad(d.getDate())}`;
+}
+
+function lastDays(n) {
+  const days = [];
+  const today = new Date();
+  for (let i = n - 1; i >= 0; i--) {
+    const d = new Date(today);
+    d.setDate(today.getDate() - i);
+    days.push(isoDate(d));
+  }
+  return days;
+}
+
+async function fetchJSON(path) {
+  const resp = await fetch("api" + path);
+  if (!resp.ok) {
+    throw new Error(path + ": " + resp.status);
+  }
+  return resp.json();
+}
+
+// fetchDaily requests a per-day endpoint for every date, using null for missing days
+async function fetchDaily(path, days) {
+  return Promise.all(days.map((d) => fetchJSON(path + "?date=" + d).catch(() => null)));
+}
+
+function svgEl(name, attrs, text) {
+  const el = document.createElementNS("http://www.w3.org/2000/svg", name);
+  for (const [k, v] of Object.entries(attrs)) {
+    el.setAttribute(k, v);
+  }
+  if (text !== undefined) {
+    el.textContent = text;
+  }
+  return el;
+}
+
+function chartScale(values) {
+  const max = Math.max(1, ...values.filter((v) => v !== null));
+  return (v) => 170 * (v / max);
+}
+
+function barChart(svg, labels, values) {
+  const scale = chartScale(values);
+  const width = 700 / labels.length;
+  labels.forEach((label, i) => {
+    const v = values[i] || 0;
+    const h = scale(v);
+    svg.appendChild(svgEl("rect", { x: i * width + 4, y: 180 - h, width: width - 8, height: h }));
+    svg.appendChild(svgEl("text", { x: i * width + 4, y: 195 }, label.slice(5)));
+  });
+}
+
+function lineChart(svg, labels, values) {
+  const scale = chartScale(values);
+  const width = 700 / labels.length;
+  const points = [];
+  labels.forEach((label, i) => {
+    if (values[i] !== null) {
+      points.push(`${i * width + width / 2},${180 - scale(values[i])}`);
+    }
+    svg.appendChild(svgEl("text", { x: i * width + 4, y: 195 }, label.slice(5)));
+  });
+  svg.appendChild(svgEl("polyline", { points: points.join(" ") }));
+}
+
+function renderCalendar(container, activities) {
+  const byDay = {};
+  for (const a of activities) {
+    const day = a.startTimeLocal.slice(0, 10);
+    (byDay[day] = byDay[day] || []).push(a);
+  }
+
+  // Start the grid on the Monday four weeks ago
+  const start = new Date();
+  start.setDate(start.getDate() - 27 - ((start.getDay() + 6) % 7));
+  for (let i = 0; i < 35; i++) {
+    const d = new Date(start);
+    d.setDate(start.getDate() + i);
+    const key = isoDate(d);
+    const cell = document.createElement("div");
+    cell.className = "day" + (byDay[key] ? " active" : "");
+    cell.innerHTML = `<div class="date">${key.slice(5)}</div>`;
+    for (const a of byDay[key] || []) {
+      const item = document.createElement("div");
+      item.textContent = a.activityName || a.activityType;
+      cell.appendChild(item);
+    }
+    container.appendChild(cell);
+  }
+}
+
+async function main() {
+  const days = lastDays(DAYS);
+
+  fetchJSON("/user/profile")
+    .then((p) => (document.getElementById("profile").textContent = p.displayName))
+    .catch(() => {});
+
+  const [sleep, hrv, list] = await Promise.all([
+    fetchDaily("/health/sleep", days),
+    fetchDaily("/health/hrv", days),
+    fetchJSON("/activities?pageSize=100").catch(() => ({ activities: [] })),
+  ]);
+
+  barChart(
+    document.getElementById("sleep-chart"),
+    days,
+    sleep.map((s) =>
+      s && s.sleepTimeSeconds != null ? s.sleepTimeSeconds / 3600 : null,
+    ),
+  );
+  lineChart(
+    document.getElementById("hrv-chart"),
+    days,
+    hrv.map((h) => (h && h.lastNightAvg ? h.lastNightAvg : null)),
+  );
+
+  const activities = list.activities || [];
+  const minutes = days.map((d) =>
+    activities
+      .filter((a) => a.startTimeLocal.slice(0, 10) === d)
+      .reduce((sum, a) => sum + a.duration / 60, 0),
+  );
+  barChart(document.getElementById("load-chart"), days, minutes);
+  renderCalendar(document.getElementById("calendar"), activities);
+}
+
+main();
@@ -0,0 +1,239 @@
+// Package sheets appends Garmin data to a Google Sheet through the Sheets API,
+// authenticating as a Google Cloud service account
+package sheets
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// DefaultBaseURL is the Google Sheets API host
+const DefaultBaseURL = "https://sheets.googleapis.com"
+
+// scope grants read and write access to the spreadsheets shared with the
+// service account
+const scope = "https://www.googleapis.com/auth/spreadsheets"
+
+// ServiceAccountKey holds the fields of a service account JSON key file used
+// to request access tokens
+type ServiceAccountKey struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// LoadServiceAccountKey reads a key file downloaded from the Google Cloud console
+func LoadServiceAccountKey(path string) (*ServiceAccountKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account key: %w", err)
+	}
+	var key ServiceAccountKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, fmt.Errorf("failed to parse service account key: %w", err)
+	}
+	if key.ClientEmail == "" || key.PrivateKey == "" {
+		return nil, errors.New("service account key lacks client_email or private_key")
+	}
+	if key.TokenURI == "" {
+		key.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+	return &key, nil
+}
+
+// Client reads and appends rows of one spreadsheet. The spreadsheet must be
+// shared with the service account's email address.
+type Client struct {
+	HTTPClient    *resty.Client
+	spreadsheetID string
+	key           *ServiceAccountKey
+	signer        *rsa.PrivateKey
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewClient creates a client for the spreadsheet with the given ID, the part of
+// its URL after /spreadsheets/d/
+func NewClient(key *ServiceAccountKey, spreadsheetID string) (*Client, error) {
+	block, _ := pem.Decode([]byte(key.PrivateKey))
+	if block == nil {
+		return nil, errors.New("service account private key is not PEM encoded")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse service account private key: %w", err)
+	}
+	signer, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("service account private key is not an RSA key")
+	}
+
+	client := resty.New()
+	client.SetBaseURL(DefaultBaseURL)
+	client.SetTimeout(60 * time.Second)
+	client.SetHeader("Accept", "application/json")
+	return &Client{HTTPClient: client, spreadsheetID: spreadsheetID, key: key, signer: signer}, nil
+}
+
+// accessToken returns a cached token, exchanging a signed JWT for a new one
+// shortly before the old one expires
+func (c *Client) accessToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != "" && time.Until(c.expiry) > time.Minute {
+		return c.token, nil
+	}
+
+	assertion, err := c.assertion(time.Now())
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	resp, err := c.HTTPClient.R().
+		SetContext(ctx).
+		SetFormData(map[string]string{
+			"grant_type": "urn:ietf:params:oauth:grant-type:jwt-bearer",
+			"assertion":  assertion,
+		}).
+		SetResult(&result).
+		Post(c.key.TokenURI)
+	if err != nil {
+		return "", fmt.Errorf("failed to get access token: %w", err)
+	}
+	if resp.IsError() {
+		return "", fmt.Errorf("failed to get access token: %s: %s", resp.Status(), resp.String())
+	}
+	c.token = result.AccessToken
+	c.expiry = time.Now().Add(time.Duration(result.ExpiresIn) * time.Second)
+	return c.token, nil
+}
+
+// assertion returns the RS256-signed JWT requesting a token for scope
+func (c *Client) assertion(now time.Time) (string, error) {
+	encode := func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(data), err
+	}
+	header, err := encode(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := encode(map[string]interface{}{
+		"iss":   c.key.ClientEmail,
+		"scope": scope,
+		"aud":   c.key.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := header + "." + claims
+	sum := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, c.signer, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token request: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// request returns a request authorized with an access token
+func (c *Client) request(ctx context.Context) (*resty.Request, error) {
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.HTTPClient.R().
+		SetContext(ctx).
+		SetAuthToken(token).
+		SetPathParam("spreadsheet", c.spreadsheetID), nil
+}
+
+// valueRange is the Sheets API representation of cell values
+type valueRange struct {
+	Values [][]interface{} `json:"values"`
+}
+
+// Column returns the values of the first column of sheet, including its
+// header, as displayed in the sheet
+func (c *Client) Column(ctx context.Context, sheet string) ([]string, error) {
+	req, err := c.request(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result valueRange
+	resp, err := req.
+		SetPathParam("range", quoteSheet(sheet)+"!A:A").
+		SetResult(&result).
+		Get("/v4/spreadsheets/{spreadsheet}/values/{range}")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sheet %s: %w", sheet, err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("failed to read sheet %s: %s: %s", sheet, resp.Status(), resp.String())
+	}
+	column := make([]string, len(result.Values))
+	for i, row := range result.Values {
+		if len(row) > 0 {
+			column[i] = fmt.Sprint(row[0])
+		}
+	}
+	return column, nil
+}
+
+// AppendRows adds rows below the last row of sheet. Values are stored as
+// given rather than parsed, so dates stay text that Column returns unchanged.
+func (c *Client) AppendRows(ctx context.Context, sheet string, rows [][]interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	req, err := c.request(ctx)
+	if err != nil {
+		return err
+	}
+	resp, err := req.
+		SetPathParam("range", quoteSheet(sheet)+"!A1").
+		SetQueryParam("valueInputOption", "RAW").
+		SetQueryParam("insertDataOption", "INSERT_ROWS").
+		SetBody(valueRange{Values: rows}).
+		Post("/v4/spreadsheets/{spreadsheet}/values/{range}:append")
+	if err != nil {
+		return fmt.Errorf("failed to append to sheet %s: %w", sheet, err)
+	}
+	if resp.IsError() {
+		return fmt.Errorf("failed to append to sheet %s: %s: %s", sheet, resp.Status(), resp.String())
+	}
+	return nil
+}
+
+// quoteSheet quotes a sheet name for use in A1 notation
+func quoteSheet(sheet string) string {
+	quoted := []rune{'\''}
+	for _, r := range sheet {
+		if r == '\'' {
+			quoted = append(quoted, r)
+		}
+		quoted = append(quoted, r)
+	}
+	return string(append(quoted, '\''))
+}
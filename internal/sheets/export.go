@@ -0,0 +1,146 @@
+package sheets
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/csvlog"
+)
+
+// Config selects the spreadsheet and sheets the exporter writes to
+type Config struct {
+	SpreadsheetID string `json:"spreadsheetId"`
+	// CredentialsFile is the service account JSON key file
+	CredentialsFile string `json:"credentialsFile"`
+	DailySheet      string `json:"dailySheet,omitempty"`      // default "Daily"
+	ActivitiesSheet string `json:"activitiesSheet,omitempty"` // default "Activities"
+}
+
+var (
+	dailyHeader    = []interface{}{"Date", "Steps", "Resting HR", "Sleep (min)", "Sleep score"}
+	dailyMetrics   = []string{csvlog.MetricSteps, csvlog.MetricRestingHR, csvlog.MetricSleepMinutes, csvlog.MetricSleepScore}
+	activityHeader = []interface{}{"Activity ID", "Start", "Name", "Type", "Duration (min)", "Distance (km)"}
+)
+
+// Exporter pushes daily summaries and activities to a spreadsheet, one row
+// each. Rows already in the sheet are recognized by their first column, so
+// pushing the same days again adds nothing.
+type Exporter struct {
+	Client          *Client
+	DailySheet      string
+	ActivitiesSheet string
+}
+
+// NewExporter creates an exporter from the configuration
+func NewExporter(cfg Config) (*Exporter, error) {
+	if cfg.SpreadsheetID == "" || cfg.CredentialsFile == "" {
+		return nil, errors.New("spreadsheetId and credentialsFile are required")
+	}
+	key, err := LoadServiceAccountKey(cfg.CredentialsFile)
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewClient(key, cfg.SpreadsheetID)
+	if err != nil {
+		return nil, err
+	}
+	e := &Exporter{Client: client, DailySheet: cfg.DailySheet, ActivitiesSheet: cfg.ActivitiesSheet}
+	if e.DailySheet == "" {
+		e.DailySheet = "Daily"
+	}
+	if e.ActivitiesSheet == "" {
+		e.ActivitiesSheet = "Activities"
+	}
+	return e, nil
+}
+
+// PushDaily appends a row per day of the values not yet in the daily sheet and
+// returns how many were added. Metrics without a value that day are left blank.
+func (e *Exporter) PushDaily(ctx context.Context, values []csvlog.Value) (int, error) {
+	days := make(map[string]map[string]float64)
+	for _, v := range values {
+		day := v.Date.String()
+		if days[day] == nil {
+			days[day] = make(map[string]float64)
+		}
+		days[day][v.Metric] = v.Value
+	}
+
+	keys := make([]string, 0, len(days))
+	for day := range days {
+		keys = append(keys, day)
+	}
+	sort.Strings(keys)
+	rows := make([][]interface{}, len(keys))
+	for i, day := range keys {
+		row := []interface{}{day}
+		for _, m := range dailyMetrics {
+			if v, ok := days[day][m]; ok {
+				row = append(row, v)
+			} else {
+				row = append(row, "")
+			}
+		}
+		rows[i] = row
+	}
+	return e.appendNew(ctx, e.DailySheet, dailyHeader, rows)
+}
+
+// PushActivities appends a row per activity not yet in the activities sheet and
+// returns how many were added
+func (e *Exporter) PushActivities(ctx context.Context, activities []api.Activity) (int, error) {
+	sorted := append([]api.Activity(nil), activities...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].StartTime.Before(sorted[j].StartTime.Time) })
+
+	rows := make([][]interface{}, len(sorted))
+	for i, a := range sorted {
+		rows[i] = []interface{}{
+			strconv.FormatInt(a.ActivityID, 10),
+			a.StartTime.Format("2006-01-02 15:04"),
+			a.Name,
+			a.Type,
+			math.Round(a.Duration/60*10) / 10,
+			math.Round(a.Distance/10) / 100,
+		}
+	}
+	return e.appendNew(ctx, e.ActivitiesSheet, activityHeader, rows)
+}
+
+// appendNew appends the rows whose first cell is not yet in the sheet's first
+// column, starting an empty sheet with header
+func (e *Exporter) appendNew(ctx context.Context, sheet string, header []interface{}, rows [][]interface{}) (int, error) {
+	column, err := e.Client.Column(ctx, sheet)
+	if err != nil {
+		return 0, err
+	}
+	existing := make(map[string]bool, len(column))
+	for _, key := range column {
+		existing[key] = true
+	}
+
+	var fresh [][]interface{}
+	if len(column) == 0 {
+		fresh = append(fresh, header)
+	}
+	for _, row := range rows {
+		if key := row[0].(string); !existing[key] {
+			existing[key] = true
+			fresh = append(fresh, row)
+		}
+	}
+	added := len(fresh)
+	if len(column) == 0 {
+		added--
+	}
+	if added == 0 {
+		return 0, nil
+	}
+	if err := e.Client.AppendRows(ctx, sheet, fresh); err != nil {
+		return 0, err
+	}
+	return added, nil
+}
@@ -0,0 +1,140 @@
+package sheets
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/csvlog"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSheets serves the token endpoint and the values API of one spreadsheet
+type fakeSheets struct {
+	t      *testing.T
+	public *rsa.PublicKey
+
+	mu     sync.Mutex
+	tokens int
+	sheets map[string][][]interface{}
+}
+
+func (f *fakeSheets) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.URL.Path == "/token" {
+		assert.Equal(f.t, "urn:ietf:params:oauth:grant-type:jwt-bearer", r.FormValue("grant_type"))
+		parts := strings.Split(r.FormValue("assertion"), ".")
+		if assert.Len(f.t, parts, 3) {
+			sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+			sum := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+			assert.NoError(f.t, rsa.VerifyPKCS1v15(f.public, crypto.SHA256, sum[:], sig))
+		}
+		f.tokens++
+		w.Write([]byte(`{"access_token": "token-1", "expires_in": 3600}`))
+		return
+	}
+
+	assert.Equal(f.t, "Bearer token-1", r.Header.Get("Authorization"))
+	path := strings.TrimPrefix(r.URL.Path, "/v4/spreadsheets/sheet-id/values/")
+	sheet, _, _ := strings.Cut(path, "!")
+	sheet = strings.Trim(sheet, "'")
+	switch r.Method {
+	case http.MethodGet:
+		var column [][]interface{}
+		for _, row := range f.sheets[sheet] {
+			column = append(column, row[:1])
+		}
+		json.NewEncoder(w).Encode(valueRange{Values: column})
+	case http.MethodPost:
+		assert.True(f.t, strings.HasSuffix(path, ":append"))
+		assert.Equal(f.t, "RAW", r.URL.Query().Get("valueInputOption"))
+		var body valueRange
+		assert.NoError(f.t, json.NewDecoder(r.Body).Decode(&body))
+		f.sheets[sheet] = append(f.sheets[sheet], body.Values...)
+		w.Write([]byte(`{}`))
+	}
+}
+
+func newTestExporter(t *testing.T) (*Exporter, *fakeSheets) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	assert.NoError(t, err)
+
+	fake := &fakeSheets{t: t, public: &private.PublicKey, sheets: map[string][][]interface{}{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(&ServiceAccountKey{
+		ClientEmail: "exporter@project.iam.gserviceaccount.com",
+		PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		TokenURI:    server.URL + "/token",
+	}, "sheet-id")
+	assert.NoError(t, err)
+	client.HTTPClient.SetBaseURL(server.URL)
+	return &Exporter{Client: client, DailySheet: "Daily", ActivitiesSheet: "My Activities"}, fake
+}
+
+func TestPushDaily(t *testing.T) {
+	e, fake := newTestExporter(t)
+	ctx := context.Background()
+	day := func(s string) api.Date {
+		d, _ := api.ParseDate(s)
+		return d
+	}
+	values := []csvlog.Value{
+		{Date: day("2024-03-02"), Metric: csvlog.MetricSteps, Value: 8000},
+		{Date: day("2024-03-01"), Metric: csvlog.MetricSteps, Value: 9500},
+		{Date: day("2024-03-01"), Metric: csvlog.MetricRestingHR, Value: 52},
+	}
+
+	added, err := e.PushDaily(ctx, values)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, added)
+	assert.Equal(t, [][]interface{}{
+		{"Date", "Steps", "Resting HR", "Sleep (min)", "Sleep score"},
+		{"2024-03-01", 9500.0, 52.0, "", ""},
+		{"2024-03-02", 8000.0, "", "", ""},
+	}, fake.sheets["Daily"])
+
+	added, err = e.PushDaily(ctx, append(values, csvlog.Value{Date: day("2024-03-03"), Metric: csvlog.MetricSteps, Value: 4000}))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, added)
+	assert.Len(t, fake.sheets["Daily"], 4)
+	assert.Equal(t, 1, fake.tokens, "the access token is reused")
+}
+
+func TestPushActivities(t *testing.T) {
+	e, fake := newTestExporter(t)
+	start := api.NewGarminTime(time.Date(2024, 3, 1, 7, 30, 0, 0, time.UTC))
+	activities := []api.Activity{{ActivityID: 42, Name: "Morning Run", Type: "running", StartTime: start, Duration: 1800, Distance: 5012}}
+
+	added, err := e.PushActivities(context.Background(), activities)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, added)
+	assert.Equal(t, []interface{}{"42", "2024-03-01 07:30", "Morning Run", "running", 30.0, 5.01}, fake.sheets["My Activities"][1])
+
+	added, err = e.PushActivities(context.Background(), activities)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, added)
+}
+
+func TestQuoteSheet(t *testing.T) {
+	assert.Equal(t, "'Runner''s log'", quoteSheet("Runner's log"))
+}
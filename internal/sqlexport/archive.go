@@ -0,0 +1,314 @@
+package sqlexport
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// ErrNotArchived is returned for data the archive doesn't hold, e.g. a day
+// that was never exported or a metric the native schema has no column for. It
+// matches fs.ErrNotExist.
+var ErrNotArchived error = notArchivedError{}
+
+type notArchivedError struct{}
+
+func (notArchivedError) Error() string { return "not in the archive" }
+
+func (notArchivedError) Is(target error) bool { return target == fs.ErrNotExist }
+
+// Archive serves the data of a SchemaNative export without contacting Garmin
+// Connect. It only reads the file. Activities carry their summary; the daily
+// data is limited to the steps, resting heart rate and sleep columns.
+type Archive struct {
+	db   *sql.DB
+	path string
+}
+
+// OpenArchive opens a SchemaNative export at path read-only
+func OpenArchive(path string) (*Archive, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	db, err := sql.Open("sqlite", "file:"+(&url.URL{Path: path}).EscapedPath()+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	var tables int
+	err = db.QueryRow(`SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name IN ('activities', 'daily')`).Scan(&tables)
+	if err == nil && tables != 2 {
+		err = errors.New("not a native export: the activities and daily tables are missing")
+	}
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open archive %s: %w", path, err)
+	}
+	return &Archive{db: db, path: path}, nil
+}
+
+// Close closes the archive
+func (a *Archive) Close() error {
+	return a.db.Close()
+}
+
+// UpdatedAt returns when the archive file was last written, or the zero time
+// if that can't be read
+func (a *Archive) UpdatedAt() time.Time {
+	info, err := os.Stat(a.path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// Location returns time.Local: the archive keeps no profile. Activity start
+// times keep the offset they were exported with.
+func (a *Archive) Location(ctx context.Context) (*time.Location, error) {
+	return time.Local, nil
+}
+
+// GetActivitiesByDate returns the archived activities started between start
+// and end (inclusive), oldest first
+func (a *Archive) GetActivitiesByDate(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.Activity, error) {
+	// start_time begins with the local date of the activity
+	activities, err := a.activities(ctx, `WHERE substr(start_time, 1, 10) BETWEEN ? AND ? ORDER BY start_time`,
+		start.Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived activities: %w", err)
+	}
+	return activities, nil
+}
+
+// GetActivities returns one page of the archived activities, newest first
+func (a *Archive) GetActivities(ctx context.Context, req api.PageRequest, opts ...api.RequestOption) ([]api.Activity, *api.Pagination, error) {
+	page := api.FirstPage(req.PageSize)
+	page.Page = max(req.Page, 1)
+	pagination := &api.Pagination{Page: page.Page, PageSize: page.PageSize}
+	if err := a.db.QueryRowContext(ctx, `SELECT count(*) FROM activities`).Scan(&pagination.TotalCount); err != nil {
+		return nil, nil, fmt.Errorf("failed to get archived activities: %w", err)
+	}
+	activities, err := a.activities(ctx, `ORDER BY start_time DESC LIMIT ? OFFSET ?`, page.PageSize, page.Offset())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get archived activities: %w", err)
+	}
+	return activities, pagination, nil
+}
+
+// GetActivityDetails returns the summary of an archived activity; the fields
+// the archive has no columns for are left zero
+func (a *Archive) GetActivityDetails(ctx context.Context, activityID int64, opts ...api.RequestOption) (*api.ActivityDetail, error) {
+	activities, err := a.activities(ctx, `WHERE activity_id = ?`, activityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived activity %d: %w", activityID, err)
+	}
+	if len(activities) == 0 {
+		return nil, fmt.Errorf("activity %d: %w", activityID, ErrNotArchived)
+	}
+	return &api.ActivityDetail{Activity: activities[0]}, nil
+}
+
+// activities runs a query on the activities table with the given clauses
+func (a *Archive) activities(ctx context.Context, clauses string, args ...interface{}) ([]api.Activity, error) {
+	rows, err := a.db.QueryContext(ctx, `SELECT activity_id, name, type, start_time, duration_seconds, distance_meters FROM activities `+clauses, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var activities []api.Activity
+	for rows.Next() {
+		var act api.Activity
+		var name, typ, start sql.NullString
+		var duration, distance sql.NullFloat64
+		if err := rows.Scan(&act.ActivityID, &name, &typ, &start, &duration, &distance); err != nil {
+			return nil, err
+		}
+		act.Name, act.Type = name.String, typ.String
+		act.Duration, act.Distance = duration.Float64, distance.Float64
+		if t, err := time.Parse(time.RFC3339, start.String); err == nil {
+			act.StartTime = api.NewGarminTime(t)
+		}
+		activities = append(activities, act)
+	}
+	return activities, rows.Err()
+}
+
+// dailyColumns are the columns of the daily table, in the order days scans them
+const dailyColumns = `date, steps, step_goal, resting_hr, sleep_seconds, deep_sleep_seconds,
+	light_sleep_seconds, rem_sleep_seconds, awake_seconds, sleep_score`
+
+// archivedDay is one row of the daily table
+type archivedDay struct {
+	date                           api.Date
+	steps, stepGoal, restingHR     sql.NullInt64
+	sleep, deep, light, rem, awake sql.NullInt64
+	score                          sql.NullInt64
+}
+
+// days returns the archived days from start to end (inclusive)
+func (a *Archive) days(ctx context.Context, start, end time.Time) ([]archivedDay, error) {
+	rows, err := a.db.QueryContext(ctx, `SELECT `+dailyColumns+` FROM daily WHERE date BETWEEN ? AND ? ORDER BY date`,
+		start.Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []archivedDay
+	for rows.Next() {
+		var d archivedDay
+		var date string
+		if err := rows.Scan(&date, &d.steps, &d.stepGoal, &d.restingHR, &d.sleep, &d.deep, &d.light, &d.rem, &d.awake, &d.score); err != nil {
+			return nil, err
+		}
+		day, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid archived date %q: %w", date, err)
+		}
+		d.date = api.NewDate(day)
+		days = append(days, d)
+	}
+	return days, rows.Err()
+}
+
+// day returns the archived row of date, or ErrNotArchived
+func (a *Archive) day(ctx context.Context, date time.Time) (archivedDay, error) {
+	days, err := a.days(ctx, date, date)
+	if err != nil {
+		return archivedDay{}, err
+	}
+	if len(days) == 0 {
+		return archivedDay{}, fmt.Errorf("%s: %w", date.Format("2006-01-02"), ErrNotArchived)
+	}
+	return days[0], nil
+}
+
+// intPtr converts a nullable column to an optional value
+func intPtr(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	return api.Ptr(int(v.Int64))
+}
+
+func (d archivedDay) toSteps() *api.DailySteps {
+	if !d.steps.Valid {
+		return nil
+	}
+	return &api.DailySteps{CalendarDate: d.date, TotalSteps: int(d.steps.Int64), Goal: int(d.stepGoal.Int64)}
+}
+
+func (d archivedDay) toSleep() *api.SleepData {
+	if !d.sleep.Valid && !d.score.Valid {
+		return nil
+	}
+	return &api.SleepData{
+		CalendarDate:      d.date,
+		SleepTimeSeconds:  intPtr(d.sleep),
+		DeepSleepSeconds:  intPtr(d.deep),
+		LightSleepSeconds: intPtr(d.light),
+		RemSleepSeconds:   intPtr(d.rem),
+		AwakeSeconds:      intPtr(d.awake),
+		SleepScore:        intPtr(d.score),
+	}
+}
+
+func (d archivedDay) toStats() *api.UserStats {
+	if !d.steps.Valid && !d.restingHR.Valid {
+		return nil
+	}
+	return &api.UserStats{Date: d.date, TotalSteps: intPtr(d.steps), RestingHR: intPtr(d.restingHR)}
+}
+
+// GetStepsDataRange returns the archived steps from start to end (inclusive)
+func (a *Archive) GetStepsDataRange(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.DailySteps, error) {
+	days, err := a.days(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived steps: %w", err)
+	}
+	var steps []api.DailySteps
+	for _, d := range days {
+		if s := d.toSteps(); s != nil {
+			steps = append(steps, *s)
+		}
+	}
+	return steps, nil
+}
+
+// GetSleepDataRange returns the archived sleep from start to end (inclusive)
+func (a *Archive) GetSleepDataRange(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.SleepData, error) {
+	days, err := a.days(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived sleep: %w", err)
+	}
+	var sleep []api.SleepData
+	for _, d := range days {
+		if s := d.toSleep(); s != nil {
+			sleep = append(sleep, *s)
+		}
+	}
+	return sleep, nil
+}
+
+// GetStepsData returns the archived steps of one day
+func (a *Archive) GetStepsData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.DailySteps, error) {
+	d, err := a.day(ctx, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived steps: %w", err)
+	}
+	if s := d.toSteps(); s != nil {
+		return s, nil
+	}
+	return nil, fmt.Errorf("steps of %s: %w", date.Format("2006-01-02"), ErrNotArchived)
+}
+
+// GetSleepData returns the archived sleep of one night
+func (a *Archive) GetSleepData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.SleepData, error) {
+	d, err := a.day(ctx, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived sleep: %w", err)
+	}
+	if s := d.toSleep(); s != nil {
+		return s, nil
+	}
+	return nil, fmt.Errorf("sleep of %s: %w", date.Format("2006-01-02"), ErrNotArchived)
+}
+
+// GetUserStats returns the archived steps and resting heart rate of one day
+func (a *Archive) GetUserStats(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.UserStats, error) {
+	d, err := a.day(ctx, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived stats: %w", err)
+	}
+	if s := d.toStats(); s != nil {
+		return s, nil
+	}
+	return nil, fmt.Errorf("stats of %s: %w", date.Format("2006-01-02"), ErrNotArchived)
+}
+
+// GetUserProfile returns ErrNotArchived; the archive keeps no profile
+func (a *Archive) GetUserProfile(ctx context.Context, opts ...api.RequestOption) (*api.UserProfile, error) {
+	return nil, fmt.Errorf("user profile: %w", ErrNotArchived)
+}
+
+// GetStressData returns ErrNotArchived; the archive keeps no stress data
+func (a *Archive) GetStressData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.DailyStress, error) {
+	return nil, fmt.Errorf("stress: %w", ErrNotArchived)
+}
+
+// GetHRVData returns ErrNotArchived; the archive keeps no HRV data
+func (a *Archive) GetHRVData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.HRVData, error) {
+	return nil, fmt.Errorf("HRV: %w", ErrNotArchived)
+}
+
+// GetBodyBatteryData returns ErrNotArchived; the archive keeps no Body Battery data
+func (a *Archive) GetBodyBatteryData(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.BodyBatteryData, error) {
+	return nil, fmt.Errorf("Body Battery: %w", ErrNotArchived)
+}
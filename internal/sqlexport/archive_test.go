@@ -0,0 +1,85 @@
+package sqlexport
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/archive"
+	"github.com/sstent/go-garminconnect/internal/server"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ archive.Reader = (*Archive)(nil)
+	_ server.Backend = (*Archive)(nil)
+)
+
+func TestArchive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "garmin.sqlite")
+	ctx := context.Background()
+	_, err := Write(ctx, path, SchemaNative, testData())
+	assert.NoError(t, err)
+
+	a, err := OpenArchive(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer a.Close()
+	assert.WithinDuration(t, time.Now(), a.UpdatedAt(), time.Minute)
+
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	activities, err := a.GetActivitiesByDate(ctx, day, day)
+	assert.NoError(t, err)
+	if assert.Len(t, activities, 1) {
+		assert.Equal(t, testData().Activities[0], activities[0])
+	}
+	activities, err = a.GetActivitiesByDate(ctx, day.AddDate(0, 0, 1), day.AddDate(0, 0, 7))
+	assert.NoError(t, err)
+	assert.Empty(t, activities)
+
+	page, pagination, err := a.GetActivities(ctx, api.PageRequest{Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.Len(t, page, 1)
+	assert.Equal(t, &api.Pagination{Page: 1, PageSize: 10, TotalCount: 1}, pagination)
+
+	detail, err := a.GetActivityDetails(ctx, 42)
+	assert.NoError(t, err)
+	assert.Equal(t, "Morning Run", detail.Name)
+	_, err = a.GetActivityDetails(ctx, 7)
+	assert.ErrorIs(t, err, ErrNotArchived)
+
+	steps, err := a.GetStepsDataRange(ctx, day, day.AddDate(0, 0, 6))
+	assert.NoError(t, err)
+	if assert.Len(t, steps, 1) {
+		assert.Equal(t, 9500, steps[0].TotalSteps)
+		assert.Equal(t, 8000, steps[0].Goal)
+	}
+	sleep, err := a.GetSleepData(ctx, day)
+	assert.NoError(t, err)
+	assert.Equal(t, api.Ptr(27000), sleep.SleepTimeSeconds)
+	assert.Equal(t, api.Ptr(82), sleep.SleepScore)
+	assert.Nil(t, sleep.RemSleepSeconds)
+	stats, err := a.GetUserStats(ctx, day)
+	assert.NoError(t, err)
+	assert.Equal(t, api.Ptr(52), stats.RestingHR)
+
+	_, err = a.GetUserStats(ctx, day.AddDate(0, 0, 1))
+	assert.ErrorIs(t, err, fs.ErrNotExist, "days never exported are not found")
+	_, err = a.GetHRVData(ctx, day)
+	assert.ErrorIs(t, err, ErrNotArchived)
+}
+
+func TestOpenArchive(t *testing.T) {
+	_, err := OpenArchive(filepath.Join(t.TempDir(), "missing.sqlite"))
+	assert.Error(t, err, "a missing archive is not created")
+
+	dir := filepath.Join(t.TempDir(), "DBs")
+	_, err = Write(context.Background(), dir, SchemaGarminDB, testData())
+	assert.NoError(t, err)
+	_, err = OpenArchive(filepath.Join(dir, GarminDBFile))
+	assert.ErrorContains(t, err, "not a native export")
+}
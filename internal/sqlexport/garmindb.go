@@ -0,0 +1,170 @@
+package sqlexport
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/csvlog"
+)
+
+// GarminDB stores times the way SQLAlchemy does, in local time without an
+// offset, and distances in kilometers
+const (
+	garminDBDateTime = "2006-01-02 15:04:05.000000"
+	garminDBTime     = "15:04:05.000000"
+)
+
+// garminDBTables are the GarminDB tables written, with all their columns so
+// queries written for GarminDB run; columns this client has no data for stay
+// NULL
+var garminDBTables = map[string][]string{
+	GarminDBFile: {
+		`CREATE TABLE IF NOT EXISTS daily_summary (
+			day DATE PRIMARY KEY,
+			hr_min INTEGER, hr_max INTEGER, rhr INTEGER, stress_avg INTEGER,
+			step_goal INTEGER, steps INTEGER,
+			moderate_activity_time TIME, vigorous_activity_time TIME, intensity_time_goal TIME,
+			floors_up FLOAT, floors_down FLOAT, floors_goal FLOAT,
+			distance FLOAT,
+			calories_goal INTEGER, calories_total INTEGER, calories_bmr INTEGER, calories_active INTEGER, calories_consumed INTEGER,
+			hydration_goal INTEGER, hydration_intake INTEGER, sweat_loss INTEGER,
+			spo2_avg FLOAT, spo2_min FLOAT,
+			rr_waking_avg FLOAT, rr_max FLOAT, rr_min FLOAT,
+			bb_charged INTEGER, bb_max INTEGER, bb_min INTEGER,
+			description VARCHAR
+		)`,
+		`CREATE TABLE IF NOT EXISTS resting_hr (
+			day DATE PRIMARY KEY,
+			resting_heart_rate FLOAT
+		)`,
+		`CREATE TABLE IF NOT EXISTS sleep (
+			day DATE PRIMARY KEY,
+			start DATETIME, "end" DATETIME,
+			total_sleep TIME, deep_sleep TIME, light_sleep TIME, rem_sleep TIME, awake TIME,
+			avg_spo2 FLOAT, avg_rr FLOAT, avg_stress FLOAT,
+			score INTEGER, qualifier VARCHAR
+		)`,
+	},
+	ActivitiesDBFile: {
+		`CREATE TABLE IF NOT EXISTS activities (
+			activity_id VARCHAR PRIMARY KEY,
+			name VARCHAR, description VARCHAR, type VARCHAR,
+			course_id INTEGER, laps INTEGER,
+			sport VARCHAR, sub_sport VARCHAR,
+			device_serial_number INTEGER,
+			self_eval_feel VARCHAR, self_eval_effort VARCHAR,
+			training_load FLOAT, training_effect FLOAT, anaerobic_training_effect FLOAT,
+			start_time DATETIME, stop_time DATETIME,
+			elapsed_time TIME, moving_time TIME,
+			distance FLOAT, cycles FLOAT,
+			avg_hr INTEGER, max_hr INTEGER, avg_rr FLOAT, max_rr FLOAT,
+			calories INTEGER,
+			avg_cadence INTEGER, max_cadence INTEGER,
+			avg_speed FLOAT, max_speed FLOAT,
+			ascent FLOAT, descent FLOAT,
+			max_temperature FLOAT, min_temperature FLOAT, avg_temperature FLOAT,
+			start_lat FLOAT, start_long FLOAT, stop_lat FLOAT, stop_long FLOAT
+		)`,
+	},
+}
+
+// garminDBDuration formats seconds as a TIME column; GarminDB stores
+// durations as a time of day, so they wrap after 24 hours
+func garminDBDuration(seconds float64) string {
+	return time.Time{}.Add(time.Duration(seconds * float64(time.Second))).Format(garminDBTime)
+}
+
+// garminDBSeconds formats optional seconds as a nullable TIME column
+func garminDBSeconds(seconds *int) interface{} {
+	if seconds == nil {
+		return nil
+	}
+	return garminDBDuration(float64(*seconds))
+}
+
+func writeGarminDBDays(ctx context.Context, tx *sql.Tx, days csvlog.Days) (Result, error) {
+	if err := exec(ctx, tx, garminDBTables[GarminDBFile]...); err != nil {
+		return Result{}, err
+	}
+
+	var result Result
+	order, rows := dailyRows(days)
+	for _, day := range order {
+		r := rows[day]
+		if _, err := tx.ExecContext(ctx, `INSERT INTO daily_summary (day) VALUES (?) ON CONFLICT DO NOTHING`, day); err != nil {
+			return Result{}, err
+		}
+		if s := r.steps; s != nil {
+			_, err := tx.ExecContext(ctx, `UPDATE daily_summary SET steps = ?, step_goal = ?, distance = ? WHERE day = ?`,
+				s.TotalSteps, s.Goal, s.DistanceMeters/1000, day)
+			if err != nil {
+				return Result{}, err
+			}
+		}
+		if s := r.stats; s != nil {
+			_, err := tx.ExecContext(ctx, `UPDATE daily_summary SET rhr = ?, calories_total = ? WHERE day = ?`,
+				nullInt(s.RestingHR), nullInt(s.TotalCalories), day)
+			if err != nil {
+				return Result{}, err
+			}
+			if s.RestingHR != nil {
+				_, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO resting_hr VALUES (?, ?)`, day, *s.RestingHR)
+				if err != nil {
+					return Result{}, err
+				}
+			}
+		}
+		if s := r.sleep; s != nil && api.Value(s.SleepTimeSeconds) > 0 {
+			var start, end interface{}
+			if !s.SleepStart.IsZero() {
+				start = s.SleepStart.Local().Format(garminDBDateTime)
+				end = s.SleepEnd.Local().Format(garminDBDateTime)
+			}
+			_, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO sleep
+				(day, start, "end", total_sleep, deep_sleep, light_sleep, rem_sleep, awake, score)
+				VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				day, start, end, garminDBSeconds(s.SleepTimeSeconds), garminDBSeconds(s.DeepSleepSeconds),
+				garminDBSeconds(s.LightSleepSeconds), garminDBSeconds(s.RemSleepSeconds), garminDBSeconds(s.AwakeSeconds),
+				nullInt(s.SleepScore))
+			if err != nil {
+				return Result{}, err
+			}
+		}
+		result.Days++
+	}
+	return result, nil
+}
+
+func writeGarminDBActivities(ctx context.Context, tx *sql.Tx, activities []api.Activity) (Result, error) {
+	if err := exec(ctx, tx, garminDBTables[ActivitiesDBFile]...); err != nil {
+		return Result{}, err
+	}
+
+	var result Result
+	for _, a := range activities {
+		var avgSpeed interface{}
+		if a.Duration > 0 {
+			avgSpeed = a.Distance / a.Duration * 3.6
+		}
+		start := a.StartTime.Time
+		stop := start.Add(time.Duration(a.Duration * float64(time.Second)))
+		_, err := tx.ExecContext(ctx, `INSERT INTO activities
+			(activity_id, name, type, sport, start_time, stop_time, elapsed_time, distance, avg_speed)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (activity_id) DO UPDATE SET name = excluded.name, type = excluded.type,
+				sport = excluded.sport, start_time = excluded.start_time, stop_time = excluded.stop_time,
+				elapsed_time = excluded.elapsed_time, distance = excluded.distance, avg_speed = excluded.avg_speed`,
+			strconv.FormatInt(a.ActivityID, 10), a.Name, a.Type, a.Type,
+			start.Format(garminDBDateTime), stop.Format(garminDBDateTime), garminDBDuration(a.Duration),
+			a.Distance/1000, avgSpeed)
+		if err != nil {
+			return Result{}, fmt.Errorf("failed to write activity %d: %w", a.ActivityID, err)
+		}
+		result.Activities++
+	}
+	return result, nil
+}
@@ -0,0 +1,216 @@
+// Package sqlexport writes activities and daily health data to SQLite, either
+// in its own schema or in the tables of the GarminDB project so its notebooks
+// and plots can read data synced by this client
+package sqlexport
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/csvlog"
+	_ "modernc.org/sqlite"
+)
+
+// Schema selects the table layout of an export
+type Schema string
+
+const (
+	// SchemaNative writes the activities and daily tables to one file
+	SchemaNative Schema = "native"
+	// SchemaGarminDB writes GarminDB's garmin.db and garmin_activities.db to a
+	// directory, e.g. GarminDB's default ~/HealthData/DBs
+	SchemaGarminDB Schema = "garmindb"
+)
+
+// GarminDB file names
+const (
+	GarminDBFile     = "garmin.db"
+	ActivitiesDBFile = "garmin_activities.db"
+)
+
+// Data is what an export writes
+type Data struct {
+	Activities []api.Activity
+	csvlog.Days
+}
+
+// Result counts the rows an export wrote; rows already present are updated
+type Result struct {
+	Activities int
+	Days       int
+}
+
+// Write stores data at dest, a file for SchemaNative and a directory for
+// SchemaGarminDB. Tables are created when missing and rows are upserted by
+// activity ID or day, so exports of overlapping periods can be repeated.
+func Write(ctx context.Context, dest string, schema Schema, data Data) (Result, error) {
+	switch schema {
+	case SchemaNative:
+		return writeDB(ctx, dest, func(tx *sql.Tx) (Result, error) {
+			return writeNative(ctx, tx, data)
+		})
+	case SchemaGarminDB:
+		days, err := writeDB(ctx, filepath.Join(dest, GarminDBFile), func(tx *sql.Tx) (Result, error) {
+			return writeGarminDBDays(ctx, tx, data.Days)
+		})
+		if err != nil {
+			return days, err
+		}
+		activities, err := writeDB(ctx, filepath.Join(dest, ActivitiesDBFile), func(tx *sql.Tx) (Result, error) {
+			return writeGarminDBActivities(ctx, tx, data.Activities)
+		})
+		return Result{Activities: activities.Activities, Days: days.Days}, err
+	}
+	return Result{}, fmt.Errorf("unknown schema %q", schema)
+}
+
+// writeDB opens the database at path and runs write in one transaction
+func writeDB(ctx context.Context, path string, write func(tx *sql.Tx) (Result, error)) (Result, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return Result{}, fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer db.Close()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	result, err := write(tx)
+	if err != nil {
+		tx.Rollback()
+		return Result{}, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return Result{}, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return result, nil
+}
+
+// exec runs the statements in order, stopping at the first error
+func exec(ctx context.Context, tx *sql.Tx, statements ...string) error {
+	for _, s := range statements {
+		if _, err := tx.ExecContext(ctx, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dailyRow is the data of one day
+type dailyRow struct {
+	steps *api.DailySteps
+	sleep *api.SleepData
+	stats *api.UserStats
+}
+
+// dailyRows merges the daily data into one row per day, returning the days in
+// the order they first appear
+func dailyRows(days csvlog.Days) ([]string, map[string]*dailyRow) {
+	rows := make(map[string]*dailyRow)
+	var order []string
+	row := func(d api.Date) *dailyRow {
+		key := d.String()
+		r, ok := rows[key]
+		if !ok {
+			r = &dailyRow{}
+			rows[key] = r
+			order = append(order, key)
+		}
+		return r
+	}
+	for i := range days.Steps {
+		row(days.Steps[i].CalendarDate).steps = &days.Steps[i]
+	}
+	for i := range days.Sleep {
+		row(days.Sleep[i].CalendarDate).sleep = &days.Sleep[i]
+	}
+	for i := range days.Stats {
+		row(days.Stats[i].Date).stats = &days.Stats[i]
+	}
+	return order, rows
+}
+
+// nullInt converts an optional value for a nullable column
+func nullInt(v *int) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func writeNative(ctx context.Context, tx *sql.Tx, data Data) (Result, error) {
+	err := exec(ctx, tx,
+		`CREATE TABLE IF NOT EXISTS activities (
+			activity_id INTEGER PRIMARY KEY,
+			name TEXT,
+			type TEXT,
+			start_time TEXT,
+			duration_seconds REAL,
+			distance_meters REAL
+		)`,
+		`CREATE TABLE IF NOT EXISTS daily (
+			date TEXT PRIMARY KEY,
+			steps INTEGER,
+			step_goal INTEGER,
+			resting_hr INTEGER,
+			sleep_seconds INTEGER,
+			deep_sleep_seconds INTEGER,
+			light_sleep_seconds INTEGER,
+			rem_sleep_seconds INTEGER,
+			awake_seconds INTEGER,
+			sleep_score INTEGER
+		)`,
+	)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var result Result
+	for _, a := range data.Activities {
+		_, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO activities VALUES (?, ?, ?, ?, ?, ?)`,
+			a.ActivityID, a.Name, a.Type, a.StartTime.Format(time.RFC3339), a.Duration, a.Distance)
+		if err != nil {
+			return Result{}, err
+		}
+		result.Activities++
+	}
+
+	order, rows := dailyRows(data.Days)
+	for _, day := range order {
+		r := rows[day]
+		if _, err := tx.ExecContext(ctx, `INSERT INTO daily (date) VALUES (?) ON CONFLICT DO NOTHING`, day); err != nil {
+			return Result{}, err
+		}
+		if s := r.steps; s != nil {
+			_, err := tx.ExecContext(ctx, `UPDATE daily SET steps = ?, step_goal = ? WHERE date = ?`, s.TotalSteps, s.Goal, day)
+			if err != nil {
+				return Result{}, err
+			}
+		}
+		if s := r.stats; s != nil && s.RestingHR != nil {
+			if _, err := tx.ExecContext(ctx, `UPDATE daily SET resting_hr = ? WHERE date = ?`, *s.RestingHR, day); err != nil {
+				return Result{}, err
+			}
+		}
+		if s := r.sleep; s != nil {
+			_, err := tx.ExecContext(ctx, `UPDATE daily SET sleep_seconds = ?, deep_sleep_seconds = ?, light_sleep_seconds = ?,
+				rem_sleep_seconds = ?, awake_seconds = ?, sleep_score = ? WHERE date = ?`,
+				nullInt(s.SleepTimeSeconds), nullInt(s.DeepSleepSeconds), nullInt(s.LightSleepSeconds),
+				nullInt(s.RemSleepSeconds), nullInt(s.AwakeSeconds), nullInt(s.SleepScore), day)
+			if err != nil {
+				return Result{}, err
+			}
+		}
+		result.Days++
+	}
+	return result, nil
+}
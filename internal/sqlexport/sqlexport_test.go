@@ -0,0 +1,106 @@
+package sqlexport
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/csvlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func testData() Data {
+	day := api.NewDate(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	return Data{
+		Activities: []api.Activity{{
+			ActivityID: 42,
+			Name:       "Morning Run",
+			Type:       "running",
+			StartTime:  api.NewGarminTime(time.Date(2024, 3, 1, 7, 0, 0, 0, time.UTC)),
+			Duration:   1800,
+			Distance:   6000,
+		}},
+		Days: csvlog.Days{
+			Steps: []api.DailySteps{{CalendarDate: day, TotalSteps: 9500, Goal: 8000, DistanceMeters: 7200}},
+			Sleep: []api.SleepData{{CalendarDate: day, SleepTimeSeconds: api.Ptr(27000), DeepSleepSeconds: api.Ptr(5400), SleepScore: api.Ptr(82)}},
+			Stats: []api.UserStats{{Date: day, RestingHR: api.Ptr(52)}},
+		},
+	}
+}
+
+func openDB(t *testing.T, path string) *sql.DB {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestWriteNative(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "garmin.sqlite")
+	ctx := context.Background()
+	result, err := Write(ctx, path, SchemaNative, testData())
+	assert.NoError(t, err)
+	assert.Equal(t, Result{Activities: 1, Days: 1}, result)
+
+	// Repeating the export updates the rows
+	_, err = Write(ctx, path, SchemaNative, testData())
+	assert.NoError(t, err)
+
+	db := openDB(t, path)
+	var name, start string
+	assert.NoError(t, db.QueryRow(`SELECT name, start_time FROM activities WHERE activity_id = 42`).Scan(&name, &start))
+	assert.Equal(t, "Morning Run", name)
+	assert.Equal(t, "2024-03-01T07:00:00Z", start)
+
+	var steps, rhr, sleep, score, count int
+	assert.NoError(t, db.QueryRow(`SELECT steps, resting_hr, sleep_seconds, sleep_score FROM daily WHERE date = '2024-03-01'`).Scan(&steps, &rhr, &sleep, &score))
+	assert.Equal(t, []int{9500, 52, 27000, 82}, []int{steps, rhr, sleep, score})
+	assert.NoError(t, db.QueryRow(`SELECT count(*) FROM daily`).Scan(&count))
+	assert.Equal(t, 1, count)
+}
+
+func TestWriteGarminDB(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "DBs")
+	result, err := Write(context.Background(), dir, SchemaGarminDB, testData())
+	assert.NoError(t, err)
+	assert.Equal(t, Result{Activities: 1, Days: 1}, result)
+
+	garmin := openDB(t, filepath.Join(dir, GarminDBFile))
+	var steps, rhr int
+	var distance float64
+	var hrMin sql.NullInt64
+	assert.NoError(t, garmin.QueryRow(`SELECT steps, rhr, distance, hr_min FROM daily_summary WHERE day = '2024-03-01'`).Scan(&steps, &rhr, &distance, &hrMin))
+	assert.Equal(t, 9500, steps)
+	assert.Equal(t, 52, rhr)
+	assert.Equal(t, 7.2, distance, "kilometers")
+	assert.False(t, hrMin.Valid)
+
+	var total, deep string
+	var score int
+	assert.NoError(t, garmin.QueryRow(`SELECT total_sleep, deep_sleep, score FROM sleep WHERE day = '2024-03-01'`).Scan(&total, &deep, &score))
+	assert.Equal(t, "07:30:00.000000", total)
+	assert.Equal(t, "01:30:00.000000", deep)
+	assert.Equal(t, 82, score)
+
+	activities := openDB(t, filepath.Join(dir, ActivitiesDBFile))
+	// The driver parses DATETIME columns when scanning, so read the stored text
+	var id, sport, start, stop, elapsed string
+	var km, speed float64
+	assert.NoError(t, activities.QueryRow(`SELECT activity_id, sport, start_time || '', stop_time || '', elapsed_time, distance, avg_speed FROM activities`).
+		Scan(&id, &sport, &start, &stop, &elapsed, &km, &speed))
+	assert.Equal(t, "42", id)
+	assert.Equal(t, "running", sport)
+	assert.Equal(t, "2024-03-01 07:00:00.000000", start)
+	assert.Equal(t, "2024-03-01 07:30:00.000000", stop)
+	assert.Equal(t, "00:30:00.000000", elapsed)
+	assert.Equal(t, 6.0, km)
+	assert.InDelta(t, 12.0, speed, 1e-9, "km/h")
+
+	_, err = Write(context.Background(), dir, Schema("garmin"), testData())
+	assert.ErrorContains(t, err, `unknown schema "garmin"`)
+}
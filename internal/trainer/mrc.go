@@ -0,0 +1,66 @@
+package trainer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// EncodeMRC writes the workout as an MRC course file with power in percent of FTP
+func EncodeMRC(w io.Writer, workout api.Workout, ftp float64) error {
+	return encodeCourse(w, workout, ftp, false)
+}
+
+// EncodeERG writes the workout as an ERG course file with power in watts,
+// which needs ftp even when the workout has no power targets
+func EncodeERG(w io.Writer, workout api.Workout, ftp float64) error {
+	if ftp <= 0 {
+		return ErrNeedsFTP
+	}
+	return encodeCourse(w, workout, ftp, true)
+}
+
+func encodeCourse(w io.Writer, workout api.Workout, ftp float64, watts bool) error {
+	intervals, err := Intervals(workout, ftp)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "[COURSE HEADER]")
+	fmt.Fprintln(bw, "VERSION = 2")
+	fmt.Fprintln(bw, "UNITS = ENGLISH")
+	fmt.Fprintf(bw, "DESCRIPTION = %s\n", workout.Description)
+	fmt.Fprintf(bw, "FILE NAME = %s\n", workout.Name)
+	if watts {
+		fmt.Fprintf(bw, "FTP = %.0f\n", ftp)
+		fmt.Fprintln(bw, "MINUTES WATTS")
+	} else {
+		fmt.Fprintln(bw, "MINUTES PERCENT")
+	}
+	fmt.Fprintln(bw, "[END COURSE HEADER]")
+	fmt.Fprintln(bw, "[COURSE DATA]")
+
+	// Each interval is a line from its start to its end power; steps meet at
+	// the same minute so the trainer jumps between them
+	value := func(fraction float64) float64 {
+		if watts {
+			return fraction * ftp
+		}
+		return fraction * 100
+	}
+	var minutes float64
+	for _, iv := range intervals {
+		fmt.Fprintf(bw, "%.2f\t%.0f\n", minutes, value(iv.PowerStart))
+		minutes += iv.Duration.Minutes()
+		fmt.Fprintf(bw, "%.2f\t%.0f\n", minutes, value(iv.PowerEnd))
+	}
+	fmt.Fprintln(bw, "[END COURSE DATA]")
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write course file: %w", err)
+	}
+	return nil
+}
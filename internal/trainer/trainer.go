@@ -0,0 +1,90 @@
+// Package trainer converts Garmin structured workouts to and from the workout
+// files of indoor training platforms: Zwift's ZWO and the MRC/ERG course files
+// read by TrainerRoad and most trainer apps
+package trainer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+// ErrNeedsFTP is returned when a workout has power targets in watts but no FTP
+// was given to express them relative to
+var ErrNeedsFTP = errors.New("workout has power targets; an FTP is required")
+
+// Intensities used for steps without a power target, as a fraction of FTP
+const (
+	warmupStart   = 0.50
+	warmupEnd     = 0.75
+	cooldownStart = 0.75
+	cooldownEnd   = 0.50
+	recoveryPower = 0.55
+	intervalPower = 0.75
+)
+
+// Interval is a block of constant or ramping power, relative to FTP
+type Interval struct {
+	Type       string // the api.Step* type of the source step
+	Duration   time.Duration
+	PowerStart float64 // fraction of FTP at the start
+	PowerEnd   float64 // fraction of FTP at the end; equal to PowerStart unless ramping
+}
+
+// Ramp reports whether the power changes over the interval
+func (i Interval) Ramp() bool {
+	return i.PowerStart != i.PowerEnd
+}
+
+// Intervals converts a workout to a flat list of intervals with power relative
+// to ftp in watts. Power targets become the middle of their range; warm-ups
+// and cool-downs with a range ramp across it. Steps with heart rate, pace or no
+// target get a default intensity for their type. Only time-based steps can be
+// converted, since trainer files have no notion of distance or lap presses.
+func Intervals(w api.Workout, ftp float64) ([]Interval, error) {
+	var intervals []Interval
+	for _, step := range w.FlatSteps() {
+		if step.EndCondition != "time" || step.EndConditionValue == nil {
+			return nil, fmt.Errorf("step %d: only time-based steps can be converted, got %q", step.Order, step.EndCondition)
+		}
+		iv := Interval{
+			Type:     step.Type,
+			Duration: time.Duration(*step.EndConditionValue * float64(time.Second)),
+		}
+
+		if step.TargetType == "power.zone" && step.TargetLow != nil && step.TargetHigh != nil {
+			if ftp <= 0 {
+				return nil, ErrNeedsFTP
+			}
+			low, high := *step.TargetLow/ftp, *step.TargetHigh/ftp
+			switch step.Type {
+			case api.StepWarmup:
+				iv.PowerStart, iv.PowerEnd = low, high
+			case api.StepCooldown:
+				iv.PowerStart, iv.PowerEnd = high, low
+			default:
+				iv.PowerStart = (low + high) / 2
+				iv.PowerEnd = iv.PowerStart
+			}
+		} else {
+			iv.PowerStart, iv.PowerEnd = defaultPower(step.Type)
+		}
+		intervals = append(intervals, iv)
+	}
+	return intervals, nil
+}
+
+func defaultPower(stepType string) (start, end float64) {
+	switch stepType {
+	case api.StepWarmup:
+		return warmupStart, warmupEnd
+	case api.StepCooldown:
+		return cooldownStart, cooldownEnd
+	case api.StepRecovery, api.StepRest:
+		return recoveryPower, recoveryPower
+	default:
+		return intervalPower, intervalPower
+	}
+}
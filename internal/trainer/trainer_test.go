@@ -0,0 +1,138 @@
+package trainer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func timed(stepType string, seconds float64) api.WorkoutStep {
+	return api.WorkoutStep{Type: stepType, EndCondition: "time", EndConditionValue: api.Ptr(seconds)}
+}
+
+func powered(step api.WorkoutStep, low, high float64) api.WorkoutStep {
+	step.TargetType = "power.zone"
+	step.TargetLow, step.TargetHigh = api.Ptr(low), api.Ptr(high)
+	return step
+}
+
+func sweetSpot() api.Workout {
+	return api.Workout{
+		Name:      "Sweet spot",
+		SportType: "cycling",
+		Steps: []api.WorkoutStep{
+			timed(api.StepWarmup, 600),
+			{Type: api.StepRepeat, Iterations: 2, Steps: []api.WorkoutStep{
+				powered(timed(api.StepInterval, 480), 220, 240),
+				timed(api.StepRecovery, 120),
+			}},
+			powered(timed(api.StepCooldown, 300), 100, 150),
+		},
+	}
+}
+
+func TestIntervals(t *testing.T) {
+	intervals, err := Intervals(sweetSpot(), 250)
+	assert.NoError(t, err)
+	assert.Len(t, intervals, 6)
+	assert.Equal(t, Interval{Type: api.StepWarmup, Duration: 10 * time.Minute, PowerStart: 0.5, PowerEnd: 0.75}, intervals[0])
+	assert.InDelta(t, 0.92, intervals[1].PowerStart, 1e-9)
+	assert.False(t, intervals[1].Ramp())
+	assert.Equal(t, 0.55, intervals[2].PowerStart)
+	assert.InDelta(t, 0.6, intervals[5].PowerStart, 1e-9)
+	assert.InDelta(t, 0.4, intervals[5].PowerEnd, 1e-9)
+
+	_, err = Intervals(sweetSpot(), 0)
+	assert.ErrorIs(t, err, ErrNeedsFTP)
+
+	distance := api.Workout{Steps: []api.WorkoutStep{{Order: 3, Type: api.StepInterval, EndCondition: "distance", EndConditionValue: api.Ptr(1000.0)}}}
+	_, err = Intervals(distance, 250)
+	assert.ErrorContains(t, err, "step 3")
+}
+
+func TestEncodeZWO(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, EncodeZWO(&buf, sweetSpot(), 250))
+	out := buf.String()
+	assert.Contains(t, out, "<workout_file>")
+	assert.Contains(t, out, "<sportType>bike</sportType>")
+	assert.Contains(t, out, `<Warmup Duration="600" PowerLow="0.5" PowerHigh="0.75"></Warmup>`)
+	assert.Contains(t, out, `<SteadyState Duration="480" Power="0.92"></SteadyState>`)
+	assert.Contains(t, out, `<SteadyState Duration="120" Power="0.55"></SteadyState>`)
+	assert.Contains(t, out, `<Cooldown Duration="300" PowerLow="0.6" PowerHigh="0.4"></Cooldown>`)
+
+	run := api.Workout{Name: "Easy", SportType: "running", Steps: []api.WorkoutStep{timed(api.StepInterval, 1800)}}
+	buf.Reset()
+	assert.NoError(t, EncodeZWO(&buf, run, 0))
+	assert.Contains(t, buf.String(), "<sportType>run</sportType>")
+}
+
+func TestEncodeCourse(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, EncodeMRC(&buf, sweetSpot(), 250))
+	out := buf.String()
+	assert.Contains(t, out, "MINUTES PERCENT\n")
+	assert.Contains(t, out, "[COURSE DATA]\n0.00\t50\n10.00\t75\n10.00\t92\n18.00\t92\n18.00\t55\n20.00\t55\n")
+	assert.Contains(t, out, "35.00\t40\n[END COURSE DATA]\n")
+
+	buf.Reset()
+	assert.NoError(t, EncodeERG(&buf, sweetSpot(), 250))
+	assert.Contains(t, buf.String(), "FTP = 250\nMINUTES WATTS\n")
+	assert.Contains(t, buf.String(), "10.00\t230\n")
+
+	assert.ErrorIs(t, EncodeERG(&buf, api.Workout{}, 0), ErrNeedsFTP)
+}
+
+func TestDecodeZWO(t *testing.T) {
+	zwo := `<workout_file>
+  <author>Coach</author>
+  <name>Over-unders</name>
+  <description>Threshold work</description>
+  <sportType>bike</sportType>
+  <workout>
+    <Warmup Duration="600" PowerLow="0.45" PowerHigh="0.75"/>
+    <IntervalsT Repeat="3" OnDuration="120" OffDuration="60" OnPower="1.05" OffPower="0.9"/>
+    <textevent timeoffset="10" message="Go!"/>
+    <FreeRide Duration="300"/>
+    <Cooldown Duration="300" PowerLow="0.7" PowerHigh="0.4"/>
+  </workout>
+</workout_file>`
+
+	workout, err := DecodeZWO(strings.NewReader(zwo), 200)
+	assert.NoError(t, err)
+	assert.Equal(t, "Over-unders", workout.Name)
+	assert.Equal(t, "cycling", workout.SportType)
+	assert.Len(t, workout.Steps, 4)
+
+	warmup := workout.Steps[0]
+	assert.Equal(t, api.StepWarmup, warmup.Type)
+	assert.Equal(t, 90.0, *warmup.TargetLow)
+	assert.Equal(t, 150.0, *warmup.TargetHigh)
+
+	repeat := workout.Steps[1]
+	assert.Equal(t, api.StepRepeat, repeat.Type)
+	assert.Equal(t, 3, repeat.Iterations)
+	assert.Equal(t, 204.0, *repeat.Steps[0].TargetLow)
+	assert.Equal(t, 216.0, *repeat.Steps[0].TargetHigh)
+	assert.Equal(t, api.StepRecovery, repeat.Steps[1].Type)
+	assert.Equal(t, []int{1, 2, 3, 4}, []int{warmup.Order, repeat.Order, repeat.Steps[0].Order, repeat.Steps[1].Order})
+
+	assert.Equal(t, "no.target", workout.Steps[2].TargetType)
+	assert.Equal(t, 80.0, *workout.Steps[3].TargetLow)
+
+	// Converting back keeps the shape of the workout
+	intervals, err := Intervals(*workout, 200)
+	assert.NoError(t, err)
+	assert.Len(t, intervals, 9)
+	assert.InDelta(t, 1.05, intervals[1].PowerStart, 1e-9)
+	assert.Equal(t, 0.7, intervals[8].PowerStart)
+
+	_, err = DecodeZWO(strings.NewReader(zwo), 0)
+	assert.ErrorIs(t, err, ErrNeedsFTP)
+	_, err = DecodeZWO(strings.NewReader("<workout_file><workout/></workout_file>"), 200)
+	assert.Error(t, err)
+}
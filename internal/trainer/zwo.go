@@ -0,0 +1,179 @@
+package trainer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+)
+
+type zwoFile struct {
+	XMLName     xml.Name     `xml:"workout_file"`
+	Author      string       `xml:"author"`
+	Name        string       `xml:"name"`
+	Description string       `xml:"description"`
+	SportType   string       `xml:"sportType"`
+	Workout     []zwoSegment `xml:"workout>SteadyState"` // each segment names itself via XMLName
+}
+
+// zwoSegment is one element of the workout; its name selects the segment kind
+type zwoSegment struct {
+	XMLName   xml.Name
+	Duration  int     `xml:"Duration,attr"`
+	Power     float64 `xml:"Power,attr,omitempty"`
+	PowerLow  float64 `xml:"PowerLow,attr,omitempty"`
+	PowerHigh float64 `xml:"PowerHigh,attr,omitempty"`
+}
+
+// EncodeZWO writes the workout as a Zwift .zwo file. Running workouts are
+// written with sportType "run"; everything else as a bike workout.
+func EncodeZWO(w io.Writer, workout api.Workout, ftp float64) error {
+	intervals, err := Intervals(workout, ftp)
+	if err != nil {
+		return err
+	}
+
+	file := zwoFile{
+		Author:      "Garmin Connect",
+		Name:        workout.Name,
+		Description: workout.Description,
+		SportType:   "bike",
+	}
+	if workout.SportType == "running" {
+		file.SportType = "run"
+	}
+	for _, iv := range intervals {
+		seg := zwoSegment{Duration: int(iv.Duration.Seconds())}
+		switch {
+		case iv.Type == api.StepWarmup && iv.Ramp():
+			seg.XMLName.Local = "Warmup"
+		case iv.Type == api.StepCooldown && iv.Ramp():
+			seg.XMLName.Local = "Cooldown"
+		case iv.Ramp():
+			seg.XMLName.Local = "Ramp"
+		default:
+			seg.XMLName.Local = "SteadyState"
+			seg.Power = round(iv.PowerStart)
+		}
+		if iv.Ramp() {
+			seg.PowerLow, seg.PowerHigh = round(iv.PowerStart), round(iv.PowerEnd)
+		}
+		file.Workout = append(file.Workout, seg)
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(file); err != nil {
+		return fmt.Errorf("failed to write ZWO: %w", err)
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// round keeps FTP fractions to three decimals, as Zwift writes them
+func round(v float64) float64 {
+	return float64(int(v*1000+0.5)) / 1000
+}
+
+// zwoInput is the subset of a .zwo file read by DecodeZWO
+type zwoInput struct {
+	Name        string `xml:"name"`
+	Description string `xml:"description"`
+	SportType   string `xml:"sportType"`
+	Workout     struct {
+		Segments []zwoElement `xml:",any"`
+	} `xml:"workout"`
+}
+
+// zwoElement holds the attributes of every segment kind
+type zwoElement struct {
+	XMLName     xml.Name
+	Duration    float64 `xml:"Duration,attr"`
+	Power       float64 `xml:"Power,attr"`
+	PowerLow    float64 `xml:"PowerLow,attr"`
+	PowerHigh   float64 `xml:"PowerHigh,attr"`
+	Repeat      int     `xml:"Repeat,attr"`
+	OnDuration  float64 `xml:"OnDuration,attr"`
+	OffDuration float64 `xml:"OffDuration,attr"`
+	OnPower     float64 `xml:"OnPower,attr"`
+	OffPower    float64 `xml:"OffPower,attr"`
+}
+
+// targetSpread is the width of the power window around a steady ZWO power,
+// as a fraction of the power on either side
+const targetSpread = 0.03
+
+// DecodeZWO reads a Zwift .zwo file into a structured workout with power
+// targets in watts for ftp. IntervalsT blocks become repeat groups and
+// FreeRide segments steps without a target.
+func DecodeZWO(r io.Reader, ftp float64) (*api.Workout, error) {
+	var file zwoInput
+	if err := xml.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse ZWO: %w", err)
+	}
+	if ftp <= 0 {
+		return nil, ErrNeedsFTP
+	}
+
+	workout := &api.Workout{
+		Name:        file.Name,
+		Description: file.Description,
+		SportType:   "cycling",
+	}
+	if file.SportType == "run" {
+		workout.SportType = "running"
+	}
+
+	order := 0
+	step := func(stepType string, seconds, low, high float64) api.WorkoutStep {
+		order++
+		s := api.WorkoutStep{
+			Order:             order,
+			Type:              stepType,
+			EndCondition:      "time",
+			EndConditionValue: api.Ptr(seconds),
+			TargetType:        "no.target",
+		}
+		if high > 0 {
+			s.TargetType = "power.zone"
+			s.TargetLow = api.Ptr(math.Round(min(low, high) * ftp))
+			s.TargetHigh = api.Ptr(math.Round(max(low, high) * ftp))
+		}
+		return s
+	}
+	steady := func(stepType string, seconds, power float64) api.WorkoutStep {
+		return step(stepType, seconds, power*(1-targetSpread), power*(1+targetSpread))
+	}
+
+	for _, seg := range file.Workout.Segments {
+		switch seg.XMLName.Local {
+		case "Warmup":
+			workout.Steps = append(workout.Steps, step(api.StepWarmup, seg.Duration, seg.PowerLow, seg.PowerHigh))
+		case "Cooldown":
+			workout.Steps = append(workout.Steps, step(api.StepCooldown, seg.Duration, seg.PowerLow, seg.PowerHigh))
+		case "Ramp":
+			workout.Steps = append(workout.Steps, step(api.StepInterval, seg.Duration, seg.PowerLow, seg.PowerHigh))
+		case "SteadyState":
+			workout.Steps = append(workout.Steps, steady(api.StepInterval, seg.Duration, seg.Power))
+		case "FreeRide", "MaxEffort":
+			workout.Steps = append(workout.Steps, step(api.StepInterval, seg.Duration, 0, 0))
+		case "IntervalsT":
+			order++
+			repeat := api.WorkoutStep{Order: order, Type: api.StepRepeat, Iterations: max(seg.Repeat, 1)}
+			repeat.Steps = []api.WorkoutStep{
+				steady(api.StepInterval, seg.OnDuration, seg.OnPower),
+				steady(api.StepRecovery, seg.OffDuration, seg.OffPower),
+			}
+			workout.Steps = append(workout.Steps, repeat)
+		default:
+			// Text events and other annotations carry no effort
+			continue
+		}
+	}
+	if len(workout.Steps) == 0 {
+		return nil, fmt.Errorf("ZWO file %q has no workout segments", file.Name)
+	}
+	return workout, nil
+}
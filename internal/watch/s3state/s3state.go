@@ -0,0 +1,252 @@
+// Package s3state stores the state of a watcher as a JSON object in
+// S3-compatible object storage, e.g. AWS S3, MinIO or Cloudflare R2, for
+// watchers on hosts without a persistent volume
+package s3state
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/watch"
+)
+
+// Config locates the object and the credentials to sign requests with
+type Config struct {
+	Bucket string
+	Key    string
+	// Endpoint is the base URL of an S3-compatible service; empty means AWS.
+	// Objects of custom endpoints are addressed by path, e.g.
+	// http://minio:9000/<bucket>/<key>.
+	Endpoint        string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// HTTPClient defaults to a client with a 30 second timeout
+	HTTPClient *http.Client
+}
+
+// State is a watch.State stored as one object
+type State struct {
+	cfg    Config
+	url    *url.URL
+	client *http.Client
+	// now is replaced in tests
+	now func() time.Time
+}
+
+var _ watch.State = (*State)(nil)
+
+// ConfigFromEnv returns the config of an s3://bucket/key URL with the
+// endpoint, region and credentials of the standard AWS environment variables
+// (AWS_ENDPOINT_URL_S3 or AWS_ENDPOINT_URL, AWS_REGION or AWS_DEFAULT_REGION,
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN)
+func ConfigFromEnv(rawURL string) (Config, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "s3" || u.Host == "" || strings.Trim(u.Path, "/") == "" {
+		return Config{}, fmt.Errorf("invalid S3 URL %q, expected s3://bucket/key", rawURL)
+	}
+	cfg := Config{
+		Bucket:          u.Host,
+		Key:             strings.TrimPrefix(u.Path, "/"),
+		Endpoint:        firstEnv("AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"),
+		Region:          firstEnv("AWS_REGION", "AWS_DEFAULT_REGION"),
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return Config{}, errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required for S3 state")
+	}
+	return cfg, nil
+}
+
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// New returns the state stored at the object of cfg
+func New(cfg Config) (*State, error) {
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	var u *url.URL
+	if cfg.Endpoint == "" {
+		u = &url.URL{Scheme: "https", Host: cfg.Bucket + ".s3." + cfg.Region + ".amazonaws.com", Path: "/" + cfg.Key}
+	} else {
+		base, err := url.Parse(cfg.Endpoint)
+		if err != nil || base.Host == "" {
+			return nil, fmt.Errorf("invalid S3 endpoint %q", cfg.Endpoint)
+		}
+		u = &url.URL{Scheme: base.Scheme, Host: base.Host, Path: strings.TrimSuffix(base.Path, "/") + "/" + cfg.Bucket + "/" + cfg.Key}
+	}
+	// SigV4 signs the path with every reserved character escaped
+	u.RawPath = escapePath(u.Path)
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &State{cfg: cfg, url: u, client: client, now: time.Now}, nil
+}
+
+// Load reads the saved state; a missing object is not an error
+func (s *State) Load(ctx context.Context) (watch.Seen, bool, error) {
+	resp, err := s.do(ctx, http.MethodGet, nil)
+	if err != nil {
+		return watch.Seen{}, false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return watch.Seen{}, false, nil
+	}
+	if err := checkStatus(resp); err != nil {
+		return watch.Seen{}, false, fmt.Errorf("failed to load watch state from S3: %w", err)
+	}
+	var seen watch.Seen
+	if err := json.NewDecoder(resp.Body).Decode(&seen); err != nil {
+		return watch.Seen{}, false, fmt.Errorf("failed to decode watch state: %w", err)
+	}
+	return seen, true, nil
+}
+
+// Save replaces the object; S3 writes objects atomically
+func (s *State) Save(ctx context.Context, seen watch.Seen) error {
+	body, err := json.Marshal(seen)
+	if err != nil {
+		return fmt.Errorf("failed to encode watch state: %w", err)
+	}
+	resp, err := s.do(ctx, http.MethodPut, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := checkStatus(resp); err != nil {
+		return fmt.Errorf("failed to save watch state to S3: %w", err)
+	}
+	return nil
+}
+
+// do sends a signed request for the object
+func (s *State) do(ctx context.Context, method string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.url.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if s.cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.cfg.SessionToken)
+	}
+	sign(req, body, s.cfg.Region, s.cfg.AccessKeyID, s.cfg.SecretAccessKey, s.now())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach S3: %w", err)
+	}
+	return resp, nil
+}
+
+// checkStatus returns the error of a failed response, with the S3 error
+// message when there is one
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode < 300 {
+		return nil
+	}
+	var s3Err struct {
+		Code    string
+		Message string
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if xmlErr := xml.Unmarshal(data, &s3Err); xmlErr == nil && s3Err.Code != "" {
+		return fmt.Errorf("S3 error %d: %s: %s", resp.StatusCode, s3Err.Code, s3Err.Message)
+	}
+	return fmt.Errorf("S3 error %d", resp.StatusCode)
+}
+
+// sign adds an AWS Signature Version 4 Authorization header to req, signing
+// the host and every header set on req
+func sign(req *http.Request, body []byte, region, accessKeyID, secret string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+secret), date)
+	for _, part := range []string{region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+// escapePath escapes everything in path but unreserved characters and slashes
+func escapePath(path string) string {
+	var b strings.Builder
+	for _, c := range []byte(path) {
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-._~/", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,117 @@
+// Package sqlitestate stores the state of a watcher in SQLite, e.g. a file on
+// a volume shared by the containers of a deployment
+package sqlitestate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/watch"
+	_ "modernc.org/sqlite"
+)
+
+// State is a watch.State stored in a SQLite database
+type State struct {
+	db *sql.DB
+}
+
+var _ watch.State = (*State)(nil)
+
+// Open opens the state database at path, creating it if needed
+func Open(path string) (*State, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create watch state directory: %w", err)
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open watch state: %w", err)
+	}
+	for _, s := range []string{
+		`CREATE TABLE IF NOT EXISTS watch_saved (id INTEGER PRIMARY KEY CHECK (id = 1), saved_at TEXT NOT NULL)`,
+		`CREATE TABLE IF NOT EXISTS watch_activities (activity_id INTEGER PRIMARY KEY)`,
+		`CREATE TABLE IF NOT EXISTS watch_metrics (key TEXT PRIMARY KEY, value REAL NOT NULL)`,
+	} {
+		if _, err := db.Exec(s); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to create watch state: %w", err)
+		}
+	}
+	return &State{db: db}, nil
+}
+
+// Close closes the database
+func (s *State) Close() error {
+	return s.db.Close()
+}
+
+// Load reads the saved state
+func (s *State) Load(ctx context.Context) (watch.Seen, bool, error) {
+	var saved int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM watch_saved`).Scan(&saved); err != nil {
+		return watch.Seen{}, false, err
+	}
+	if saved == 0 {
+		return watch.Seen{}, false, nil
+	}
+
+	seen := watch.Seen{Metrics: make(map[string]float64)}
+	rows, err := s.db.QueryContext(ctx, `SELECT activity_id FROM watch_activities ORDER BY activity_id`)
+	if err != nil {
+		return watch.Seen{}, false, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return watch.Seen{}, false, err
+		}
+		seen.Activities = append(seen.Activities, id)
+	}
+	if err := rows.Err(); err != nil {
+		return watch.Seen{}, false, err
+	}
+
+	rows, err = s.db.QueryContext(ctx, `SELECT key, value FROM watch_metrics`)
+	if err != nil {
+		return watch.Seen{}, false, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var key string
+		var v float64
+		if err := rows.Scan(&key, &v); err != nil {
+			return watch.Seen{}, false, err
+		}
+		seen.Metrics[key] = v
+	}
+	return seen, true, rows.Err()
+}
+
+// Save adds seen to the stored state in one transaction. A watcher only ever
+// adds to what it has seen, so rows are upserted rather than replaced.
+func (s *State) Save(ctx context.Context, seen watch.Seen) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, id := range seen.Activities {
+		if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO watch_activities VALUES (?)`, id); err != nil {
+			return err
+		}
+	}
+	for key, v := range seen.Metrics {
+		if _, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO watch_metrics VALUES (?, ?)`, key, v); err != nil {
+			return err
+		}
+	}
+	_, err = tx.ExecContext(ctx, `INSERT OR REPLACE INTO watch_saved VALUES (1, ?)`, time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
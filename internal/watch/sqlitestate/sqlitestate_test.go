@@ -0,0 +1,42 @@
+package sqlitestate
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/sstent/go-garminconnect/internal/watch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "watch.db")
+	ctx := context.Background()
+	state, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, ok, err := state.Load(ctx)
+	assert.NoError(t, err)
+	assert.False(t, ok, "nothing saved yet")
+
+	assert.NoError(t, state.Save(ctx, watch.Seen{}))
+	seen, ok, err := state.Load(ctx)
+	assert.NoError(t, err)
+	assert.True(t, ok, "an empty state was saved")
+	assert.Empty(t, seen.Activities)
+
+	assert.NoError(t, state.Save(ctx, watch.Seen{Activities: []int64{2, 1}, Metrics: map[string]float64{"2024-03-01/steps": 3000}}))
+	assert.NoError(t, state.Save(ctx, watch.Seen{Activities: []int64{1, 2}, Metrics: map[string]float64{"2024-03-01/steps": 9000}}))
+	assert.NoError(t, state.Close())
+
+	state, err = Open(path)
+	if assert.NoError(t, err) {
+		defer state.Close()
+		seen, ok, err = state.Load(ctx)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, watch.Seen{Activities: []int64{1, 2}, Metrics: map[string]float64{"2024-03-01/steps": 9000}}, seen)
+	}
+}
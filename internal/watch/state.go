@@ -0,0 +1,81 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/sstent/go-garminconnect/internal/jsonfile"
+)
+
+// Seen is what a watcher has already reported
+type Seen struct {
+	Activities []int64 `json:"activities"`
+	// Metrics maps "<date>/<metric>" to the last value reported
+	Metrics map[string]float64 `json:"metrics"`
+}
+
+// State stores what a watcher has seen, so a restarted watcher, e.g. in an
+// ephemeral container, reports only what changed while it was down
+type State interface {
+	// Load returns the saved state; ok is false when nothing was saved yet
+	Load(ctx context.Context) (seen Seen, ok bool, err error)
+	Save(ctx context.Context, seen Seen) error
+}
+
+// FileState keeps the state in a JSON file
+type FileState struct {
+	Path string
+}
+
+// Load reads the state file; a missing file is not an error
+func (f FileState) Load(ctx context.Context) (Seen, bool, error) {
+	var seen *Seen
+	if err := jsonfile.Load(f.Path, &seen); err != nil {
+		return Seen{}, false, err
+	}
+	if seen == nil {
+		return Seen{}, false, nil
+	}
+	return *seen, true, nil
+}
+
+// Save replaces the state file atomically
+func (f FileState) Save(ctx context.Context, seen Seen) error {
+	return jsonfile.Save(f.Path, seen)
+}
+
+// Restore loads the saved state into the watcher, once; later calls do
+// nothing. It reports whether a saved state was found, in which case the
+// first poll reports only what changed since it was saved. Poll restores the
+// state itself; call Restore first to learn whether there was one.
+func (w *Watcher) Restore(ctx context.Context) (bool, error) {
+	if w.State == nil || w.restored {
+		return w.stored, nil
+	}
+	seen, ok, err := w.State.Load(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to load watch state: %w", err)
+	}
+	for _, id := range seen.Activities {
+		w.activities[id] = true
+	}
+	for key, v := range seen.Metrics {
+		w.metrics[key] = v
+	}
+	w.restored, w.stored = true, ok
+	return ok, nil
+}
+
+// seen returns what the watcher has seen, activities in ID order
+func (w *Watcher) seen() Seen {
+	seen := Seen{Activities: make([]int64, 0, len(w.activities)), Metrics: make(map[string]float64, len(w.metrics))}
+	for id := range w.activities {
+		seen.Activities = append(seen.Activities, id)
+	}
+	sort.Slice(seen.Activities, func(i, j int) bool { return seen.Activities[i] < seen.Activities[j] })
+	for key, v := range w.metrics {
+		seen.Metrics[key] = v
+	}
+	return seen
+}
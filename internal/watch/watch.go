@@ -0,0 +1,136 @@
+// Package watch polls Garmin Connect and turns new data into a stream of
+// events, one per new activity or changed daily metric
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/sstent/go-garminconnect/internal/csvlog"
+)
+
+// Event types
+const (
+	EventActivity    = "activity"
+	EventDailyMetric = "daily_metric"
+)
+
+// Event is one change seen by a poll
+type Event struct {
+	Type     string        `json:"type"` // one of the Event* types
+	Account  string        `json:"account,omitempty"`
+	Detected time.Time     `json:"detected"` // when the poll saw the change
+	Activity *api.Activity `json:"activity,omitempty"`
+	// Date, Metric and Value describe a daily metric; Previous is the value
+	// an earlier poll saw, e.g. steps before the day was over
+	Date     string   `json:"date,omitempty"`
+	Metric   string   `json:"metric,omitempty"`
+	Value    *float64 `json:"value,omitempty"`
+	Previous *float64 `json:"previous,omitempty"`
+}
+
+// Source defines the client methods the watcher polls
+type Source interface {
+	csvlog.Source
+	GetActivitiesByDate(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.Activity, error)
+}
+
+// Watcher remembers what earlier polls saw so each poll reports only changes.
+// It is not safe for concurrent use.
+type Watcher struct {
+	Source Source
+	// Account, if set, labels the events so streams of several accounts can
+	// be told apart
+	Account string
+	// Now returns the detection time of events; it defaults to time.Now
+	Now func() time.Time
+	// State, if set, keeps what the watcher has seen across restarts. It is
+	// restored by the first poll and saved after every poll that saw changes.
+	State State
+
+	activities map[int64]bool
+	metrics    map[string]float64
+	restored   bool // State was loaded
+	stored     bool // State holds a saved copy
+	unsaved    bool // changes not yet saved to State
+}
+
+// NewWatcher creates a watcher that has seen nothing yet
+func NewWatcher(src Source) *Watcher {
+	return &Watcher{Source: src, activities: make(map[int64]bool), metrics: make(map[string]float64)}
+}
+
+// Poll fetches the activities and daily metrics from start to end and returns
+// the events for those not seen before: activities first in start order, then
+// metrics by date. Days whose metrics fail to load are retried by the next
+// poll; the failure is returned alongside the events, with api.IsPartial true.
+func (w *Watcher) Poll(ctx context.Context, start, end time.Time) ([]Event, error) {
+	now := time.Now
+	if w.Now != nil {
+		now = w.Now
+	}
+	if _, err := w.Restore(ctx); err != nil {
+		return nil, err
+	}
+
+	activities, err := w.Source.GetActivitiesByDate(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list activities: %w", err)
+	}
+	values, fetchErr := csvlog.Fetch(ctx, w.Source, start, end)
+	if fetchErr != nil && !api.IsPartial(fetchErr) {
+		return nil, fetchErr
+	}
+
+	detected := now()
+	var events []Event
+	sort.SliceStable(activities, func(i, j int) bool { return activities[i].StartTime.Before(activities[j].StartTime.Time) })
+	for _, a := range activities {
+		if w.activities[a.ActivityID] {
+			continue
+		}
+		w.activities[a.ActivityID] = true
+		events = append(events, Event{Type: EventActivity, Account: w.Account, Detected: detected, Activity: &a})
+	}
+
+	sort.SliceStable(values, func(i, j int) bool { return values[i].Date.Before(values[j].Date.Time) })
+	for _, v := range values {
+		key := v.Date.String() + "/" + v.Metric
+		previous, seen := w.metrics[key]
+		if seen && previous == v.Value {
+			continue
+		}
+		w.metrics[key] = v.Value
+		e := Event{Type: EventDailyMetric, Account: w.Account, Detected: detected, Date: v.Date.String(), Metric: v.Metric, Value: api.Ptr(v.Value)}
+		if seen {
+			e.Previous = api.Ptr(previous)
+		}
+		events = append(events, e)
+	}
+
+	// A failed save is retried by the next poll
+	w.unsaved = w.unsaved || len(events) > 0
+	if w.State != nil && (w.unsaved || !w.stored) {
+		if err := w.State.Save(ctx, w.seen()); err != nil {
+			return events, fmt.Errorf("failed to save watch state: %w", err)
+		}
+		w.stored, w.unsaved = true, false
+	}
+	return events, fetchErr
+}
+
+// WriteJSONL writes each event as one line of JSON
+func WriteJSONL(out io.Writer, events []Event) error {
+	enc := json.NewEncoder(out)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return fmt.Errorf("failed to write event: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sstent/go-garminconnect/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSource returns whatever the test last set
+type fakeSource struct {
+	activities []api.Activity
+	steps      int
+}
+
+func (f *fakeSource) GetActivitiesByDate(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.Activity, error) {
+	return f.activities, nil
+}
+
+func (f *fakeSource) GetStepsDataRange(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.DailySteps, error) {
+	return []api.DailySteps{{CalendarDate: api.NewDate(start), TotalSteps: f.steps}}, nil
+}
+
+func (f *fakeSource) GetSleepDataRange(ctx context.Context, start, end time.Time, opts ...api.RequestOption) ([]api.SleepData, error) {
+	return nil, nil
+}
+
+func (f *fakeSource) GetUserStats(ctx context.Context, date time.Time, opts ...api.RequestOption) (*api.UserStats, error) {
+	return &api.UserStats{}, nil
+}
+
+func TestWatcherPoll(t *testing.T) {
+	src := &fakeSource{activities: []api.Activity{{ActivityID: 1, Name: "Morning Run"}}, steps: 3000}
+	detected := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	w := NewWatcher(src)
+	w.Now = func() time.Time { return detected }
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	events, err := w.Poll(ctx, day, day)
+	assert.NoError(t, err)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, EventActivity, events[0].Type)
+		assert.Equal(t, int64(1), events[0].Activity.ActivityID)
+		assert.Equal(t, Event{Type: EventDailyMetric, Detected: detected, Date: "2024-03-01", Metric: "steps", Value: api.Ptr(3000.0)}, events[1])
+	}
+
+	events, err = w.Poll(ctx, day, day)
+	assert.NoError(t, err)
+	assert.Empty(t, events, "nothing changed")
+
+	src.activities = append(src.activities, api.Activity{ActivityID: 2, Name: "Evening Ride"})
+	src.steps = 9000
+	events, err = w.Poll(ctx, day, day)
+	assert.NoError(t, err)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, int64(2), events[0].Activity.ActivityID)
+		assert.Equal(t, api.Ptr(9000.0), events[1].Value)
+		assert.Equal(t, api.Ptr(3000.0), events[1].Previous)
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteJSONL(&buf, events[1:]))
+	assert.Equal(t, `{"type":"daily_metric","detected":"2024-03-01T12:00:00Z","date":"2024-03-01","metric":"steps","value":9000,"previous":3000}`+"\n", buf.String())
+}
+
+func TestWatcherState(t *testing.T) {
+	state := FileState{Path: filepath.Join(t.TempDir(), "watch.json")}
+	src := &fakeSource{activities: []api.Activity{{ActivityID: 1}}, steps: 3000}
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	w := NewWatcher(src)
+	w.State = state
+	restored, err := w.Restore(ctx)
+	assert.NoError(t, err)
+	assert.False(t, restored, "nothing saved yet")
+	events, err := w.Poll(ctx, day, day)
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+
+	// A restarted watcher reports only what changed while it was down
+	src.activities = append(src.activities, api.Activity{ActivityID: 2})
+	w = NewWatcher(src)
+	w.State = state
+	events, err = w.Poll(ctx, day, day)
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, int64(2), events[0].Activity.ActivityID)
+	}
+
+	w = NewWatcher(src)
+	w.State = state
+	restored, err = w.Restore(ctx)
+	assert.NoError(t, err)
+	assert.True(t, restored)
+	events, err = w.Poll(ctx, day, day)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+}